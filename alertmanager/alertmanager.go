@@ -0,0 +1,284 @@
+// Package alertmanager posts Prometheus Alertmanager notifications to Feishu
+// as cards, either by rendering webhook payloads with Cards or by pointing an
+// Alertmanager webhook receiver at a Handler.
+//
+// Example Alertmanager configuration:
+//
+//	receivers:
+//	  - name: feishu
+//	    webhook_configs:
+//	      - url: http://feishu-alerts:8080/alerts
+package alertmanager
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Alert statuses.
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// MaxAlertsPerCard is the maximum number of alerts shown in a card; further
+// alerts are only counted, keeping cards within the card limits.
+const MaxAlertsPerCard = 10
+
+// Webhook is the payload Alertmanager sends to webhook receivers.
+//
+// See: https://prometheus.io/docs/alerting/latest/configuration/#webhook_config
+type Webhook struct {
+	Version  string `json:"version"`
+	GroupKey string `json:"groupKey"`
+	// TruncatedAlerts is the number of alerts left out of Alerts because of
+	// the max_alerts setting of the receiver.
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	// ExternalURL is the URL of the Alertmanager, used for silence links.
+	ExternalURL string  `json:"externalURL"`
+	Alerts      []Alert `json:"alerts"`
+}
+
+// Alert is an alert of a Webhook.
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// title returns the summary of the alert, falling back to its name.
+func (a Alert) title() string {
+	for _, key := range []string{"summary", "title"} {
+		if text := a.Annotations[key]; text != "" {
+			return text
+		}
+	}
+	if name := a.Labels["alertname"]; name != "" {
+		return name
+	}
+	return "Alert"
+}
+
+// Cards renders a webhook payload as cards: one for the firing alerts and
+// one for the resolved alerts, whichever it has. Firing cards are colored by
+// the highest "severity" label of their alerts, resolved cards green.
+//
+// Labels shared by all alerts are shown once at the top; each alert shows
+// its description, its other labels as fields, and buttons to its source
+// graph, its runbook (the "runbook_url" annotation) and to silence it.
+//
+// Example:
+//
+//	var webhook alertmanager.Webhook
+//	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+//		return err
+//	}
+//	for _, card := range alertmanager.Cards(&webhook) {
+//		if _, err := client.Send(ctx, feishubot.NewInteractiveMessage(card)); err != nil {
+//			return err
+//		}
+//	}
+func Cards(webhook *Webhook) []*feishubot.Card {
+	var firing, resolved []Alert
+	for _, alert := range webhook.Alerts {
+		if alert.Status == StatusResolved {
+			resolved = append(resolved, alert)
+		} else {
+			firing = append(firing, alert)
+		}
+	}
+
+	var cards []*feishubot.Card
+	if len(firing) > 0 {
+		cards = append(cards, groupCard(webhook, StatusFiring, firing, webhook.TruncatedAlerts))
+	}
+	if len(resolved) > 0 {
+		cards = append(cards, groupCard(webhook, StatusResolved, resolved, 0))
+	}
+	return cards
+}
+
+// groupCard renders the alerts of a webhook with the given status.
+func groupCard(webhook *Webhook, status string, alerts []Alert, truncated int) *feishubot.Card {
+	template, tagColor := feishubot.TemplateGreen, feishubot.TextTagGreen
+	emoji := "✅"
+	if status == StatusFiring {
+		template, tagColor = severityColors(alerts)
+		emoji = "🔥"
+	}
+
+	title := groupTitle(webhook, alerts)
+	if len(alerts) > 1 {
+		title = fmt.Sprintf("%s (%d)", title, len(alerts)+truncated)
+	}
+	builder := feishubot.NewCardBuilder().
+		Header(emoji+" "+title, template).
+		HeaderTags(feishubot.NewTextTag(strings.ToUpper(status), tagColor))
+
+	if len(webhook.CommonLabels) > 0 && len(alerts) > 1 {
+		builder.Fields(labelFields(webhook.CommonLabels, nil))
+		builder.Divider()
+	}
+
+	shown := alerts
+	if len(shown) > MaxAlertsPerCard {
+		shown = shown[:MaxAlertsPerCard]
+	}
+	for i, alert := range shown {
+		if i > 0 {
+			builder.Divider()
+		}
+		addAlert(builder, webhook, alert, len(alerts) > 1)
+	}
+
+	if more := len(alerts) - len(shown) + truncated; more > 0 {
+		builder.Divider().Element(&feishubot.MarkdownElement{
+			Content:  fmt.Sprintf("%d more alerts not shown", more),
+			TextSize: "notation",
+		})
+	}
+	return builder.Build()
+}
+
+// addAlert appends the description, labels and buttons of an alert. Common
+// labels are left out when shown at the top of a card of several alerts.
+func addAlert(builder *feishubot.CardBuilder, webhook *Webhook, alert Alert, grouped bool) {
+	var text strings.Builder
+	if grouped {
+		fmt.Fprintf(&text, "**%s**\n", feishubot.Sanitize(alert.title()))
+	}
+	if description := alert.Annotations["description"]; description != "" {
+		fmt.Fprintf(&text, "%s\n", feishubot.Sanitize(description))
+	}
+	if !alert.StartsAt.IsZero() {
+		fmt.Fprintf(&text, "Started %s", alert.StartsAt.Format("2006-01-02 15:04:05 MST"))
+		if alert.Status == StatusResolved && !alert.EndsAt.IsZero() {
+			fmt.Fprintf(&text, ", resolved after %s", alert.EndsAt.Sub(alert.StartsAt).Round(time.Second))
+		}
+	}
+	if content := strings.TrimSpace(text.String()); content != "" {
+		builder.Markdown(content)
+	}
+
+	var common map[string]string
+	if grouped {
+		common = webhook.CommonLabels
+	}
+	if fields := labelFields(alert.Labels, common); len(fields) > 0 {
+		builder.Fields(fields)
+	}
+
+	var buttons []feishubot.CardElement
+	if alert.GeneratorURL != "" {
+		buttons = append(buttons, feishubot.NewButtonElement("Source", "default", alert.GeneratorURL))
+	}
+	if runbook := alert.Annotations["runbook_url"]; runbook != "" {
+		buttons = append(buttons, feishubot.NewButtonElement("Runbook", "primary", runbook))
+	}
+	if alert.Status != StatusResolved && webhook.ExternalURL != "" && len(alert.Labels) > 0 {
+		buttons = append(buttons, feishubot.NewButtonElement("Silence", "default", SilenceURL(webhook.ExternalURL, alert.Labels)))
+	}
+	if len(buttons) > 0 {
+		builder.Actions(buttons...)
+	}
+}
+
+// groupTitle returns the title of a card: the common summary or alert name,
+// or the group labels.
+func groupTitle(webhook *Webhook, alerts []Alert) string {
+	if len(alerts) == 1 {
+		return feishubot.Sanitize(alerts[0].title())
+	}
+	if summary := webhook.CommonAnnotations["summary"]; summary != "" {
+		return feishubot.Sanitize(summary)
+	}
+	if name := webhook.CommonLabels["alertname"]; name != "" {
+		return feishubot.Sanitize(name)
+	}
+	if len(webhook.GroupLabels) > 0 {
+		parts := make([]string, 0, len(webhook.GroupLabels))
+		for _, name := range sortedKeys(webhook.GroupLabels) {
+			parts = append(parts, name+"="+webhook.GroupLabels[name])
+		}
+		return feishubot.Sanitize(strings.Join(parts, ", "))
+	}
+	return "Alerts"
+}
+
+// labelFields returns the labels not in skip as fields, sorted by name with
+// alertname and severity left out as they are shown otherwise.
+func labelFields(labels, skip map[string]string) []feishubot.KV {
+	var fields []feishubot.KV
+	for _, name := range sortedKeys(labels) {
+		if name == "alertname" || name == "severity" {
+			continue
+		}
+		if _, ok := skip[name]; ok {
+			continue
+		}
+		fields = append(fields, feishubot.KV{Label: name, Value: labels[name]})
+	}
+	return fields
+}
+
+// severityColors returns the header template and tag color of the highest
+// severity of alerts.
+func severityColors(alerts []Alert) (template, tagColor string) {
+	rank := 0
+	for _, alert := range alerts {
+		switch strings.ToLower(alert.Labels["severity"]) {
+		case "critical", "page", "error":
+			rank = 3
+		case "warning", "warn":
+			if rank < 2 {
+				rank = 2
+			}
+		default:
+			if rank < 1 {
+				rank = 1
+			}
+		}
+	}
+	switch rank {
+	case 3:
+		return feishubot.TemplateRed, feishubot.TextTagRed
+	case 2:
+		return feishubot.TemplateOrange, feishubot.TextTagOrange
+	}
+	return feishubot.TemplateBlue, feishubot.TextTagBlue
+}
+
+// SilenceURL returns the URL of the Alertmanager UI page creating a silence
+// matching the labels.
+func SilenceURL(externalURL string, labels map[string]string) string {
+	matchers := make([]string, 0, len(labels))
+	for _, name := range sortedKeys(labels) {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+	filter := "{" + strings.Join(matchers, ",") + "}"
+	return strings.TrimSuffix(externalURL, "/") + "/#/silences/new?filter=" + url.QueryEscape(filter)
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,137 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+const firingPayload = `{
+	"version": "4",
+	"groupKey": "{}:{alertname=\"HighLatency\"}",
+	"truncatedAlerts": 0,
+	"status": "firing",
+	"receiver": "feishu",
+	"groupLabels": {"alertname": "HighLatency"},
+	"commonLabels": {"alertname": "HighLatency", "job": "api"},
+	"commonAnnotations": {"summary": "API latency above 1s"},
+	"externalURL": "http://alertmanager:9093",
+	"alerts": [
+		{
+			"status": "firing",
+			"labels": {"alertname": "HighLatency", "job": "api", "instance": "api-1", "severity": "warning"},
+			"annotations": {"summary": "API latency above 1s", "description": "p99 is 1.4s", "runbook_url": "https://wiki.example.com/latency"},
+			"startsAt": "2024-05-01T12:00:00Z",
+			"endsAt": "0001-01-01T00:00:00Z",
+			"generatorURL": "http://prometheus:9090/graph?g0.expr=latency",
+			"fingerprint": "a1"
+		},
+		{
+			"status": "firing",
+			"labels": {"alertname": "HighLatency", "job": "api", "instance": "api-2", "severity": "critical"},
+			"annotations": {"summary": "API latency above 1s"},
+			"startsAt": "2024-05-01T12:01:00Z",
+			"endsAt": "0001-01-01T00:00:00Z",
+			"fingerprint": "a2"
+		},
+		{
+			"status": "resolved",
+			"labels": {"alertname": "HighLatency", "job": "api", "instance": "api-3"},
+			"annotations": {"summary": "API latency above 1s"},
+			"startsAt": "2024-05-01T11:00:00Z",
+			"endsAt": "2024-05-01T11:05:30Z",
+			"fingerprint": "a3"
+		}
+	]
+}`
+
+func TestCards(t *testing.T) {
+	var webhook Webhook
+	require.NoError(t, json.Unmarshal([]byte(firingPayload), &webhook))
+
+	cards := Cards(&webhook)
+	require.Len(t, cards, 2)
+
+	firing, err := json.Marshal(cards[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {
+			"title": {"tag": "plain_text", "content": "🔥 API latency above 1s (2)"},
+			"template": "red",
+			"text_tag_list": [{"tag": "text_tag", "text": {"tag": "plain_text", "content": "FIRING"}, "color": "red"}]
+		},
+		"body": {"elements": [
+			{"tag": "div", "fields": [{"is_short": true, "text": {"tag": "lark_md", "content": "**job:** api"}}]},
+			{"tag": "hr"},
+			{"tag": "markdown", "content": "**API latency above 1s**\np99 is 1.4s\nStarted 2024-05-01 12:00:00 UTC"},
+			{"tag": "div", "fields": [{"is_short": true, "text": {"tag": "lark_md", "content": "**instance:** api-1"}}]},
+			{"tag": "column_set", "columns": [
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Source"}, "type": "default", "url": "http://prometheus:9090/graph?g0.expr=latency", "width": "fill"}
+				]},
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Runbook"}, "type": "primary", "url": "https://wiki.example.com/latency", "width": "fill"}
+				]},
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Silence"}, "type": "default", "url": "http://alertmanager:9093/#/silences/new?filter=%7Balertname%3D%22HighLatency%22%2Cinstance%3D%22api-1%22%2Cjob%3D%22api%22%2Cseverity%3D%22warning%22%7D", "width": "fill"}
+				]}
+			]},
+			{"tag": "hr"},
+			{"tag": "markdown", "content": "**API latency above 1s**\nStarted 2024-05-01 12:01:00 UTC"},
+			{"tag": "div", "fields": [{"is_short": true, "text": {"tag": "lark_md", "content": "**instance:** api-2"}}]},
+			{"tag": "column_set", "columns": [
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Silence"}, "type": "default", "url": "http://alertmanager:9093/#/silences/new?filter=%7Balertname%3D%22HighLatency%22%2Cinstance%3D%22api-2%22%2Cjob%3D%22api%22%2Cseverity%3D%22critical%22%7D", "width": "fill"}
+				]}
+			]}
+		]}
+	}`, string(firing))
+
+	resolved, err := json.Marshal(cards[1])
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {
+			"title": {"tag": "plain_text", "content": "✅ API latency above 1s"},
+			"template": "green",
+			"text_tag_list": [{"tag": "text_tag", "text": {"tag": "plain_text", "content": "RESOLVED"}, "color": "green"}]
+		},
+		"body": {"elements": [
+			{"tag": "markdown", "content": "Started 2024-05-01 11:00:00 UTC, resolved after 5m30s"},
+			{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**instance:** api-3"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**job:** api"}}
+			]}
+		]}
+	}`, string(resolved))
+}
+
+func TestCardsTruncated(t *testing.T) {
+	webhook := &Webhook{TruncatedAlerts: 5, CommonLabels: map[string]string{"alertname": "Down"}}
+	for i := 0; i < MaxAlertsPerCard+2; i++ {
+		webhook.Alerts = append(webhook.Alerts, Alert{Status: StatusFiring, Labels: map[string]string{"alertname": "Down"}})
+	}
+
+	cards := Cards(webhook)
+	require.Len(t, cards, 1)
+	require.Equal(t, "🔥 Down (17)", cards[0].Header.Title.Content)
+	elements := cards[0].Body.Elements
+	require.Equal(t, "7 more alerts not shown", elements[len(elements)-1].(*feishubot.MarkdownElement).Content)
+}
+
+func TestLabelFieldsEscapedOnce(t *testing.T) {
+	fields := labelFields(map[string]string{"alertname": "Down", "pod": "<at id=all></at>"}, nil)
+	require.Equal(t, []feishubot.KV{{Label: "pod", Value: "<at id=all></at>"}}, fields)
+
+	div := feishubot.NewFieldsElement(fields, 2).(*feishubot.Div)
+	require.Equal(t, "**pod:** &lt;at id=all&gt;&lt;/at&gt;", div.Fields[0].Text.Content)
+}
+
+func TestSilenceURL(t *testing.T) {
+	require.Equal(t,
+		"https://am.example.com/#/silences/new?filter=%7Balertname%3D%22Down%22%7D",
+		SilenceURL("https://am.example.com/", map[string]string{"alertname": "Down"}))
+}
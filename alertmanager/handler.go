@@ -0,0 +1,77 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// maxBodyBytes is the maximum size of webhook request bodies read.
+const maxBodyBytes = 4 << 20
+
+// Handler is an http.Handler receiving Alertmanager webhook notifications
+// and posting them as cards rendered by Cards.
+//
+// Notifications that cannot be posted are answered with status 502, so that
+// Alertmanager retries them.
+type Handler struct {
+	send feishubot.SendFunc
+
+	// OnError is called with errors of handling notifications. If nil, such
+	// errors are only reported in the response.
+	OnError func(error)
+}
+
+// NewHandler creates a handler posting notifications with send.
+//
+// Example:
+//
+//	client := feishubot.NewClient(webhookURL, secret)
+//	http.Handle("/alerts", alertmanager.NewHandler(feishubot.WebhookSender(client)))
+func NewHandler(send feishubot.SendFunc) *Handler {
+	return &Handler{send: send}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var webhook Webhook
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxBodyBytes)).Decode(&webhook); err != nil {
+		h.handleError(fmt.Errorf("failed to decode notification: %w", err))
+		http.Error(w, "invalid notification", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Notify(req.Context(), &webhook); err != nil {
+		h.handleError(err)
+		http.Error(w, "failed to post notification", http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Notify posts the cards of a webhook payload.
+func (h *Handler) Notify(ctx context.Context, webhook *Webhook) error {
+	for _, card := range Cards(webhook) {
+		if err := h.send(ctx, feishubot.NewInteractiveMessage(card)); err != nil {
+			return fmt.Errorf("failed to post alerts of group %s: %w", webhook.GroupKey, err)
+		}
+	}
+	return nil
+}
+
+// handleError reports err to the error handler.
+func (h *Handler) handleError(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+	}
+}
@@ -0,0 +1,52 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	var sent []*feishubot.Message
+	handler := NewHandler(func(_ context.Context, msg *feishubot.Message) error {
+		sent = append(sent, msg)
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader(firingPayload)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, sent, 2)
+	require.Equal(t, feishubot.MsgTypeInteractive, sent[0].MsgType)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/alerts", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	var errs []error
+	handler.OnError = func(err error) { errs = append(errs, err) }
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader("{")))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Len(t, errs, 1)
+}
+
+func TestHandlerSendError(t *testing.T) {
+	handler := NewHandler(func(context.Context, *feishubot.Message) error {
+		return errors.New("webhook down")
+	})
+
+	var errs []error
+	handler.OnError = func(err error) { errs = append(errs, err) }
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader(firingPayload)))
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "webhook down")
+}
@@ -0,0 +1,328 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ReceiveIDType is the kind of ID a message is addressed to with the
+// APIClient.
+type ReceiveIDType string
+
+const (
+	ReceiveIDTypeChatID  ReceiveIDType = "chat_id"
+	ReceiveIDTypeOpenID  ReceiveIDType = "open_id"
+	ReceiveIDTypeUnionID ReceiveIDType = "union_id"
+	ReceiveIDTypeUserID  ReceiveIDType = "user_id"
+	ReceiveIDTypeEmail   ReceiveIDType = "email"
+)
+
+// ErrInvalidRecipient is returned when a message recipient has no ID.
+var ErrInvalidRecipient = errors.New("invalid recipient")
+
+// Recipient is the chat or user a message is sent to with the APIClient.
+type Recipient struct {
+	IDType ReceiveIDType
+	ID     string
+}
+
+// ToChat addresses a group or direct chat by chat ID ("oc_...").
+func ToChat(chatID string) Recipient {
+	return Recipient{IDType: ReceiveIDTypeChatID, ID: chatID}
+}
+
+// ToOpenID addresses a user by open ID ("ou_...").
+func ToOpenID(openID string) Recipient {
+	return Recipient{IDType: ReceiveIDTypeOpenID, ID: openID}
+}
+
+// ToUnionID addresses a user by union ID ("on_...").
+func ToUnionID(unionID string) Recipient {
+	return Recipient{IDType: ReceiveIDTypeUnionID, ID: unionID}
+}
+
+// ToUserID addresses a user by tenant user ID.
+func ToUserID(userID string) Recipient {
+	return Recipient{IDType: ReceiveIDTypeUserID, ID: userID}
+}
+
+// ToEmail addresses a user by email address.
+func ToEmail(email string) Recipient {
+	return Recipient{IDType: ReceiveIDTypeEmail, ID: email}
+}
+
+// SentMessage describes a message sent with the APIClient.
+type SentMessage struct {
+	MessageID string `json:"message_id"`
+	ChatID    string `json:"chat_id"`
+	MsgType   string `json:"msg_type"`
+	// CreateTime is the send time in milliseconds since epoch.
+	CreateTime string `json:"create_time"`
+}
+
+// APIClient sends messages as a Feishu app through the Open API, addressed
+// to any chat or user the app's bot can reach, instead of the single chat of
+// a custom bot webhook. Messages are built with the same constructors as
+// for Client, so code can switch between webhook and API delivery.
+//
+// The app needs the im:message:send_as_bot permission, and the bot must be
+// a member of chats it sends to.
+//
+// Example:
+//
+//	api := feishubot.NewAPIClient(appID, appSecret)
+//	sent, err := api.Send(ctx, feishubot.ToChat("oc_xxx"), feishubot.NewTextMessage("Hello"))
+type APIClient struct {
+	AppID      string
+	AppSecret  string
+	HTTPClient HTTPClient
+
+	// BaseURL is the base URL of the Open API. If empty,
+	// DefaultOpenAPIBaseURL is used.
+	BaseURL string
+
+	// ValidateCards checks interactive cards against the documented card
+	// limits before sending, like Client.ValidateCards.
+	ValidateCards bool
+
+	// SendInterval is the minimum interval between messages sent by
+	// SendToMany. If zero, DefaultSendInterval is used.
+	SendInterval time.Duration
+
+	tokens *tokenCache
+}
+
+// NewAPIClient creates a client for the app with the given credentials.
+// The default HTTP client has a 30 second timeout.
+func NewAPIClient(appID, appSecret string) *APIClient {
+	return &APIClient{
+		AppID:     appID,
+		AppSecret: appSecret,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		tokens: newTokenCache(),
+	}
+}
+
+// SetHTTPClient sets a custom HTTP client.
+func (c *APIClient) SetHTTPClient(client HTTPClient) {
+	c.HTTPClient = client
+}
+
+// SetDomain sets the domain of the Open API, e.g. DomainLark for Lark apps.
+// See OpenAPIBaseURL.
+func (c *APIClient) SetDomain(domain string) {
+	c.BaseURL = OpenAPIBaseURL(domain)
+}
+
+// SetValidateCards enables or disables checking interactive cards against
+// the card limits before sending. See Card.Validate.
+func (c *APIClient) SetValidateCards(enabled bool) {
+	c.ValidateCards = enabled
+}
+
+// openAPI returns the Open API caller of the client's app.
+func (c *APIClient) openAPI() *openAPI {
+	return &openAPI{
+		appID:      c.AppID,
+		appSecret:  c.AppSecret,
+		baseURL:    c.BaseURL,
+		httpClient: c.HTTPClient,
+		tokens:     c.tokens,
+	}
+}
+
+// Send sends msg to the recipient.
+//
+// See: https://open.feishu.cn/document/server-docs/im-v1/message/create
+func (c *APIClient) Send(ctx context.Context, to Recipient, msg *Message) (*SentMessage, error) {
+	if to.ID == "" || to.IDType == "" {
+		return nil, fmt.Errorf("%w: recipient ID and ID type are required", ErrInvalidRecipient)
+	}
+
+	content, err := c.messageContent(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]string{
+		"receive_id": to.ID,
+		"msg_type":   string(msg.MsgType),
+		"content":    content,
+	}
+	path := "/im/v1/messages?receive_id_type=" + url.QueryEscape(string(to.IDType))
+
+	var sent SentMessage
+	if err := c.openAPI().callJSON(ctx, http.MethodPost, path, body, &sent); err != nil {
+		return nil, fmt.Errorf("failed to send message to %s %s: %w", to.IDType, to.ID, err)
+	}
+	return &sent, nil
+}
+
+// messageContent returns the JSON encoded content of msg in the form of the
+// Open API, validating cards if enabled.
+func (c *APIClient) messageContent(msg *Message) (string, error) {
+	if card := msg.card(); c.ValidateCards && msg.MsgType == MsgTypeInteractive && card != nil {
+		if err := validateCardLimits(card); err != nil {
+			return "", err
+		}
+	}
+	return apiMessageContent(msg)
+}
+
+// apiMessageContent returns the JSON encoded content of msg as expected by
+// the Open API, which differs from webhooks for cards and posts: cards are
+// the content itself, and post content is not wrapped in a "post" object.
+func apiMessageContent(msg *Message) (string, error) {
+	var content interface{}
+	if msg.Content != nil {
+		content = msg.Content
+	}
+	switch msg.MsgType {
+	case MsgTypeInteractive:
+		content = msg.card()
+	case MsgTypePost:
+		if post, ok := msg.Content["post"]; ok {
+			content = post
+		}
+	}
+	if content == nil {
+		return "", fmt.Errorf("message of type %s has no content", msg.MsgType)
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message content: %w", err)
+	}
+	return string(data), nil
+}
+
+// UpdateCard replaces the card of an interactive message previously sent by
+// the app, e.g. to turn a "deploying…" status card into "deployed ✅"
+// instead of sending a new message. The sent card must be shared, see
+// Card.SetUpdateMulti, and messages can only be updated within 14 days of
+// being sent.
+//
+// See: https://open.feishu.cn/document/server-docs/im-v1/message-card/patch
+//
+// Example:
+//
+//	sent, err := api.Send(ctx, feishubot.ToChat(chatID), feishubot.NewInteractiveMessage(deployingCard))
+//	// ...
+//	err = api.UpdateCard(ctx, sent.MessageID, deployedCard)
+func (c *APIClient) UpdateCard(ctx context.Context, messageID string, card *Card) error {
+	if messageID == "" {
+		return errors.New("failed to update card: message ID is required")
+	}
+
+	content, err := c.messageContent(NewInteractiveMessage(card))
+	if err != nil {
+		return err
+	}
+
+	path := "/im/v1/messages/" + url.PathEscape(messageID)
+	body := map[string]string{"content": content}
+	if err := c.openAPI().callJSON(ctx, http.MethodPatch, path, body, nil); err != nil {
+		return fmt.Errorf("failed to update card of message %s: %w", messageID, err)
+	}
+	return nil
+}
+
+// Recall withdraws a message previously sent by the app, e.g. a false
+// positive alert. Bots can recall their own messages within the recall time
+// limit of the tenant, 24 hours by default.
+//
+// See: https://open.feishu.cn/document/server-docs/im-v1/message/delete
+func (c *APIClient) Recall(ctx context.Context, messageID string) error {
+	if messageID == "" {
+		return errors.New("failed to recall message: message ID is required")
+	}
+
+	path := "/im/v1/messages/" + url.PathEscape(messageID)
+	if err := c.openAPI().call(ctx, http.MethodDelete, path, "", nil, nil); err != nil {
+		return fmt.Errorf("failed to recall message %s: %w", messageID, err)
+	}
+	return nil
+}
+
+// Chat is a chat the app's bot is a member of.
+type Chat struct {
+	ChatID      string `json:"chat_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Avatar      string `json:"avatar"`
+	OwnerID     string `json:"owner_id"`
+	// External reports whether the chat includes users of other tenants.
+	External bool `json:"external"`
+	// ChatStatus is "normal", "dissolved" or "dissolved_save".
+	ChatStatus string `json:"chat_status"`
+}
+
+// ListBotChats returns the group chats the app's bot is a member of, e.g. to
+// find the chat ID for Send or NewShareChatMessage. All pages are fetched.
+// The app needs the im:chat:readonly permission.
+//
+// See: https://open.feishu.cn/document/server-docs/group/chat/list
+func (c *APIClient) ListBotChats(ctx context.Context) ([]Chat, error) {
+	var chats []Chat
+	pageToken := ""
+	for {
+		query := url.Values{"page_size": {"100"}}
+		if pageToken != "" {
+			query.Set("page_token", pageToken)
+		}
+
+		var data struct {
+			Items     []Chat `json:"items"`
+			PageToken string `json:"page_token"`
+			HasMore   bool   `json:"has_more"`
+		}
+		path := "/im/v1/chats?" + query.Encode()
+		if err := c.openAPI().call(ctx, http.MethodGet, path, "", nil, &data); err != nil {
+			return nil, fmt.Errorf("failed to list bot chats: %w", err)
+		}
+		chats = append(chats, data.Items...)
+
+		if !data.HasMore || data.PageToken == "" {
+			return chats, nil
+		}
+		pageToken = data.PageToken
+	}
+}
+
+// Pin pins a message in its chat, e.g. an announcement sent by the app. The
+// bot must be a member of the chat.
+//
+// See: https://open.feishu.cn/document/server-docs/im-v1/pin/create
+func (c *APIClient) Pin(ctx context.Context, messageID string) error {
+	if messageID == "" {
+		return errors.New("failed to pin message: message ID is required")
+	}
+
+	body := map[string]string{"message_id": messageID}
+	if err := c.openAPI().callJSON(ctx, http.MethodPost, "/im/v1/pins", body, nil); err != nil {
+		return fmt.Errorf("failed to pin message %s: %w", messageID, err)
+	}
+	return nil
+}
+
+// Unpin removes the pin of a message from its chat.
+//
+// See: https://open.feishu.cn/document/server-docs/im-v1/pin/delete
+func (c *APIClient) Unpin(ctx context.Context, messageID string) error {
+	if messageID == "" {
+		return errors.New("failed to unpin message: message ID is required")
+	}
+
+	path := "/im/v1/pins/" + url.PathEscape(messageID)
+	if err := c.openAPI().call(ctx, http.MethodDelete, path, "", nil, nil); err != nil {
+		return fmt.Errorf("failed to unpin message %s: %w", messageID, err)
+	}
+	return nil
+}
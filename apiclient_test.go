@@ -0,0 +1,216 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIClientSend(t *testing.T) {
+	var gotQuery string
+	var gotBody map[string]string
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/im/v1/messages", r.URL.Path)
+		require.Equal(t, "Bearer t-cli_a", r.Header.Get("Authorization"))
+		gotQuery = r.URL.RawQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"message_id":"om_xxx","chat_id":"oc_xxx","msg_type":"text","create_time":"1700000000000"}}`))
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+
+	sent, err := client.Send(context.Background(), ToChat("oc_xxx"), NewTextMessage("hello"))
+	require.NoError(t, err)
+	require.Equal(t, &SentMessage{MessageID: "om_xxx", ChatID: "oc_xxx", MsgType: "text", CreateTime: "1700000000000"}, sent)
+	require.Equal(t, "receive_id_type=chat_id", gotQuery)
+	require.Equal(t, "oc_xxx", gotBody["receive_id"])
+	require.Equal(t, "text", gotBody["msg_type"])
+	require.JSONEq(t, `{"text":"hello"}`, gotBody["content"])
+
+	_, err = client.Send(context.Background(), ToEmail("dev@example.com"), NewTextMessage("hello"))
+	require.NoError(t, err)
+	require.Equal(t, "receive_id_type=email", gotQuery)
+	require.Equal(t, "dev@example.com", gotBody["receive_id"])
+
+	_, err = client.Send(context.Background(), Recipient{}, NewTextMessage("hello"))
+	require.ErrorIs(t, err, ErrInvalidRecipient)
+}
+
+func TestAPIClientSendError(t *testing.T) {
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":230002,"msg":"Bot/User can NOT be out of the chat."}`))
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+
+	_, err := client.Send(context.Background(), ToChat("oc_xxx"), NewTextMessage("hello"))
+	require.EqualError(t, err, "failed to send message to chat_id oc_xxx: API error (code 230002): Bot/User can NOT be out of the chat.")
+
+	client.AppSecret = ""
+	_, err = client.Send(context.Background(), ToChat("oc_xxx"), NewTextMessage("hello"))
+	require.ErrorIs(t, err, ErrNoAppCredentials)
+}
+
+func TestAPIClientValidateCards(t *testing.T) {
+	client := NewAPIClient("cli_a", "secret")
+	client.SetValidateCards(true)
+
+	builder := NewCardBuilder()
+	for i := 0; i <= MaxCardElements; i++ {
+		builder.Markdown("x")
+	}
+	_, err := client.Send(context.Background(), ToChat("oc_xxx"), NewInteractiveMessage(builder.Build()))
+	require.ErrorIs(t, err, ErrCardLimitExceeded)
+}
+
+func TestRecipients(t *testing.T) {
+	require.Equal(t, Recipient{IDType: ReceiveIDTypeChatID, ID: "oc_x"}, ToChat("oc_x"))
+	require.Equal(t, Recipient{IDType: ReceiveIDTypeOpenID, ID: "ou_x"}, ToOpenID("ou_x"))
+	require.Equal(t, Recipient{IDType: ReceiveIDTypeUnionID, ID: "on_x"}, ToUnionID("on_x"))
+	require.Equal(t, Recipient{IDType: ReceiveIDTypeUserID, ID: "u"}, ToUserID("u"))
+	require.Equal(t, Recipient{IDType: ReceiveIDTypeEmail, ID: "a@b.c"}, ToEmail("a@b.c"))
+}
+
+func TestAPIMessageContent(t *testing.T) {
+	card := NewCardBuilder().Markdown("hi").Build()
+	post := NewPostMessage(LanguageZhCN, NewPostContent("Title", NewParagraph(NewTextElement("text"))))
+
+	tests := []struct {
+		name string
+		msg  *Message
+		want string
+	}{
+		{name: "text", msg: NewTextMessage("hi"), want: `{"text":"hi"}`},
+		{name: "image", msg: NewImageMessage("img_x"), want: `{"image_key":"img_x"}`},
+		{name: "typed card", msg: NewInteractiveMessage(card), want: mustMarshal(t, card)},
+		{name: "map card", msg: NewInteractiveMessageFromMap(map[string]interface{}{"elements": []interface{}{}}), want: `{"elements":[]}`},
+		{name: "post", msg: post, want: `{"zh_cn":{"title":"Title","content":[[{"tag":"text","text":"text"}]]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := apiMessageContent(tt.msg)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, got)
+		})
+	}
+
+	_, err := apiMessageContent(&Message{MsgType: MsgTypeText})
+	require.ErrorContains(t, err, "has no content")
+}
+
+func mustMarshal(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestAPIClientUpdateCard(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody map[string]string
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		if r.URL.Path == "/im/v1/messages/om_old" {
+			_, _ = w.Write([]byte(`{"code":230031,"msg":"The message is too old to be updated."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+
+	card := NewCardBuilder().Header("Deployed ✅", TemplateGreen).Build()
+	require.NoError(t, client.UpdateCard(context.Background(), "om_xxx", card))
+	require.Equal(t, http.MethodPatch, gotMethod)
+	require.Equal(t, "/im/v1/messages/om_xxx", gotPath)
+	require.JSONEq(t, mustMarshal(t, card), gotBody["content"])
+
+	err := client.UpdateCard(context.Background(), "om_old", card)
+	require.EqualError(t, err, "failed to update card of message om_old: API error (code 230031): The message is too old to be updated.")
+
+	require.ErrorContains(t, client.UpdateCard(context.Background(), "", card), "message ID is required")
+}
+
+func TestCardSetUpdateMulti(t *testing.T) {
+	card := NewCard("2.0").SetUpdateMulti(true)
+	require.Equal(t, map[string]interface{}{"update_multi": true}, card.Config)
+}
+
+func TestAPIClientRecall(t *testing.T) {
+	var got []string
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/im/v1/messages/om_expired" {
+			_, _ = w.Write([]byte(`{"code":230026,"msg":"No permission to recall this message."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+
+	require.NoError(t, client.Recall(context.Background(), "om_xxx"))
+	err := client.Recall(context.Background(), "om_expired")
+	require.EqualError(t, err, "failed to recall message om_expired: API error (code 230026): No permission to recall this message.")
+	require.Equal(t, []string{"DELETE /im/v1/messages/om_xxx", "DELETE /im/v1/messages/om_expired"}, got)
+
+	require.ErrorContains(t, client.Recall(context.Background(), ""), "message ID is required")
+}
+
+func TestAPIClientPin(t *testing.T) {
+	var got []string
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, map[string]string{"message_id": "om_xxx"}, body)
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+
+	require.NoError(t, client.Pin(context.Background(), "om_xxx"))
+	require.NoError(t, client.Unpin(context.Background(), "om_xxx"))
+	require.Equal(t, []string{"POST /im/v1/pins", "DELETE /im/v1/pins/om_xxx"}, got)
+
+	require.ErrorContains(t, client.Pin(context.Background(), ""), "message ID is required")
+	require.ErrorContains(t, client.Unpin(context.Background(), ""), "message ID is required")
+}
+
+func TestAPIClientListBotChats(t *testing.T) {
+	var gotTokens []string
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/im/v1/chats", r.URL.Path)
+		require.Equal(t, "100", r.URL.Query().Get("page_size"))
+		gotTokens = append(gotTokens, r.URL.Query().Get("page_token"))
+		if r.URL.Query().Get("page_token") == "" {
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"items":[{"chat_id":"oc_a","name":"Ops","chat_status":"normal"}],"page_token":"p2","has_more":true}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"items":[{"chat_id":"oc_b","name":"Partners","external":true,"chat_status":"normal"}],"has_more":false}}`))
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+
+	chats, err := client.ListBotChats(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []Chat{
+		{ChatID: "oc_a", Name: "Ops", ChatStatus: "normal"},
+		{ChatID: "oc_b", Name: "Partners", External: true, ChatStatus: "normal"},
+	}, chats)
+	require.Equal(t, []string{"", "p2"}, gotTokens)
+}
@@ -30,6 +30,56 @@ type Client struct {
 	WebhookURL string
 	Secret     string
 	HTTPClient HTTPClient
+
+	// SanitizeText applies Sanitize to the text of text messages and to the
+	// title and the text, markdown and link elements of post messages before
+	// sending. Enable it when message text comes from external users.
+	SanitizeText bool
+
+	// AutoSplitBytes, when positive, splits text messages longer than this
+	// many bytes into several messages with SplitText and sends them in order.
+	AutoSplitBytes int
+
+	// ValidateCards checks interactive cards against the documented card
+	// limits before sending and returns an error wrapping
+	// ErrCardLimitExceeded instead of sending cards that Feishu would reject.
+	ValidateCards bool
+
+	// Keyword is the custom keyword required by the security settings of
+	// the bot, if any. It is appended to text messages not containing it,
+	// and other messages not containing it cause a warning. See
+	// SetKeyword.
+	Keyword string
+
+	// MaxRetries is the number of times sending a message is retried after
+	// network errors, server errors and rate limiting. Zero disables
+	// retries. See SetRetry.
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubled for each
+	// further retry. If zero, DefaultRetryBackoff is used.
+	RetryBackoff time.Duration
+
+	// limiter delays requests exceeding the rate limit, see SetRateLimit.
+	limiter *rateLimiter
+
+	// WarningHandler is called with non-fatal problems detected before
+	// sending, such as card elements that need interaction callbacks (see
+	// Message.WebhookWarnings). If nil, warnings are discarded and not
+	// computed.
+	WarningHandler func(warning string)
+
+	// AppID and AppSecret are the credentials of a Feishu app, needed for
+	// operations that webhooks do not support, such as uploading images.
+	// See SetAppCredentials.
+	AppID     string
+	AppSecret string
+
+	// OpenAPIBaseURL is the base URL of the Open API. If empty,
+	// DefaultOpenAPIBaseURL is used.
+	OpenAPIBaseURL string
+
+	tokens *tokenCache
 }
 
 // Response represents the response from the Feishu webhook API.
@@ -49,13 +99,16 @@ type Response struct {
 //
 // The default HTTP client has a 30 second timeout. For custom timeout settings,
 // use SetHTTPClient after creating the client.
+//
+// Warnings are discarded by default; use SetWarningHandler to receive them.
 func NewClient(webhookURL string, secret string) *Client {
 	return &Client{
 		WebhookURL: webhookURL,
 		Secret:     secret,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: defaultTimeout,
 		},
+		tokens: newTokenCache(),
 	}
 }
 
@@ -65,15 +118,67 @@ func (c *Client) SetHTTPClient(client HTTPClient) {
 	c.HTTPClient = client
 }
 
+// SetSanitizeText enables or disables automatic sanitizing of message text.
+// See Sanitize for what is escaped and stripped.
+func (c *Client) SetSanitizeText(enabled bool) {
+	c.SanitizeText = enabled
+}
+
+// SetValidateCards enables or disables checking interactive cards against
+// the card limits before sending. See Card.Validate.
+func (c *Client) SetValidateCards(enabled bool) {
+	c.ValidateCards = enabled
+}
+
+// SetWarningHandler sets the function called with non-fatal warnings, e.g.
+// a logger. Warnings are only computed if a handler is set; pass nil to
+// discard them.
+func (c *Client) SetWarningHandler(handler func(warning string)) {
+	c.WarningHandler = handler
+}
+
+// SetKeyword sets the custom keyword required by the bot's security
+// settings. Pass an empty keyword to disable adding it.
+func (c *Client) SetKeyword(keyword string) {
+	c.Keyword = keyword
+}
+
+// SetRetry enables retrying failed sends up to maxRetries times, waiting
+// backoff before the first retry and doubling it for each further retry. A
+// Retry-After header longer than the backoff is honored. Pass zero
+// maxRetries to disable retries.
+func (c *Client) SetRetry(maxRetries int, backoff time.Duration) {
+	c.MaxRetries = maxRetries
+	c.RetryBackoff = backoff
+}
+
+// SetRateLimit delays sends, including retries and the chunks of split
+// messages, to stay within limit, e.g. DefaultRateLimit when several
+// goroutines share a client. A zero limit disables rate limiting.
+func (c *Client) SetRateLimit(limit RateLimit) {
+	c.limiter = newRateLimiter(limit)
+}
+
+// SetAutoSplit enables splitting of long text messages into chunks of at most
+// maxBytes bytes, sent in order. A value of zero disables splitting.
+func (c *Client) SetAutoSplit(maxBytes int) {
+	c.AutoSplitBytes = maxBytes
+}
+
 // Send sends a message to the Feishu webhook.
 //
 // If a secret is configured, the timestamp and signature will be automatically
-// added to the message for security verification.
+// added to the message for security verification. Messages that already carry
+// a signature (see Message.WithSignature) are sent as-is.
 //
 // Parameters:
 //   - ctx: Context for the request, can be used for cancellation
 //   - msg: The message to send
 //
+// If auto-split is enabled and a text message is too long, it is sent as
+// several messages in order; sending stops at the first failing chunk and the
+// response of the last sent chunk is returned.
+//
 // Returns:
 //   - The API response
 //   - An error if the request fails or returns a non-zero code
@@ -81,23 +186,82 @@ func (c *Client) Send(ctx context.Context, msg *Message) (*Response, error) {
 	// Clone the message to avoid modifying the original
 	msgCopy := *msg
 
-	// Add signature if secret is configured
-	if c.Secret != "" {
-		timestamp := time.Now().Unix()
-		sign, err := GenSign(c.Secret, timestamp)
+	if c.SanitizeText {
+		msgCopy.Content = sanitizeContent(msgCopy.MsgType, msgCopy.Content)
+	}
+
+	if card := msgCopy.card(); c.ValidateCards && msgCopy.MsgType == MsgTypeInteractive && card != nil {
+		if err := validateCardLimits(card); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.WarningHandler != nil {
+		for _, warning := range msgCopy.WebhookWarnings() {
+			c.WarningHandler(warning)
+		}
+	}
+
+	chunks := c.splitMessage(&msgCopy)
+	if len(chunks) == 1 {
+		return c.send(ctx, chunks[0])
+	}
+
+	var resp *Response
+	for i, chunk := range chunks {
+		var err error
+		resp, err = c.send(ctx, chunk)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate signature: %w", err)
+			return resp, fmt.Errorf("failed to send chunk %d/%d: %w", i+1, len(chunks), err)
 		}
-		msgCopy.Timestamp = timestamp
-		msgCopy.Sign = sign
+	}
+	return resp, nil
+}
+
+// splitMessage splits a long text message according to AutoSplitBytes.
+// Messages that need no splitting are returned as the only element.
+func (c *Client) splitMessage(msg *Message) []*Message {
+	text, ok := msg.Content["text"].(string)
+	if c.AutoSplitBytes <= 0 || msg.MsgType != MsgTypeText || !ok || len(text) <= c.AutoSplitBytes {
+		return []*Message{msg}
+	}
+
+	chunks := SplitText(text, c.AutoSplitBytes)
+	messages := make([]*Message, 0, len(chunks))
+	for _, chunk := range chunks {
+		chunkMsg := *msg
+		chunkMsg.Content = map[string]interface{}{"text": chunk}
+		messages = append(messages, &chunkMsg)
+	}
+	return messages
+}
+
+// send signs and posts a single message. msg is modified in place.
+func (c *Client) send(ctx context.Context, msg *Message) (*Response, error) {
+	if c.Keyword != "" {
+		msg.Content = addKeyword(msg.MsgType, msg.Content, c.Keyword)
+	}
+
+	// Add signature if secret is configured and the message is not pre-signed
+	if err := signMessage(msg, c.Secret); err != nil {
+		return nil, err
 	}
 
 	// Marshal message to JSON
-	body, err := json.Marshal(msgCopy)
+	body, err := json.Marshal(msg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal message: %w", err)
 	}
+	if c.Keyword != "" && c.WarningHandler != nil && !bytes.Contains(body, []byte(c.Keyword)) {
+		c.WarningHandler(fmt.Sprintf("%s message does not contain the keyword %q required by the bot", msg.MsgType, c.Keyword))
+	}
+
+	return c.postWithRetry(ctx, body)
+}
 
+// post posts a marshalled message once. Errors worth retrying are returned
+// as *retryableError.
+func (c *Client) post(ctx context.Context, body []byte) (*Response, error) {
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.WebhookURL, bytes.NewReader(body))
 	if err != nil {
@@ -109,26 +273,56 @@ func (c *Client) Send(ctx context.Context, msg *Message) (*Response, error) {
 	// Send request
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		err = fmt.Errorf("failed to send request: %w", err)
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, &retryableError{err: err}
 	}
 	defer resp.Body.Close()
 
 	// Read response body
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, &retryableError{err: fmt.Errorf("failed to read response body: %w", err)}
 	}
 
 	// Parse response
 	var apiResp Response
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		err = fmt.Errorf("failed to unmarshal response: %w", err)
+		if isRetryableStatus(resp.StatusCode) {
+			return nil, &retryableError{err: err, after: retryAfter(resp.Header)}
+		}
+		return nil, err
 	}
 
 	// Check for API errors
 	if apiResp.Code != 0 {
-		return &apiResp, fmt.Errorf("API error (code %d): %s", apiResp.Code, apiResp.Msg)
+		err := fmt.Errorf("API error (code %d): %s", apiResp.Code, apiResp.Msg)
+		if apiResp.Code == codeFrequencyLimited || isRetryableStatus(resp.StatusCode) {
+			return &apiResp, &retryableError{err: err, after: retryAfter(resp.Header)}
+		}
+		return &apiResp, err
 	}
 
 	return &apiResp, nil
 }
+
+// signMessage adds the current timestamp and its signature to msg when a
+// secret is given and the message does not already carry a signature.
+func signMessage(msg *Message, secret string) error {
+	if secret == "" || msg.Sign != "" {
+		return nil
+	}
+
+	timestamp := time.Now().Unix()
+	sign, err := GenSign(secret, timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to generate signature: %w", err)
+	}
+	msg.Timestamp = timestamp
+	msg.Sign = sign
+
+	return nil
+}
@@ -298,6 +298,75 @@ func TestSignatureGeneration(t *testing.T) {
 	require.Equal(t, expectedSign, capturedMsg.Sign)
 }
 
+// TestSendPreSigned tests that a pre-signed message is sent without re-signing.
+func TestSendPreSigned(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+	}{
+		{"client with secret", "client_secret"},
+		{"client without secret", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capturedMsg Message
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				err := json.NewDecoder(r.Body).Decode(&capturedMsg)
+				require.NoError(t, err)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(SuccessResponse)
+			}))
+			defer server.Close()
+
+			sign, err := GenSign("signing_service_secret", 1599360473)
+			require.NoError(t, err)
+
+			client := NewClient(server.URL+"/webhook", tt.secret)
+			message := NewTextMessage("test").WithSignature(1599360473, sign)
+			_, err = client.Send(context.Background(), message)
+
+			require.NoError(t, err)
+			require.Equal(t, int64(1599360473), capturedMsg.Timestamp)
+			require.Equal(t, sign, capturedMsg.Sign)
+		})
+	}
+}
+
+// TestSendWarnings tests that webhook warnings are reported before sending.
+func TestSendWarnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(SuccessResponse)
+	}))
+	defer server.Close()
+
+	var warnings []string
+	client := NewClient(server.URL+"/webhook", "")
+	require.Nil(t, client.WarningHandler)
+	client.SetWarningHandler(func(warning string) {
+		warnings = append(warnings, warning)
+	})
+
+	message := NewInteractiveMessage(NewCard("2.0").SetBody(&CardBody{
+		Elements: []CardElement{NewSelectPersonElement("Assignee", nil)},
+	}))
+	_, err := client.Send(context.Background(), message)
+
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], "select_person")
+
+	// A nil handler discards warnings
+	client.SetWarningHandler(nil)
+	_, err = client.Send(context.Background(), message)
+	require.NoError(t, err)
+}
+
 // TestRequestHeaders tests that correct headers are sent.
 func TestRequestHeaders(t *testing.T) {
 	var capturedContentType string
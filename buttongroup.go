@@ -0,0 +1,60 @@
+package feishubot
+
+// NewButtonGroup lays out buttons in rows of at most perRow equally wide
+// buttons, wrapping to further rows as needed, and returns one column set
+// per row. perRow is capped at MaxActionButtons; zero or less uses three.
+//
+// Buttons created with NewButtonElement fill their column unless a width
+// was set with WithButtonWidth. The last row is padded with empty columns
+// so that buttons line up with the rows above. Column sets are used instead
+// of an action module, which schema 2.0 cards do not support.
+//
+// Example:
+//
+//	builder.Element(feishubot.NewButtonGroup(3,
+//		feishubot.NewButtonElement("Logs", "default", logsURL),
+//		feishubot.NewButtonElement("Metrics", "default", metricsURL),
+//		feishubot.NewButtonElement("Runbook", "default", runbookURL),
+//		feishubot.NewButtonElement("Rollback", "danger", rollbackURL),
+//	)...)
+func NewButtonGroup(perRow int, buttons ...CardElement) []CardElement {
+	if perRow <= 0 {
+		perRow = 3
+	}
+	if perRow > MaxActionButtons {
+		perRow = MaxActionButtons
+	}
+	if len(buttons) == 0 {
+		return []CardElement{}
+	}
+	if len(buttons) < perRow {
+		perRow = len(buttons)
+	}
+
+	rows := make([]CardElement, 0, (len(buttons)+perRow-1)/perRow)
+	for start := 0; start < len(buttons); start += perRow {
+		row := NewColumnSet()
+		for i := start; i < start+perRow; i++ {
+			column := NewColumn(1)
+			if i < len(buttons) {
+				column.Elements = append(column.Elements, fillButton(buttons[i]))
+			}
+			row.Columns = append(row.Columns, column)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// fillButton returns a copy of a button element that fills its column,
+// unless the button already has a width.
+func fillButton(element CardElement) CardElement {
+	button, ok := element.(*ButtonElement)
+	if !ok || button.Width != "" {
+		return element
+	}
+
+	result := *button
+	result.Width = "fill"
+	return &result
+}
@@ -0,0 +1,100 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewButtonGroup(t *testing.T) {
+	button := func(text string) CardElement {
+		return NewButtonElement(text, "default", "https://example.com/"+text)
+	}
+
+	tests := []struct {
+		name    string
+		perRow  int
+		buttons []CardElement
+		want    string
+	}{
+		{
+			name:    "wraps and pads last row",
+			perRow:  2,
+			buttons: []CardElement{button("a"), button("b"), button("c")},
+			want: `[
+				{"tag": "column_set", "columns": [
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+						{"tag": "button", "text": {"tag": "plain_text", "content": "a"}, "type": "default", "url": "https://example.com/a", "width": "fill"}
+					]},
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+						{"tag": "button", "text": {"tag": "plain_text", "content": "b"}, "type": "default", "url": "https://example.com/b", "width": "fill"}
+					]}
+				]},
+				{"tag": "column_set", "columns": [
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+						{"tag": "button", "text": {"tag": "plain_text", "content": "c"}, "type": "default", "url": "https://example.com/c", "width": "fill"}
+					]},
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": []}
+				]}
+			]`,
+		},
+		{
+			name:   "fewer buttons than per row and custom width",
+			perRow: 0,
+			buttons: []CardElement{
+				NewButtonElement("a", "primary", "https://example.com/a", WithButtonWidth("100px")),
+				NewMarkdownElement("note"),
+			},
+			want: `[
+				{"tag": "column_set", "columns": [
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+						{"tag": "button", "text": {"tag": "plain_text", "content": "a"}, "type": "primary", "url": "https://example.com/a", "width": "100px"}
+					]},
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+						{"tag": "markdown", "content": "note"}
+					]}
+				]}
+			]`,
+		},
+		{
+			name:    "typed button",
+			perRow:  1,
+			buttons: []CardElement{&ButtonElement{Text: NewCardTitle("a"), Type: "primary"}},
+			want: `[
+				{"tag": "column_set", "columns": [
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+						{"tag": "button", "text": {"tag": "plain_text", "content": "a"}, "type": "primary", "width": "fill"}
+					]}
+				]}
+			]`,
+		},
+		{
+			name: "no buttons",
+			want: `[]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(NewButtonGroup(tt.perRow, tt.buttons...))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestNewButtonGroupPerRowLimit(t *testing.T) {
+	buttons := make([]CardElement, 7)
+	for i := range buttons {
+		buttons[i] = NewButtonElement("b", "default", "https://example.com")
+	}
+
+	rows := NewButtonGroup(10, buttons...)
+	require.Len(t, rows, 2)
+	require.Len(t, rows[0].(*ColumnSet).Columns, MaxActionButtons)
+	require.Len(t, rows[1].(*ColumnSet).Columns, MaxActionButtons)
+
+	// The original buttons are not modified
+	require.Empty(t, buttons[0].(*ButtonElement).Width)
+}
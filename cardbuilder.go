@@ -0,0 +1,136 @@
+package feishubot
+
+// Header templates (colors) for CardHeader.Template.
+const (
+	TemplateBlue      = "blue"
+	TemplateWathet    = "wathet"
+	TemplateTurquoise = "turquoise"
+	TemplateGreen     = "green"
+	TemplateYellow    = "yellow"
+	TemplateOrange    = "orange"
+	TemplateRed       = "red"
+	TemplateCarmine   = "carmine"
+	TemplateViolet    = "violet"
+	TemplatePurple    = "purple"
+	TemplateIndigo    = "indigo"
+	TemplateGrey      = "grey"
+	TemplateDefault   = "default"
+)
+
+// CardBuilder builds a schema 2.0 card through chained calls, as a shorter
+// alternative to assembling Card, CardHeader and CardBody by hand.
+//
+// Example:
+//
+//	card := feishubot.NewCardBuilder().
+//		Header("Deploy finished", feishubot.TemplateGreen).
+//		Subtitle("api-gateway · production").
+//		HeaderTags(feishubot.NewTextTag("v1.4.2", feishubot.TextTagGreen)).
+//		Markdown("**api-gateway** has been updated").
+//		Divider().
+//		Fields([]feishubot.KV{
+//			{Label: "Version", Value: "v1.4.2"},
+//			{Label: "Duration", Value: "3m12s"},
+//		}).
+//		Actions(
+//			feishubot.NewButtonElement("Logs", "default", "https://example.com/logs"),
+//			feishubot.NewButtonElement("Rollback", "danger", "https://example.com/rollback"),
+//		).
+//		Build()
+//	message := feishubot.NewInteractiveMessage(card)
+type CardBuilder struct {
+	header   *CardHeader
+	elements []CardElement
+}
+
+// NewCardBuilder creates a new CardBuilder.
+func NewCardBuilder() *CardBuilder {
+	return &CardBuilder{}
+}
+
+// Header sets a header with a plain text title and the given template, such
+// as TemplateBlue. An empty template uses the default header style.
+func (b *CardBuilder) Header(title, template string) *CardBuilder {
+	header := b.headerOrNew()
+	header.Title = NewCardTitle(title)
+	header.Template = template
+	return b
+}
+
+// Subtitle sets a plain text subtitle shown below the header title.
+func (b *CardBuilder) Subtitle(subtitle string) *CardBuilder {
+	b.headerOrNew().SetSubtitle(subtitle)
+	return b
+}
+
+// HeaderIcon sets the icon shown before the header title, e.g.
+// NewStandardIcon("alarm_outlined", "red").
+func (b *CardBuilder) HeaderIcon(icon *CardIcon) *CardBuilder {
+	b.headerOrNew().SetIcon(icon)
+	return b
+}
+
+// HeaderTags appends colored tags shown after the header title, e.g.
+// NewTextTag("P1", TextTagRed). Headers show at most MaxHeaderTextTags tags.
+func (b *CardBuilder) HeaderTags(tags ...TextTag) *CardBuilder {
+	b.headerOrNew().AddTextTags(tags...)
+	return b
+}
+
+// headerOrNew returns the header, creating it if needed.
+func (b *CardBuilder) headerOrNew() *CardHeader {
+	if b.header == nil {
+		b.header = &CardHeader{}
+	}
+	return b.header
+}
+
+// Markdown appends a markdown element.
+func (b *CardBuilder) Markdown(content string) *CardBuilder {
+	return b.Element(NewMarkdownElement(content))
+}
+
+// Divider appends a divider (hr) element.
+func (b *CardBuilder) Divider() *CardBuilder {
+	return b.Element(NewHrCardElement())
+}
+
+// Fields appends label/value pairs laid out in two columns, see
+// NewFieldsElement.
+func (b *CardBuilder) Fields(pairs []KV) *CardBuilder {
+	return b.Element(NewFieldsElement(pairs, 2))
+}
+
+// Actions appends the given buttons on one row, wrapping after
+// MaxActionButtons buttons. The buttons are laid out in column sets, see
+// NewButtonGroup, because schema 2.0 cards do not support action modules.
+func (b *CardBuilder) Actions(buttons ...CardElement) *CardBuilder {
+	return b.Buttons(len(buttons), buttons...)
+}
+
+// Buttons appends buttons in rows of at most perRow equally wide buttons,
+// see NewButtonGroup.
+func (b *CardBuilder) Buttons(perRow int, buttons ...CardElement) *CardBuilder {
+	return b.Element(NewButtonGroup(perRow, buttons...)...)
+}
+
+// Element appends arbitrary elements, e.g. those built with NewColumnSet or
+// NewChartElement.
+func (b *CardBuilder) Element(elements ...CardElement) *CardBuilder {
+	b.elements = append(b.elements, elements...)
+	return b
+}
+
+// Build creates the card.
+func (b *CardBuilder) Build() *Card {
+	elements := b.elements
+	if elements == nil {
+		elements = []CardElement{}
+	}
+
+	card := NewCard("2.0").SetBody(&CardBody{Elements: elements})
+	if b.header != nil {
+		card.SetHeader(b.header)
+	}
+	return card
+}
@@ -0,0 +1,89 @@
+package feishubot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCardBuilder_Build(t *testing.T) {
+	button := NewButtonElement("Logs", "default", "https://example.com/logs")
+
+	tests := []struct {
+		name    string
+		builder *CardBuilder
+		want    *Card
+	}{
+		{
+			name:    "empty",
+			builder: NewCardBuilder(),
+			want:    &Card{Schema: "2.0", Body: &CardBody{Elements: []CardElement{}}},
+		},
+		{
+			name: "rich header",
+			builder: NewCardBuilder().
+				Subtitle("production").
+				HeaderIcon(NewStandardIcon("alarm_outlined", "red")).
+				HeaderTags(NewTextTag("P1", TextTagRed)).
+				Header("Alert", TemplateRed).
+				HeaderTags(NewTextTag("api", TextTagNeutral)),
+			want: &Card{
+				Schema: "2.0",
+				Header: &CardHeader{
+					Title:    NewCardTitle("Alert"),
+					Subtitle: NewCardTitle("production"),
+					Template: "red",
+					Icon:     NewStandardIcon("alarm_outlined", "red"),
+					TextTagList: []TextTag{
+						NewTextTag("P1", TextTagRed),
+						NewTextTag("api", TextTagNeutral),
+					},
+				},
+				Body: &CardBody{Elements: []CardElement{}},
+			},
+		},
+		{
+			name: "all elements",
+			builder: NewCardBuilder().
+				Header("Deploy finished", TemplateGreen).
+				Markdown("**api** updated").
+				Divider().
+				Fields([]KV{
+					{Label: "Version", Value: "v1.4.2"},
+					{Label: "Logs", Value: "#123", URL: "https://ci.example.com/123"},
+				}).
+				Actions(button).
+				Buttons(2, button).
+				Element(NewMarkdownElement("footer")),
+			want: &Card{
+				Schema: "2.0",
+				Header: &CardHeader{
+					Title:    NewCardTitle("Deploy finished"),
+					Template: "green",
+				},
+				Body: &CardBody{Elements: []CardElement{
+					NewMarkdownElement("**api** updated"),
+					NewHrCardElement(),
+					&Div{
+						Fields: []DivField{
+							NewDivField(NewCardMarkdownTitle("**Version:** v1.4.2"), true),
+							NewDivField(NewCardMarkdownTitle("**Logs:** [#123](https://ci.example.com/123)"), true),
+						},
+					},
+					NewColumnSet(NewColumn(1, fillButton(button))),
+					NewColumnSet(NewColumn(1, fillButton(button))),
+					NewMarkdownElement("footer"),
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.builder.Build()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("CardBuilder.Build() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
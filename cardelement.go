@@ -0,0 +1,701 @@
+package feishubot
+
+import "encoding/json"
+
+// DivField is a text field of a div element. Short fields are laid out side
+// by side in multiple columns; other fields take the full width.
+type DivField struct {
+	IsShort bool       `json:"is_short"`
+	Text    *CardTitle `json:"text"`
+}
+
+// NewDivField creates a div field.
+func NewDivField(text *CardTitle, isShort bool) DivField {
+	return DivField{
+		IsShort: isShort,
+		Text:    text,
+	}
+}
+
+// Div is a typed builder for the card div element, supporting a text, fields
+// and an extra element shown at the end (e.g. a button or image).
+//
+// Example:
+//
+//	div := feishubot.NewDiv(feishubot.NewCardMarkdownTitle("**Deploy finished**")).
+//		AddField(feishubot.NewCardMarkdownTitle("**Service**\napi"), true).
+//		AddField(feishubot.NewCardMarkdownTitle("**Version**\nv1.4.2"), true).
+//		SetExtra(feishubot.NewButtonElement("Logs", "default", "https://example.com/logs"))
+//	body := &feishubot.CardBody{Elements: []feishubot.CardElement{div}}
+type Div struct {
+	Text   *CardTitle  `json:"text,omitempty"`
+	Fields []DivField  `json:"fields,omitempty"`
+	Extra  CardElement `json:"extra,omitempty"`
+}
+
+// NewDiv creates a div with the given text, which may be nil.
+func NewDiv(text *CardTitle) *Div {
+	return &Div{
+		Text: text,
+	}
+}
+
+// AddField appends a field to the div.
+func (d *Div) AddField(text *CardTitle, isShort bool) *Div {
+	d.Fields = append(d.Fields, NewDivField(text, isShort))
+	return d
+}
+
+// SetExtra sets the extra element shown at the end of the div.
+func (d *Div) SetExtra(extra CardElement) *Div {
+	d.Extra = extra
+	return d
+}
+
+// Tag returns "div".
+func (d *Div) Tag() string {
+	return "div"
+}
+
+// ToElement returns the div as a card element. Since *Div implements
+// CardElement, it can also be used directly.
+func (d *Div) ToElement() CardElement {
+	return d
+}
+
+// MarshalJSON encodes the div including its tag.
+func (d *Div) MarshalJSON() ([]byte, error) {
+	type alias Div
+	return marshalTagged(d.Tag(), (*alias)(d))
+}
+
+// UnmarshalJSON decodes the div, including its extra element.
+func (d *Div) UnmarshalJSON(data []byte) error {
+	type alias Div
+	aux := struct {
+		*alias
+		Extra json.RawMessage `json:"extra"`
+	}{alias: (*alias)(d)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	d.Extra = nil
+	if aux.Extra != nil {
+		extra, err := DecodeCardElement(aux.Extra)
+		if err != nil {
+			return err
+		}
+		d.Extra = extra
+	}
+	return nil
+}
+
+// Column is a column of a column set.
+type Column struct {
+	// Weight is the relative width of the column when Width is "weighted".
+	Weight int `json:"weight,omitempty"`
+	// Width is "weighted", "auto" or a fixed width such as "100px".
+	Width string `json:"width,omitempty"`
+	// VerticalAlign is "top", "center" or "bottom".
+	VerticalAlign string `json:"vertical_align,omitempty"`
+	// BackgroundStyle is "default" or a color such as "grey".
+	BackgroundStyle string        `json:"background_style,omitempty"`
+	Padding         Padding       `json:"padding,omitempty"`
+	Elements        []CardElement `json:"elements"`
+}
+
+// NewColumn creates a column holding the given elements.
+// A positive weight creates a weighted column; otherwise the column width
+// adapts to its content.
+func NewColumn(weight int, elements ...CardElement) *Column {
+	column := &Column{
+		Width:    "auto",
+		Elements: elements,
+	}
+	if weight > 0 {
+		column.Width = "weighted"
+		column.Weight = weight
+	}
+	return column
+}
+
+// SetWidth sets the width of the column ("weighted", "auto" or e.g. "100px").
+func (c *Column) SetWidth(width string) *Column {
+	c.Width = width
+	return c
+}
+
+// SetVerticalAlign sets the vertical alignment ("top", "center" or "bottom").
+func (c *Column) SetVerticalAlign(align string) *Column {
+	c.VerticalAlign = align
+	return c
+}
+
+// SetBackgroundStyle sets the background style ("default" or a color).
+func (c *Column) SetBackgroundStyle(style string) *Column {
+	c.BackgroundStyle = style
+	return c
+}
+
+// SetPadding sets the padding inside the column, e.g. PaddingAll(8).
+func (c *Column) SetPadding(padding Padding) *Column {
+	c.Padding = padding
+	return c
+}
+
+// Tag returns "column".
+func (c *Column) Tag() string {
+	return "column"
+}
+
+// ToElement returns the column as a card element. Since *Column implements
+// CardElement, it can also be used directly.
+func (c *Column) ToElement() CardElement {
+	return c
+}
+
+// MarshalJSON encodes the column including its tag. The weight is only
+// included for weighted columns.
+func (c *Column) MarshalJSON() ([]byte, error) {
+	type alias Column
+	column := alias(*c)
+	if column.Elements == nil {
+		column.Elements = []CardElement{}
+	}
+	if column.Width != "weighted" {
+		column.Weight = 0
+	}
+	return marshalTagged(c.Tag(), &column)
+}
+
+// UnmarshalJSON decodes the column, including its elements.
+func (c *Column) UnmarshalJSON(data []byte) error {
+	type alias Column
+	aux := struct {
+		*alias
+		Elements []json.RawMessage `json:"elements"`
+	}{alias: (*alias)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	elements, err := decodeCardElements(aux.Elements)
+	if err != nil {
+		return err
+	}
+	c.Elements = elements
+	return nil
+}
+
+// ColumnSet is a typed builder for the card column_set element, which lays
+// out columns side by side.
+//
+// Example:
+//
+//	columns := feishubot.NewColumnSet(
+//		feishubot.NewColumn(1, feishubot.NewMarkdownElement("**CPU**\n42%")),
+//		feishubot.NewColumn(1, feishubot.NewMarkdownElement("**Memory**\n73%")),
+//	).SetHorizontalSpacing(feishubot.SpacingMedium).SetBackgroundStyle("grey")
+//	body := &feishubot.CardBody{Elements: []feishubot.CardElement{columns}}
+type ColumnSet struct {
+	Columns []*Column `json:"columns"`
+	// FlexMode controls how columns wrap on narrow screens:
+	// "none", "stretch", "flow", "bisect" or "trisect".
+	FlexMode string `json:"flex_mode,omitempty"`
+	// HorizontalSpacing is the spacing between columns, e.g. SpacingMedium
+	// or SpacingPx(8).
+	HorizontalSpacing Spacing `json:"horizontal_spacing,omitempty"`
+	// BackgroundStyle is "default" or a color such as "grey".
+	BackgroundStyle string `json:"background_style,omitempty"`
+	Margin          Margin `json:"margin,omitempty"`
+}
+
+// NewColumnSet creates a column set from columns.
+func NewColumnSet(columns ...*Column) *ColumnSet {
+	return &ColumnSet{
+		Columns: columns,
+	}
+}
+
+// SetFlexMode sets how columns wrap on narrow screens.
+func (s *ColumnSet) SetFlexMode(mode string) *ColumnSet {
+	s.FlexMode = mode
+	return s
+}
+
+// SetHorizontalSpacing sets the spacing between columns.
+func (s *ColumnSet) SetHorizontalSpacing(spacing Spacing) *ColumnSet {
+	s.HorizontalSpacing = spacing
+	return s
+}
+
+// SetMargin sets the margin around the column set, e.g. MarginXY(8, 0).
+func (s *ColumnSet) SetMargin(margin Margin) *ColumnSet {
+	s.Margin = margin
+	return s
+}
+
+// SetBackgroundStyle sets the background style of the column set.
+func (s *ColumnSet) SetBackgroundStyle(style string) *ColumnSet {
+	s.BackgroundStyle = style
+	return s
+}
+
+// Tag returns "column_set".
+func (s *ColumnSet) Tag() string {
+	return "column_set"
+}
+
+// ToElement returns the column set as a card element. Since *ColumnSet
+// implements CardElement, it can also be used directly.
+func (s *ColumnSet) ToElement() CardElement {
+	return s
+}
+
+// MarshalJSON encodes the column set including its tag.
+func (s *ColumnSet) MarshalJSON() ([]byte, error) {
+	type alias ColumnSet
+	set := alias(*s)
+	if set.Columns == nil {
+		set.Columns = []*Column{}
+	}
+	return marshalTagged(s.Tag(), &set)
+}
+
+// ActionLayout controls how the elements of an action module are arranged.
+type ActionLayout string
+
+const (
+	// ActionLayoutDefault lets Feishu arrange the elements.
+	ActionLayoutDefault ActionLayout = "default"
+
+	// ActionLayoutBisected places two elements per row.
+	ActionLayoutBisected ActionLayout = "bisected"
+
+	// ActionLayoutTrisected places three elements per row.
+	ActionLayoutTrisected ActionLayout = "trisected"
+
+	// ActionLayoutFlow places elements in a row, wrapping when needed.
+	ActionLayoutFlow ActionLayout = "flow"
+)
+
+// NewActionElement creates an action module grouping interactive elements
+// such as buttons and selects on one row.
+//
+// The action module only exists in card schema 1.0: Feishu rejects schema
+// 2.0 cards containing one, and Card.Lint reports it. Use NewButtonGroup to
+// lay out buttons in schema 2.0 cards, or convert the finished card with
+// Card.ToV1Map.
+//
+// Example:
+//
+//	actions := feishubot.NewActionElement(feishubot.ActionLayoutBisected,
+//		feishubot.NewButtonElement("Approve", "primary", "https://example.com/approve"),
+//		feishubot.NewButtonElement("Reject", "danger", "https://example.com/reject"),
+//	)
+func NewActionElement(layout ActionLayout, actions ...CardElement) CardElement {
+	if actions == nil {
+		actions = []CardElement{}
+	}
+
+	result := MapElement{
+		"tag":     "action",
+		"actions": actions,
+	}
+	if layout != "" {
+		result["layout"] = layout
+	}
+
+	return result
+}
+
+// CardIcon is an icon shown in card elements such as buttons and headers.
+type CardIcon struct {
+	// Tag is "standard_icon" for icons from the Feishu icon library or
+	// "custom_icon" for uploaded images.
+	Tag    string `json:"tag"`
+	Token  string `json:"token,omitempty"`
+	Color  string `json:"color,omitempty"`
+	ImgKey string `json:"img_key,omitempty"`
+}
+
+// NewStandardIcon creates an icon from the Feishu icon library (formerly
+// ud_icon), e.g. "chat-forbidden_outlined". The color is optional.
+func NewStandardIcon(token, color string) *CardIcon {
+	return &CardIcon{
+		Tag:   "standard_icon",
+		Token: token,
+		Color: color,
+	}
+}
+
+// NewCustomIcon creates an icon from an uploaded image.
+func NewCustomIcon(imgKey string) *CardIcon {
+	return &CardIcon{
+		Tag:    "custom_icon",
+		ImgKey: imgKey,
+	}
+}
+
+// ButtonSize is the size of a button.
+type ButtonSize string
+
+const (
+	// ButtonSizeTiny is the smallest button size.
+	ButtonSizeTiny ButtonSize = "tiny"
+
+	// ButtonSizeSmall is a small button.
+	ButtonSizeSmall ButtonSize = "small"
+
+	// ButtonSizeMedium is the default button size.
+	ButtonSizeMedium ButtonSize = "medium"
+
+	// ButtonSizeLarge is a large button.
+	ButtonSizeLarge ButtonSize = "large"
+)
+
+// ButtonOption configures optional button settings in NewButtonElement,
+// NewFormSubmitButton and NewFormResetButton.
+type ButtonOption func(button *ButtonElement)
+
+// WithButtonIcon shows an icon before the button text.
+func WithButtonIcon(icon *CardIcon) ButtonOption {
+	return func(button *ButtonElement) {
+		button.Icon = icon
+	}
+}
+
+// WithButtonSize sets the button size.
+func WithButtonSize(size ButtonSize) ButtonOption {
+	return func(button *ButtonElement) {
+		button.Size = size
+	}
+}
+
+// WithButtonWidth sets the button width: "default", "fill" to take the full
+// width of its container, or a fixed width such as "100px".
+func WithButtonWidth(width string) ButtonOption {
+	return func(button *ButtonElement) {
+		button.Width = width
+	}
+}
+
+// WithButtonDisabled disables the button. If tips is not empty, it is shown
+// when the user hovers over or taps the disabled button.
+func WithButtonDisabled(tips string) ButtonOption {
+	return func(button *ButtonElement) {
+		button.Disabled = true
+		if tips != "" {
+			button.DisabledTips = NewCardTitle(tips)
+		}
+	}
+}
+
+// ElementOption configures optional settings of interactive card elements
+// such as person pickers.
+type ElementOption func(element MapElement)
+
+// WithElementName sets the name identifying the element in callbacks and
+// form submissions.
+func WithElementName(name string) ElementOption {
+	return func(element MapElement) {
+		element["name"] = name
+	}
+}
+
+// WithElementRequired marks the element as required when used in a form.
+func WithElementRequired() ElementOption {
+	return func(element MapElement) {
+		element["required"] = true
+	}
+}
+
+// NewSelectPersonElement creates a person picker (select_person element).
+// userIDs restricts the selectable people to the given open IDs; if empty,
+// all members of the chat can be selected.
+//
+// Selections are delivered through interaction callbacks, which only app
+// bots support. Sending a card with this element through a custom bot
+// webhook produces a warning; see Message.WebhookWarnings.
+func NewSelectPersonElement(placeholder string, userIDs []string, opts ...ElementOption) CardElement {
+	element := MapElement{
+		"tag":         "select_person",
+		"placeholder": NewCardTitle(placeholder),
+	}
+
+	if len(userIDs) > 0 {
+		options := make([]map[string]interface{}, 0, len(userIDs))
+		for _, id := range userIDs {
+			options = append(options, map[string]interface{}{"value": id})
+		}
+		element["options"] = options
+	}
+
+	for _, opt := range opts {
+		opt(element)
+	}
+
+	return element
+}
+
+// NewDatePickerElement creates a date picker. initialDate is an optional
+// preselected date in "2006-01-02" format.
+//
+// Like all pickers, it needs interaction callbacks; see NewSelectPersonElement.
+func NewDatePickerElement(placeholder, initialDate string, opts ...ElementOption) CardElement {
+	return newPickerElement("date_picker", placeholder, "initial_date", initialDate, opts)
+}
+
+// NewTimePickerElement creates a time picker. initialTime is an optional
+// preselected time in "15:04" format.
+func NewTimePickerElement(placeholder, initialTime string, opts ...ElementOption) CardElement {
+	return newPickerElement("picker_time", placeholder, "initial_time", initialTime, opts)
+}
+
+// NewDateTimePickerElement creates a date and time picker. initialDateTime is
+// an optional preselected value in "2006-01-02 15:04" format.
+func NewDateTimePickerElement(placeholder, initialDateTime string, opts ...ElementOption) CardElement {
+	return newPickerElement("picker_datetime", placeholder, "initial_datetime", initialDateTime, opts)
+}
+
+// newPickerElement creates a picker element with an optional initial value.
+func newPickerElement(tag, placeholder, initialKey, initial string, opts []ElementOption) CardElement {
+	element := MapElement{
+		"tag":         tag,
+		"placeholder": NewCardTitle(placeholder),
+	}
+	if initial != "" {
+		element[initialKey] = initial
+	}
+
+	for _, opt := range opts {
+		opt(element)
+	}
+
+	return element
+}
+
+// NewCheckerElement creates a checker (checkbox) element for task-list style
+// cards. Checking an item needs interaction callbacks; see
+// NewSelectPersonElement.
+func NewCheckerElement(text string, checked bool, opts ...ElementOption) CardElement {
+	element := MapElement{
+		"tag":     "checker",
+		"text":    NewCardTitle(text),
+		"checked": checked,
+	}
+
+	for _, opt := range opts {
+		opt(element)
+	}
+
+	return element
+}
+
+// WithOverallCheckable sets whether the whole checker area, not only the
+// checkbox, toggles the checked state.
+func WithOverallCheckable(enabled bool) ElementOption {
+	return func(element MapElement) {
+		element["overall_checkable"] = enabled
+	}
+}
+
+// NewFormElement creates a form container. Input values of the interactive
+// elements inside the form are submitted together when a button created with
+// NewFormSubmitButton is clicked.
+//
+// Forms need interaction callbacks, so they only work with app bots or card
+// templates; sending one through a custom bot webhook produces a warning.
+//
+// Example:
+//
+//	form := feishubot.NewFormElement("rollback",
+//		feishubot.NewInputElement("Reason", feishubot.WithElementName("reason"), feishubot.WithElementRequired()),
+//		feishubot.NewDatePickerElement("Date", "", feishubot.WithElementName("date")),
+//		feishubot.NewFormSubmitButton("Submit", "submit"),
+//		feishubot.NewFormResetButton("Reset", "reset"),
+//	)
+func NewFormElement(name string, elements ...CardElement) *FormElement {
+	if elements == nil {
+		elements = []CardElement{}
+	}
+
+	return &FormElement{
+		Name:     name,
+		Elements: elements,
+	}
+}
+
+// InputType is the type of an input element.
+type InputType string
+
+const (
+	// InputTypeText is a single-line text field.
+	InputTypeText InputType = "text"
+
+	// InputTypeMultilineText is a multi-line text field.
+	InputTypeMultilineText InputType = "multiline_text"
+
+	// InputTypePassword is a text field that hides its content.
+	InputTypePassword InputType = "password"
+)
+
+// NewInputElement creates a text input field.
+func NewInputElement(placeholder string, opts ...ElementOption) CardElement {
+	element := MapElement{
+		"tag":         "input",
+		"placeholder": NewCardTitle(placeholder),
+	}
+
+	for _, opt := range opts {
+		opt(element)
+	}
+
+	return element
+}
+
+// WithInputLabel sets the label shown next to an input field.
+func WithInputLabel(label string) ElementOption {
+	return func(element MapElement) {
+		element["label"] = NewCardTitle(label)
+	}
+}
+
+// WithInputDefaultValue sets the initial value of an input field.
+func WithInputDefaultValue(value string) ElementOption {
+	return func(element MapElement) {
+		element["default_value"] = value
+	}
+}
+
+// WithInputType sets the type of an input field.
+func WithInputType(inputType InputType) ElementOption {
+	return func(element MapElement) {
+		element["input_type"] = inputType
+	}
+}
+
+// WithInputMaxLength sets the maximum number of characters of an input field.
+func WithInputMaxLength(maxLength int) ElementOption {
+	return func(element MapElement) {
+		element["max_length"] = maxLength
+	}
+}
+
+// NewFormSubmitButton creates a button submitting the form it is placed in.
+func NewFormSubmitButton(text, name string, opts ...ButtonOption) *ButtonElement {
+	return newFormButton(text, name, "primary", "form_submit", opts)
+}
+
+// NewFormResetButton creates a button resetting the form it is placed in.
+func NewFormResetButton(text, name string, opts ...ButtonOption) *ButtonElement {
+	return newFormButton(text, name, "default", "form_reset", opts)
+}
+
+// newFormButton creates a form action button.
+func newFormButton(text, name, buttonType, actionType string, opts []ButtonOption) *ButtonElement {
+	button := &ButtonElement{
+		Text:       NewCardTitle(text),
+		Type:       buttonType,
+		ActionType: actionType,
+		Name:       name,
+	}
+
+	for _, opt := range opts {
+		opt(button)
+	}
+
+	return button
+}
+
+// ImageCombinationMode is the layout of a multi-image collage.
+type ImageCombinationMode string
+
+const (
+	// ImageCombinationDouble shows two images side by side, one large.
+	ImageCombinationDouble ImageCombinationMode = "double"
+
+	// ImageCombinationTriple shows three images, one large and two small.
+	ImageCombinationTriple ImageCombinationMode = "triple"
+
+	// ImageCombinationBisect shows images in a grid of two columns.
+	ImageCombinationBisect ImageCombinationMode = "bisect"
+
+	// ImageCombinationTrisect shows images in a grid of three columns.
+	ImageCombinationTrisect ImageCombinationMode = "trisect"
+)
+
+// NewImageCombinationElement creates an img_combination element showing
+// several images as one collage, e.g. for screenshot galleries.
+// The image keys must be obtained from Feishu image upload API.
+func NewImageCombinationElement(mode ImageCombinationMode, imageKeys ...string) CardElement {
+	images := make([]map[string]interface{}, 0, len(imageKeys))
+	for _, key := range imageKeys {
+		images = append(images, map[string]interface{}{"img_key": key})
+	}
+
+	return MapElement{
+		"tag":              "img_combination",
+		"combination_mode": mode,
+		"img_list":         images,
+	}
+}
+
+// TextOption configures the styling of a plain text element.
+type TextOption func(text map[string]interface{})
+
+// WithTextLines clamps the text to at most n lines, truncating the rest.
+func WithTextLines(n int) TextOption {
+	return func(text map[string]interface{}) {
+		text["lines"] = n
+	}
+}
+
+// WithTextSize sets the text size, e.g. "heading", "normal" or "notation".
+func WithTextSize(size string) TextOption {
+	return func(text map[string]interface{}) {
+		text["text_size"] = size
+	}
+}
+
+// WithTextColor sets the text color, e.g. "default", "grey" or "red".
+func WithTextColor(color string) TextOption {
+	return func(text map[string]interface{}) {
+		text["text_color"] = color
+	}
+}
+
+// WithTextAlign sets the text alignment: "left", "center" or "right".
+func WithTextAlign(align string) TextOption {
+	return func(text map[string]interface{}) {
+		text["text_align"] = align
+	}
+}
+
+// NewPlainTextElement creates a plain text element, rendered as a div holding
+// plain_text content. Unlike markdown, plain text supports clamping to a
+// maximum number of lines.
+//
+// Example:
+//
+//	text := feishubot.NewPlainTextElement(stackTrace,
+//		feishubot.WithTextLines(5),
+//		feishubot.WithTextSize("notation"),
+//		feishubot.WithTextColor("grey"),
+//	)
+func NewPlainTextElement(content string, opts ...TextOption) CardElement {
+	text := map[string]interface{}{
+		"tag":     "plain_text",
+		"content": content,
+	}
+
+	for _, opt := range opts {
+		opt(text)
+	}
+
+	return MapElement{
+		"tag":  "div",
+		"text": text,
+	}
+}
@@ -0,0 +1,404 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		name string
+		div  *Div
+		want string
+	}{
+		{
+			name: "text only",
+			div:  NewDiv(NewCardTitle("Hello")),
+			want: `{"tag":"div","text":{"tag":"plain_text","content":"Hello"}}`,
+		},
+		{
+			name: "fields and extra",
+			div: NewDiv(nil).
+				AddField(NewCardMarkdownTitle("**Service**\napi"), true).
+				AddField(NewCardMarkdownTitle("**Note**\nrolling"), false).
+				SetExtra(NewButtonElement("Logs", "default", "https://example.com/logs")),
+			want: `{
+				"tag": "div",
+				"fields": [
+					{"is_short": true, "text": {"tag": "lark_md", "content": "**Service**\napi"}},
+					{"is_short": false, "text": {"tag": "lark_md", "content": "**Note**\nrolling"}}
+				],
+				"extra": {
+					"tag": "button",
+					"text": {"tag": "plain_text", "content": "Logs"},
+					"type": "default",
+					"url": "https://example.com/logs"
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.div.ToElement())
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestColumnSet(t *testing.T) {
+	tests := []struct {
+		name string
+		set  *ColumnSet
+		want string
+	}{
+		{
+			name: "weighted columns",
+			set: NewColumnSet(
+				NewColumn(1, NewMarkdownElement("CPU")),
+				NewColumn(2, NewMarkdownElement("Memory")).SetVerticalAlign("center"),
+			).SetHorizontalSpacing("8px").SetBackgroundStyle("grey").SetFlexMode("bisect"),
+			want: `{
+				"tag": "column_set",
+				"flex_mode": "bisect",
+				"horizontal_spacing": "8px",
+				"background_style": "grey",
+				"columns": [
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": [{"tag": "markdown", "content": "CPU"}]},
+					{"tag": "column", "width": "weighted", "weight": 2, "vertical_align": "center", "elements": [{"tag": "markdown", "content": "Memory"}]}
+				]
+			}`,
+		},
+		{
+			name: "auto and fixed width columns",
+			set: NewColumnSet(
+				NewColumn(0).SetBackgroundStyle("default"),
+				NewColumn(0, NewMarkdownElement("text")).SetWidth("100px"),
+			),
+			want: `{
+				"tag": "column_set",
+				"columns": [
+					{"tag": "column", "width": "auto", "background_style": "default", "elements": []},
+					{"tag": "column", "width": "100px", "elements": [{"tag": "markdown", "content": "text"}]}
+				]
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.set.ToElement())
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestNewActionElement(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  ActionLayout
+		actions []CardElement
+		want    string
+	}{
+		{
+			name:   "bisected buttons",
+			layout: ActionLayoutBisected,
+			actions: []CardElement{
+				NewButtonElement("Approve", "primary", "https://example.com/approve"),
+				NewButtonElement("Reject", "danger", "https://example.com/reject"),
+			},
+			want: `{
+				"tag": "action",
+				"layout": "bisected",
+				"actions": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Approve"}, "type": "primary", "url": "https://example.com/approve"},
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Reject"}, "type": "danger", "url": "https://example.com/reject"}
+				]
+			}`,
+		},
+		{
+			name: "no layout and no actions",
+			want: `{"tag": "action", "actions": []}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(NewActionElement(tt.layout, tt.actions...))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestButtonOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ButtonOption
+		want string
+	}{
+		{
+			name: "no options",
+			want: `{"tag":"button","text":{"tag":"plain_text","content":"Logs"},"type":"default","url":"https://example.com"}`,
+		},
+		{
+			name: "standard icon, size and width",
+			opts: []ButtonOption{
+				WithButtonIcon(NewStandardIcon("file-link-text_outlined", "blue")),
+				WithButtonSize(ButtonSizeSmall),
+				WithButtonWidth("fill"),
+			},
+			want: `{
+				"tag": "button",
+				"text": {"tag": "plain_text", "content": "Logs"},
+				"type": "default",
+				"url": "https://example.com",
+				"icon": {"tag": "standard_icon", "token": "file-link-text_outlined", "color": "blue"},
+				"size": "small",
+				"width": "fill"
+			}`,
+		},
+		{
+			name: "custom icon and disabled with tips",
+			opts: []ButtonOption{
+				WithButtonIcon(NewCustomIcon("img_v3_xxx")),
+				WithButtonDisabled("Already approved"),
+			},
+			want: `{
+				"tag": "button",
+				"text": {"tag": "plain_text", "content": "Logs"},
+				"type": "default",
+				"url": "https://example.com",
+				"icon": {"tag": "custom_icon", "img_key": "img_v3_xxx"},
+				"disabled": true,
+				"disabled_tips": {"tag": "plain_text", "content": "Already approved"}
+			}`,
+		},
+		{
+			name: "disabled without tips",
+			opts: []ButtonOption{WithButtonDisabled("")},
+			want: `{"tag":"button","text":{"tag":"plain_text","content":"Logs"},"type":"default","url":"https://example.com","disabled":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(NewButtonElement("Logs", "default", "https://example.com", tt.opts...))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestNewSelectPersonElement(t *testing.T) {
+	tests := []struct {
+		name    string
+		userIDs []string
+		opts    []ElementOption
+		want    string
+	}{
+		{
+			name: "all chat members",
+			want: `{"tag":"select_person","placeholder":{"tag":"plain_text","content":"Assignee"}}`,
+		},
+		{
+			name:    "restricted options with name",
+			userIDs: []string{"ou_1", "ou_2"},
+			opts:    []ElementOption{WithElementName("assignee"), WithElementRequired()},
+			want: `{
+				"tag": "select_person",
+				"placeholder": {"tag": "plain_text", "content": "Assignee"},
+				"options": [{"value": "ou_1"}, {"value": "ou_2"}],
+				"name": "assignee",
+				"required": true
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(NewSelectPersonElement("Assignee", tt.userIDs, tt.opts...))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestPickerElements(t *testing.T) {
+	tests := []struct {
+		name string
+		elem CardElement
+		want string
+	}{
+		{
+			name: "date picker",
+			elem: NewDatePickerElement("Release date", "2024-05-01", WithElementName("date")),
+			want: `{"tag":"date_picker","placeholder":{"tag":"plain_text","content":"Release date"},"initial_date":"2024-05-01","name":"date"}`,
+		},
+		{
+			name: "time picker without initial value",
+			elem: NewTimePickerElement("Start time", ""),
+			want: `{"tag":"picker_time","placeholder":{"tag":"plain_text","content":"Start time"}}`,
+		},
+		{
+			name: "date time picker",
+			elem: NewDateTimePickerElement("Maintenance window", "2024-05-01 22:00", WithElementRequired()),
+			want: `{"tag":"picker_datetime","placeholder":{"tag":"plain_text","content":"Maintenance window"},"initial_datetime":"2024-05-01 22:00","required":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.elem)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+
+			message := NewInteractiveMessage(NewCard("2.0").SetBody(&CardBody{Elements: []CardElement{tt.elem}}))
+			require.Len(t, message.WebhookWarnings(), 1)
+		})
+	}
+}
+
+func TestNewCheckerElement(t *testing.T) {
+	tests := []struct {
+		name    string
+		checked bool
+		opts    []ElementOption
+		want    string
+	}{
+		{
+			name: "unchecked",
+			want: `{"tag":"checker","text":{"tag":"plain_text","content":"Review PR"},"checked":false}`,
+		},
+		{
+			name:    "checked and overall checkable",
+			checked: true,
+			opts:    []ElementOption{WithOverallCheckable(true), WithElementName("review")},
+			want:    `{"tag":"checker","text":{"tag":"plain_text","content":"Review PR"},"checked":true,"overall_checkable":true,"name":"review"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(NewCheckerElement("Review PR", tt.checked, tt.opts...))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestFormElements(t *testing.T) {
+	form := NewFormElement("rollback",
+		NewInputElement("Reason",
+			WithElementName("reason"),
+			WithElementRequired(),
+			WithInputLabel("Reason"),
+			WithInputDefaultValue("bad deploy"),
+			WithInputType(InputTypeMultilineText),
+			WithInputMaxLength(200),
+		),
+		NewFormSubmitButton("Submit", "submit"),
+		NewFormResetButton("Reset", "reset", WithButtonSize(ButtonSizeSmall)),
+	)
+
+	data, err := json.Marshal(form)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"tag": "form",
+		"name": "rollback",
+		"elements": [
+			{
+				"tag": "input",
+				"placeholder": {"tag": "plain_text", "content": "Reason"},
+				"name": "reason",
+				"required": true,
+				"label": {"tag": "plain_text", "content": "Reason"},
+				"default_value": "bad deploy",
+				"input_type": "multiline_text",
+				"max_length": 200
+			},
+			{"tag": "button", "text": {"tag": "plain_text", "content": "Submit"}, "type": "primary", "action_type": "form_submit", "name": "submit"},
+			{"tag": "button", "text": {"tag": "plain_text", "content": "Reset"}, "type": "default", "action_type": "form_reset", "name": "reset", "size": "small"}
+		]
+	}`, string(data))
+
+	data, err = json.Marshal(NewFormElement("empty"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"tag":"form","name":"empty","elements":[]}`, string(data))
+}
+
+func TestFormWebhookWarnings(t *testing.T) {
+	message := NewInteractiveMessage(NewCard("2.0").SetBody(&CardBody{
+		Elements: []CardElement{
+			NewFormElement("form",
+				NewInputElement("Reason"),
+				NewDatePickerElement("Date", ""),
+			),
+			NewInputElement("Standalone"),
+		},
+	}))
+
+	warnings := message.WebhookWarnings()
+	require.Len(t, warnings, 2)
+	require.Contains(t, warnings[0], `"form" at body.elements[0]`)
+	require.Contains(t, warnings[1], `"input" at body.elements[1]`)
+}
+
+func TestNewImageCombinationElement(t *testing.T) {
+	data, err := json.Marshal(NewImageCombinationElement(ImageCombinationBisect, "img_1", "img_2"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"tag": "img_combination",
+		"combination_mode": "bisect",
+		"img_list": [{"img_key": "img_1"}, {"img_key": "img_2"}]
+	}`, string(data))
+
+	data, err = json.Marshal(NewImageCombinationElement(ImageCombinationTrisect))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"tag":"img_combination","combination_mode":"trisect","img_list":[]}`, string(data))
+}
+
+func TestNewPlainTextElement(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []TextOption
+		want string
+	}{
+		{
+			name: "no styling",
+			want: `{"tag":"div","text":{"tag":"plain_text","content":"hello"}}`,
+		},
+		{
+			name: "all styling",
+			opts: []TextOption{
+				WithTextLines(2),
+				WithTextSize("notation"),
+				WithTextColor("grey"),
+				WithTextAlign("center"),
+			},
+			want: `{
+				"tag": "div",
+				"text": {
+					"tag": "plain_text",
+					"content": "hello",
+					"lines": 2,
+					"text_size": "notation",
+					"text_color": "grey",
+					"text_align": "center"
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(NewPlainTextElement("hello", tt.opts...))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
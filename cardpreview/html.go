@@ -0,0 +1,243 @@
+package cardpreview
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// headerColors maps header templates to approximate background colors.
+var headerColors = map[string]string{
+	feishubot.TemplateBlue:      "#3370ff",
+	feishubot.TemplateWathet:    "#4fa8f5",
+	feishubot.TemplateTurquoise: "#00b2b2",
+	feishubot.TemplateGreen:     "#34c724",
+	feishubot.TemplateYellow:    "#ffc60a",
+	feishubot.TemplateOrange:    "#ff8800",
+	feishubot.TemplateRed:       "#f54a45",
+	feishubot.TemplateCarmine:   "#e0407a",
+	feishubot.TemplateViolet:    "#d136d1",
+	feishubot.TemplatePurple:    "#7f3bf5",
+	feishubot.TemplateIndigo:    "#4954e6",
+	feishubot.TemplateGrey:      "#8f959e",
+}
+
+const htmlStyle = `body{background:#f5f6f7;font-family:-apple-system,"PingFang SC",sans-serif;font-size:14px;color:#1f2329}
+.card{max-width:600px;margin:24px auto;background:#fff;border-radius:8px;box-shadow:0 2px 8px rgba(0,0,0,.1);overflow:hidden}
+.header{padding:12px 16px;background:#f2f3f5}
+.header.colored{color:#fff}
+.title{font-size:16px;font-weight:600}
+.subtitle{font-size:13px;opacity:.8}
+.tag{display:inline-block;margin-left:6px;padding:0 6px;border-radius:4px;font-size:12px;background:rgba(255,255,255,.3)}
+.body{padding:12px 16px}
+.body>*{margin:8px 0}
+.fields{display:flex;flex-wrap:wrap}
+.field{flex:1 0 100%}
+.field.short{flex:1 0 50%}
+.columns{display:flex;gap:8px}
+.column{flex:1}
+.button{display:inline-block;margin:0 8px 4px 0;padding:4px 12px;border:1px solid #d0d3d6;border-radius:6px;color:#1f2329;text-decoration:none}
+.button.primary{background:#3370ff;border-color:#3370ff;color:#fff}
+.button.danger{border-color:#f54a45;color:#f54a45}
+.placeholder{padding:8px;border:1px dashed #d0d3d6;border-radius:6px;color:#8f959e;text-align:center}
+table{border-collapse:collapse;width:100%}
+th,td{border:1px solid #dee0e3;padding:4px 8px;text-align:left}
+hr{border:none;border-top:1px solid #dee0e3}`
+
+// HTML renders an approximate preview of card as a standalone HTML page.
+// Links are kept only if they use the http, https or lark scheme.
+func HTML(card *feishubot.Card) (string, error) {
+	doc, err := decodeCard(card)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Card preview</title>\n<style>\n")
+	b.WriteString(htmlStyle)
+	b.WriteString("\n</style>\n</head>\n<body>\n<div class=\"card\">\n")
+
+	if header, ok := doc["header"].(map[string]interface{}); ok {
+		writeHTMLHeader(&b, header)
+	}
+
+	b.WriteString("<div class=\"body\">\n")
+	for _, e := range cardElements(doc) {
+		if elem, ok := e.(map[string]interface{}); ok {
+			writeHTMLElement(&b, elem)
+		}
+	}
+	b.WriteString("</div>\n</div>\n</body>\n</html>\n")
+
+	return b.String(), nil
+}
+
+// writeHTMLHeader renders the card header.
+func writeHTMLHeader(b *strings.Builder, header map[string]interface{}) {
+	class, style := "header", ""
+	if color, ok := headerColors[stringField(header, "template")]; ok {
+		class += " colored"
+		style = fmt.Sprintf(" style=\"background:%s\"", color)
+	}
+
+	fmt.Fprintf(b, "<div class=\"%s\"%s>\n<div class=\"title\">%s", class, style, html.EscapeString(textContent(header["title"])))
+	for _, tag := range children(header, "text_tag_list") {
+		fmt.Fprintf(b, "<span class=\"tag\">%s</span>", html.EscapeString(textContent(tag["text"])))
+	}
+	b.WriteString("</div>\n")
+	if subtitle := textContent(header["subtitle"]); subtitle != "" {
+		fmt.Fprintf(b, "<div class=\"subtitle\">%s</div>\n", html.EscapeString(subtitle))
+	}
+	b.WriteString("</div>\n")
+}
+
+// writeHTMLElement renders a single element.
+func writeHTMLElement(b *strings.Builder, elem map[string]interface{}) {
+	switch tag := stringField(elem, "tag"); tag {
+	case "markdown", "lark_md":
+		fmt.Fprintf(b, "<div class=\"markdown\">%s</div>\n", markdownToHTML(stringField(elem, "content")))
+
+	case "div":
+		b.WriteString("<div class=\"div\">\n")
+		if text, ok := elem["text"].(map[string]interface{}); ok {
+			fmt.Fprintf(b, "<div>%s</div>\n", textToHTML(text))
+		}
+		if fields := children(elem, "fields"); len(fields) > 0 {
+			b.WriteString("<div class=\"fields\">\n")
+			for _, field := range fields {
+				class := "field"
+				if short, _ := field["is_short"].(bool); short {
+					class += " short"
+				}
+				text, _ := field["text"].(map[string]interface{})
+				fmt.Fprintf(b, "<div class=\"%s\">%s</div>\n", class, textToHTML(text))
+			}
+			b.WriteString("</div>\n")
+		}
+		if extra, ok := elem["extra"].(map[string]interface{}); ok {
+			writeHTMLElement(b, extra)
+		}
+		b.WriteString("</div>\n")
+
+	case "hr":
+		b.WriteString("<hr>\n")
+
+	case "column_set":
+		b.WriteString("<div class=\"columns\">\n")
+		for _, column := range children(elem, "columns") {
+			b.WriteString("<div class=\"column\">\n")
+			for _, child := range children(column, "elements") {
+				writeHTMLElement(b, child)
+			}
+			b.WriteString("</div>\n")
+		}
+		b.WriteString("</div>\n")
+
+	case "action":
+		b.WriteString("<div class=\"action\">\n")
+		for _, action := range children(elem, "actions") {
+			writeHTMLElement(b, action)
+		}
+		b.WriteString("</div>\n")
+
+	case "button":
+		fmt.Fprintf(b, "<a class=\"button %s\" href=\"%s\">%s</a>\n",
+			html.EscapeString(stringField(elem, "type")), html.EscapeString(safeURL(stringField(elem, "url"))),
+			html.EscapeString(textContent(elem["text"])))
+
+	case "collapsible_panel", "form":
+		title := stringField(elem, "name")
+		if header, ok := elem["header"].(map[string]interface{}); ok {
+			title = textContent(header["title"])
+		}
+		open := ""
+		if expanded, _ := elem["expanded"].(bool); expanded || tag == "form" {
+			open = " open"
+		}
+		fmt.Fprintf(b, "<details%s>\n<summary>%s</summary>\n", open, markdownToHTML(title))
+		for _, child := range children(elem, "elements") {
+			writeHTMLElement(b, child)
+		}
+		b.WriteString("</details>\n")
+
+	case "table":
+		writeHTMLTable(b, elem)
+
+	default:
+		fmt.Fprintf(b, "<div class=\"placeholder\">[%s]</div>\n", html.EscapeString(placeholderLabel(elem)))
+	}
+}
+
+// writeHTMLTable renders a table element.
+func writeHTMLTable(b *strings.Builder, elem map[string]interface{}) {
+	columns := children(elem, "columns")
+
+	b.WriteString("<table>\n<tr>")
+	for _, column := range columns {
+		fmt.Fprintf(b, "<th>%s</th>", html.EscapeString(columnName(column)))
+	}
+	b.WriteString("</tr>\n")
+
+	for _, row := range children(elem, "rows") {
+		b.WriteString("<tr>")
+		for _, column := range columns {
+			fmt.Fprintf(b, "<td>%s</td>", html.EscapeString(cellText(row[stringField(column, "name")])))
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>\n")
+}
+
+// safeSchemes are the URL schemes of links in previews; links with other
+// schemes, such as javascript, are not followed.
+var safeSchemes = map[string]bool{
+	"http":  true,
+	"https": true,
+	"lark":  true,
+}
+
+// safeURL returns rawURL if it has one of the safeSchemes, "#" otherwise.
+func safeURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || !safeSchemes[strings.ToLower(u.Scheme)] {
+		return "#"
+	}
+	return rawURL
+}
+
+// textToHTML renders a text object, interpreting markdown content.
+func textToHTML(text map[string]interface{}) string {
+	content := textContent(text)
+	if tag := stringField(text, "tag"); tag == "lark_md" || tag == "markdown" {
+		return markdownToHTML(content)
+	}
+	return strings.ReplaceAll(html.EscapeString(content), "\n", "<br>")
+}
+
+var (
+	markdownLink   = regexp.MustCompile(`\[([^\]]*)\]\(([^)]*)\)`)
+	markdownBold   = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	markdownItalic = regexp.MustCompile(`\*(.+?)\*`)
+	markdownStrike = regexp.MustCompile(`~~(.+?)~~`)
+	markdownCode   = regexp.MustCompile("`([^`]+)`")
+)
+
+// markdownToHTML converts the markdown subset supported by Feishu cards to
+// HTML: bold, italic, strikethrough, inline code, links and line breaks.
+func markdownToHTML(s string) string {
+	s = html.EscapeString(s)
+	s = markdownCode.ReplaceAllString(s, "<code>$1</code>")
+	s = markdownLink.ReplaceAllStringFunc(s, func(link string) string {
+		m := markdownLink.FindStringSubmatch(link)
+		href := html.EscapeString(safeURL(html.UnescapeString(m[2])))
+		return `<a href="` + href + `">` + m[1] + `</a>`
+	})
+	s = markdownBold.ReplaceAllString(s, "<b>$1</b>")
+	s = markdownItalic.ReplaceAllString(s, "<i>$1</i>")
+	s = markdownStrike.ReplaceAllString(s, "<s>$1</s>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
@@ -0,0 +1,68 @@
+package cardpreview
+
+import (
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTML(t *testing.T) {
+	got, err := HTML(testCard())
+	require.NoError(t, err)
+
+	for _, want := range []string{
+		`<div class="header colored" style="background:#34c724">`,
+		`<div class="title">Deploy &lt;prod&gt;<span class="tag">P1</span></div>`,
+		`<div class="subtitle">v1.4.2</div>`,
+		`<b>Done</b> in <code>3m</code>, see <a href="https://example.com/logs">logs</a>`,
+		`<div class="field short"><b>CPU</b><br>42%</div>`,
+		`<hr>`,
+		`<div class="columns">`,
+		`<a class="button primary" href="https://example.com">Open</a>`,
+		"<details>\n<summary>More</summary>",
+		`<tr><th>Service</th><th>replicas</th></tr>`,
+		`<tr><td>api</td><td>3</td></tr>`,
+		`<div class="placeholder">[date_picker: Pick a date]</div>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML() missing %q\ngot: %s", want, got)
+		}
+	}
+}
+
+func TestHTMLUnsafeButtonURL(t *testing.T) {
+	card := feishubot.NewCard("2.0").SetBody(&feishubot.CardBody{Elements: []feishubot.CardElement{
+		feishubot.NewButtonElement("Open", "primary", "javascript:alert(1)"),
+		feishubot.NewButtonElement("Chat", "default", "lark://applink.feishu.cn/client/chat/open"),
+	}})
+
+	got, err := HTML(card)
+	require.NoError(t, err)
+	require.Contains(t, got, `<a class="button primary" href="#">Open</a>`)
+	require.Contains(t, got, `<a class="button default" href="lark://applink.feishu.cn/client/chat/open">Chat</a>`)
+	require.NotContains(t, got, "javascript")
+}
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "escapes html", s: "<b>&", want: "&lt;b&gt;&amp;"},
+		{name: "emphasis", s: "**bold** *italic* ~~gone~~", want: "<b>bold</b> <i>italic</i> <s>gone</s>"},
+		{name: "line breaks", s: "a\nb", want: "a<br>b"},
+		{name: "link", s: "[logs](https://example.com/?a=1&b=2)", want: `<a href="https://example.com/?a=1&amp;b=2">logs</a>`},
+		{name: "unsafe link", s: "[x](javascript:alert(1)) [y](JavaScript:alert)", want: `<a href="#">x</a>) <a href="#">y</a>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownToHTML(tt.s); got != tt.want {
+				t.Errorf("markdownToHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Package cardpreview renders approximate local previews of Feishu cards, so
+// that card layouts can be iterated on without sending test messages to a
+// real chat.
+//
+// The previews only approximate the Feishu client: common elements such as
+// markdown, divs, column sets, buttons and tables are rendered with their
+// layout, while other elements are shown as labeled placeholders.
+//
+// Example:
+//
+//	html, err := cardpreview.HTML(card)
+//	if err != nil {
+//		return err
+//	}
+//	os.WriteFile("preview.html", []byte(html), 0o644)
+package cardpreview
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// decodeCard converts card into its generic JSON form.
+func decodeCard(card *feishubot.Card) (map[string]interface{}, error) {
+	data, err := json.Marshal(card)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal card: %w", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal card: %w", err)
+	}
+	return doc, nil
+}
+
+// cardElements returns the top-level elements of a card, in the schema 2.0
+// body or at the top level as in schema 1.0.
+func cardElements(doc map[string]interface{}) []interface{} {
+	if body, ok := doc["body"].(map[string]interface{}); ok {
+		elements, _ := body["elements"].([]interface{})
+		return elements
+	}
+	elements, _ := doc["elements"].([]interface{})
+	return elements
+}
+
+// textContent returns the content of a text object such as a CardTitle.
+func textContent(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		content, _ := v["content"].(string)
+		return content
+	}
+	return ""
+}
+
+// children returns the elements nested in an element under key.
+func children(elem map[string]interface{}, key string) []map[string]interface{} {
+	items, _ := elem[key].([]interface{})
+	result := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// stringField returns a string field of an element.
+func stringField(elem map[string]interface{}, key string) string {
+	s, _ := elem[key].(string)
+	return s
+}
+
+// cellText returns the display text of a table cell value.
+func cellText(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		texts := make([]string, 0, len(v))
+		for _, item := range v {
+			texts = append(texts, cellText(item))
+		}
+		return strings.Join(texts, ", ")
+	case map[string]interface{}:
+		if text, ok := v["text"].(string); ok {
+			return text
+		}
+		return textContent(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// columnName returns the display name of a table column.
+func columnName(column map[string]interface{}) string {
+	if name := stringField(column, "display_name"); name != "" {
+		return name
+	}
+	return stringField(column, "name")
+}
+
+// placeholderLabel returns the label of elements that previews do not
+// render, made of the tag and the element's placeholder or text.
+func placeholderLabel(elem map[string]interface{}) string {
+	label := stringField(elem, "tag")
+	if placeholder := textContent(elem["placeholder"]); placeholder != "" {
+		return label + ": " + placeholder
+	}
+	if text := textContent(elem["text"]); text != "" {
+		return label + ": " + text
+	}
+	return label
+}
@@ -0,0 +1,61 @@
+package cardpreview
+
+import (
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// testCard returns a card using the elements rendered by the previews.
+func testCard() *feishubot.Card {
+	table := feishubot.NewTable(
+		feishubot.NewTableColumn("service", "Service", feishubot.TableDataText),
+		feishubot.NewTableColumn("replicas", "", feishubot.TableDataNumber),
+	).AddRow(map[string]any{"service": "api", "replicas": 3})
+
+	return feishubot.NewCard("2.0").
+		SetHeader(&feishubot.CardHeader{
+			Title:       feishubot.NewCardTitle("Deploy <prod>"),
+			Subtitle:    feishubot.NewCardTitle("v1.4.2"),
+			Template:    feishubot.TemplateGreen,
+			TextTagList: []feishubot.TextTag{feishubot.NewTextTag("P1", "red")},
+		}).
+		SetBody(&feishubot.CardBody{Elements: []feishubot.CardElement{
+			feishubot.NewMarkdownElement("**Done** in `3m`, see [logs](https://example.com/logs)"),
+			feishubot.NewDiv(feishubot.NewCardTitle("summary")).
+				AddField(feishubot.NewCardMarkdownTitle("**CPU**\n42%"), true),
+			feishubot.NewHrCardElement(),
+			feishubot.NewColumnSet(
+				feishubot.NewColumn(1, feishubot.NewMarkdownElement("left")),
+				feishubot.NewColumn(1, feishubot.NewMarkdownElement("right")),
+			),
+			feishubot.NewActionElement(feishubot.ActionLayoutDefault,
+				feishubot.NewButtonElement("Open", "primary", "https://example.com"),
+			),
+			feishubot.NewCollapsiblePanelElement("More", false, feishubot.NewMarkdownElement("hidden")),
+			table.ToElement(),
+			feishubot.NewDatePickerElement("Pick a date", ""),
+		}})
+}
+
+func TestCellText(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "nil", value: nil, want: ""},
+		{name: "string", value: "api", want: "api"},
+		{name: "number", value: float64(3), want: "3"},
+		{name: "options", value: []any{map[string]any{"text": "ok"}, map[string]any{"text": "slow"}}, want: "ok, slow"},
+		{name: "persons", value: []any{"ou_1", "ou_2"}, want: "ou_1, ou_2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cellText(tt.value); got != tt.want {
+				t.Errorf("cellText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
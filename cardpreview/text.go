@@ -0,0 +1,123 @@
+package cardpreview
+
+import (
+	"fmt"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// textRule is the divider line of text previews.
+var textRule = strings.Repeat("─", 40)
+
+// Text renders an approximate plain text preview of card for terminals,
+// keeping markdown as-is and showing buttons as "[ Text ]".
+func Text(card *feishubot.Card) (string, error) {
+	doc, err := decodeCard(card)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if header, ok := doc["header"].(map[string]interface{}); ok {
+		b.WriteString(textContent(header["title"]))
+		for _, tag := range children(header, "text_tag_list") {
+			fmt.Fprintf(&b, " [%s]", textContent(tag["text"]))
+		}
+		b.WriteString("\n")
+		if subtitle := textContent(header["subtitle"]); subtitle != "" {
+			b.WriteString(subtitle + "\n")
+		}
+		b.WriteString(strings.Repeat("═", 40) + "\n")
+	}
+
+	for _, e := range cardElements(doc) {
+		if elem, ok := e.(map[string]interface{}); ok {
+			writeTextElement(&b, elem, "")
+		}
+	}
+
+	return b.String(), nil
+}
+
+// writeTextElement renders a single element, prefixing every line with
+// indent.
+func writeTextElement(b *strings.Builder, elem map[string]interface{}, indent string) {
+	line := func(s string) {
+		for _, l := range strings.Split(s, "\n") {
+			b.WriteString(indent + l + "\n")
+		}
+	}
+
+	switch tag := stringField(elem, "tag"); tag {
+	case "markdown", "lark_md":
+		line(stringField(elem, "content"))
+
+	case "div":
+		if text := textContent(elem["text"]); text != "" {
+			line(text)
+		}
+		for _, field := range children(elem, "fields") {
+			line(textContent(field["text"]))
+		}
+		if extra, ok := elem["extra"].(map[string]interface{}); ok {
+			writeTextElement(b, extra, indent)
+		}
+
+	case "hr":
+		line(textRule)
+
+	case "column_set":
+		for i, column := range children(elem, "columns") {
+			if i > 0 {
+				line("│")
+			}
+			for _, child := range children(column, "elements") {
+				writeTextElement(b, child, indent+"│ ")
+			}
+		}
+
+	case "action":
+		buttons := make([]string, 0)
+		for _, action := range children(elem, "actions") {
+			buttons = append(buttons, fmt.Sprintf("[ %s ]", textContent(action["text"])))
+		}
+		line(strings.Join(buttons, " "))
+
+	case "button":
+		line(fmt.Sprintf("[ %s ]", textContent(elem["text"])))
+
+	case "collapsible_panel", "form":
+		title := stringField(elem, "name")
+		if header, ok := elem["header"].(map[string]interface{}); ok {
+			title = textContent(header["title"])
+		}
+		expanded, _ := elem["expanded"].(bool)
+		if !expanded && tag != "form" {
+			line("▶ " + title)
+			return
+		}
+		line("▼ " + title)
+		for _, child := range children(elem, "elements") {
+			writeTextElement(b, child, indent+"  ")
+		}
+
+	case "table":
+		columns := children(elem, "columns")
+		names := make([]string, 0, len(columns))
+		for _, column := range columns {
+			names = append(names, columnName(column))
+		}
+		line(strings.Join(names, " | "))
+		for _, row := range children(elem, "rows") {
+			cells := make([]string, 0, len(columns))
+			for _, column := range columns {
+				cells = append(cells, cellText(row[stringField(column, "name")]))
+			}
+			line(strings.Join(cells, " | "))
+		}
+
+	default:
+		line(fmt.Sprintf("<%s>", placeholderLabel(elem)))
+	}
+}
@@ -0,0 +1,31 @@
+package cardpreview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestText(t *testing.T) {
+	got, err := Text(testCard())
+	require.NoError(t, err)
+
+	want := `Deploy <prod> [P1]
+v1.4.2
+════════════════════════════════════════
+**Done** in ` + "`3m`" + `, see [logs](https://example.com/logs)
+summary
+**CPU**
+42%
+────────────────────────────────────────
+│ left
+│
+│ right
+[ Open ]
+▶ More
+Service | replicas
+api | 3
+<date_picker: Pick a date>
+`
+	require.Equal(t, want, got)
+}
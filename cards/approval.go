@@ -0,0 +1,91 @@
+package cards
+
+import (
+	"fmt"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// ApprovalRequest describes a request shown by Approval.
+type ApprovalRequest struct {
+	// Item is what needs approval, e.g. "Deploy api-gateway v1.4.2 to prod".
+	Item string
+	// Requester is the display name of the requester. If RequesterID (an
+	// open ID) is set, the requester is @ mentioned instead.
+	Requester   string
+	RequesterID string
+	// Details are shown as a table of label/value rows.
+	Details []feishubot.KV
+	// ApproveURL and RejectURL are opened by the Approve and Reject buttons.
+	ApproveURL string
+	RejectURL  string
+	// ExpiresAt adds a note when the request expires, if not zero.
+	ExpiresAt time.Time
+}
+
+// Approval creates an approval request card with the requester, the item,
+// a details table, Approve/Reject buttons and an expiry note.
+//
+// The buttons open URLs, so the approval itself is handled by the linked
+// service; this works with custom bot webhooks, which cannot receive
+// interaction callbacks.
+//
+// Example:
+//
+//	card := cards.Approval(cards.ApprovalRequest{
+//		Item:        "Deploy api-gateway v1.4.2 to prod",
+//		RequesterID: "ou_xxx",
+//		Details: []feishubot.KV{
+//			{Label: "Change", Value: "#456", URL: "https://git.example.com/pr/456"},
+//			{Label: "Window", Value: "18:00-19:00"},
+//		},
+//		ApproveURL: "https://deploy.example.com/approve/789",
+//		RejectURL:  "https://deploy.example.com/reject/789",
+//		ExpiresAt:  time.Now().Add(2 * time.Hour),
+//	})
+func Approval(req ApprovalRequest) *feishubot.Card {
+	requester := req.Requester
+	if req.RequesterID != "" {
+		requester = fmt.Sprintf("<at id=%s></at>", req.RequesterID)
+	}
+
+	builder := feishubot.NewCardBuilder().
+		Header("📝 Approval request", feishubot.TemplateOrange).
+		Markdown(fmt.Sprintf("**%s**\nRequested by %s", req.Item, requester))
+
+	if len(req.Details) > 0 {
+		table := feishubot.NewTable(
+			feishubot.NewTableColumn("label", "Field", feishubot.TableDataText),
+			feishubot.NewTableColumn("value", "Value", feishubot.TableDataLarkMd),
+		)
+		for _, kv := range req.Details {
+			value := kv.Value
+			if kv.URL != "" {
+				value = fmt.Sprintf("[%s](%s)", kv.Value, kv.URL)
+			}
+			table.AddRow(map[string]interface{}{"label": kv.Label, "value": value})
+		}
+		builder.Element(table.ToElement())
+	}
+
+	var buttons []feishubot.CardElement
+	if req.ApproveURL != "" {
+		buttons = append(buttons, feishubot.NewButtonElement("Approve", "primary", req.ApproveURL))
+	}
+	if req.RejectURL != "" {
+		buttons = append(buttons, feishubot.NewButtonElement("Reject", "danger", req.RejectURL))
+	}
+	if len(buttons) > 0 {
+		builder.Actions(buttons...)
+	}
+
+	if !req.ExpiresAt.IsZero() {
+		builder.Element(&feishubot.MarkdownElement{
+			Content:  "⏰ Expires " + req.ExpiresAt.Format("2006-01-02 15:04 -07:00"),
+			TextSize: "notation",
+		})
+	}
+
+	return builder.Build()
+}
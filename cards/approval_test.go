@@ -0,0 +1,76 @@
+package cards
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproval(t *testing.T) {
+	tests := []struct {
+		name string
+		req  ApprovalRequest
+		want string
+	}{
+		{
+			name: "full request",
+			req: ApprovalRequest{
+				Item:        "Deploy api to prod",
+				Requester:   "Alice",
+				RequesterID: "ou_xxx",
+				Details: []feishubot.KV{
+					{Label: "Change", Value: "#456", URL: "https://git.example.com/pr/456"},
+					{Label: "Window", Value: "18:00-19:00"},
+				},
+				ApproveURL: "https://deploy.example.com/approve",
+				RejectURL:  "https://deploy.example.com/reject",
+				ExpiresAt:  time.Date(2024, 5, 1, 18, 0, 0, 0, time.FixedZone("CST", 8*3600)),
+			},
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "📝 Approval request"}, "template": "orange"},
+				"body": {"elements": [
+					{"tag": "markdown", "content": "**Deploy api to prod**\nRequested by <at id=ou_xxx></at>"},
+					{"tag": "table", "columns": [
+						{"name": "label", "display_name": "Field", "data_type": "text"},
+						{"name": "value", "display_name": "Value", "data_type": "lark_md"}
+					], "rows": [
+						{"label": "Change", "value": "[#456](https://git.example.com/pr/456)"},
+						{"label": "Window", "value": "18:00-19:00"}
+					]},
+					{"tag": "column_set", "columns": [
+						{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+							{"tag": "button", "text": {"tag": "plain_text", "content": "Approve"}, "type": "primary", "url": "https://deploy.example.com/approve", "width": "fill"}
+						]},
+						{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+							{"tag": "button", "text": {"tag": "plain_text", "content": "Reject"}, "type": "danger", "url": "https://deploy.example.com/reject", "width": "fill"}
+						]}
+					]},
+					{"tag": "markdown", "content": "⏰ Expires 2024-05-01 18:00 +08:00", "text_size": "notation"}
+				]}
+			}`,
+		},
+		{
+			name: "minimal request",
+			req:  ApprovalRequest{Item: "Rotate keys", Requester: "Bob"},
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "📝 Approval request"}, "template": "orange"},
+				"body": {"elements": [
+					{"tag": "markdown", "content": "**Rotate keys**\nRequested by Bob"}
+				]}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(Approval(tt.req))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
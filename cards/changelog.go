@@ -0,0 +1,235 @@
+package cards
+
+import (
+	"fmt"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+const (
+	// defaultCollapseAfter is the number of items above which a changelog
+	// section starts collapsed.
+	defaultCollapseAfter = 10
+
+	// diffCollapseLines is the number of diff lines above which a file
+	// starts collapsed.
+	diffCollapseLines = 20
+
+	// maxDiffBytes limits the diff shown per file so that the code block
+	// stays within MaxMarkdownBytes.
+	maxDiffBytes = feishubot.MaxMarkdownBytes - 64
+)
+
+// Changes describes a config change or release shown by Changelog.
+type Changes struct {
+	Title string
+	// Summary is shown above the changes. It may contain markdown.
+	Summary string
+	// Added, Removed and Changed items may contain markdown.
+	Added   []string
+	Removed []string
+	Changed []string
+	// CollapseAfter collapses sections with more items than this. Zero
+	// uses 10; a negative value never collapses.
+	CollapseAfter int
+}
+
+// expanded reports whether a section of n items or lines starts expanded.
+func (c Changes) expanded(n int) bool {
+	switch {
+	case c.CollapseAfter < 0:
+		return true
+	case c.CollapseAfter == 0:
+		return n <= defaultCollapseAfter
+	}
+	return n <= c.CollapseAfter
+}
+
+// Changelog creates a card for config-change and release-notes
+// notifications, listing added, removed and changed items in green, red and
+// orange sections. Sections with many items start collapsed.
+//
+// Example:
+//
+//	card := cards.Changelog(cards.Changes{
+//		Title:   "Release v1.5.0",
+//		Added:   []string{"Dark mode", "CSV export"},
+//		Removed: []string{"Legacy API v1"},
+//		Changed: []string{"Faster search"},
+//	})
+func Changelog(changes Changes) *feishubot.Card {
+	builder := feishubot.NewCardBuilder().Header("📝 "+changes.Title, feishubot.TemplateBlue)
+	if changes.Summary != "" {
+		builder.Markdown(changes.Summary)
+	}
+
+	sections := []struct {
+		label string
+		color string
+		items []string
+	}{
+		{"Added", "green", changes.Added},
+		{"Removed", "red", changes.Removed},
+		{"Changed", "orange", changes.Changed},
+	}
+	empty := true
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+		empty = false
+
+		title := fmt.Sprintf("<font color='%s'>**%s (%d)**</font>", section.color, section.label, len(section.items))
+		list := "- " + strings.Join(section.items, "\n- ")
+		builder.Element(feishubot.NewCollapsiblePanelElement(title, changes.expanded(len(section.items)),
+			feishubot.NewMarkdownElement(list),
+		))
+	}
+	if empty && changes.Summary == "" {
+		builder.Markdown("No changes.")
+	}
+
+	return builder.Build()
+}
+
+// Diff creates a card showing a unified diff, such as the output of
+// "git diff", with one section per file. Each section shows the file's added
+// and removed line counts and its hunks as a highlighted diff code block;
+// files with more than 20 diff lines start collapsed.
+//
+// Example:
+//
+//	out, _ := exec.Command("git", "diff", "HEAD~1", "--", "config/").Output()
+//	card := cards.Diff("Config change", string(out))
+func Diff(title, diff string) *feishubot.Card {
+	files := parseUnifiedDiff(diff)
+
+	builder := feishubot.NewCardBuilder().Header("📝 "+title, feishubot.TemplateBlue)
+	if len(files) == 0 {
+		return builder.Markdown("No changes.").Build()
+	}
+
+	added, removed := 0, 0
+	for _, file := range files {
+		added += file.added
+		removed += file.removed
+	}
+	builder.Markdown(fmt.Sprintf("**%d %s changed** %s",
+		len(files), plural(len(files), "file", "files"), diffStat(added, removed)))
+
+	for _, file := range files {
+		title := diffStat(file.added, file.removed)
+		if file.name != "" {
+			title = fmt.Sprintf("`%s` %s", file.name, title)
+		}
+
+		var elements []feishubot.CardElement
+		if len(file.lines) > 0 {
+			code := feishubot.TruncateText(strings.Join(file.lines, "\n"), maxDiffBytes)
+			elements = append(elements, feishubot.NewMarkdownElement("```diff\n"+code+"\n```"))
+		}
+		builder.Element(feishubot.NewCollapsiblePanelElement(title, len(file.lines) <= diffCollapseLines, elements...))
+	}
+
+	return builder.Build()
+}
+
+// diffStat formats added and removed line counts in green and red.
+func diffStat(added, removed int) string {
+	return fmt.Sprintf("<font color='green'>+%d</font> <font color='red'>-%d</font>", added, removed)
+}
+
+// plural returns singular if n is one, plural otherwise.
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// diffFile is the part of a unified diff for a single file.
+type diffFile struct {
+	name    string
+	lines   []string
+	added   int
+	removed int
+}
+
+// parseUnifiedDiff splits a unified diff into files. Hunks without file
+// headers are collected in a single unnamed file; extended headers such as
+// "index" lines are skipped.
+func parseUnifiedDiff(diff string) []*diffFile {
+	var (
+		files  []*diffFile
+		file   *diffFile
+		inHunk bool
+	)
+	newFile := func(name string) {
+		file = &diffFile{name: name}
+		files = append(files, file)
+		inHunk = false
+	}
+
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSuffix(lines[i], "\r")
+
+		if strings.HasPrefix(line, "diff --git ") {
+			name := ""
+			if j := strings.LastIndex(line, " b/"); j >= 0 {
+				name = line[j+3:]
+			}
+			newFile(name)
+			continue
+		}
+
+		if strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			name := diffPath(lines[i+1][4:])
+			if name == "/dev/null" {
+				name = diffPath(line[4:])
+			}
+			if file == nil || inHunk {
+				newFile(name)
+			} else {
+				file.name = name
+			}
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(line, "@@") {
+			inHunk = true
+		}
+		if !inHunk {
+			continue
+		}
+
+		if file == nil {
+			newFile("")
+			inHunk = true
+		}
+		file.lines = append(file.lines, line)
+		switch {
+		case strings.HasPrefix(line, "+"):
+			file.added++
+		case strings.HasPrefix(line, "-"):
+			file.removed++
+		}
+	}
+
+	return files
+}
+
+// diffPath returns the path of a "---" or "+++" header, without timestamp
+// and "a/" or "b/" prefix.
+func diffPath(header string) string {
+	if i := strings.IndexByte(header, '\t'); i >= 0 {
+		header = header[:i]
+	}
+	header = strings.TrimSpace(header)
+	if strings.HasPrefix(header, "a/") || strings.HasPrefix(header, "b/") {
+		return header[2:]
+	}
+	return header
+}
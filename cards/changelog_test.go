@@ -0,0 +1,157 @@
+package cards
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelog(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes Changes
+		want    string
+	}{
+		{
+			name: "all sections",
+			changes: Changes{
+				Title:         "Release v1.5.0",
+				Summary:       "Shipped on Monday",
+				Added:         []string{"Dark mode", "CSV export"},
+				Removed:       []string{"Legacy API v1"},
+				Changed:       []string{"a", "b", "c"},
+				CollapseAfter: 2,
+			},
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "📝 Release v1.5.0"}, "template": "blue"},
+				"body": {"elements": [
+					{"tag": "markdown", "content": "Shipped on Monday"},
+					{"tag": "collapsible_panel", "expanded": true,
+						"header": {"title": {"tag": "markdown", "content": "<font color='green'>**Added (2)**</font>"}},
+						"elements": [{"tag": "markdown", "content": "- Dark mode\n- CSV export"}]},
+					{"tag": "collapsible_panel", "expanded": true,
+						"header": {"title": {"tag": "markdown", "content": "<font color='red'>**Removed (1)**</font>"}},
+						"elements": [{"tag": "markdown", "content": "- Legacy API v1"}]},
+					{"tag": "collapsible_panel", "expanded": false,
+						"header": {"title": {"tag": "markdown", "content": "<font color='orange'>**Changed (3)**</font>"}},
+						"elements": [{"tag": "markdown", "content": "- a\n- b\n- c"}]}
+				]}
+			}`,
+		},
+		{
+			name:    "no changes",
+			changes: Changes{Title: "Config"},
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "📝 Config"}, "template": "blue"},
+				"body": {"elements": [{"tag": "markdown", "content": "No changes."}]}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(Changelog(tt.changes))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestChangesExpanded(t *testing.T) {
+	require.True(t, Changes{}.expanded(10))
+	require.False(t, Changes{}.expanded(11))
+	require.True(t, Changes{CollapseAfter: -1}.expanded(1000))
+	require.False(t, Changes{CollapseAfter: 1}.expanded(2))
+}
+
+func TestDiff(t *testing.T) {
+	diff := `diff --git a/config/app.yaml b/config/app.yaml
+index 1234567..89abcde 100644
+--- a/config/app.yaml
++++ b/config/app.yaml
+@@ -1,3 +1,3 @@
+ name: api
+-replicas: 2
++replicas: 3
+ port: 8080
+diff --git a/config/old.yaml b/config/old.yaml
+deleted file mode 100644
+--- a/config/old.yaml
++++ /dev/null
+@@ -1 +0,0 @@
+-legacy: true
+`
+
+	data, err := json.Marshal(Diff("Config change", diff))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {"title": {"tag": "plain_text", "content": "📝 Config change"}, "template": "blue"},
+		"body": {"elements": [
+			{"tag": "markdown", "content": "**2 files changed** <font color='green'>+1</font> <font color='red'>-2</font>"},
+			{"tag": "collapsible_panel", "expanded": true,
+				"header": {"title": {"tag": "markdown", "content": "`+"`config/app.yaml`"+` <font color='green'>+1</font> <font color='red'>-1</font>"}},
+				"elements": [{"tag": "markdown", "content": "`+"```"+`diff\n@@ -1,3 +1,3 @@\n name: api\n-replicas: 2\n+replicas: 3\n port: 8080\n`+"```"+`"}]},
+			{"tag": "collapsible_panel", "expanded": true,
+				"header": {"title": {"tag": "markdown", "content": "`+"`config/old.yaml`"+` <font color='green'>+0</font> <font color='red'>-1</font>"}},
+				"elements": [{"tag": "markdown", "content": "`+"```"+`diff\n@@ -1 +0,0 @@\n-legacy: true\n`+"```"+`"}]}
+		]}
+	}`, string(data))
+}
+
+func TestParseUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want []*diffFile
+	}{
+		{
+			name: "plain diff with timestamps",
+			diff: "--- a.txt\t2024-05-01 10:00:00\n+++ a.txt\t2024-05-01 11:00:00\n@@ -1 +1 @@\n-x\n+y\n" +
+				"--- b.txt\n+++ b.txt\n@@ -0,0 +1 @@\n+z\n",
+			want: []*diffFile{
+				{name: "a.txt", lines: []string{"@@ -1 +1 @@", "-x", "+y"}, added: 1, removed: 1},
+				{name: "b.txt", lines: []string{"@@ -0,0 +1 @@", "+z"}, added: 1},
+			},
+		},
+		{
+			name: "hunks only",
+			diff: "@@ -1 +1 @@\r\n-x\r\n+y\r\n",
+			want: []*diffFile{
+				{lines: []string{"@@ -1 +1 @@", "-x", "+y"}, added: 1, removed: 1},
+			},
+		},
+		{
+			name: "binary file",
+			diff: "diff --git a/logo.png b/logo.png\nBinary files differ\n",
+			want: []*diffFile{{name: "logo.png"}},
+		},
+		{
+			name: "empty",
+			diff: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, parseUnifiedDiff(tt.diff))
+		})
+	}
+}
+
+func TestDiffCollapsesLargeFiles(t *testing.T) {
+	diff := "--- a/big.txt\n+++ b/big.txt\n@@ -0,0 +1,30 @@\n" + strings.Repeat("+line\n", 30)
+
+	card := Diff("Big change", diff)
+	require.Len(t, card.Body.Elements, 2)
+	data, err := json.Marshal(card.Body.Elements[1])
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"expanded":false`)
+
+	require.Equal(t, []feishubot.CardElement{feishubot.NewMarkdownElement("No changes.")}, Diff("Nothing", "").Body.Elements)
+}
@@ -0,0 +1,185 @@
+package cards
+
+import (
+	"sort"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// chartColors is the palette used for series, in order.
+var chartColors = []string{"#3370FF", "#34C724", "#FF8800", "#F54A45", "#7F3BF5", "#14C0FF", "#FFC60A", "#8F959E"}
+
+// maxChartPointMarkers is the number of points per series up to which line
+// charts mark each point.
+const maxChartPointMarkers = 30
+
+// Point is a value at a point in time.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is a named time series. Points need not be sorted.
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+// LineChart creates a card holding a line chart of the series, one line per
+// series, e.g. for latency or request rates.
+//
+// Time labels are formatted according to the time span covered: hours and
+// minutes within a day, month and day with time within a month, and dates
+// otherwise. Series share the color palette in order and a legend is shown
+// for more than one series.
+//
+// Example:
+//
+//	card := cards.LineChart("p99 latency (ms)", []cards.Series{
+//		{Name: "api", Points: apiPoints},
+//		{Name: "web", Points: webPoints},
+//	})
+func LineChart(title string, series []Series) *feishubot.Card {
+	return chartCard(title, LineChartElement(series))
+}
+
+// BarChart creates a card holding a bar chart of the series, with the bars
+// of multiple series grouped by time. See LineChart for how times are
+// labeled.
+func BarChart(title string, series []Series) *feishubot.Card {
+	return chartCard(title, BarChartElement(series))
+}
+
+// LineChartElement creates the chart element used by LineChart, e.g. for a
+// ReportSection.
+func LineChartElement(series []Series) feishubot.CardElement {
+	chartSeries, labels := toChartSeries(series)
+	spec := feishubot.NewLineChartSpec("", chartSeries...)
+	spec["axes"] = chartAxes(labels, false)
+	spec["point"] = map[string]interface{}{"visible": maxSeriesLen(series) <= maxChartPointMarkers}
+	return chartElement(spec)
+}
+
+// BarChartElement creates the chart element used by BarChart, e.g. for a
+// ReportSection.
+func BarChartElement(series []Series) feishubot.CardElement {
+	chartSeries, labels := toChartSeries(series)
+	spec := feishubot.NewBarChartSpec("", chartSeries...)
+	spec["axes"] = chartAxes(labels, true)
+	return chartElement(spec)
+}
+
+// chartCard creates a card holding a chart element below a header.
+func chartCard(title string, chart feishubot.CardElement) *feishubot.Card {
+	builder := feishubot.NewCardBuilder()
+	if title != "" {
+		builder.Header(title, feishubot.TemplateBlue)
+	}
+	return builder.Element(chart).Build()
+}
+
+// chartElement wraps a spec in a wide chart element and sets the palette.
+func chartElement(spec map[string]interface{}) feishubot.CardElement {
+	spec["color"] = chartColors
+	return feishubot.NewChartElement(spec, feishubot.WithChartAspectRatio("16:9"))
+}
+
+// chartAxes returns a time band axis holding labels in order and a value
+// axis. Bars always start at zero; lines may zoom in on the range of values.
+func chartAxes(labels []string, zero bool) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"orient": "bottom",
+			"type":   "band",
+			"domain": labels,
+			"label":  map[string]interface{}{"autoHide": true, "autoRotate": true},
+		},
+		{
+			"orient": "left",
+			"type":   "linear",
+			"zero":   zero,
+			"nice":   true,
+		},
+	}
+}
+
+// toChartSeries converts time series to chart series with formatted time
+// labels, sorting points by time. It also returns the labels of all series
+// in time order, since VChart would otherwise order the x axis by first
+// appearance when series cover different times.
+func toChartSeries(series []Series) ([]feishubot.ChartSeries, []string) {
+	layout := timeLayout(series)
+
+	var times []time.Time
+	result := make([]feishubot.ChartSeries, 0, len(series))
+	for _, s := range series {
+		points := append([]Point(nil), s.Points...)
+		sort.SliceStable(points, func(i, j int) bool {
+			return points[i].Time.Before(points[j].Time)
+		})
+
+		chartPoints := make([]feishubot.ChartPoint, 0, len(points))
+		for _, p := range points {
+			chartPoints = append(chartPoints, feishubot.ChartPoint{X: p.Time.Format(layout), Y: p.Value})
+			times = append(times, p.Time)
+		}
+		result = append(result, feishubot.ChartSeries{Name: s.Name, Points: chartPoints})
+	}
+
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	labels := make([]string, 0, len(times))
+	seen := make(map[string]bool, len(times))
+	for _, t := range times {
+		if label := t.Format(layout); !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+
+	return result, labels
+}
+
+// timeLayout returns the layout for time labels based on the time span and
+// resolution of all points.
+func timeLayout(series []Series) string {
+	var first, last time.Time
+	subDay := false
+	for _, s := range series {
+		for _, p := range s.Points {
+			if first.IsZero() || p.Time.Before(first) {
+				first = p.Time
+			}
+			if last.IsZero() || p.Time.After(last) {
+				last = p.Time
+			}
+			hour, min, sec := p.Time.Clock()
+			if hour != 0 || min != 0 || sec != 0 {
+				subDay = true
+			}
+		}
+	}
+
+	switch {
+	case first.YearDay() == last.YearDay() && first.Year() == last.Year():
+		return "15:04"
+	case last.Sub(first) <= 31*24*time.Hour && subDay:
+		return "01-02 15:04"
+	case first.Year() == last.Year():
+		return "01-02"
+	}
+	return "2006-01-02"
+}
+
+// maxSeriesLen returns the number of points of the longest series.
+func maxSeriesLen(series []Series) int {
+	n := 0
+	for _, s := range series {
+		if len(s.Points) > n {
+			n = len(s.Points)
+		}
+	}
+	return n
+}
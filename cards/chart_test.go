@@ -0,0 +1,119 @@
+package cards
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineChart(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, 5, 1, hour, 0, 0, 0, time.UTC)
+	}
+
+	card := LineChart("Latency", []Series{
+		{Name: "api", Points: []Point{{Time: at(11), Value: 180}, {Time: at(10), Value: 120}}},
+		{Name: "web", Points: []Point{{Time: at(9), Value: 80}}},
+	})
+
+	data, err := json.Marshal(card)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {"title": {"tag": "plain_text", "content": "Latency"}, "template": "blue"},
+		"body": {"elements": [{
+			"tag": "chart",
+			"aspect_ratio": "16:9",
+			"chart_spec": {
+				"type": "line",
+				"data": {"values": [
+					{"x": "10:00", "y": 120, "series": "api"},
+					{"x": "11:00", "y": 180, "series": "api"},
+					{"x": "09:00", "y": 80, "series": "web"}
+				]},
+				"xField": "x",
+				"yField": "y",
+				"seriesField": "series",
+				"legends": {"visible": true},
+				"color": ["#3370FF", "#34C724", "#FF8800", "#F54A45", "#7F3BF5", "#14C0FF", "#FFC60A", "#8F959E"],
+				"point": {"visible": true},
+				"axes": [
+					{"orient": "bottom", "type": "band", "domain": ["09:00", "10:00", "11:00"],
+						"label": {"autoHide": true, "autoRotate": true}},
+					{"orient": "left", "type": "linear", "zero": false, "nice": true}
+				]
+			}
+		}]}
+	}`, string(data))
+}
+
+func TestBarChart(t *testing.T) {
+	card := BarChart("", []Series{
+		{Name: "deploys", Points: []Point{
+			{Time: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Value: 3},
+			{Time: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), Value: 5},
+		}},
+	})
+	require.Nil(t, card.Header)
+
+	data, err := json.Marshal(card.Body.Elements[0])
+	require.NoError(t, err)
+
+	var chart struct {
+		Spec map[string]any `json:"chart_spec"`
+	}
+	require.NoError(t, json.Unmarshal(data, &chart))
+	require.Equal(t, "bar", chart.Spec["type"])
+	require.Equal(t, []any{"x", "series"}, chart.Spec["xField"])
+	require.Equal(t, map[string]any{"visible": false}, chart.Spec["legends"])
+	require.Equal(t, []any{
+		map[string]any{"orient": "bottom", "type": "band", "domain": []any{"05-01", "05-02"},
+			"label": map[string]any{"autoHide": true, "autoRotate": true}},
+		map[string]any{"orient": "left", "type": "linear", "zero": true, "nice": true},
+	}, chart.Spec["axes"])
+	require.NotContains(t, chart.Spec, "point")
+}
+
+func TestTimeLayout(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		points []Point
+		want   string
+	}{
+		{
+			name:   "within a day",
+			points: []Point{{Time: day.Add(time.Hour)}, {Time: day.Add(5 * time.Hour)}},
+			want:   "15:04",
+		},
+		{
+			name:   "hourly over days",
+			points: []Point{{Time: day.Add(time.Hour)}, {Time: day.Add(49 * time.Hour)}},
+			want:   "01-02 15:04",
+		},
+		{
+			name:   "daily",
+			points: []Point{{Time: day}, {Time: day.AddDate(0, 2, 0)}},
+			want:   "01-02",
+		},
+		{
+			name:   "across years",
+			points: []Point{{Time: day}, {Time: day.AddDate(1, 0, 0)}},
+			want:   "2006-01-02",
+		},
+		{
+			name:   "hourly over months",
+			points: []Point{{Time: day.Add(time.Hour)}, {Time: day.AddDate(0, 3, 0)}},
+			want:   "01-02",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, timeLayout([]Series{{Points: tt.points}}))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package cards
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Severity is the severity of an incident.
+type Severity string
+
+const (
+	SeveritySEV1 Severity = "SEV1"
+	SeveritySEV2 Severity = "SEV2"
+	SeveritySEV3 Severity = "SEV3"
+	SeveritySEV4 Severity = "SEV4"
+)
+
+// colors returns the header template and tag color for the severity.
+func (s Severity) colors() (template, tagColor string) {
+	switch s {
+	case SeveritySEV1:
+		return feishubot.TemplateRed, "red"
+	case SeveritySEV2:
+		return feishubot.TemplateOrange, "orange"
+	case SeveritySEV3:
+		return feishubot.TemplateYellow, "yellow"
+	}
+	return feishubot.TemplateBlue, "blue"
+}
+
+// TimelineEntry is an entry of an incident timeline.
+type TimelineEntry struct {
+	Time time.Time
+	Text string
+}
+
+// IncidentReport describes an incident shown by Incident.
+type IncidentReport struct {
+	Title    string
+	Severity Severity
+	// Impact summarizes the user-facing impact. It may contain markdown.
+	Impact   string
+	Timeline []TimelineEntry
+	// OnCallID is the open ID of the current on-call person.
+	OnCallID string
+	// RunbookURL and WarRoomURL add buttons when set.
+	RunbookURL string
+	WarRoomURL string
+}
+
+// Incident creates a card for paging channels showing the severity as a
+// colored tag, the impact, a timeline, the current on-call person and
+// buttons for the runbook and war room.
+//
+// Example:
+//
+//	card := cards.Incident(cards.IncidentReport{
+//		Title:    "Checkout error rate above 5%",
+//		Severity: cards.SeveritySEV1,
+//		Impact:   "About 8% of checkouts fail in eu-west",
+//		Timeline: []cards.TimelineEntry{
+//			{Time: firedAt, Text: "Alert fired"},
+//			{Time: ackedAt, Text: "Acknowledged by on-call"},
+//		},
+//		OnCallID:   "ou_xxx",
+//		RunbookURL: "https://wiki.example.com/runbooks/checkout",
+//		WarRoomURL: "https://meet.example.com/incident-42",
+//	})
+func Incident(report IncidentReport) *feishubot.Card {
+	template, tagColor := report.Severity.colors()
+
+	builder := feishubot.NewCardBuilder().Header("🚨 "+report.Title, template)
+
+	if report.Impact != "" {
+		builder.Markdown("**Impact**\n" + report.Impact)
+	}
+
+	if report.OnCallID != "" {
+		builder.Element(feishubot.NewColumnSet(
+			feishubot.NewColumn(0, feishubot.NewMarkdownElement("**On-call**")).SetVerticalAlign("center"),
+			feishubot.NewColumn(1, feishubot.MapElement{
+				"tag":       "person",
+				"user_id":   report.OnCallID,
+				"size":      "small",
+				"show_name": true,
+			}),
+		))
+	}
+
+	if len(report.Timeline) > 0 {
+		lines := make([]string, 0, len(report.Timeline))
+		for _, entry := range report.Timeline {
+			lines = append(lines, fmt.Sprintf("%s %s", entry.Time.Format("15:04"), entry.Text))
+		}
+		builder.Element(&feishubot.MarkdownElement{
+			Content:  strings.Join(lines, "\n"),
+			TextSize: "notation",
+		})
+	}
+
+	var buttons []feishubot.CardElement
+	if report.RunbookURL != "" {
+		buttons = append(buttons, feishubot.NewButtonElement("Runbook", "default", report.RunbookURL))
+	}
+	if report.WarRoomURL != "" {
+		buttons = append(buttons, feishubot.NewButtonElement("Join war room", "danger", report.WarRoomURL))
+	}
+	if len(buttons) > 0 {
+		builder.Actions(buttons...)
+	}
+
+	card := builder.Build()
+	if report.Severity != "" {
+		card.Header.TextTagList = []feishubot.TextTag{feishubot.NewTextTag(string(report.Severity), tagColor)}
+	}
+	return card
+}
@@ -0,0 +1,100 @@
+package cards
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncident(t *testing.T) {
+	firedAt := time.Date(2024, 5, 1, 10, 2, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		report IncidentReport
+		want   string
+	}{
+		{
+			name: "full report",
+			report: IncidentReport{
+				Title:    "Checkout errors",
+				Severity: SeveritySEV1,
+				Impact:   "8% of checkouts fail",
+				Timeline: []TimelineEntry{
+					{Time: firedAt, Text: "Alert fired"},
+					{Time: firedAt.Add(3 * time.Minute), Text: "Acknowledged"},
+				},
+				OnCallID:   "ou_xxx",
+				RunbookURL: "https://wiki.example.com/runbook",
+				WarRoomURL: "https://meet.example.com/42",
+			},
+			want: `{
+				"schema": "2.0",
+				"header": {
+					"title": {"tag": "plain_text", "content": "🚨 Checkout errors"},
+					"template": "red",
+					"text_tag_list": [{"tag": "text_tag", "text": {"tag": "plain_text", "content": "SEV1"}, "color": "red"}]
+				},
+				"body": {"elements": [
+					{"tag": "markdown", "content": "**Impact**\n8% of checkouts fail"},
+					{"tag": "column_set", "columns": [
+						{"tag": "column", "width": "auto", "vertical_align": "center", "elements": [{"tag": "markdown", "content": "**On-call**"}]},
+						{"tag": "column", "width": "weighted", "weight": 1, "elements": [{"tag": "person", "user_id": "ou_xxx", "size": "small", "show_name": true}]}
+					]},
+					{"tag": "markdown", "content": "10:02 Alert fired\n10:05 Acknowledged", "text_size": "notation"},
+					{"tag": "column_set", "columns": [
+						{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+							{"tag": "button", "text": {"tag": "plain_text", "content": "Runbook"}, "type": "default", "url": "https://wiki.example.com/runbook", "width": "fill"}
+						]},
+						{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+							{"tag": "button", "text": {"tag": "plain_text", "content": "Join war room"}, "type": "danger", "url": "https://meet.example.com/42", "width": "fill"}
+						]}
+					]}
+				]}
+			}`,
+		},
+		{
+			name:   "minimal report",
+			report: IncidentReport{Title: "Disk almost full"},
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "🚨 Disk almost full"}, "template": "blue"},
+				"body": {"elements": []}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := Incident(tt.report)
+			require.Empty(t, card.Lint())
+
+			data, err := json.Marshal(card)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestSeverityColors(t *testing.T) {
+	tests := []struct {
+		severity     Severity
+		wantTemplate string
+		wantTag      string
+	}{
+		{SeveritySEV1, "red", "red"},
+		{SeveritySEV2, "orange", "orange"},
+		{SeveritySEV3, "yellow", "yellow"},
+		{SeveritySEV4, "blue", "blue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.severity), func(t *testing.T) {
+			template, tag := tt.severity.colors()
+			require.Equal(t, tt.wantTemplate, template)
+			require.Equal(t, tt.wantTag, tag)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+// Package cards provides prebuilt cards for common notifications, such as CI
+// pipeline results, built with the feishubot card builders.
+package cards
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Status is the status of a pipeline or one of its stages.
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusRunning   Status = "running"
+	StatusPending   Status = "pending"
+	StatusSkipped   Status = "skipped"
+	StatusCanceled  Status = "canceled"
+)
+
+// Emoji returns the emoji representing the status.
+func (s Status) Emoji() string {
+	switch s {
+	case StatusSucceeded:
+		return "✅"
+	case StatusFailed:
+		return "❌"
+	case StatusRunning:
+		return "⏳"
+	case StatusSkipped:
+		return "⏭️"
+	case StatusCanceled:
+		return "🚫"
+	}
+	return "⏸️"
+}
+
+// template returns the header template matching the status.
+func (s Status) template() string {
+	switch s {
+	case StatusSucceeded:
+		return feishubot.TemplateGreen
+	case StatusFailed:
+		return feishubot.TemplateRed
+	case StatusRunning:
+		return feishubot.TemplateBlue
+	}
+	return feishubot.TemplateGrey
+}
+
+// Stage is a stage of a pipeline run.
+type Stage struct {
+	Name   string
+	Status Status
+	// Duration is omitted from the card if zero.
+	Duration time.Duration
+}
+
+// maxPipelineChanges is the maximum number of changes listed by Pipeline.
+const maxPipelineChanges = 5
+
+// Change is a commit built by a pipeline run.
+type Change struct {
+	// ID is the commit hash; it is shortened to 8 characters.
+	ID      string
+	Message string
+	Author  string
+	// URL links the commit when set.
+	URL string
+}
+
+// markdown renders the change as a list item with the first line of its
+// message.
+func (c Change) markdown() string {
+	id := c.ID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	if id != "" && c.URL != "" {
+		id = fmt.Sprintf("[%s](%s)", id, c.URL)
+	}
+
+	message, _, _ := strings.Cut(c.Message, "\n")
+	parts := make([]string, 0, 2)
+	for _, part := range []string{id, feishubot.Sanitize(message)} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	line := "- " + strings.Join(parts, " ")
+	if c.Author != "" {
+		line += " · " + feishubot.Sanitize(c.Author)
+	}
+	return line
+}
+
+// PipelineRun describes a CI pipeline run shown by Pipeline.
+type PipelineRun struct {
+	// Name identifies the pipeline, e.g. "api-gateway #123".
+	Name string
+	// Status is the overall status. If empty, it is derived from the
+	// stages: failed if any stage failed, running if any stage is running
+	// or pending, succeeded otherwise.
+	Status Status
+	Stages []Stage
+	// Duration is the total duration, omitted if zero.
+	Duration time.Duration
+	// Trigger describes what started the run, e.g. "push to main by alice".
+	Trigger string
+	// Changes are the commits built by the run. At most 5 are listed.
+	Changes []Change
+	// LogsURL and RerunURL add buttons when set.
+	LogsURL  string
+	RerunURL string
+}
+
+// Pipeline creates a card showing the stage-by-stage status of a CI
+// pipeline run with its duration, trigger and buttons for logs and re-run.
+//
+// Example:
+//
+//	card := cards.Pipeline(cards.PipelineRun{
+//		Name: "api-gateway #123",
+//		Stages: []cards.Stage{
+//			{Name: "Build", Status: cards.StatusSucceeded, Duration: 72 * time.Second},
+//			{Name: "Test", Status: cards.StatusFailed, Duration: 3 * time.Minute},
+//			{Name: "Deploy", Status: cards.StatusSkipped},
+//		},
+//		Trigger: "push to main by alice",
+//		LogsURL: "https://ci.example.com/123",
+//	})
+//	message := feishubot.NewInteractiveMessage(card)
+func Pipeline(run PipelineRun) *feishubot.Card {
+	status := run.Status
+	if status == "" {
+		status = overallStatus(run.Stages)
+	}
+
+	builder := feishubot.NewCardBuilder().
+		Header(fmt.Sprintf("%s %s %s", status.Emoji(), run.Name, status), status.template())
+
+	if len(run.Stages) > 0 {
+		lines := make([]string, 0, len(run.Stages))
+		for _, stage := range run.Stages {
+			line := fmt.Sprintf("%s **%s**", stage.Status.Emoji(), stage.Name)
+			if stage.Duration > 0 {
+				line += " · " + formatDuration(stage.Duration)
+			}
+			lines = append(lines, line)
+		}
+		builder.Markdown(strings.Join(lines, "\n"))
+	}
+
+	var fields []feishubot.KV
+	if run.Duration > 0 {
+		fields = append(fields, feishubot.KV{Label: "Duration", Value: formatDuration(run.Duration)})
+	}
+	if run.Trigger != "" {
+		fields = append(fields, feishubot.KV{Label: "Trigger", Value: run.Trigger})
+	}
+	if len(fields) > 0 {
+		builder.Fields(fields)
+	}
+
+	if len(run.Changes) > 0 {
+		lines := []string{"**Changes**"}
+		for i, change := range run.Changes {
+			if i == maxPipelineChanges {
+				lines = append(lines, fmt.Sprintf("- and %d more", len(run.Changes)-i))
+				break
+			}
+			lines = append(lines, change.markdown())
+		}
+		builder.Markdown(strings.Join(lines, "\n"))
+	}
+
+	var buttons []feishubot.CardElement
+	if run.LogsURL != "" {
+		buttons = append(buttons, feishubot.NewButtonElement("View logs", "primary", run.LogsURL))
+	}
+	if run.RerunURL != "" {
+		buttons = append(buttons, feishubot.NewButtonElement("Re-run", "default", run.RerunURL))
+	}
+	if len(buttons) > 0 {
+		builder.Actions(buttons...)
+	}
+
+	return builder.Build()
+}
+
+// overallStatus derives the status of a pipeline from its stages.
+func overallStatus(stages []Stage) Status {
+	status := StatusSucceeded
+	for _, stage := range stages {
+		switch stage.Status {
+		case StatusFailed:
+			return StatusFailed
+		case StatusRunning, StatusPending:
+			status = StatusRunning
+		}
+	}
+	return status
+}
+
+// formatDuration formats d rounded to seconds, e.g. "1m12s".
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.String()
+	}
+	return d.Round(time.Second).String()
+}
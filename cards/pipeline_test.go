@@ -0,0 +1,109 @@
+package cards
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline(t *testing.T) {
+	tests := []struct {
+		name string
+		run  PipelineRun
+		want string
+	}{
+		{
+			name: "failed run",
+			run: PipelineRun{
+				Name: "api #123",
+				Stages: []Stage{
+					{Name: "Build", Status: StatusSucceeded, Duration: 72*time.Second + 300*time.Millisecond},
+					{Name: "Test", Status: StatusFailed, Duration: 3 * time.Minute},
+					{Name: "Deploy", Status: StatusSkipped},
+				},
+				Duration: 4*time.Minute + 12*time.Second,
+				Trigger:  "push to main by alice",
+				LogsURL:  "https://ci.example.com/123",
+				RerunURL: "https://ci.example.com/123/rerun",
+			},
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "❌ api #123 failed"}, "template": "red"},
+				"body": {"elements": [
+					{"tag": "markdown", "content": "✅ **Build** · 1m12s\n❌ **Test** · 3m0s\n⏭️ **Deploy**"},
+					{"tag": "div", "fields": [
+						{"is_short": true, "text": {"tag": "lark_md", "content": "**Duration:** 4m12s"}},
+						{"is_short": true, "text": {"tag": "lark_md", "content": "**Trigger:** push to main by alice"}}
+					]},
+					{"tag": "column_set", "columns": [
+						{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+							{"tag": "button", "text": {"tag": "plain_text", "content": "View logs"}, "type": "primary", "url": "https://ci.example.com/123", "width": "fill"}
+						]},
+						{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+							{"tag": "button", "text": {"tag": "plain_text", "content": "Re-run"}, "type": "default", "url": "https://ci.example.com/123/rerun", "width": "fill"}
+						]}
+					]}
+				]}
+			}`,
+		},
+		{
+			name: "changes",
+			run: PipelineRun{
+				Name:   "api #124",
+				Status: StatusSucceeded,
+				Changes: []Change{
+					{ID: "9f2c1a7be0d34", Message: "Fix retry loop\n\nDetails", Author: "alice", URL: "https://git.example.com/c/9f2c1a7b"},
+					{Message: "Bump deps"},
+					{ID: "3"}, {ID: "4"}, {ID: "5"}, {ID: "6"}, {ID: "7"},
+				},
+			},
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "✅ api #124 succeeded"}, "template": "green"},
+				"body": {"elements": [
+					{"tag": "markdown", "content": "**Changes**\n- [9f2c1a7b](https://git.example.com/c/9f2c1a7b) Fix retry loop · alice\n- Bump deps\n- 3\n- 4\n- 5\n- and 2 more"}
+				]}
+			}`,
+		},
+		{
+			name: "minimal explicit status",
+			run:  PipelineRun{Name: "nightly", Status: StatusCanceled},
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "🚫 nightly canceled"}, "template": "grey"},
+				"body": {"elements": []}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(Pipeline(tt.run))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestOverallStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		stages []Stage
+		want   Status
+	}{
+		{name: "no stages", want: StatusSucceeded},
+		{name: "all succeeded", stages: []Stage{{Status: StatusSucceeded}, {Status: StatusSkipped}}, want: StatusSucceeded},
+		{name: "running", stages: []Stage{{Status: StatusSucceeded}, {Status: StatusRunning}, {Status: StatusPending}}, want: StatusRunning},
+		{name: "failed wins", stages: []Stage{{Status: StatusRunning}, {Status: StatusFailed}}, want: StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overallStatus(tt.stages); got != tt.want {
+				t.Errorf("overallStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cards
+
+import feishubot "github.com/cium-cc/feishurobot"
+
+// ReportSection is a section of a report card.
+type ReportSection struct {
+	Title string
+	// Metrics are laid out as label/value fields side by side.
+	Metrics []feishubot.KV
+	// Elements such as charts or tables are shown after the metrics.
+	Elements []feishubot.CardElement
+}
+
+// Report creates a summary card for scheduled digest posts, such as daily
+// or weekly reports. period is shown after the title, e.g. "2024-05-01" or
+// "Week 18"; sections are separated by dividers.
+//
+// Example:
+//
+//	card := cards.Report("Daily report", "2024-05-01",
+//		cards.ReportSection{
+//			Title: "Traffic",
+//			Metrics: []feishubot.KV{
+//				{Label: "Requests", Value: "1.2M"},
+//				{Label: "Error rate", Value: "0.03%"},
+//			},
+//		},
+//		cards.ReportSection{
+//			Title:    "Latency",
+//			Elements: []feishubot.CardElement{latencyChart},
+//		},
+//	)
+func Report(title, period string, sections ...ReportSection) *feishubot.Card {
+	header := "📊 " + title
+	if period != "" {
+		header += " · " + period
+	}
+
+	builder := feishubot.NewCardBuilder().Header(header, feishubot.TemplateWathet)
+	for i, section := range sections {
+		if i > 0 {
+			builder.Divider()
+		}
+		if section.Title != "" {
+			builder.Markdown("**" + section.Title + "**")
+		}
+		if len(section.Metrics) > 0 {
+			builder.Fields(section.Metrics)
+		}
+		builder.Element(section.Elements...)
+	}
+
+	return builder.Build()
+}
@@ -0,0 +1,69 @@
+package cards
+
+import (
+	"encoding/json"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		period   string
+		sections []ReportSection
+		want     string
+	}{
+		{
+			name:   "sections",
+			title:  "Daily report",
+			period: "2024-05-01",
+			sections: []ReportSection{
+				{
+					Title: "Traffic",
+					Metrics: []feishubot.KV{
+						{Label: "Requests", Value: "1.2M"},
+						{Label: "Errors", Value: "0.03%"},
+					},
+				},
+				{
+					Title:    "Latency",
+					Elements: []feishubot.CardElement{feishubot.NewMarkdownElement("p99 120ms")},
+				},
+			},
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "📊 Daily report · 2024-05-01"}, "template": "wathet"},
+				"body": {"elements": [
+					{"tag": "markdown", "content": "**Traffic**"},
+					{"tag": "div", "fields": [
+						{"is_short": true, "text": {"tag": "lark_md", "content": "**Requests:** 1.2M"}},
+						{"is_short": true, "text": {"tag": "lark_md", "content": "**Errors:** 0.03%"}}
+					]},
+					{"tag": "hr"},
+					{"tag": "markdown", "content": "**Latency**"},
+					{"tag": "markdown", "content": "p99 120ms"}
+				]}
+			}`,
+		},
+		{
+			name:  "no period or sections",
+			title: "Weekly report",
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "📊 Weekly report"}, "template": "wathet"},
+				"body": {"elements": []}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(Report(tt.title, tt.period, tt.sections...))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
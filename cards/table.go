@@ -0,0 +1,259 @@
+package cards
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// tableField is a struct field shown as a table column.
+type tableField struct {
+	index  []int
+	column feishubot.TableColumn
+}
+
+// TableFromStructs creates a card holding a table of rows, a slice of
+// structs or pointers to structs, with one column per exported field.
+//
+// Column names follow the `json` tag or the field name. Data types are
+// inferred from field types: numbers become number columns, time.Time
+// becomes a date column, and everything else, including time.Duration and
+// fmt.Stringer values, becomes text. The `card` tag overrides this:
+//
+//	type DeploymentStatus struct {
+//		Service  string    `card:"Service"`
+//		Replicas int       `card:"Replicas,align=right"`
+//		CPU      float64   `card:"CPU (%),precision=1"`
+//		Owner    string    `card:"Owner,persons"`
+//		Updated  time.Time `card:"Updated,format=MM/DD HH:mm"`
+//		Internal string    `card:"-"`
+//	}
+//
+// The first part of the tag is the column header, followed by an optional
+// data type (text, lark_md, markdown, number, date, persons or options) and
+// width=, align=, precision= or format= settings. A tag of "-" skips the
+// field. Nil rows are skipped.
+//
+// Example:
+//
+//	card, err := cards.TableFromStructs("Deployments", statuses)
+func TableFromStructs(title string, rows interface{}) (*feishubot.Card, error) {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("TableFromStructs: expected slice, got %s", rv.Kind())
+	}
+
+	elemType := rv.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("TableFromStructs: expected slice of structs, got slice of %s", elemType.Kind())
+	}
+
+	fields, err := tableFields(elemType, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]feishubot.TableColumn, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, field.column)
+	}
+	table := feishubot.NewTable(columns...)
+
+	for i := 0; i < rv.Len(); i++ {
+		item := rv.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
+			item = item.Elem()
+		}
+
+		row := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			value, ok := fieldByIndex(item, field.index)
+			if !ok {
+				continue
+			}
+			if cell := tableCell(value, field.column.DataType); cell != nil {
+				row[field.column.Name] = cell
+			}
+		}
+		table.AddRow(row)
+	}
+
+	builder := feishubot.NewCardBuilder()
+	if title != "" {
+		builder.Header(title, feishubot.TemplateBlue)
+	}
+	return builder.Element(table.ToElement()).Build(), nil
+}
+
+// tableFields returns the columns of a struct type, flattening embedded
+// structs like encoding/json does.
+func tableFields(t reflect.Type, index []int) ([]tableField, error) {
+	var fields []tableField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup("card")
+		if tag == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+		fieldType := sf.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if sf.Anonymous && !hasTag && fieldType.Kind() == reflect.Struct && fieldType != timeType {
+			embedded, err := tableFields(fieldType, fieldIndex)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, embedded...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		name := sf.Name
+		if jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]; jsonName == "-" {
+			if !hasTag {
+				continue
+			}
+		} else if jsonName != "" {
+			name = jsonName
+		}
+
+		column := feishubot.NewTableColumn(name, sf.Name, inferDataType(fieldType))
+		if err := applyCardTag(&column, tag); err != nil {
+			return nil, fmt.Errorf("TableFromStructs: field %s: %w", sf.Name, err)
+		}
+		fields = append(fields, tableField{index: fieldIndex, column: column})
+	}
+	return fields, nil
+}
+
+// inferDataType returns the column data type for a field type.
+func inferDataType(t reflect.Type) feishubot.TableDataType {
+	switch {
+	case t == timeType:
+		return feishubot.TableDataDate
+	case t == durationType, t.Implements(stringerType), reflect.PtrTo(t).Implements(stringerType):
+		return feishubot.TableDataText
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return feishubot.TableDataNumber
+	}
+	return feishubot.TableDataText
+}
+
+// applyCardTag applies the settings of a `card` struct tag to column.
+func applyCardTag(column *feishubot.TableColumn, tag string) error {
+	if tag == "" {
+		return nil
+	}
+
+	parts := strings.Split(tag, ",")
+	if parts[0] != "" {
+		column.DisplayName = parts[0]
+	}
+
+	for _, part := range parts[1:] {
+		key, value, hasValue := strings.Cut(part, "=")
+		if !hasValue {
+			switch dataType := feishubot.TableDataType(key); dataType {
+			case feishubot.TableDataText, feishubot.TableDataLarkMd, feishubot.TableDataMarkdown,
+				feishubot.TableDataNumber, feishubot.TableDataDate, feishubot.TableDataPersons,
+				feishubot.TableDataOptions:
+				column.DataType = dataType
+			default:
+				return fmt.Errorf("unknown data type %q", key)
+			}
+			continue
+		}
+
+		switch key {
+		case "width":
+			column.Width = value
+		case "align":
+			column.HorizontalAlign = value
+		case "format":
+			column.DateFormat = value
+		case "precision":
+			precision, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid precision %q: %w", value, err)
+			}
+			column.Format = &feishubot.TableNumberFormat{Precision: precision}
+		default:
+			return fmt.Errorf("unknown option %q", key)
+		}
+	}
+	return nil
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but reports false instead
+// of panicking when an embedded pointer is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// tableCell converts a field value to a cell value for the column data type.
+// It returns nil for nil pointers and zero times, leaving the cell empty.
+func tableCell(v reflect.Value, dataType feishubot.TableDataType) interface{} {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr && !v.Type().Implements(stringerType) {
+			v = v.Elem()
+		}
+	}
+
+	value := v.Interface()
+	switch x := value.(type) {
+	case time.Time:
+		if x.IsZero() {
+			return nil
+		}
+		if dataType == feishubot.TableDataDate {
+			return x.UnixMilli()
+		}
+		return x.Format(time.RFC3339)
+	case fmt.Stringer:
+		return x.String()
+	}
+
+	if dataType == feishubot.TableDataText && v.Kind() != reflect.String {
+		return fmt.Sprint(value)
+	}
+	return value
+}
@@ -0,0 +1,127 @@
+package cards
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type tableLevel int
+
+func (l tableLevel) String() string {
+	return [...]string{"low", "high"}[l]
+}
+
+type tableMeta struct {
+	Region string `json:"region"`
+}
+
+type deploymentStatus struct {
+	tableMeta
+	Service  string        `json:"service" card:"Service,width=120px"`
+	Replicas int           `card:",align=right"`
+	CPU      float64       `json:"cpu" card:"CPU (%),precision=1"`
+	Owner    []string      `json:"owner" card:"Owner,persons"`
+	Updated  time.Time     `json:"updated" card:"Updated,format=MM/DD HH:mm"`
+	Uptime   time.Duration `json:"uptime"`
+	Level    tableLevel    `json:"level"`
+	Healthy  bool          `json:"healthy"`
+	Note     *string       `json:"note"`
+	Internal string        `card:"-"`
+	Skipped  string        `json:"-"`
+	private  string
+}
+
+func TestTableFromStructs(t *testing.T) {
+	updated := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	note := "canary"
+	rows := []*deploymentStatus{
+		{
+			tableMeta: tableMeta{Region: "eu"},
+			Service:   "api",
+			Replicas:  3,
+			CPU:       41.5,
+			Owner:     []string{"ou_1"},
+			Updated:   updated,
+			Uptime:    90 * time.Minute,
+			Level:     1,
+			Healthy:   true,
+			Note:      &note,
+			Internal:  "x",
+			private:   "y",
+		},
+		nil,
+		{Service: "worker"},
+	}
+
+	card, err := TableFromStructs("Deployments", rows)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(card)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {"title": {"tag": "plain_text", "content": "Deployments"}, "template": "blue"},
+		"body": {"elements": [{
+			"tag": "table",
+			"columns": [
+				{"name": "region", "display_name": "Region", "data_type": "text"},
+				{"name": "service", "display_name": "Service", "data_type": "text", "width": "120px"},
+				{"name": "Replicas", "display_name": "Replicas", "data_type": "number", "horizontal_align": "right"},
+				{"name": "cpu", "display_name": "CPU (%)", "data_type": "number", "format": {"precision": 1}},
+				{"name": "owner", "display_name": "Owner", "data_type": "persons"},
+				{"name": "updated", "display_name": "Updated", "data_type": "date", "date_format": "MM/DD HH:mm"},
+				{"name": "uptime", "display_name": "Uptime", "data_type": "text"},
+				{"name": "level", "display_name": "Level", "data_type": "text"},
+				{"name": "healthy", "display_name": "Healthy", "data_type": "text"},
+				{"name": "note", "display_name": "Note", "data_type": "text"}
+			],
+			"rows": [
+				{
+					"region": "eu", "service": "api", "Replicas": 3, "cpu": 41.5, "owner": ["ou_1"],
+					"updated": 1714557600000, "uptime": "1h30m0s", "level": "high", "healthy": "true",
+					"note": "canary"
+				},
+				{
+					"region": "", "service": "worker", "Replicas": 0, "cpu": 0, "owner": null,
+					"uptime": "0s", "level": "low", "healthy": "false"
+				}
+			]
+		}]}
+	}`, string(data))
+}
+
+func TestTableFromStructsNoTitle(t *testing.T) {
+	card, err := TableFromStructs("", []struct{ Name string }{{Name: "a"}})
+	require.NoError(t, err)
+	require.Nil(t, card.Header)
+	require.Len(t, card.Body.Elements, 1)
+}
+
+func TestTableFromStructsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rows any
+	}{
+		{name: "not a slice", rows: struct{}{}},
+		{name: "not structs", rows: []int{1}},
+		{name: "unknown data type", rows: []struct {
+			A string `card:"A,colour"`
+		}{}},
+		{name: "unknown option", rows: []struct {
+			A string `card:"A,size=1"`
+		}{}},
+		{name: "invalid precision", rows: []struct {
+			A float64 `card:"A,precision=x"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := TableFromStructs("", tt.rows)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// StreamElementID is the element ID of the markdown element that
+// APIClient.StreamCard appends to cards for the streamed text.
+const StreamElementID = "stream_text"
+
+// DefaultStreamFlushInterval is the default minimum interval between
+// updates of a CardStream.
+const DefaultStreamFlushInterval = 300 * time.Millisecond
+
+// ErrStreamClosed is returned when writing to a closed CardStream.
+var ErrStreamClosed = errors.New("card stream closed")
+
+// CardStream is a sent card whose text is streamed in increments, such as
+// the response of an LLM, with a typing effect in the Feishu client. It is
+// created by APIClient.StreamCard.
+//
+// Appended text is sent at most once per FlushInterval; call Close when
+// done to send the remaining text and end the streaming mode. Feishu ends
+// the streaming mode of a card on its own after 10 minutes.
+//
+// A CardStream is safe for concurrent use.
+type CardStream struct {
+	// CardID is the ID of the card entity.
+	CardID string
+	// Message is the sent message holding the card.
+	Message *SentMessage
+
+	// FlushInterval is the minimum interval between updates. If zero,
+	// every Append sends an update.
+	FlushInterval time.Duration
+
+	client *APIClient
+
+	mu        sync.Mutex
+	text      string
+	flushed   string
+	sequence  int
+	lastFlush time.Time
+	closed    bool
+	now       func() time.Time
+}
+
+// StreamCard creates a card entity with streaming mode enabled from card,
+// appending an empty markdown element with ID StreamElementID, and sends it
+// to the recipient. Text written to the returned stream is shown in that
+// element. card must use schema 2.0 and is not modified.
+//
+// The app needs the cardkit:card:write permission in addition to
+// im:message:send_as_bot.
+//
+// See: https://open.feishu.cn/document/cardkit-v1/streaming-updates-openapi-overview
+//
+// Example:
+//
+//	card := feishubot.NewCardBuilder().Header("Assistant", feishubot.TemplateBlue).Build()
+//	stream, err := api.StreamCard(ctx, feishubot.ToChat(chatID), card)
+//	if err != nil {
+//		return err
+//	}
+//	for token := range tokens {
+//		if err := stream.Append(ctx, token); err != nil {
+//			return err
+//		}
+//	}
+//	return stream.Close(ctx)
+func (c *APIClient) StreamCard(ctx context.Context, to Recipient, card *Card) (*CardStream, error) {
+	if c.ValidateCards {
+		if err := validateCardLimits(card); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.Marshal(streamingCard(card))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal card: %w", err)
+	}
+
+	var created struct {
+		CardID string `json:"card_id"`
+	}
+	body := map[string]string{"type": "card_json", "data": string(data)}
+	if err := c.openAPI().callJSON(ctx, http.MethodPost, "/cardkit/v1/cards", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create card: %w", err)
+	}
+
+	msg := NewInteractiveMessageFromMap(map[string]interface{}{
+		"type": "card",
+		"data": map[string]string{"card_id": created.CardID},
+	})
+	sent, err := c.Send(ctx, to, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CardStream{
+		CardID:        created.CardID,
+		Message:       sent,
+		FlushInterval: DefaultStreamFlushInterval,
+		client:        c,
+		now:           time.Now,
+	}, nil
+}
+
+// streamingCard returns a copy of card with streaming mode enabled and the
+// stream element appended to the body.
+func streamingCard(card *Card) *Card {
+	streaming := *card
+	streaming.Config = make(map[string]interface{}, len(card.Config)+1)
+	for key, value := range card.Config {
+		streaming.Config[key] = value
+	}
+	streaming.Config["streaming_mode"] = true
+
+	body := &CardBody{}
+	if card.Body != nil {
+		*body = *card.Body
+	}
+	body.Elements = append(body.Elements[:len(body.Elements):len(body.Elements)],
+		&MarkdownElement{ElementID: StreamElementID})
+	streaming.Body = body
+	return &streaming
+}
+
+// Append appends text to the streamed text, sending an update if
+// FlushInterval has passed since the last one.
+func (s *CardStream) Append(ctx context.Context, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStreamClosed
+	}
+	s.text += text
+	if s.now().Sub(s.lastFlush) < s.FlushInterval {
+		return nil
+	}
+	return s.flush(ctx)
+}
+
+// Flush sends the text appended since the last update, if any.
+func (s *CardStream) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStreamClosed
+	}
+	return s.flush(ctx)
+}
+
+// Text returns the streamed text, including text not sent yet.
+func (s *CardStream) Text() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.text
+}
+
+// Close sends the remaining text and ends the streaming mode of the card.
+// Closing a closed stream does nothing.
+func (s *CardStream) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	if err := s.flush(ctx); err != nil {
+		return err
+	}
+
+	s.sequence++
+	body := map[string]interface{}{
+		"settings": `{"config":{"streaming_mode":false}}`,
+		"sequence": s.sequence,
+	}
+	path := "/cardkit/v1/cards/" + url.PathEscape(s.CardID) + "/settings"
+	if err := s.client.openAPI().callJSON(ctx, http.MethodPatch, path, body, nil); err != nil {
+		return fmt.Errorf("failed to end streaming of card %s: %w", s.CardID, err)
+	}
+	s.closed = true
+	return nil
+}
+
+// flush sends the full text if it changed since the last update. Feishu
+// renders the difference to the previous text with a typing effect.
+func (s *CardStream) flush(ctx context.Context) error {
+	if s.text == s.flushed {
+		return nil
+	}
+
+	s.sequence++
+	body := map[string]interface{}{
+		"content":  s.text,
+		"sequence": s.sequence,
+	}
+	path := "/cardkit/v1/cards/" + url.PathEscape(s.CardID) + "/elements/" + StreamElementID + "/content"
+	if err := s.client.openAPI().callJSON(ctx, http.MethodPut, path, body, nil); err != nil {
+		return fmt.Errorf("failed to stream text to card %s: %w", s.CardID, err)
+	}
+	s.flushed = s.text
+	s.lastFlush = s.now()
+	return nil
+}
@@ -0,0 +1,79 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIClientStreamCard(t *testing.T) {
+	var cardJSON string
+	var sentContent string
+	var updates []map[string]interface{}
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		switch r.Method + " " + r.URL.Path {
+		case "POST /cardkit/v1/cards":
+			require.Equal(t, "card_json", body["type"])
+			cardJSON = body["data"].(string)
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"card_id":"7355372766134157313"}}`))
+		case "POST /im/v1/messages":
+			require.Equal(t, "interactive", body["msg_type"])
+			sentContent = body["content"].(string)
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"message_id":"om_xxx","chat_id":"oc_xxx"}}`))
+		case "PUT /cardkit/v1/cards/7355372766134157313/elements/stream_text/content":
+			updates = append(updates, body)
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+		case "PATCH /cardkit/v1/cards/7355372766134157313/settings":
+			updates = append(updates, body)
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+
+	card := NewCardBuilder().Header("Assistant", TemplateBlue).Build()
+	stream, err := client.StreamCard(context.Background(), ToChat("oc_xxx"), card)
+	require.NoError(t, err)
+	require.Nil(t, card.Config)
+	require.Equal(t, "7355372766134157313", stream.CardID)
+	require.Equal(t, "om_xxx", stream.Message.MessageID)
+	require.JSONEq(t, `{"type":"card","data":{"card_id":"7355372766134157313"}}`, sentContent)
+
+	var sentCard map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(cardJSON), &sentCard))
+	require.Equal(t, map[string]interface{}{"streaming_mode": true}, sentCard["config"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"tag": "markdown", "content": "", "element_id": "stream_text"},
+	}, sentCard["body"].(map[string]interface{})["elements"])
+
+	now := time.Unix(1700000000, 0)
+	stream.now = func() time.Time { return now }
+	stream.FlushInterval = time.Second
+
+	require.NoError(t, stream.Append(context.Background(), "Hello"))
+	require.NoError(t, stream.Append(context.Background(), ", wor"))
+	now = now.Add(time.Second)
+	require.NoError(t, stream.Append(context.Background(), "ld"))
+	require.NoError(t, stream.Append(context.Background(), "!"))
+	require.Equal(t, "Hello, world!", stream.Text())
+	require.NoError(t, stream.Close(context.Background()))
+	require.NoError(t, stream.Close(context.Background()))
+
+	require.Equal(t, []map[string]interface{}{
+		{"content": "Hello", "sequence": float64(1)},
+		{"content": "Hello, world", "sequence": float64(2)},
+		{"content": "Hello, world!", "sequence": float64(3)},
+		{"settings": `{"config":{"streaming_mode":false}}`, "sequence": float64(4)},
+	}, updates)
+
+	require.ErrorIs(t, stream.Append(context.Background(), "more"), ErrStreamClosed)
+}
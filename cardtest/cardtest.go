@@ -0,0 +1,109 @@
+// Package cardtest provides golden file snapshot tests for Feishu cards, so
+// that card layout regressions are caught in CI rather than in a live chat.
+//
+// Snapshots are stored as indented JSON with sorted keys under testdata.
+// Run the tests with CARDTEST_UPDATE=1 to create or update them after an
+// intended change, and review the golden file diff like any other change.
+//
+// Example:
+//
+//	func TestDeployCard(t *testing.T) {
+//		card := buildDeployCard(deploy)
+//		cardtest.AssertGolden(t, "deploy", card)
+//	}
+package cardtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// UpdateEnv is the environment variable that, when set to a non-empty
+// value, makes AssertGolden write golden files instead of comparing them.
+const UpdateEnv = "CARDTEST_UPDATE"
+
+// TestingT is the subset of testing.TB used by AssertGolden.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Marshal serializes v, such as a *feishubot.Card or *feishubot.Message,
+// deterministically: object keys are sorted, output is indented with two
+// spaces and HTML characters are not escaped, so that markdown stays
+// readable in golden files.
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal card: %w", err)
+	}
+
+	// Decode into generic values, whose map keys encoding/json sorts
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode card: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// GoldenPath returns the path of the golden file for name,
+// "testdata/<name>.golden.json".
+func GoldenPath(name string) string {
+	return filepath.Join("testdata", name+".golden.json")
+}
+
+// AssertGolden compares the serialized form of v with the golden file for
+// name and reports a line diff if they differ. If the CARDTEST_UPDATE
+// environment variable is set, the golden file is written instead.
+func AssertGolden(t TestingT, name string, v interface{}) {
+	t.Helper()
+
+	got, err := Marshal(v)
+	if err != nil {
+		t.Fatalf("cardtest: %v", err)
+		return
+	}
+
+	path := GoldenPath(name)
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("cardtest: failed to create golden directory: %v", err)
+			return
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("cardtest: failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("cardtest: golden file %s does not exist, run with %s=1 to create it", path, UpdateEnv)
+		return
+	}
+	if err != nil {
+		t.Fatalf("cardtest: failed to read golden file: %v", err)
+		return
+	}
+
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("cardtest: card does not match %s (-want +got):\n%s\nrun with %s=1 to update it", path, diff, UpdateEnv)
+	}
+}
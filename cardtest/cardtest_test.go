@@ -0,0 +1,85 @@
+package cardtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeT records failures reported by AssertGolden.
+type fakeT struct {
+	errors []string
+	fatal  bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Fatalf(format string, args ...interface{}) {
+	f.Errorf(format, args...)
+	f.fatal = true
+}
+
+func testCard(title string) *feishubot.Card {
+	return feishubot.NewCardBuilder().
+		Header(title, feishubot.TemplateBlue).
+		Markdown("<font color='green'>ok</font>").
+		Build()
+}
+
+// chdir changes into dir for the duration of the test.
+func chdir(t *testing.T, dir string) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(wd))
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	got, err := Marshal(map[string]any{"b": 1.50, "a": "<x>"})
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"a\": \"<x>\",\n  \"b\": 1.5\n}\n", string(got))
+
+	_, err = Marshal(func() {})
+	require.Error(t, err)
+}
+
+func TestAssertGolden(t *testing.T) {
+	AssertGolden(t, "example", testCard("Deploy"))
+}
+
+func TestAssertGoldenMismatch(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	ft := &fakeT{}
+	AssertGolden(ft, "card", testCard("Deploy"))
+	require.True(t, ft.fatal)
+	require.Contains(t, ft.errors[0], "does not exist")
+
+	t.Setenv(UpdateEnv, "1")
+	ft = &fakeT{}
+	AssertGolden(ft, "card", testCard("Deploy"))
+	require.Empty(t, ft.errors)
+	_, err := os.Stat(filepath.Join("testdata", "card.golden.json"))
+	require.NoError(t, err)
+
+	t.Setenv(UpdateEnv, "")
+	ft = &fakeT{}
+	AssertGolden(ft, "card", testCard("Deploy"))
+	require.Empty(t, ft.errors)
+
+	AssertGolden(ft, "card", testCard("Rollback"))
+	require.False(t, ft.fatal)
+	require.Len(t, ft.errors, 1)
+	require.Contains(t, ft.errors[0], `"content": "Deploy"`)
+	require.Contains(t, ft.errors[0], `"content": "Rollback"`)
+}
@@ -0,0 +1,249 @@
+package feishubot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// CardElement is an element of a card body, such as a markdown text, a div
+// or a column set.
+//
+// Elements modeled by this package are typed (*MarkdownElement,
+// *HrElement, *Div, *ColumnSet, *Column, *ButtonElement and *FormElement),
+// which allows type switches when processing parsed or built cards. All
+// other elements, such as pickers, inputs and images, are represented as
+// MapElement; so are the elements built with ElementOption values, e.g. by
+// NewSelectPersonElement, NewInputElement or NewCollapsiblePanelElement.
+type CardElement interface {
+	// Tag returns the element tag, e.g. "markdown" or "column_set".
+	Tag() string
+}
+
+// MapElement is a card element represented as a map of its JSON fields,
+// including "tag". It is used for elements without a typed representation
+// and for parsed elements carrying fields the typed representation lacks.
+type MapElement map[string]interface{}
+
+// Tag returns the "tag" field of the element.
+func (e MapElement) Tag() string {
+	tag, _ := e["tag"].(string)
+	return tag
+}
+
+// MarkdownElement is a markdown text element.
+type MarkdownElement struct {
+	Content string `json:"content"`
+	// TextAlign is "left", "center" or "right".
+	TextAlign string `json:"text_align,omitempty"`
+	// TextSize is e.g. "heading", "normal" or "notation".
+	TextSize string `json:"text_size,omitempty"`
+	// ElementID identifies the element for updates of card entities, see
+	// APIClient.StreamCard.
+	ElementID string `json:"element_id,omitempty"`
+}
+
+// Tag returns "markdown".
+func (e *MarkdownElement) Tag() string {
+	return "markdown"
+}
+
+// MarshalJSON encodes the element including its tag.
+func (e *MarkdownElement) MarshalJSON() ([]byte, error) {
+	type alias MarkdownElement
+	return marshalTagged(e.Tag(), (*alias)(e))
+}
+
+// HrElement is a divider element.
+type HrElement struct{}
+
+// Tag returns "hr".
+func (e *HrElement) Tag() string {
+	return "hr"
+}
+
+// MarshalJSON encodes the element including its tag.
+func (e *HrElement) MarshalJSON() ([]byte, error) {
+	return marshalTagged(e.Tag(), struct{}{})
+}
+
+// ButtonElement is a button element, see NewButtonElement.
+type ButtonElement struct {
+	Text *CardTitle `json:"text,omitempty"`
+	// Type is the button style, e.g. "default", "primary" or "danger".
+	Type string `json:"type,omitempty"`
+	URL  string `json:"url,omitempty"`
+	// ActionType is "form_submit" or "form_reset" for buttons in forms.
+	ActionType string `json:"action_type,omitempty"`
+	// Name identifies the button in callbacks and form submissions.
+	Name string     `json:"name,omitempty"`
+	Size ButtonSize `json:"size,omitempty"`
+	// Width is "default", "fill" or a fixed width such as "100px".
+	Width        string     `json:"width,omitempty"`
+	Icon         *CardIcon  `json:"icon,omitempty"`
+	Disabled     bool       `json:"disabled,omitempty"`
+	DisabledTips *CardTitle `json:"disabled_tips,omitempty"`
+}
+
+// Tag returns "button".
+func (e *ButtonElement) Tag() string {
+	return "button"
+}
+
+// MarshalJSON encodes the element including its tag.
+func (e *ButtonElement) MarshalJSON() ([]byte, error) {
+	type alias ButtonElement
+	return marshalTagged(e.Tag(), (*alias)(e))
+}
+
+// FormElement is a form container, see NewFormElement.
+type FormElement struct {
+	// Name identifies the form in callbacks.
+	Name     string        `json:"name"`
+	Elements []CardElement `json:"elements"`
+}
+
+// Tag returns "form".
+func (e *FormElement) Tag() string {
+	return "form"
+}
+
+// MarshalJSON encodes the form including its tag.
+func (e *FormElement) MarshalJSON() ([]byte, error) {
+	type alias FormElement
+	form := *(*alias)(e)
+	if form.Elements == nil {
+		form.Elements = []CardElement{}
+	}
+	return marshalTagged(e.Tag(), &form)
+}
+
+// UnmarshalJSON decodes the form, including its elements.
+func (e *FormElement) UnmarshalJSON(data []byte) error {
+	type alias FormElement
+	aux := struct {
+		*alias
+		Elements []json.RawMessage `json:"elements"`
+	}{alias: (*alias)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	elements, err := decodeCardElements(aux.Elements)
+	if err != nil {
+		return err
+	}
+	e.Elements = elements
+	return nil
+}
+
+// cardElementTypes maps element tags to constructors of their typed
+// representation, see RegisterCardElement.
+var cardElementTypes = map[string]func() CardElement{
+	"markdown":   func() CardElement { return &MarkdownElement{} },
+	"hr":         func() CardElement { return &HrElement{} },
+	"div":        func() CardElement { return &Div{} },
+	"column_set": func() CardElement { return &ColumnSet{} },
+	"column":     func() CardElement { return &Column{} },
+	"button":     func() CardElement { return &ButtonElement{} },
+	"form":       func() CardElement { return &FormElement{} },
+}
+
+// RegisterCardElement registers a typed representation for elements with the
+// given tag, used when decoding cards with ParseCard or DecodeCardElement.
+// newElement must return a pointer that JSON can be decoded into, and whose
+// JSON encoding includes the tag.
+//
+// RegisterCardElement is meant to be called from init functions; it must
+// not be called concurrently with decoding.
+func RegisterCardElement(tag string, newElement func() CardElement) {
+	cardElementTypes[tag] = newElement
+}
+
+// DecodeCardElement decodes the JSON of a single card element.
+//
+// Elements with a registered tag are decoded into their typed representation
+// unless that would lose fields, e.g. styling options the typed element does
+// not model; such elements and elements with unknown tags are decoded into a
+// MapElement.
+func DecodeCardElement(data []byte) (CardElement, error) {
+	var head struct {
+		Tag string `json:"tag"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("failed to decode card element: %w", err)
+	}
+
+	if newElement, ok := cardElementTypes[head.Tag]; ok {
+		element := newElement()
+		if err := json.Unmarshal(data, element); err == nil && encodesTo(element, data) {
+			return element, nil
+		}
+	}
+
+	var element MapElement
+	if err := json.Unmarshal(data, &element); err != nil {
+		return nil, fmt.Errorf("failed to decode card element: %w", err)
+	}
+	return element, nil
+}
+
+// decodeCardElements decodes a list of raw card elements. A nil list
+// results in nil.
+func decodeCardElements(raws []json.RawMessage) ([]CardElement, error) {
+	if raws == nil {
+		return nil, nil
+	}
+
+	elements := make([]CardElement, 0, len(raws))
+	for _, raw := range raws {
+		element, err := DecodeCardElement(raw)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+	return elements, nil
+}
+
+// encodesTo reports whether v encodes to JSON equivalent to data.
+func encodesTo(v interface{}, data []byte) bool {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+
+	var want, got interface{}
+	if err := json.Unmarshal(data, &want); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(encoded, &got); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// marshalTagged encodes v, which must encode to a JSON object, with a "tag"
+// field added in front.
+func marshalTagged(tag string, v interface{}) ([]byte, error) {
+	fields, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	tagField, err := json.Marshal(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"tag":`)
+	buf.Write(tagField)
+	if len(fields) > 2 {
+		buf.WriteByte(',')
+		buf.Write(fields[1:])
+	} else {
+		buf.WriteByte('}')
+	}
+	return buf.Bytes(), nil
+}
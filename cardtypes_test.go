@@ -0,0 +1,157 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCardElement(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want CardElement
+	}{
+		{
+			name: "markdown",
+			data: `{"tag":"markdown","content":"hello","text_align":"center"}`,
+			want: &MarkdownElement{Content: "hello", TextAlign: "center"},
+		},
+		{
+			name: "hr",
+			data: `{"tag":"hr"}`,
+			want: &HrElement{},
+		},
+		{
+			name: "div with extra",
+			data: `{"tag":"div","text":{"tag":"plain_text","content":"text"},"extra":{"tag":"button","type":"primary"}}`,
+			want: &Div{
+				Text:  NewCardTitle("text"),
+				Extra: &ButtonElement{Type: "primary"},
+			},
+		},
+		{
+			name: "column set",
+			data: `{"tag":"column_set","flex_mode":"bisect","columns":[{"tag":"column","width":"weighted","weight":2,"elements":[{"tag":"hr"}]}]}`,
+			want: &ColumnSet{
+				FlexMode: "bisect",
+				Columns: []*Column{
+					{Width: "weighted", Weight: 2, Elements: []CardElement{&HrElement{}}},
+				},
+			},
+		},
+		{
+			name: "button",
+			data: `{"tag":"button","text":{"tag":"plain_text","content":"Open"},"type":"primary","url":"https://example.com","width":"fill"}`,
+			want: &ButtonElement{
+				Text:  NewCardTitle("Open"),
+				Type:  "primary",
+				URL:   "https://example.com",
+				Width: "fill",
+			},
+		},
+		{
+			name: "form",
+			data: `{"tag":"form","name":"rollback","elements":[{"tag":"input","name":"reason"},{"tag":"button","type":"primary","action_type":"form_submit","name":"submit"}]}`,
+			want: &FormElement{
+				Name: "rollback",
+				Elements: []CardElement{
+					MapElement{"tag": "input", "name": "reason"},
+					&ButtonElement{Type: "primary", ActionType: "form_submit", Name: "submit"},
+				},
+			},
+		},
+		{
+			name: "unmodeled fields fall back to map",
+			data: `{"tag":"markdown","content":"hello","margin":"4px"}`,
+			want: MapElement{"tag": "markdown", "content": "hello", "margin": "4px"},
+		},
+		{
+			name: "styled div text keeps unknown fields",
+			data: `{"tag":"div","text":{"tag":"plain_text","content":"x","lines":2}}`,
+			want: &Div{Text: &CardTitle{
+				Tag:           "plain_text",
+				Content:       "x",
+				UnknownFields: UnknownFields{"lines": json.RawMessage(`2`)},
+			}},
+		},
+		{
+			name: "unknown tag",
+			data: `{"tag":"person","user_id":"ou_xxx"}`,
+			want: MapElement{"tag": "person", "user_id": "ou_xxx"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCardElement([]byte(tt.data))
+			require.NoError(t, err)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DecodeCardElement() mismatch (-want +got):\n%s", diff)
+			}
+
+			// Decoded elements encode to the original JSON
+			data, err := json.Marshal(got)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.data, string(data))
+		})
+	}
+
+	_, err := DecodeCardElement([]byte(`[]`))
+	require.Error(t, err)
+}
+
+type testPersonElement struct {
+	UserID string `json:"user_id"`
+}
+
+func (e *testPersonElement) Tag() string {
+	return "person"
+}
+
+func (e *testPersonElement) MarshalJSON() ([]byte, error) {
+	type alias testPersonElement
+	return marshalTagged(e.Tag(), (*alias)(e))
+}
+
+func TestRegisterCardElement(t *testing.T) {
+	RegisterCardElement("person", func() CardElement { return &testPersonElement{} })
+	defer delete(cardElementTypes, "person")
+
+	got, err := DecodeCardElement([]byte(`{"tag":"person","user_id":"ou_xxx"}`))
+	require.NoError(t, err)
+	require.Equal(t, &testPersonElement{UserID: "ou_xxx"}, got)
+}
+
+func TestCardElementTag(t *testing.T) {
+	elements := []CardElement{
+		NewMarkdownElement("x"),
+		NewHrCardElement(),
+		NewDivElement(NewCardTitle("x")),
+		NewColumnSet(NewColumn(1)),
+		NewColumn(1),
+		NewButtonElement("x", "default", ""),
+		NewFormElement("x"),
+		MapElement{},
+	}
+
+	var tags []string
+	for _, e := range elements {
+		tags = append(tags, e.Tag())
+	}
+	require.Equal(t, []string{"markdown", "hr", "div", "column_set", "column", "button", "form", ""}, tags)
+}
+
+func TestMarshalTagged(t *testing.T) {
+	data, err := marshalTagged("hr", struct{}{})
+	require.NoError(t, err)
+	require.Equal(t, `{"tag":"hr"}`, string(data))
+
+	data, err = marshalTagged("markdown", struct {
+		Content string `json:"content"`
+	}{"x"})
+	require.NoError(t, err)
+	require.Equal(t, `{"tag":"markdown","content":"x"}`, string(data))
+}
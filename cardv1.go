@@ -0,0 +1,206 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ToV1Map converts the card to the schema 1.0 card format, which has no
+// schema field and keeps the elements at the top level instead of in a body.
+// It is meant for tenants and tools that still expect the old format;
+// layout settings of the body such as direction and padding have no
+// equivalent in schema 1.0 and are dropped.
+//
+// Example:
+//
+//	message := feishubot.NewInteractiveMessageFromMap(card.ToV1Map())
+func (c *Card) ToV1Map() map[string]interface{} {
+	elements := []CardElement{}
+	if c.Body != nil && c.Body.Elements != nil {
+		elements = c.Body.Elements
+	}
+
+	result := map[string]interface{}{
+		"elements": elements,
+	}
+
+	if c.Config != nil {
+		result["config"] = c.Config
+	}
+	if header := c.header(); header != nil {
+		result["header"] = header
+	}
+	if len(c.I18nHeader) > 0 {
+		result["i18n_header"] = c.I18nHeader
+	}
+	if len(c.I18nElements) > 0 {
+		result["i18n_elements"] = c.I18nElements
+	}
+	addUnknown(result, c.UnknownFields)
+
+	return result
+}
+
+// isCardSchemaV1 reports whether schema identifies a schema 1.0 card, which
+// usually has no schema field at all.
+func isCardSchemaV1(schema string) bool {
+	return schema == "" || schema == "1.0"
+}
+
+// convertV1Elements converts schema 1.0 elements to their schema 2.0
+// equivalents, including elements nested in containers such as column sets,
+// collapsible panels and forms. The note element, which was removed in
+// schema 2.0, is replaced by a markdown element with notation sized text,
+// and action modules by column sets of their buttons, see NewButtonGroup.
+func convertV1Elements(elements []CardElement) []CardElement {
+	if elements == nil {
+		return nil
+	}
+	result := make([]CardElement, 0, len(elements))
+	for _, element := range elements {
+		result = append(result, convertV1Element(element)...)
+	}
+	return result
+}
+
+// containerTags are the tags of elements holding elements in their
+// "elements" field, such as collapsible panels, for elements decoded as a
+// MapElement.
+var containerTags = map[string]bool{
+	"column":                true,
+	"collapsible_panel":     true,
+	"form":                  true,
+	"interactive_container": true,
+}
+
+// convertV1Element converts a schema 1.0 element, returning the elements
+// replacing it.
+func convertV1Element(element CardElement) []CardElement {
+	switch e := element.(type) {
+	case *ColumnSet:
+		for _, column := range e.Columns {
+			column.Elements = convertV1Elements(column.Elements)
+		}
+	case *Column:
+		e.Elements = convertV1Elements(e.Elements)
+	case *FormElement:
+		e.Elements = convertV1Elements(e.Elements)
+	case MapElement:
+		switch tag := e.Tag(); {
+		case tag == "note":
+			return []CardElement{convertV1Note(e)}
+		case tag == "action":
+			return convertV1Action(e)
+		case tag == "column_set":
+			columns, _ := e["columns"].([]interface{})
+			for _, column := range columns {
+				if column, ok := column.(map[string]interface{}); ok {
+					convertV1Children(column, "elements")
+				}
+			}
+		case containerTags[tag]:
+			convertV1Children(e, "elements")
+		}
+	}
+	return []CardElement{element}
+}
+
+// convertV1Children converts the elements held in the field of an element
+// decoded as a map.
+func convertV1Children(element map[string]interface{}, field string) {
+	children := mapElements(element[field])
+	if children == nil {
+		return
+	}
+	converted := convertV1Elements(children)
+	result := make([]interface{}, len(converted))
+	for i, child := range converted {
+		result[i] = child
+	}
+	element[field] = result
+}
+
+// mapElements returns the elements of a list decoded from JSON, or built
+// with NewActionElement, nil if it is not a list.
+func mapElements(value interface{}) []CardElement {
+	switch list := value.(type) {
+	case []CardElement:
+		return list
+	case []interface{}:
+		elements := make([]CardElement, 0, len(list))
+		for _, item := range list {
+			switch item := item.(type) {
+			case map[string]interface{}:
+				elements = append(elements, MapElement(item))
+			case CardElement:
+				elements = append(elements, item)
+			}
+		}
+		return elements
+	}
+	return nil
+}
+
+// convertV1Action converts a schema 1.0 action module into column sets of
+// its elements, keeping the number of elements per row of its layout.
+func convertV1Action(action MapElement) []CardElement {
+	elements := mapElements(action["actions"])
+	actions := make([]CardElement, len(elements))
+	for i, element := range elements {
+		actions[i] = typedElement(element)
+	}
+	perRow := len(actions)
+	switch layout, _ := action["layout"].(string); ActionLayout(layout) {
+	case ActionLayoutBisected:
+		perRow = 2
+	case ActionLayoutTrisected:
+		perRow = 3
+	}
+	return NewButtonGroup(perRow, actions...)
+}
+
+// typedElement returns the typed representation of an element held as a
+// MapElement, such as a button of a parsed action module, see
+// DecodeCardElement. Other elements are returned unchanged.
+func typedElement(element CardElement) CardElement {
+	m, ok := element.(MapElement)
+	if !ok {
+		return element
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return element
+	}
+	typed, err := DecodeCardElement(data)
+	if err != nil {
+		return element
+	}
+	return typed
+}
+
+// convertV1Note converts a schema 1.0 note element into a markdown element
+// joining the texts of the note. Plain texts are escaped, lark_md texts are
+// kept as they are, and images in the note are dropped.
+func convertV1Note(note MapElement) CardElement {
+	var texts []string
+	children, _ := note["elements"].([]interface{})
+	for _, child := range children {
+		child, ok := child.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		content, ok := child["content"].(string)
+		if !ok {
+			continue
+		}
+		if child["tag"] == "plain_text" {
+			content = EscapeMarkdown(content)
+		}
+		texts = append(texts, content)
+	}
+
+	return &MarkdownElement{
+		Content:  strings.Join(texts, " "),
+		TextSize: "notation",
+	}
+}
@@ -0,0 +1,117 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCardV1(t *testing.T) {
+	data := []byte(`{
+		"config": {"wide_screen_mode": true},
+		"header": {"title": {"tag": "plain_text", "content": "Deploy"}},
+		"elements": [
+			{"tag": "div", "text": {"tag": "lark_md", "content": "details"}},
+			{"tag": "note", "elements": [
+				{"tag": "plain_text", "content": "triggered by *bot*"},
+				{"tag": "img", "img_key": "img_xxx"},
+				{"tag": "lark_md", "content": "**ci**"}
+			]}
+		]
+	}`)
+
+	card, err := ParseCard(data)
+	require.NoError(t, err)
+
+	require.Equal(t, "2.0", card.Schema)
+	require.Equal(t, "Deploy", card.Header.Title.Content)
+	require.Equal(t, []CardElement{
+		&Div{Text: &CardTitle{Tag: "lark_md", Content: "details"}},
+		&MarkdownElement{Content: "triggered by &#42;bot&#42; **ci**", TextSize: "notation"},
+	}, card.Body.Elements)
+}
+
+func TestCardToV1Map(t *testing.T) {
+	card := NewCard("2.0").
+		SetConfig(map[string]any{"wide_screen_mode": true}).
+		SetHeader(&CardHeader{Title: NewCardTitle("Title")}).
+		SetBody(&CardBody{
+			Padding:  "12px",
+			Elements: []CardElement{NewMarkdownElement("Hello")},
+		})
+
+	got, err := json.Marshal(card.ToV1Map())
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"config": {"wide_screen_mode": true},
+		"header": {"title": {"tag": "plain_text", "content": "Title"}},
+		"elements": [{"tag": "markdown", "content": "Hello"}]
+	}`, string(got))
+
+	// Round trip through ParseCard
+	parsed, err := ParseCard(got)
+	require.NoError(t, err)
+	require.Equal(t, card.Body.Elements, parsed.Body.Elements)
+
+	got, err = json.Marshal(NewCard("2.0").ToV1Map())
+	require.NoError(t, err)
+	require.JSONEq(t, `{"elements": []}`, string(got))
+}
+
+func TestParseCardV1Nested(t *testing.T) {
+	data := []byte(`{
+		"elements": [
+			{"tag": "action", "layout": "bisected", "actions": [
+				{"tag": "button", "text": {"tag": "plain_text", "content": "Logs"}, "type": "default", "url": "https://example.com/logs"},
+				{"tag": "button", "text": {"tag": "plain_text", "content": "Metrics"}, "type": "default", "url": "https://example.com/metrics"},
+				{"tag": "button", "text": {"tag": "plain_text", "content": "Rollback"}, "type": "danger", "url": "https://example.com/rollback"}
+			]},
+			{"tag": "column_set", "flex_mode": "none", "background_style": "grey", "columns": [
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "note", "elements": [{"tag": "plain_text", "content": "in a column"}]}
+				]}
+			]},
+			{"tag": "collapsible_panel", "expanded": false, "header": {"title": {"tag": "plain_text", "content": "More"}}, "elements": [
+				{"tag": "action", "actions": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Open"}, "type": "primary", "url": "https://example.com"}
+				]}
+			]}
+		]
+	}`)
+
+	card, err := ParseCard(data)
+	require.NoError(t, err)
+	require.Empty(t, card.Lint())
+
+	got, err := json.Marshal(card.Body.Elements)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"tag": "column_set", "columns": [
+			{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+				{"tag": "button", "text": {"tag": "plain_text", "content": "Logs"}, "type": "default", "url": "https://example.com/logs", "width": "fill"}
+			]},
+			{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+				{"tag": "button", "text": {"tag": "plain_text", "content": "Metrics"}, "type": "default", "url": "https://example.com/metrics", "width": "fill"}
+			]}
+		]},
+		{"tag": "column_set", "columns": [
+			{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+				{"tag": "button", "text": {"tag": "plain_text", "content": "Rollback"}, "type": "danger", "url": "https://example.com/rollback", "width": "fill"}
+			]},
+			{"tag": "column", "width": "weighted", "weight": 1, "elements": []}
+		]},
+		{"tag": "column_set", "flex_mode": "none", "background_style": "grey", "columns": [
+			{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+				{"tag": "markdown", "content": "in a column", "text_size": "notation"}
+			]}
+		]},
+		{"tag": "collapsible_panel", "expanded": false, "header": {"title": {"tag": "plain_text", "content": "More"}}, "elements": [
+			{"tag": "column_set", "columns": [
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Open"}, "type": "primary", "url": "https://example.com", "width": "fill"}
+				]}
+			]}
+		]}
+	]`, string(got))
+}
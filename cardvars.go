@@ -0,0 +1,150 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrMissingCardVariable is returned by SubstituteCardVariables when the
+// card references a variable that has no value.
+var ErrMissingCardVariable = errors.New("missing card variable")
+
+// cardVariablePattern matches ${name} placeholders.
+var cardVariablePattern = regexp.MustCompile(`\$\{([^{}]+)\}`)
+
+// SubstituteCardVariables replaces ${name} placeholders in card JSON, as
+// produced by the Feishu card builder tool, with the given values and
+// returns the card for NewInteractiveMessageFromMap.
+//
+// Substitution is type-aware:
+//   - A string that consists only of a placeholder is replaced by the value
+//     itself, so numbers, booleans, objects and arrays keep their JSON type.
+//   - Inside an array, such a placeholder with a slice value is expanded into
+//     the slice items, e.g. to repeat elements once per row.
+//   - A placeholder embedded in a longer string is replaced by the value's
+//     text: strings as-is, other values as JSON.
+//
+// Values may be of any type that can be marshaled to JSON. A placeholder
+// without a value results in an error wrapping ErrMissingCardVariable.
+//
+// Example:
+//
+//	card, err := feishubot.SubstituteCardVariables(templateJSON, map[string]any{
+//		"service": "api-gateway",
+//		"replicas": 3,
+//		"rows": []map[string]any{{"tag": "markdown", "content": "node-1 ok"}},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	message := feishubot.NewInteractiveMessageFromMap(card)
+func SubstituteCardVariables(data []byte, vars map[string]interface{}) (map[string]interface{}, error) {
+	var card map[string]interface{}
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, fmt.Errorf("failed to parse card: %w", err)
+	}
+
+	// Normalize the values to their JSON representation so that structs and
+	// typed slices are handled like the decoded card.
+	encoded, err := json.Marshal(vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal card variables: %w", err)
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(encoded, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal card variables: %w", err)
+	}
+
+	result, err := substituteValue(card, values)
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[string]interface{}), nil
+}
+
+// substituteValue replaces placeholders in v.
+func substituteValue(v interface{}, values map[string]interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			substituted, err := substituteValue(item, values)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = substituted
+		}
+		return result, nil
+
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if name, ok := wholePlaceholder(item); ok {
+				if items, ok := values[name].([]interface{}); ok {
+					result = append(result, items...)
+					continue
+				}
+			}
+			substituted, err := substituteValue(item, values)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, substituted)
+		}
+		return result, nil
+
+	case string:
+		if name, ok := wholePlaceholder(v); ok {
+			value, ok := values[name]
+			if !ok {
+				return nil, fmt.Errorf("%w %q", ErrMissingCardVariable, name)
+			}
+			return value, nil
+		}
+		return substituteString(v, values)
+	}
+
+	return v, nil
+}
+
+// substituteString replaces placeholders embedded in s with the text of
+// their values.
+func substituteString(s string, values map[string]interface{}) (string, error) {
+	var err error
+	result := cardVariablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := strings.TrimSpace(match[2 : len(match)-1])
+		value, ok := values[name]
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("%w %q", ErrMissingCardVariable, name)
+			}
+			return match
+		}
+		if text, ok := value.(string); ok {
+			return text
+		}
+		encoded, _ := json.Marshal(value)
+		return string(encoded)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// wholePlaceholder reports whether v is a string consisting of a single
+// placeholder, returning the variable name.
+func wholePlaceholder(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return "", false
+	}
+	loc := cardVariablePattern.FindStringSubmatchIndex(s)
+	if loc == nil || loc[0] != 0 || loc[1] != len(s) {
+		return "", false
+	}
+	return strings.TrimSpace(s[loc[2]:loc[3]]), true
+}
@@ -0,0 +1,111 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstituteCardVariables(t *testing.T) {
+	type row struct {
+		Tag     string `json:"tag"`
+		Content string `json:"content"`
+	}
+
+	tests := []struct {
+		name string
+		card string
+		vars map[string]any
+		want string
+	}{
+		{
+			name: "string",
+			card: `{"header":{"title":{"tag":"plain_text","content":"${title}"}}}`,
+			vars: map[string]any{"title": "Deploy"},
+			want: `{"header":{"title":{"tag":"plain_text","content":"Deploy"}}}`,
+		},
+		{
+			name: "typed values",
+			card: `{"config":{"update_multi":"${multi}"},"body":{"elements":[{"tag":"chart","chart_spec":{"data":"${data}"},"height":"${ height }"}]}}`,
+			vars: map[string]any{
+				"multi":  true,
+				"data":   map[string]any{"values": []int{1, 2}},
+				"height": 240,
+			},
+			want: `{"config":{"update_multi":true},"body":{"elements":[{"tag":"chart","chart_spec":{"data":{"values":[1,2]}},"height":240}]}}`,
+		},
+		{
+			name: "embedded in text",
+			card: `{"body":{"elements":[{"tag":"markdown","content":"**${service}** has ${replicas} replicas"}]}}`,
+			vars: map[string]any{"service": "api", "replicas": 3},
+			want: `{"body":{"elements":[{"tag":"markdown","content":"**api** has 3 replicas"}]}}`,
+		},
+		{
+			name: "array expanded",
+			card: `{"body":{"elements":[{"tag":"hr"},"${rows}",{"tag":"hr"}]}}`,
+			vars: map[string]any{"rows": []row{
+				{Tag: "markdown", Content: "node-1"},
+				{Tag: "markdown", Content: "node-2"},
+			}},
+			want: `{"body":{"elements":[{"tag":"hr"},{"tag":"markdown","content":"node-1"},{"tag":"markdown","content":"node-2"},{"tag":"hr"}]}}`,
+		},
+		{
+			name: "array as value",
+			card: `{"body":{"elements":"${rows}"}}`,
+			vars: map[string]any{"rows": []row{{Tag: "markdown", Content: "node-1"}}},
+			want: `{"body":{"elements":[{"tag":"markdown","content":"node-1"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SubstituteCardVariables([]byte(tt.card), tt.vars)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(got)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestSubstituteCardVariablesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		card    string
+		vars    map[string]any
+		wantErr error
+	}{
+		{
+			name: "invalid json",
+			card: `{"body":`,
+		},
+		{
+			name:    "missing whole value",
+			card:    `{"title":"${title}"}`,
+			wantErr: ErrMissingCardVariable,
+		},
+		{
+			name:    "missing embedded value",
+			card:    `{"title":"Deploy ${service}"}`,
+			wantErr: ErrMissingCardVariable,
+		},
+		{
+			name: "unmarshalable value",
+			card: `{}`,
+			vars: map[string]any{"f": func() {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SubstituteCardVariables([]byte(tt.card), tt.vars)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr))
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// walkCard calls fn for every element of card, i.e. every JSON object with a
+// "tag" field, together with its JSON path such as "body.elements[2]".
+//
+// The card may be any value that marshals to a card JSON object, such as
+// the result of Card.ToMap. Objects are visited in document order, with
+// object keys visited in sorted order for deterministic paths.
+func walkCard(card interface{}, fn func(path string, elem map[string]interface{})) error {
+	data, err := json.Marshal(card)
+	if err != nil {
+		return fmt.Errorf("failed to marshal card: %w", err)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to unmarshal card: %w", err)
+	}
+
+	walkValue("", doc, fn)
+	return nil
+}
+
+// walkValue recursively visits v, calling fn for objects with a "tag".
+func walkValue(path string, v interface{}, fn func(path string, elem map[string]interface{})) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if _, ok := v["tag"].(string); ok && path != "" {
+			fn(path, v)
+		}
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			walkValue(childPath, v[k], fn)
+		}
+
+	case []interface{}:
+		for i, item := range v {
+			walkValue(fmt.Sprintf("%s[%d]", path, i), item, fn)
+		}
+	}
+}
+
+// callbackElementTags are card element tags that only work when the card
+// sender can receive interaction callbacks, which custom bot webhooks cannot.
+var callbackElementTags = map[string]bool{
+	"select_person":       true,
+	"multi_select_person": true,
+	"date_picker":         true,
+	"picker_time":         true,
+	"picker_datetime":     true,
+	"checker":             true,
+	"form":                true,
+	"input":               true,
+}
+
+// WebhookWarnings returns warnings for parts of an interactive message that
+// do not work when sent through a custom bot webhook, such as elements that
+// rely on interaction callbacks. The card can still be sent, but users will
+// not be able to interact with those elements.
+//
+// Client.Send reports these warnings through Client.WarningHandler.
+func (m *Message) WebhookWarnings() []string {
+	card := m.card()
+	if m.MsgType != MsgTypeInteractive || card == nil {
+		return nil
+	}
+
+	var warnings []string
+	var reported []string
+	err := walkCard(card, func(path string, elem map[string]interface{}) {
+		tag := elem["tag"].(string)
+		if !callbackElementTags[tag] {
+			return
+		}
+		// Elements inside an already reported container such as a form
+		// are covered by the container's warning.
+		if hasPathPrefix(path, reported) {
+			return
+		}
+
+		reported = append(reported, path)
+		warnings = append(warnings, fmt.Sprintf(
+			"card element %q at %s requires interaction callbacks, which custom bot webhooks do not support",
+			tag, path,
+		))
+	})
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	return warnings
+}
@@ -0,0 +1,74 @@
+package feishubot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkCard(t *testing.T) {
+	card := NewCard("2.0").
+		SetHeader(&CardHeader{Title: NewCardTitle("Title")}).
+		SetBody(&CardBody{
+			Elements: []CardElement{
+				NewMarkdownElement("Hello"),
+				NewColumnSet(
+					NewColumn(1, NewHrCardElement()),
+				).ToElement(),
+			},
+		})
+
+	var paths []string
+	var tags []string
+	err := walkCard(card.ToMap(), func(path string, elem map[string]interface{}) {
+		paths = append(paths, path)
+		tags = append(tags, elem["tag"].(string))
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"body.elements[0]",
+		"body.elements[1]",
+		"body.elements[1].columns[0]",
+		"body.elements[1].columns[0].elements[0]",
+		"header.title",
+	}, paths)
+	require.Equal(t, []string{"markdown", "column_set", "column", "hr", "plain_text"}, tags)
+}
+
+func TestMessageWebhookWarnings(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *Message
+		want    []string
+	}{
+		{
+			name:    "text message",
+			message: NewTextMessage("hello"),
+		},
+		{
+			name: "card without callback elements",
+			message: NewInteractiveMessage(NewCard("2.0").SetBody(&CardBody{
+				Elements: []CardElement{NewButtonElement("Open", "primary", "https://example.com")},
+			})),
+		},
+		{
+			name: "card with person picker",
+			message: NewInteractiveMessage(NewCard("2.0").SetBody(&CardBody{
+				Elements: []CardElement{
+					NewMarkdownElement("Pick an assignee"),
+					NewSelectPersonElement("Assignee", nil),
+				},
+			})),
+			want: []string{
+				`card element "select_person" at body.elements[1] requires interaction callbacks, which custom bot webhooks do not support`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.message.WebhookWarnings())
+		})
+	}
+}
@@ -0,0 +1,339 @@
+// Package certmon warns about TLS certificates close to expiry. It probes
+// TLS endpoints and parses PEM files, and posts a card listing the
+// certificates expiring within a number of days and the checks that failed.
+//
+// Example, checking once:
+//
+//	monitor := certmon.New(feishubot.WebhookSender(feishubot.NewClient(webhookURL, secret)),
+//		certmon.Target{Address: "api.example.com:443"},
+//		certmon.Target{Name: "ingress", File: "/etc/ingress/tls.crt"},
+//	)
+//	monitor.WarnDays = 21
+//	err := monitor.Run(ctx)
+//
+// Example, checking daily with the schedule package:
+//
+//	scheduler.Add(schedule.Job{
+//		Name:    "certificates",
+//		Cron:    "0 9 * * *",
+//		Message: monitor.Message,
+//	})
+package certmon
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/schedule"
+)
+
+// Defaults of Monitor.
+const (
+	DefaultWarnDays     = 30
+	DefaultCriticalDays = 7
+	DefaultTimeout      = 10 * time.Second
+	DefaultTitle        = "Certificates expiring"
+)
+
+// Target is a TLS endpoint or PEM file to check. Exactly one of Address
+// and File is set.
+type Target struct {
+	// Name is shown in cards. If empty, Address or File is shown.
+	Name string
+	// Address is the "host:port" of a TLS endpoint.
+	Address string
+	// ServerName is the name sent with SNI. If empty, the host of Address
+	// is used.
+	ServerName string
+	// File is the path of a PEM file holding one or more certificates.
+	File string
+}
+
+// String returns the name of the target.
+func (t Target) String() string {
+	switch {
+	case t.Name != "":
+		return t.Name
+	case t.Address != "":
+		return t.Address
+	}
+	return t.File
+}
+
+// Result is the result of checking a target.
+type Result struct {
+	Target Target
+	// Certificate is the certificate expiring first among the certificate
+	// chain of the endpoint or the certificates of the file, nil if the
+	// check failed.
+	Certificate *x509.Certificate
+	// DaysLeft is the number of whole days until the certificate expires,
+	// negative if it has expired.
+	DaysLeft int
+	Err      error
+}
+
+// Monitor checks the certificates of targets.
+type Monitor struct {
+	send    feishubot.SendFunc
+	Targets []Target
+
+	// WarnDays is the number of days before expiry certificates are
+	// reported. If zero, DefaultWarnDays is used.
+	WarnDays int
+
+	// CriticalDays is the number of days before expiry from which the card
+	// is red instead of orange. If zero, DefaultCriticalDays is used.
+	CriticalDays int
+
+	// Timeout bounds probing an endpoint, and checking all targets and
+	// sending the card in Message. If zero, DefaultTimeout is used.
+	Timeout time.Duration
+
+	// Title is the title of the cards. If empty, DefaultTitle is used.
+	Title string
+
+	// TLSConfig, if set, is the base configuration of probes. Certificates
+	// are not verified, so that expired certificates can be reported.
+	TLSConfig *tls.Config
+
+	now func() time.Time
+}
+
+// New creates a monitor checking targets and posting warnings with send.
+func New(send feishubot.SendFunc, targets ...Target) *Monitor {
+	return &Monitor{send: send, Targets: targets, now: time.Now}
+}
+
+// Check checks all targets concurrently and returns their results in the
+// order of the targets.
+func (m *Monitor) Check(ctx context.Context) []Result {
+	results := make([]Result, len(m.Targets))
+	var wg sync.WaitGroup
+	for i, target := range m.Targets {
+		wg.Add(1)
+		go func(i int, target Target) {
+			defer wg.Done()
+			results[i] = m.check(ctx, target)
+		}(i, target)
+	}
+	wg.Wait()
+	return results
+}
+
+// Run checks all targets and posts a card if certificates expire within
+// WarnDays or checks failed.
+func (m *Monitor) Run(ctx context.Context) error {
+	card := m.Card(m.Check(ctx))
+	if card == nil {
+		return nil
+	}
+	if err := m.send(ctx, feishubot.NewInteractiveMessage(card)); err != nil {
+		return fmt.Errorf("failed to post certificate warnings: %w", err)
+	}
+	return nil
+}
+
+// Message is a schedule.MessageFunc checking all targets, returning the
+// card of Card as a message, or nil if there is nothing to report.
+func (m *Monitor) Message(schedule.Run) (*feishubot.Message, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout())
+	defer cancel()
+
+	card := m.Card(m.Check(ctx))
+	if card == nil {
+		return nil, nil
+	}
+	return feishubot.NewInteractiveMessage(card), nil
+}
+
+// Card renders the results of certificates expiring within WarnDays and of
+// failed checks, or returns nil if there are none. Certificates expiring
+// first are listed first.
+func (m *Monitor) Card(results []Result) *feishubot.Card {
+	warnDays, criticalDays := m.WarnDays, m.CriticalDays
+	if warnDays <= 0 {
+		warnDays = DefaultWarnDays
+	}
+	if criticalDays <= 0 {
+		criticalDays = DefaultCriticalDays
+	}
+
+	var expiring, failed []Result
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed = append(failed, r)
+		case r.DaysLeft <= warnDays:
+			expiring = append(expiring, r)
+		}
+	}
+	if len(expiring) == 0 && len(failed) == 0 {
+		return nil
+	}
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].Certificate.NotAfter.Before(expiring[j].Certificate.NotAfter)
+	})
+
+	template := feishubot.TemplateOrange
+	if len(expiring) > 0 && expiring[0].DaysLeft <= criticalDays {
+		template = feishubot.TemplateRed
+	}
+	title := m.Title
+	if title == "" {
+		title = DefaultTitle
+	}
+	builder := feishubot.NewCardBuilder().
+		Header(title, template).
+		Subtitle(fmt.Sprintf("%d expiring within %d days, %d failed checks", len(expiring), warnDays, len(failed)))
+
+	for _, r := range expiring {
+		cert := r.Certificate
+		builder.Markdown(fmt.Sprintf("**%s** %s (%s)\n%s", feishubot.Sanitize(r.Target.String()),
+			expiry(r.DaysLeft), cert.NotAfter.UTC().Format("2006-01-02 15:04 MST"), describe(cert)))
+	}
+	if len(failed) > 0 {
+		if len(expiring) > 0 {
+			builder.Divider()
+		}
+		for _, r := range failed {
+			builder.Markdown(fmt.Sprintf("**%s** ❌ %s", feishubot.Sanitize(r.Target.String()),
+				feishubot.Sanitize(r.Err.Error())))
+		}
+	}
+	return builder.Build()
+}
+
+// check checks a target.
+func (m *Monitor) check(ctx context.Context, target Target) Result {
+	var (
+		certs []*x509.Certificate
+		err   error
+	)
+	switch {
+	case target.Address != "" && target.File != "":
+		err = errors.New("target has both an address and a file")
+	case target.Address != "":
+		certs, err = m.probe(ctx, target)
+	case target.File != "":
+		certs, err = readCertificates(target.File)
+	default:
+		err = errors.New("target has no address or file")
+	}
+	if err != nil {
+		return Result{Target: target, Err: err}
+	}
+
+	first := certs[0]
+	for _, cert := range certs[1:] {
+		if cert.NotAfter.Before(first.NotAfter) {
+			first = cert
+		}
+	}
+	return Result{
+		Target:      target,
+		Certificate: first,
+		DaysLeft:    int(first.NotAfter.Sub(m.now()).Hours() / 24),
+	}
+}
+
+// probe returns the certificate chain presented by an endpoint.
+func (m *Monitor) probe(ctx context.Context, target Target) ([]*x509.Certificate, error) {
+	config := &tls.Config{}
+	if m.TLSConfig != nil {
+		config = m.TLSConfig.Clone()
+	}
+	config.InsecureSkipVerify = true
+	config.ServerName = target.ServerName
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(target.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address: %w", err)
+		}
+		config.ServerName = host
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.timeout())
+	defer cancel()
+	dialer := &tls.Dialer{Config: config}
+	conn, err := dialer.DialContext(ctx, "tcp", target.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate presented")
+	}
+	return certs, nil
+}
+
+// timeout returns the timeout of probes.
+func (m *Monitor) timeout() time.Duration {
+	if m.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return m.Timeout
+}
+
+// readCertificates parses the certificates of a PEM file.
+func readCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificates: %w", err)
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	return certs, nil
+}
+
+// expiry describes the days left until a certificate expires.
+func expiry(daysLeft int) string {
+	switch {
+	case daysLeft < 0:
+		return fmt.Sprintf("🔴 expired %d days ago", -daysLeft)
+	case daysLeft == 0:
+		return "🔴 expires today"
+	case daysLeft == 1:
+		return "🟠 expires tomorrow"
+	}
+	return fmt.Sprintf("🟠 expires in %d days", daysLeft)
+}
+
+// describe returns the subject and issuer of a certificate.
+func describe(cert *x509.Certificate) string {
+	subject := cert.Subject.CommonName
+	if subject == "" && len(cert.DNSNames) > 0 {
+		subject = cert.DNSNames[0]
+	}
+	return feishubot.Sanitize(fmt.Sprintf("%s, issued by %s", subject, cert.Issuer.CommonName))
+}
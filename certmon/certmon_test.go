@@ -0,0 +1,168 @@
+package certmon
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/schedule"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+// writeCertificates writes a PEM file of self-signed certificates expiring
+// at the given times.
+func writeCertificates(t *testing.T, notAfter ...time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var data []byte
+	for i, expiry := range notAfter {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 1)),
+			Subject:      pkix.Name{CommonName: "example.com"},
+			Issuer:       pkix.Name{CommonName: "example.com"},
+			NotBefore:    expiry.AddDate(-1, 0, 0),
+			NotAfter:     expiry,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		require.NoError(t, err)
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+
+	path := filepath.Join(t.TempDir(), "tls.crt")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// newMonitor returns a monitor at now recording the messages it sends.
+func newMonitor(sent *[]*feishubot.Message, targets ...Target) *Monitor {
+	monitor := New(func(_ context.Context, msg *feishubot.Message) error {
+		*sent = append(*sent, msg)
+		return nil
+	}, targets...)
+	monitor.now = func() time.Time { return now }
+	return monitor
+}
+
+// cardJSON returns the JSON of a card.
+func cardJSON(t *testing.T, card *feishubot.Card) string {
+	t.Helper()
+	data, err := json.Marshal(card)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCheckFile(t *testing.T) {
+	path := writeCertificates(t, now.AddDate(0, 0, 90), now.AddDate(0, 0, 12).Add(time.Hour))
+
+	var sent []*feishubot.Message
+	results := newMonitor(&sent, Target{Name: "ingress", File: path}).Check(context.Background())
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, 12, results[0].DaysLeft)
+	require.Equal(t, "example.com", results[0].Certificate.Subject.CommonName)
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "https://")
+
+	var sent []*feishubot.Message
+	monitor := newMonitor(&sent, Target{Address: address})
+	expiry := server.Certificate().NotAfter
+	monitor.now = func() time.Time { return expiry.AddDate(0, 0, -5).Add(-time.Hour) }
+
+	results := monitor.Check(context.Background())
+	require.NoError(t, results[0].Err)
+	require.Equal(t, 5, results[0].DaysLeft)
+
+	require.NoError(t, monitor.Run(context.Background()))
+	require.Len(t, sent, 1)
+	data := cardJSON(t, sent[0].TypedCard)
+	require.Contains(t, data, address)
+	require.Contains(t, data, "expires in 5 days")
+	require.Contains(t, data, `"template":"red"`)
+}
+
+func TestCheckErrors(t *testing.T) {
+	var sent []*feishubot.Message
+	monitor := newMonitor(&sent,
+		Target{Name: "missing", File: filepath.Join(t.TempDir(), "missing.crt")},
+		Target{Name: "closed", Address: "127.0.0.1:1"},
+		Target{Name: "empty"},
+	)
+	monitor.Timeout = time.Second
+
+	results := monitor.Check(context.Background())
+	require.Len(t, results, 3)
+	require.ErrorIs(t, results[0].Err, os.ErrNotExist)
+	require.ErrorContains(t, results[1].Err, "failed to connect")
+	require.EqualError(t, results[2].Err, "target has no address or file")
+
+	data := cardJSON(t, monitor.Card(results))
+	require.Contains(t, data, "0 expiring within 30 days, 3 failed checks")
+	require.Contains(t, data, `"template":"orange"`)
+	require.Contains(t, data, "**empty** ❌ target has no address or file")
+}
+
+func TestCard(t *testing.T) {
+	results := []Result{
+		{Target: Target{Name: "ok"}, Certificate: &x509.Certificate{NotAfter: now.AddDate(0, 0, 60)}, DaysLeft: 60},
+		{Target: Target{Name: "soon"}, Certificate: &x509.Certificate{NotAfter: now.AddDate(0, 0, 20)}, DaysLeft: 20},
+		{Target: Target{Name: "expired"}, Certificate: &x509.Certificate{NotAfter: now.AddDate(0, 0, -2)}, DaysLeft: -2},
+	}
+
+	var sent []*feishubot.Message
+	monitor := newMonitor(&sent)
+	data := cardJSON(t, monitor.Card(results))
+	require.Contains(t, data, DefaultTitle)
+	require.Contains(t, data, `"template":"red"`)
+	require.Contains(t, data, "expired 2 days ago")
+	require.Contains(t, data, "expires in 20 days")
+	require.NotContains(t, data, "**ok**")
+	require.Less(t, strings.Index(data, "**expired**"), strings.Index(data, "**soon**"))
+
+	monitor.WarnDays = 10
+	monitor.CriticalDays = -1
+	require.NotContains(t, cardJSON(t, monitor.Card(results)), "**soon**")
+
+	require.Nil(t, monitor.Card(results[:1]))
+}
+
+func TestMessage(t *testing.T) {
+	var sent []*feishubot.Message
+	monitor := newMonitor(&sent, Target{File: writeCertificates(t, now.AddDate(1, 0, 0))})
+	msg, err := monitor.Message(schedule.Run{Job: "certificates"})
+	require.NoError(t, err)
+	require.Nil(t, msg)
+
+	monitor.Targets = append(monitor.Targets, Target{File: writeCertificates(t, now.AddDate(0, 0, 3))})
+	msg, err = monitor.Message(schedule.Run{Job: "certificates"})
+	require.NoError(t, err)
+	require.Equal(t, feishubot.MsgTypeInteractive, msg.MsgType)
+}
+
+func TestRunSendError(t *testing.T) {
+	monitor := New(func(context.Context, *feishubot.Message) error {
+		return errors.New("webhook down")
+	}, Target{Name: "empty"})
+	require.EqualError(t, monitor.Run(context.Background()), "failed to post certificate warnings: webhook down")
+}
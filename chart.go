@@ -0,0 +1,123 @@
+package feishubot
+
+// ChartPoint is a single data point: a category or x-axis label and a value.
+type ChartPoint struct {
+	X string
+	Y float64
+}
+
+// ChartSeries is a named series of data points.
+type ChartSeries struct {
+	Name   string
+	Points []ChartPoint
+}
+
+// NewChartElement creates a chart element from a VChart spec, such as one
+// returned by NewLineChartSpec, NewBarChartSpec or NewPieChartSpec.
+// See https://open.feishu.cn/document/uAjLw4CM/ukzMukzMukzM/feishu-cards/card-components/content-components/chart
+//
+// Example:
+//
+//	spec := feishubot.NewLineChartSpec("Requests", feishubot.ChartSeries{
+//		Name: "api",
+//		Points: []feishubot.ChartPoint{{X: "10:00", Y: 120}, {X: "11:00", Y: 180}},
+//	})
+//	chart := feishubot.NewChartElement(spec, feishubot.WithChartAspectRatio("16:9"))
+func NewChartElement(spec map[string]interface{}, opts ...ElementOption) CardElement {
+	element := MapElement{
+		"tag":        "chart",
+		"chart_spec": spec,
+	}
+
+	for _, opt := range opts {
+		opt(element)
+	}
+
+	return element
+}
+
+// WithChartAspectRatio sets the aspect ratio of a chart: "1:1", "2:1",
+// "4:3" or "16:9".
+func WithChartAspectRatio(ratio string) ElementOption {
+	return func(element MapElement) {
+		element["aspect_ratio"] = ratio
+	}
+}
+
+// WithChartColorTheme sets the color theme of a chart, e.g. "brand",
+// "rainbow", "complementary", "converse" or "primary".
+func WithChartColorTheme(theme string) ElementOption {
+	return func(element MapElement) {
+		element["color_theme"] = theme
+	}
+}
+
+// NewLineChartSpec creates a VChart spec for a line chart with one line per
+// series. The title is optional.
+func NewLineChartSpec(title string, series ...ChartSeries) map[string]interface{} {
+	spec := seriesChartSpec("line", title, series)
+	spec["xField"] = "x"
+	return spec
+}
+
+// NewBarChartSpec creates a VChart spec for a bar chart. Multiple series are
+// shown as grouped bars. The title is optional.
+func NewBarChartSpec(title string, series ...ChartSeries) map[string]interface{} {
+	spec := seriesChartSpec("bar", title, series)
+	spec["xField"] = []string{"x", "series"}
+	return spec
+}
+
+// NewPieChartSpec creates a VChart spec for a pie chart with one slice per
+// point, using X as the category. The title is optional.
+func NewPieChartSpec(title string, slices ...ChartPoint) map[string]interface{} {
+	values := make([]map[string]interface{}, 0, len(slices))
+	for _, p := range slices {
+		values = append(values, map[string]interface{}{
+			"category": p.X,
+			"value":    p.Y,
+		})
+	}
+
+	spec := map[string]interface{}{
+		"type":          "pie",
+		"data":          map[string]interface{}{"values": values},
+		"categoryField": "category",
+		"valueField":    "value",
+		"outerRadius":   0.8,
+		"label":         map[string]interface{}{"visible": true},
+		"legends":       map[string]interface{}{"visible": true},
+	}
+	if title != "" {
+		spec["title"] = map[string]interface{}{"text": title}
+	}
+
+	return spec
+}
+
+// seriesChartSpec creates the common part of line and bar chart specs.
+func seriesChartSpec(chartType, title string, series []ChartSeries) map[string]interface{} {
+	values := make([]map[string]interface{}, 0)
+	for _, s := range series {
+		for _, p := range s.Points {
+			values = append(values, map[string]interface{}{
+				"x":      p.X,
+				"y":      p.Y,
+				"series": s.Name,
+			})
+		}
+	}
+
+	spec := map[string]interface{}{
+		"type":        chartType,
+		"data":        map[string]interface{}{"values": values},
+		"yField":      "y",
+		"seriesField": "series",
+		"legends":     map[string]interface{}{"visible": len(series) > 1},
+	}
+	if title != "" {
+		spec["title"] = map[string]interface{}{"text": title}
+	}
+
+	return spec
+}
@@ -0,0 +1,94 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartSpecs(t *testing.T) {
+	series := []ChartSeries{
+		{Name: "api", Points: []ChartPoint{{X: "10:00", Y: 120}, {X: "11:00", Y: 180}}},
+		{Name: "web", Points: []ChartPoint{{X: "10:00", Y: 80}}},
+	}
+
+	tests := []struct {
+		name string
+		spec map[string]interface{}
+		want string
+	}{
+		{
+			name: "line chart",
+			spec: NewLineChartSpec("Requests", series...),
+			want: `{
+				"type": "line",
+				"title": {"text": "Requests"},
+				"data": {"values": [
+					{"x": "10:00", "y": 120, "series": "api"},
+					{"x": "11:00", "y": 180, "series": "api"},
+					{"x": "10:00", "y": 80, "series": "web"}
+				]},
+				"xField": "x",
+				"yField": "y",
+				"seriesField": "series",
+				"legends": {"visible": true}
+			}`,
+		},
+		{
+			name: "bar chart with single series and no title",
+			spec: NewBarChartSpec("", series[1]),
+			want: `{
+				"type": "bar",
+				"data": {"values": [{"x": "10:00", "y": 80, "series": "web"}]},
+				"xField": ["x", "series"],
+				"yField": "y",
+				"seriesField": "series",
+				"legends": {"visible": false}
+			}`,
+		},
+		{
+			name: "pie chart",
+			spec: NewPieChartSpec("Share", ChartPoint{X: "ok", Y: 9}, ChartPoint{X: "failed", Y: 1}),
+			want: `{
+				"type": "pie",
+				"title": {"text": "Share"},
+				"data": {"values": [{"category": "ok", "value": 9}, {"category": "failed", "value": 1}]},
+				"categoryField": "category",
+				"valueField": "value",
+				"outerRadius": 0.8,
+				"label": {"visible": true},
+				"legends": {"visible": true}
+			}`,
+		},
+		{
+			name: "empty line chart",
+			spec: NewLineChartSpec(""),
+			want: `{
+				"type": "line",
+				"data": {"values": []},
+				"xField": "x",
+				"yField": "y",
+				"seriesField": "series",
+				"legends": {"visible": false}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.spec)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestNewChartElement(t *testing.T) {
+	spec := map[string]interface{}{"type": "line"}
+	elem := NewChartElement(spec, WithChartAspectRatio("16:9"), WithChartColorTheme("brand"))
+
+	data, err := json.Marshal(elem)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"tag":"chart","chart_spec":{"type":"line"},"aspect_ratio":"16:9","color_theme":"brand"}`, string(data))
+}
@@ -0,0 +1,72 @@
+// Package ci posts CI build results to Feishu as pipeline cards (see
+// cards.Pipeline), from a generic build descriptor that CI systems are
+// mapped to, with ready-made mappings for Jenkins and Drone.
+package ci
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/cards"
+)
+
+// Build describes a CI build independently of the CI system.
+type Build struct {
+	// Job is the name of the job or repository, e.g. "api-gateway".
+	Job string
+	// Number is the build number, omitted from the card if zero.
+	Number int
+	Status cards.Status
+	// Stages are optional; see cards.PipelineRun.
+	Stages   []cards.Stage
+	Duration time.Duration
+	// Trigger describes what started the build, e.g. "push to main by alice".
+	Trigger string
+	Changes []cards.Change
+	// LogURL and RerunURL add buttons when set.
+	LogURL   string
+	RerunURL string
+}
+
+// Name returns the job with the build number, e.g. "api-gateway #123".
+func (b Build) Name() string {
+	if b.Number == 0 {
+		return b.Job
+	}
+	return fmt.Sprintf("%s #%d", b.Job, b.Number)
+}
+
+// Card renders the build as a pipeline card.
+func (b Build) Card() *feishubot.Card {
+	return cards.Pipeline(cards.PipelineRun{
+		Name:     b.Name(),
+		Status:   b.Status,
+		Stages:   b.Stages,
+		Duration: b.Duration,
+		Trigger:  b.Trigger,
+		Changes:  b.Changes,
+		LogsURL:  b.LogURL,
+		RerunURL: b.RerunURL,
+	})
+}
+
+// Notify posts the card of a build.
+//
+// Example:
+//
+//	err := ci.Notify(ctx, feishubot.WebhookSender(client), ci.Build{
+//		Job:      "api-gateway",
+//		Number:   123,
+//		Status:   cards.StatusFailed,
+//		Duration: 4 * time.Minute,
+//		Changes:  []cards.Change{{ID: sha, Message: "Fix retry loop", Author: "alice"}},
+//		LogURL:   "https://ci.example.com/api-gateway/123",
+//	})
+func Notify(ctx context.Context, send feishubot.SendFunc, build Build) error {
+	if err := send(ctx, feishubot.NewInteractiveMessage(build.Card())); err != nil {
+		return fmt.Errorf("failed to post build %s: %w", build.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/cards"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	var sent *feishubot.Message
+	send := func(_ context.Context, msg *feishubot.Message) error {
+		sent = msg
+		return nil
+	}
+
+	build := Build{
+		Job:      "api-gateway",
+		Number:   123,
+		Status:   cards.StatusFailed,
+		Duration: 4 * time.Minute,
+		LogURL:   "https://ci.example.com/123",
+	}
+	require.NoError(t, Notify(context.Background(), send, build))
+
+	data, err := json.Marshal(sent.TypedCard)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {"title": {"tag": "plain_text", "content": "❌ api-gateway #123 failed"}, "template": "red"},
+		"body": {"elements": [
+			{"tag": "div", "fields": [{"is_short": true, "text": {"tag": "lark_md", "content": "**Duration:** 4m0s"}}]},
+			{"tag": "column_set", "columns": [
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "View logs"}, "type": "primary", "url": "https://ci.example.com/123", "width": "fill"}
+				]}
+			]}
+		]}
+	}`, string(data))
+
+	err = Notify(context.Background(), func(context.Context, *feishubot.Message) error {
+		return errors.New("webhook down")
+	}, Build{Job: "nightly"})
+	require.EqualError(t, err, "failed to post build nightly: webhook down")
+}
+
+func TestDroneBuild(t *testing.T) {
+	env := map[string]string{
+		"DRONE_REPO":           "octocat/hello-world",
+		"DRONE_BUILD_NUMBER":   "42",
+		"DRONE_BUILD_STATUS":   "success",
+		"DRONE_BUILD_LINK":     "https://drone.example.com/octocat/hello-world/42",
+		"DRONE_BUILD_STARTED":  "1700000000",
+		"DRONE_BUILD_FINISHED": "1700000090",
+		"DRONE_BUILD_EVENT":    "push",
+		"DRONE_COMMIT_BRANCH":  "main",
+		"DRONE_COMMIT_SHA":     "bcdd4bf0245c82c060407b3b24b9b87301d15ac1",
+		"DRONE_COMMIT_MESSAGE": "Update README",
+		"DRONE_COMMIT_AUTHOR":  "octocat",
+		"DRONE_COMMIT_LINK":    "https://github.com/octocat/hello-world/commit/bcdd4bf0",
+	}
+
+	build := DroneBuild(func(key string) string { return env[key] })
+	require.Equal(t, Build{
+		Job:      "octocat/hello-world",
+		Number:   42,
+		Status:   cards.StatusSucceeded,
+		Duration: 90 * time.Second,
+		Trigger:  "push to main by octocat",
+		Changes: []cards.Change{{
+			ID:      "bcdd4bf0245c82c060407b3b24b9b87301d15ac1",
+			Message: "Update README",
+			Author:  "octocat",
+			URL:     "https://github.com/octocat/hello-world/commit/bcdd4bf0",
+		}},
+		LogURL: "https://drone.example.com/octocat/hello-world/42",
+	}, build)
+}
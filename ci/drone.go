@@ -0,0 +1,75 @@
+package ci
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cium-cc/feishurobot/cards"
+)
+
+// DroneBuild returns the build described by the environment variables Drone
+// sets in pipeline steps, read with getenv, usually os.Getenv. Use it in a
+// final step running on success and failure:
+//
+//	steps:
+//	  - name: notify
+//	    image: example/feishu-notify
+//	    when:
+//	      status: [success, failure]
+//
+// See: https://docs.drone.io/pipeline/environment/reference/
+func DroneBuild(getenv func(string) string) Build {
+	build := Build{
+		Job:    getenv("DRONE_REPO"),
+		Status: droneStatus(getenv("DRONE_BUILD_STATUS")),
+		LogURL: getenv("DRONE_BUILD_LINK"),
+	}
+	build.Number, _ = strconv.Atoi(getenv("DRONE_BUILD_NUMBER"))
+
+	started, _ := strconv.ParseInt(getenv("DRONE_BUILD_STARTED"), 10, 64)
+	finished, _ := strconv.ParseInt(getenv("DRONE_BUILD_FINISHED"), 10, 64)
+	if started > 0 {
+		end := time.Now()
+		if finished > 0 {
+			end = time.Unix(finished, 0)
+		}
+		build.Duration = end.Sub(time.Unix(started, 0))
+	}
+
+	trigger := getenv("DRONE_BUILD_EVENT")
+	if branch := getenv("DRONE_COMMIT_BRANCH"); branch != "" {
+		trigger += " to " + branch
+	}
+	if author := getenv("DRONE_COMMIT_AUTHOR"); author != "" {
+		trigger += " by " + author
+	}
+	build.Trigger = strings.TrimSpace(trigger)
+
+	if sha := getenv("DRONE_COMMIT_SHA"); sha != "" {
+		build.Changes = []cards.Change{{
+			ID:      sha,
+			Message: getenv("DRONE_COMMIT_MESSAGE"),
+			Author:  getenv("DRONE_COMMIT_AUTHOR"),
+			URL:     getenv("DRONE_COMMIT_LINK"),
+		}}
+	}
+	return build
+}
+
+// droneStatus maps a Drone build status to a status.
+func droneStatus(status string) cards.Status {
+	switch status {
+	case "success":
+		return cards.StatusSucceeded
+	case "failure", "error":
+		return cards.StatusFailed
+	case "running":
+		return cards.StatusRunning
+	case "killed":
+		return cards.StatusCanceled
+	case "skipped":
+		return cards.StatusSkipped
+	}
+	return cards.StatusPending
+}
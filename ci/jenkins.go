@@ -0,0 +1,178 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/cards"
+)
+
+// maxBodyBytes is the maximum size of notification request bodies read.
+const maxBodyBytes = 1 << 20
+
+// Jenkins build phases.
+const (
+	JenkinsPhaseQueued    = "QUEUED"
+	JenkinsPhaseStarted   = "STARTED"
+	JenkinsPhaseCompleted = "COMPLETED"
+	JenkinsPhaseFinalized = "FINALIZED"
+)
+
+// JenkinsNotification is the JSON payload of the Jenkins Notification
+// plugin.
+//
+// See: https://plugins.jenkins.io/notification/
+type JenkinsNotification struct {
+	// Name is the job name.
+	Name  string       `json:"name"`
+	URL   string       `json:"url"`
+	Build JenkinsBuild `json:"build"`
+}
+
+// JenkinsBuild is the build of a JenkinsNotification.
+type JenkinsBuild struct {
+	FullURL string `json:"full_url"`
+	Number  int    `json:"number"`
+	Phase   string `json:"phase"`
+	// Status is "SUCCESS", "UNSTABLE", "FAILURE", "NOT_BUILT" or "ABORTED",
+	// set once the build completed.
+	Status string `json:"status"`
+	URL    string `json:"url"`
+	// Duration is the build duration in milliseconds, sent by recent
+	// plugin versions.
+	Duration int64      `json:"duration"`
+	SCM      JenkinsSCM `json:"scm"`
+}
+
+// JenkinsSCM is the source of a JenkinsBuild.
+type JenkinsSCM struct {
+	URL      string   `json:"url"`
+	Branch   string   `json:"branch"`
+	Commit   string   `json:"commit"`
+	Culprits []string `json:"culprits"`
+}
+
+// ToBuild maps the notification to a Build.
+func (n *JenkinsNotification) ToBuild() Build {
+	build := Build{
+		Job:      n.Name,
+		Number:   n.Build.Number,
+		Status:   jenkinsStatus(n.Build.Phase, n.Build.Status),
+		Duration: time.Duration(n.Build.Duration) * time.Millisecond,
+	}
+	if n.Build.FullURL != "" {
+		build.LogURL = strings.TrimSuffix(n.Build.FullURL, "/") + "/console"
+	}
+
+	var trigger []string
+	if n.Build.SCM.Branch != "" {
+		trigger = append(trigger, strings.TrimPrefix(n.Build.SCM.Branch, "origin/"))
+	}
+	if len(n.Build.SCM.Culprits) > 0 {
+		trigger = append(trigger, "by "+strings.Join(n.Build.SCM.Culprits, ", "))
+	}
+	build.Trigger = strings.Join(trigger, " ")
+
+	if n.Build.SCM.Commit != "" {
+		build.Changes = []cards.Change{{ID: n.Build.SCM.Commit}}
+	}
+	return build
+}
+
+// jenkinsStatus maps the phase and status of a Jenkins build to a status.
+func jenkinsStatus(phase, status string) cards.Status {
+	switch phase {
+	case JenkinsPhaseQueued:
+		return cards.StatusPending
+	case JenkinsPhaseStarted:
+		return cards.StatusRunning
+	}
+	switch status {
+	case "SUCCESS":
+		return cards.StatusSucceeded
+	case "UNSTABLE", "FAILURE":
+		return cards.StatusFailed
+	case "ABORTED":
+		return cards.StatusCanceled
+	case "NOT_BUILT":
+		return cards.StatusSkipped
+	}
+	return cards.StatusPending
+}
+
+// JenkinsHandler is an http.Handler receiving Jenkins Notification plugin
+// requests and posting completed builds as pipeline cards. Configure the
+// plugin endpoint with format JSON, protocol HTTP and event "completed" or
+// "all".
+type JenkinsHandler struct {
+	send feishubot.SendFunc
+
+	// NotifyStarted also posts builds when they start.
+	NotifyStarted bool
+
+	// OnError is called with errors of handling notifications. If nil, such
+	// errors are only reported in the response.
+	OnError func(error)
+}
+
+// NewJenkinsHandler creates a handler posting builds with send.
+//
+// Example:
+//
+//	client := feishubot.NewClient(webhookURL, secret)
+//	http.Handle("/jenkins", ci.NewJenkinsHandler(feishubot.WebhookSender(client)))
+func NewJenkinsHandler(send feishubot.SendFunc) *JenkinsHandler {
+	return &JenkinsHandler{send: send}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *JenkinsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var notification JenkinsNotification
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxBodyBytes)).Decode(&notification); err != nil {
+		h.handleError(fmt.Errorf("failed to decode notification: %w", err))
+		http.Error(w, "invalid notification", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.notify(req.Context(), &notification); err != nil {
+		h.handleError(err)
+		http.Error(w, "failed to post notification", http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// notify posts the build of a notification if its phase is notified.
+// Completed builds are posted on the COMPLETED phase, ignoring the
+// FINALIZED phase sent after it.
+func (h *JenkinsHandler) notify(ctx context.Context, notification *JenkinsNotification) error {
+	switch notification.Build.Phase {
+	case JenkinsPhaseCompleted:
+	case JenkinsPhaseStarted:
+		if !h.NotifyStarted {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return Notify(ctx, h.send, notification.ToBuild())
+}
+
+// handleError reports err to the error handler.
+func (h *JenkinsHandler) handleError(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+	}
+}
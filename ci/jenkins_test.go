@@ -0,0 +1,85 @@
+package ci
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/cards"
+	"github.com/stretchr/testify/require"
+)
+
+func jenkinsPayload(phase, status string) string {
+	return `{
+		"name": "api-gateway",
+		"url": "job/api-gateway/",
+		"build": {
+			"full_url": "http://jenkins.example.com/job/api-gateway/18/",
+			"number": 18,
+			"phase": "` + phase + `",
+			"status": "` + status + `",
+			"url": "job/api-gateway/18/",
+			"duration": 72500,
+			"scm": {
+				"url": "https://git.example.com/api-gateway.git",
+				"branch": "origin/main",
+				"commit": "c6d86dc7a1b2",
+				"culprits": ["alice", "bob"]
+			}
+		}
+	}`
+}
+
+func TestJenkinsHandler(t *testing.T) {
+	var sent []*feishubot.Message
+	handler := NewJenkinsHandler(func(_ context.Context, msg *feishubot.Message) error {
+		sent = append(sent, msg)
+		return nil
+	})
+
+	for _, phase := range []string{JenkinsPhaseStarted, JenkinsPhaseCompleted, JenkinsPhaseFinalized} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jenkins", strings.NewReader(jenkinsPayload(phase, "UNSTABLE"))))
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+	require.Len(t, sent, 1)
+	require.Equal(t, "❌ api-gateway #18 failed", sent[0].TypedCard.Header.Title.Content)
+
+	handler.NotifyStarted = true
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jenkins", strings.NewReader(jenkinsPayload(JenkinsPhaseStarted, ""))))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, sent, 2)
+	require.Equal(t, "⏳ api-gateway #18 running", sent[1].TypedCard.Header.Title.Content)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jenkins", strings.NewReader("{")))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestJenkinsNotificationToBuild(t *testing.T) {
+	notification := JenkinsNotification{
+		Name: "api-gateway",
+		Build: JenkinsBuild{
+			FullURL:  "http://jenkins.example.com/job/api-gateway/18/",
+			Number:   18,
+			Phase:    JenkinsPhaseCompleted,
+			Status:   "SUCCESS",
+			Duration: 72500,
+			SCM:      JenkinsSCM{Branch: "origin/main", Commit: "c6d86dc7a1b2", Culprits: []string{"alice"}},
+		},
+	}
+	require.Equal(t, Build{
+		Job:      "api-gateway",
+		Number:   18,
+		Status:   cards.StatusSucceeded,
+		Duration: 72500 * time.Millisecond,
+		Trigger:  "main by alice",
+		Changes:  []cards.Change{{ID: "c6d86dc7a1b2"}},
+		LogURL:   "http://jenkins.example.com/job/api-gateway/18/console",
+	}, notification.ToBuild())
+}
@@ -175,8 +175,8 @@ func sendInteractiveCardWithButtons(client *feishubot.Client) error {
 			Template: "red",
 		}).
 		SetBody(&feishubot.CardBody{
-			Direction: "vertical",
-			Padding:   "12px 12px 12px 12px",
+			Direction: feishubot.DirectionVertical,
+			Padding:   feishubot.PaddingAll(12),
 			Elements: []feishubot.CardElement{
 				feishubot.NewMarkdownElement("**High Priority Task**\n\nPlease complete this task by end of day."),
 				feishubot.NewButtonElement("View Details", "primary", "https://example.com/task/123"),
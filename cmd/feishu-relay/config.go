@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration of the relay.
+//
+// Example:
+//
+//	addr: ":8080"
+//	api_keys: [${RELAY_API_KEY}]
+//	webhooks:
+//	  - name: ops
+//	    url: https://open.feishu.cn/open-apis/bot/v2/hook/xxx
+//	    secret: ${OPS_WEBHOOK_SECRET}
+//	  - name: releases
+//	    url: https://open.feishu.cn/open-apis/bot/v2/hook/yyy
+type Config struct {
+	// Addr is the address to listen on, ":8080" by default.
+	Addr string `yaml:"addr"`
+	// APIKeys are the keys accepted from clients.
+	APIKeys []string `yaml:"api_keys"`
+	// Webhooks are the webhooks messages can be forwarded to. Messages go to
+	// the first one unless the request names others.
+	Webhooks []WebhookConfig `yaml:"webhooks"`
+}
+
+// WebhookConfig is a custom bot webhook of the relay.
+type WebhookConfig struct {
+	Name   string `yaml:"name"`
+	URL    string `yaml:"url"`
+	Secret string `yaml:"secret"`
+}
+
+// loadConfig reads the config file at path, expanding environment variables
+// in it. Without a path, a single webhook named "default" is configured
+// from FEISHU_WEBHOOK_URL and FEISHU_SECRET. In both cases, the key in
+// FEISHU_RELAY_API_KEY is accepted in addition to the configured ones.
+func loadConfig(path string) (*Config, error) {
+	var config Config
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config: %w", err)
+		}
+		if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &config); err != nil {
+			return nil, fmt.Errorf("failed to parse config: %w", err)
+		}
+	} else if url := os.Getenv("FEISHU_WEBHOOK_URL"); url != "" {
+		config.Webhooks = []WebhookConfig{{
+			Name:   "default",
+			URL:    url,
+			Secret: os.Getenv("FEISHU_SECRET"),
+		}}
+	}
+	if key := os.Getenv("FEISHU_RELAY_API_KEY"); key != "" {
+		config.APIKeys = append(config.APIKeys, key)
+	}
+
+	if config.Addr == "" {
+		config.Addr = ":8080"
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// validate checks that the config has API keys and well-formed webhooks.
+func (c *Config) validate() error {
+	if len(c.APIKeys) == 0 {
+		return errors.New("invalid config: no API keys configured")
+	}
+	for _, key := range c.APIKeys {
+		if key == "" {
+			return errors.New("invalid config: empty API key")
+		}
+	}
+	if len(c.Webhooks) == 0 {
+		return errors.New("invalid config: no webhooks configured")
+	}
+
+	names := make(map[string]bool, len(c.Webhooks))
+	for i, webhook := range c.Webhooks {
+		switch {
+		case webhook.Name == "":
+			return fmt.Errorf("invalid config: webhook %d has no name", i+1)
+		case names[webhook.Name]:
+			return fmt.Errorf("invalid config: duplicate webhook %q", webhook.Name)
+		case webhook.URL == "":
+			return fmt.Errorf("invalid config: webhook %q has no URL", webhook.Name)
+		}
+		names[webhook.Name] = true
+	}
+	return nil
+}
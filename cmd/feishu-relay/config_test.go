@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("OPS_SECRET", "s3cret")
+	t.Setenv("FEISHU_RELAY_API_KEY", "env-key")
+
+	path := filepath.Join(t.TempDir(), "relay.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+api_keys: [file-key]
+webhooks:
+  - name: ops
+    url: https://open.feishu.cn/open-apis/bot/v2/hook/xxx
+    secret: ${OPS_SECRET}
+`), 0o600))
+
+	config, err := loadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, &Config{
+		Addr:    ":8080",
+		APIKeys: []string{"file-key", "env-key"},
+		Webhooks: []WebhookConfig{
+			{Name: "ops", URL: "https://open.feishu.cn/open-apis/bot/v2/hook/xxx", Secret: "s3cret"},
+		},
+	}, config)
+
+	t.Setenv("FEISHU_WEBHOOK_URL", "https://open.feishu.cn/open-apis/bot/v2/hook/yyy")
+	t.Setenv("FEISHU_SECRET", "")
+	config, err = loadConfig("")
+	require.NoError(t, err)
+	require.Equal(t, []WebhookConfig{{Name: "default", URL: "https://open.feishu.cn/open-apis/bot/v2/hook/yyy"}}, config.Webhooks)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{name: "no keys", config: Config{}, want: "invalid config: no API keys configured"},
+		{name: "no webhooks", config: Config{APIKeys: []string{"k"}}, want: "invalid config: no webhooks configured"},
+		{
+			name:   "duplicate",
+			config: Config{APIKeys: []string{"k"}, Webhooks: []WebhookConfig{{Name: "a", URL: "u"}, {Name: "a", URL: "u"}}},
+			want:   `invalid config: duplicate webhook "a"`,
+		},
+		{
+			name:   "no URL",
+			config: Config{APIKeys: []string{"k"}, Webhooks: []WebhookConfig{{Name: "a"}}},
+			want:   `invalid config: webhook "a" has no URL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.EqualError(t, tt.config.validate(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Command feishu-relay is an HTTP server forwarding messages to Feishu
+// custom bot webhooks, signing them with the webhook secrets, so that shell
+// scripts and programs in any language can post to Feishu with a plain HTTP
+// request.
+//
+// Requests authenticate with an API key, as a bearer token or in the
+// X-API-Key header, and name the configured webhooks to post to in the "to"
+// query parameter, the first webhook by default:
+//
+//	POST /text          {"text": "..."} or a plain text body
+//	POST /markdown      {"title": "...", "content": "..."} or a plain text body
+//	POST /card          card JSON, e.g. from the Feishu card builder
+//	POST /alertmanager  Alertmanager webhook notifications
+//	GET  /healthz       health check, without authentication
+//
+// Example:
+//
+//	curl -H "Authorization: Bearer $RELAY_API_KEY" -d "Backup finished" \
+//		"http://localhost:8080/text?to=ops"
+//
+// Webhooks and API keys are read from the YAML file given with -config, see
+// Config, or from the FEISHU_WEBHOOK_URL, FEISHU_SECRET and
+// FEISHU_RELAY_API_KEY environment variables.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	configPath := flag.String("config", os.Getenv("FEISHU_RELAY_CONFIG"), "path of the YAML config file")
+	addr := flag.String("addr", "", "address to listen on, overriding the config")
+	flag.Parse()
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("feishu-relay: %v", err)
+	}
+	if *addr != "" {
+		config.Addr = *addr
+	}
+
+	srv := &http.Server{
+		Addr:              config.Addr,
+		Handler:           newServer(config),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("feishu-relay: failed to shut down: %v", err)
+		}
+	}()
+
+	log.Printf("feishu-relay: listening on %s with %d webhooks", config.Addr, len(config.Webhooks))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("feishu-relay: %v", err)
+	}
+	// Wait for requests being forwarded
+	<-shutdown
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/alertmanager"
+)
+
+// maxBodyBytes is the maximum size of request bodies read.
+const maxBodyBytes = 1 << 20
+
+// markdownCardBytes is the maximum markdown per card sent for /markdown,
+// keeping requests below the webhook request size limit of 20 KB.
+const markdownCardBytes = 18000
+
+// server is the HTTP handler of the relay.
+type server struct {
+	apiKeys  [][]byte
+	webhooks map[string]*feishubot.Client
+	// fallback is the webhook of requests naming none.
+	fallback string
+	mux      *http.ServeMux
+}
+
+// newServer creates the handler of a validated config.
+func newServer(config *Config) *server {
+	s := &server{
+		webhooks: make(map[string]*feishubot.Client, len(config.Webhooks)),
+		fallback: config.Webhooks[0].Name,
+		mux:      http.NewServeMux(),
+	}
+	for _, key := range config.APIKeys {
+		s.apiKeys = append(s.apiKeys, []byte(key))
+	}
+	for _, webhook := range config.Webhooks {
+		s.webhooks[webhook.Name] = feishubot.NewClient(webhook.URL, webhook.Secret)
+	}
+
+	s.mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	s.mux.Handle("/text", s.authorized(http.HandlerFunc(s.handleText)))
+	s.mux.Handle("/markdown", s.authorized(http.HandlerFunc(s.handleMarkdown)))
+	s.mux.Handle("/card", s.authorized(http.HandlerFunc(s.handleCard)))
+	s.mux.Handle("/alertmanager", s.authorized(http.HandlerFunc(s.handleAlertmanager)))
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mux.ServeHTTP(w, req)
+}
+
+// authorized wraps a handler of POST requests, rejecting other methods and
+// requests without a valid API key, given as a bearer token or in the
+// X-API-Key header.
+func (s *server) authorized(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		key := req.Header.Get("X-API-Key")
+		if auth := req.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			key = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if !s.validKey(key) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// validKey reports whether key is one of the API keys, in constant time.
+func (s *server) validKey(key string) bool {
+	valid := 0
+	for _, apiKey := range s.apiKeys {
+		valid |= subtle.ConstantTimeCompare([]byte(key), apiKey)
+	}
+	return key != "" && valid == 1
+}
+
+// handleText forwards a text message, given as {"text": "..."} or as a
+// plain text body.
+func (s *server) handleText(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Text string `json:"text"`
+	}
+	if !decodeBody(w, req, &body.Text, &body) {
+		return
+	}
+	if strings.TrimSpace(body.Text) == "" {
+		writeError(w, http.StatusBadRequest, "text is required")
+		return
+	}
+	s.forward(w, req, feishubot.NewTextMessage(body.Text))
+}
+
+// handleMarkdown forwards markdown as one or more cards, given as
+// {"title": "...", "content": "..."} or as a plain text body with the title
+// in the title query parameter.
+func (s *server) handleMarkdown(w http.ResponseWriter, req *http.Request) {
+	body := struct {
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	}{Title: req.URL.Query().Get("title")}
+	if !decodeBody(w, req, &body.Content, &body) {
+		return
+	}
+	if strings.TrimSpace(body.Content) == "" {
+		writeError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+
+	cards := feishubot.NewMarkdownCards(body.Title, body.Content, markdownCardBytes)
+	messages := make([]*feishubot.Message, 0, len(cards))
+	for _, card := range cards {
+		messages = append(messages, feishubot.NewInteractiveMessage(card))
+	}
+	s.forward(w, req, messages...)
+}
+
+// handleCard forwards card JSON, such as a card exported from the Feishu
+// card builder.
+func (s *server) handleCard(w http.ResponseWriter, req *http.Request) {
+	data, err := io.ReadAll(io.LimitReader(req.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	card, err := feishubot.ParseCard(data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.forward(w, req, feishubot.NewInteractiveMessage(card))
+}
+
+// handleAlertmanager forwards Alertmanager webhook notifications as cards.
+func (s *server) handleAlertmanager(w http.ResponseWriter, req *http.Request) {
+	clients, err := s.targets(req)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	handler := alertmanager.NewHandler(func(ctx context.Context, msg *feishubot.Message) error {
+		return send(ctx, clients, msg)
+	})
+	handler.OnError = func(err error) {
+		log.Printf("feishu-relay: %s: %v", req.URL.Path, err)
+	}
+	handler.ServeHTTP(w, req)
+}
+
+// forward sends messages in order to the webhooks named by the request, and
+// answers with the outcome.
+func (s *server) forward(w http.ResponseWriter, req *http.Request, messages ...*feishubot.Message) {
+	clients, err := s.targets(req)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	for _, msg := range messages {
+		if err := send(req.Context(), clients, msg); err != nil {
+			log.Printf("feishu-relay: %s: %v", req.URL.Path, err)
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"ok":true}` + "\n"))
+}
+
+// targets returns the webhooks named by the "to" query parameters of req,
+// repeated or comma separated, or the fallback webhook if none are named.
+func (s *server) targets(req *http.Request) (map[string]*feishubot.Client, error) {
+	var names []string
+	for _, to := range req.URL.Query()["to"] {
+		for _, name := range strings.Split(to, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		names = []string{s.fallback}
+	}
+
+	clients := make(map[string]*feishubot.Client, len(names))
+	for _, name := range names {
+		client, ok := s.webhooks[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown webhook %q", name)
+		}
+		clients[name] = client
+	}
+	return clients, nil
+}
+
+// send sends msg to every client, and returns the errors of failed sends.
+func send(ctx context.Context, clients map[string]*feishubot.Client, msg *feishubot.Message) error {
+	var failed []string
+	for name, client := range clients {
+		if _, err := client.Send(ctx, msg); err != nil {
+			failed = append(failed, fmt.Sprintf("failed to send to webhook %q: %v", name, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// decodeBody decodes a JSON request body into v, or reads any other body
+// into text. It answers the request and returns false on failure.
+func decodeBody(w http.ResponseWriter, req *http.Request, text *string, v interface{}) bool {
+	body := io.LimitReader(req.Body, maxBodyBytes)
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		if err := json.NewDecoder(body).Decode(v); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return false
+		}
+		return true
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return false
+	}
+	*text = string(data)
+	return true
+}
+
+// writeError answers a request with a JSON error.
+func writeError(w http.ResponseWriter, status int, message string) {
+	data, _ := json.Marshal(map[string]string{"error": message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(data, '\n'))
+}
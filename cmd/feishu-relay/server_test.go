@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// webhookRecorder is a webhook server recording the messages posted to it.
+type webhookRecorder struct {
+	mu       sync.Mutex
+	messages []map[string]interface{}
+	fail     bool
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var msg map[string]interface{}
+	_ = json.NewDecoder(req.Body).Decode(&msg)
+	r.mu.Lock()
+	r.messages = append(r.messages, msg)
+	r.mu.Unlock()
+	if r.fail {
+		_, _ = w.Write([]byte(`{"code":19021,"msg":"sign match fail or timestamp is not within one hour from current time"}`))
+		return
+	}
+	_, _ = w.Write([]byte(`{"code":0,"msg":"success"}`))
+}
+
+func newTestServer(t *testing.T) (*server, *webhookRecorder, *webhookRecorder) {
+	t.Helper()
+
+	ops, releases := &webhookRecorder{}, &webhookRecorder{}
+	opsServer, releasesServer := httptest.NewServer(ops), httptest.NewServer(releases)
+	t.Cleanup(opsServer.Close)
+	t.Cleanup(releasesServer.Close)
+
+	config := &Config{
+		APIKeys: []string{"key-1", "key-2"},
+		Webhooks: []WebhookConfig{
+			{Name: "ops", URL: opsServer.URL, Secret: "secret"},
+			{Name: "releases", URL: releasesServer.URL},
+		},
+	}
+	require.NoError(t, config.validate())
+	return newServer(config), ops, releases
+}
+
+func post(s http.Handler, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer key-1")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerText(t *testing.T) {
+	s, ops, releases := newTestServer(t)
+
+	rec := post(s, "/text", "text/plain", "Backup finished")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"ok":true}`, rec.Body.String())
+	require.Len(t, ops.messages, 1)
+	require.Equal(t, "text", ops.messages[0]["msg_type"])
+	require.Equal(t, map[string]interface{}{"text": "Backup finished"}, ops.messages[0]["content"])
+	require.NotEmpty(t, ops.messages[0]["sign"])
+	require.Empty(t, releases.messages)
+
+	rec = post(s, "/text?to=ops,releases", "application/json; charset=utf-8", `{"text":"v1.2.0 released"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, ops.messages, 2)
+	require.Len(t, releases.messages, 1)
+	require.Equal(t, map[string]interface{}{"text": "v1.2.0 released"}, releases.messages[0]["content"])
+	require.Nil(t, releases.messages[0]["sign"])
+
+	rec = post(s, "/text", "application/json", `{"text":" "}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.JSONEq(t, `{"error":"text is required"}`, rec.Body.String())
+
+	rec = post(s, "/text", "application/json", `{"text":`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = post(s, "/text?to=sales", "", "hi")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.JSONEq(t, `{"error":"unknown webhook \"sales\""}`, rec.Body.String())
+}
+
+func TestServerMarkdownAndCard(t *testing.T) {
+	s, ops, _ := newTestServer(t)
+
+	rec := post(s, "/markdown?title=Deploy", "text/markdown", "**api** deployed to `prod`")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, ops.messages, 1)
+	card := ops.messages[0]["card"].(map[string]interface{})
+	require.Equal(t, "Deploy", card["header"].(map[string]interface{})["title"].(map[string]interface{})["content"])
+
+	long := strings.Repeat("A paragraph of release notes.\n\n", 1000)
+	rec = post(s, "/markdown", "application/json", `{"title":"Notes","content":`+mustJSON(t, long)+`}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, ops.messages, 4)
+
+	rec = post(s, "/card", "application/json", `{"schema":"2.0","body":{"elements":[{"tag":"markdown","content":"hi"}]}}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, ops.messages, 5)
+	require.Equal(t, "interactive", ops.messages[4]["msg_type"])
+
+	rec = post(s, "/card", "application/json", `{"schema":"3.0"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "unsupported card schema")
+}
+
+func TestServerAlertmanager(t *testing.T) {
+	s, _, releases := newTestServer(t)
+
+	rec := post(s, "/alertmanager?to=releases", "application/json", `{
+		"status": "firing",
+		"commonLabels": {"alertname": "HighLatency"},
+		"alerts": [{"status": "firing", "labels": {"alertname": "HighLatency"}, "startsAt": "2024-05-01T10:00:00Z"}]
+	}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, releases.messages, 1)
+	require.Equal(t, "interactive", releases.messages[0]["msg_type"])
+}
+
+func TestServerErrors(t *testing.T) {
+	s, ops, _ := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/text", strings.NewReader("hi"))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/text", strings.NewReader("hi"))
+	req.Header.Set("X-API-Key", "key-2")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/text", strings.NewReader("hi"))
+	req.Header.Set("Authorization", "Bearer key-3")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/text", nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	require.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	ops.fail = true
+	rec = post(s, "/text", "", "hi")
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+	require.Contains(t, rec.Body.String(), `failed to send to webhook \"ops\"`)
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
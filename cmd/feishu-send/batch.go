@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// maxLineBytes is the maximum length of a line of a batch file, well above
+// the request size limit of webhooks.
+const maxLineBytes = 1 << 20
+
+// Clock of batch, replaced in tests.
+var (
+	batchNow   = time.Now
+	batchAfter = time.After
+)
+
+// batchResult is the result of a line of a batch file, as printed with
+// -json.
+type batchResult struct {
+	Line  int    `json:"line"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// runBatch sends the messages of a JSONL file.
+func runBatch(args []string, stdout, stderr io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return runBatchContext(ctx, os.Stdin, args, stdout, stderr)
+}
+
+// runBatchContext is runBatch stopping when ctx is done and reading "-"
+// from stdin.
+func runBatchContext(ctx context.Context, stdin io.Reader, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	client := addClientFlags(flags)
+	perSecond := flags.Int("rate-per-second", feishubot.DefaultRateLimit.PerSecond, "maximum messages per second, 0 for no limit")
+	perMinute := flags.Int("rate-per-minute", feishubot.DefaultRateLimit.PerMinute, "maximum messages per minute, 0 for no limit")
+	retryMax := flags.Int("retry-max", 3, "retries of failed sends")
+	retryBackoff := flags.Duration("retry-backoff", feishubot.DefaultRetryBackoff, "delay before the first retry")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout of sending a message, including retries but not waiting for the rate limit")
+	jsonOutput := flags.Bool("json", false, "print results as JSON lines")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: feishu-send batch [-profile name] [flags] messages.jsonl")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, `Sends one message JSON per line, such as {"msg_type": "text", "content": {"text": "..."}}, and prints the result of each line. Blank lines are skipped. Use "-" to read standard input.`)
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitError
+	}
+
+	input := stdin
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "feishu-send: %v\n", err)
+			return exitError
+		}
+		defer file.Close()
+		input = file
+	}
+
+	c, err := client.newClient()
+	if err != nil {
+		fmt.Fprintf(stderr, "feishu-send: %v\n", err)
+		return exitError
+	}
+	pace := &pacer{limit: feishubot.RateLimit{PerSecond: *perSecond, PerMinute: *perMinute}}
+	c.SetRetry(*retryMax, *retryBackoff)
+
+	var sent, failed int
+	report := func(result batchResult) {
+		if result.OK {
+			sent++
+		} else {
+			failed++
+		}
+		if *jsonOutput {
+			data, _ := json.Marshal(result)
+			fmt.Fprintf(stdout, "%s\n", data)
+		} else if result.OK {
+			fmt.Fprintf(stdout, "line %d: ok\n", result.Line)
+		} else {
+			fmt.Fprintf(stdout, "line %d: %s\n", result.Line, result.Error)
+		}
+	}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		if ctx.Err() != nil {
+			fmt.Fprintf(stderr, "feishu-send: interrupted before line %d\n", line)
+			break
+		}
+
+		msg, err := parseBatchLine(scanner.Bytes())
+		if err == nil {
+			if err := pace.wait(ctx); err != nil {
+				fmt.Fprintf(stderr, "feishu-send: interrupted before line %d\n", line)
+				break
+			}
+			err = sendWithTimeout(ctx, c, msg, *timeout)
+		}
+		if err != nil {
+			report(batchResult{Line: line, Error: err.Error()})
+		} else {
+			report(batchResult{Line: line, OK: true})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(stderr, "feishu-send: failed to read line %d: %v\n", line+1, err)
+		return exitError
+	}
+
+	fmt.Fprintf(stderr, "%d sent, %d failed\n", sent, failed)
+	switch {
+	case ctx.Err() != nil:
+		return exitError
+	case failed > 0:
+		return exitIssues
+	}
+	return exitOK
+}
+
+// parseBatchLine parses the message JSON of a line.
+func parseBatchLine(data []byte) (*feishubot.Message, error) {
+	var msg feishubot.Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("invalid message JSON: %w", err)
+	}
+	switch msg.MsgType {
+	case "":
+		return nil, errors.New("invalid message JSON: no msg_type")
+	case feishubot.MsgTypeInteractive:
+		if msg.Card == nil {
+			return nil, errors.New("invalid message JSON: interactive message has no card")
+		}
+	default:
+		if msg.Content == nil {
+			return nil, fmt.Errorf("invalid message JSON: %s message has no content", msg.MsgType)
+		}
+	}
+	return &msg, nil
+}
+
+// sendWithTimeout sends a message with a timeout.
+func sendWithTimeout(ctx context.Context, client *feishubot.Client, msg *feishubot.Message, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, err := client.Send(ctx, msg)
+	return err
+}
+
+// pacer delays the lines of a batch to stay within a rate limit. Lines are
+// paced outside the timeout of sending them, so that lines waiting for the
+// limit do not time out.
+type pacer struct {
+	limit feishubot.RateLimit
+	// sent are the times of the lines sent within the last minute, oldest
+	// first.
+	sent []time.Time
+}
+
+// wait waits until a line may be sent and records it, or returns the error
+// of ctx if it is done first.
+func (p *pacer) wait(ctx context.Context) error {
+	for {
+		now := batchNow()
+		for len(p.sent) > 0 && !p.sent[0].After(now.Add(-time.Minute)) {
+			p.sent = p.sent[1:]
+		}
+		delay := p.limit.Delay(p.sent, now)
+		if delay <= 0 {
+			p.sent = append(p.sent, now)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-batchAfter(delay):
+		}
+	}
+}
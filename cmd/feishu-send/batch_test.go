@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+const batchLines = `{"msg_type": "text", "content": {"text": "first"}}
+
+{"msg_type": "text", "content": {"text": "rejected"}}
+not json
+{"msg_type": "interactive"}
+{"msg_type": "interactive", "card": {"schema": "2.0", "body": {"elements": []}}}
+`
+
+// newBatchWebhook starts a webhook server rejecting texts "rejected" and
+// recording the others.
+func newBatchWebhook(t *testing.T, received *[]*feishubot.Message) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg feishubot.Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		if msg.Content["text"] == "rejected" {
+			json.NewEncoder(w).Encode(feishubot.Response{Code: 19024, Msg: "Key Words Not Found"})
+			return
+		}
+		*received = append(*received, &msg)
+		json.NewEncoder(w).Encode(feishubot.Response{Msg: "success"})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBatch(t *testing.T) {
+	clearEnv(t)
+	var received []*feishubot.Message
+	server := newBatchWebhook(t, &received)
+	path := writeFile(t, "messages.jsonl", batchLines)
+
+	var stdout, stderr bytes.Buffer
+	status := runBatchContext(context.Background(), nil, []string{"-webhook", server.URL, "-rate-per-second", "0", path}, &stdout, &stderr)
+	require.Equal(t, exitIssues, status, stderr.String())
+	require.Equal(t, `line 1: ok
+line 3: API error (code 19024): Key Words Not Found
+line 4: invalid message JSON: invalid character 'o' in literal null (expecting 'u')
+line 5: invalid message JSON: interactive message has no card
+line 6: ok
+`, stdout.String())
+	require.Equal(t, "2 sent, 3 failed\n", stderr.String())
+	require.Len(t, received, 2)
+	require.Equal(t, feishubot.MsgTypeInteractive, received[1].MsgType)
+}
+
+func TestBatchJSON(t *testing.T) {
+	clearEnv(t)
+	var received []*feishubot.Message
+	server := newBatchWebhook(t, &received)
+
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader(`{"msg_type": "text", "content": {"text": "first"}}` + "\n" + `{"content": {}}`)
+	status := runBatchContext(context.Background(), stdin, []string{"-webhook", server.URL, "-json", "-"}, &stdout, &stderr)
+	require.Equal(t, exitIssues, status)
+
+	var results []batchResult
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var result batchResult
+		require.NoError(t, json.Unmarshal([]byte(line), &result))
+		results = append(results, result)
+	}
+	require.Equal(t, []batchResult{
+		{Line: 1, OK: true},
+		{Line: 2, Error: "invalid message JSON: no msg_type"},
+	}, results)
+}
+
+func TestBatchInterrupted(t *testing.T) {
+	clearEnv(t)
+	var received []*feishubot.Message
+	server := newBatchWebhook(t, &received)
+	path := writeFile(t, "messages.jsonl", batchLines)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitError, runBatchContext(ctx, nil, []string{"-webhook", server.URL, path}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "interrupted before line 1")
+	require.Empty(t, received)
+}
+
+func TestBatchErrors(t *testing.T) {
+	clearEnv(t)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitError, runBatchContext(context.Background(), nil, nil, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "Usage: feishu-send batch")
+
+	stderr.Reset()
+	require.Equal(t, exitError, runBatchContext(context.Background(), nil, []string{"-webhook", "https://example.com", "missing.jsonl"}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "missing.jsonl")
+}
+
+func TestBatchRateLimit(t *testing.T) {
+	clearEnv(t)
+	var received []*feishubot.Message
+	server := newBatchWebhook(t, &received)
+
+	// Waiting for the rate limit advances a fake clock instantly
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	start := now
+	batchNow = func() time.Time { return now }
+	batchAfter = func(d time.Duration) <-chan time.Time {
+		now = now.Add(d)
+		ch := make(chan time.Time, 1)
+		ch <- now
+		return ch
+	}
+	t.Cleanup(func() { batchNow, batchAfter = time.Now, time.After })
+
+	lines := strings.Repeat(`{"msg_type": "text", "content": {"text": "backfill"}}`+"\n", feishubot.DefaultRateLimit.PerMinute+5)
+	path := writeFile(t, "messages.jsonl", lines)
+
+	var stdout, stderr bytes.Buffer
+	status := runBatchContext(context.Background(), nil, []string{"-webhook", server.URL, "-timeout", "5s", path}, &stdout, &stderr)
+	require.Equal(t, exitOK, status, stdout.String())
+	require.Equal(t, "105 sent, 0 failed\n", stderr.String())
+	require.Len(t, received, 105)
+	require.GreaterOrEqual(t, now.Sub(start), time.Minute)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// clientFlags are the flags selecting the webhook of commands that send.
+type clientFlags struct {
+	profile string
+	webhook string
+	secret  string
+}
+
+// addClientFlags adds the webhook flags to flags.
+func addClientFlags(flags *flag.FlagSet) *clientFlags {
+	c := &clientFlags{}
+	flags.StringVar(&c.profile, "profile", os.Getenv("FEISHU_PROFILE"), "name of the profile in the profiles file")
+	flags.StringVar(&c.webhook, "webhook", os.Getenv("FEISHU_WEBHOOK_URL"), "webhook URL of the custom bot")
+	flags.StringVar(&c.secret, "secret", os.Getenv("FEISHU_SECRET"), "signing secret of the custom bot")
+	return c
+}
+
+// commandLineProfile is the name of the profile of the -webhook and
+// -secret flags.
+const commandLineProfile = "command line"
+
+// loadProfile returns the webhook selected by the flags: the named profile,
+// the webhook URL and secret given as flags or environment variables, or
+// the default profile.
+func (c *clientFlags) loadProfile() (*feishubot.Profile, error) {
+	profile, err := c.loadProfileUnvalidated()
+	if err != nil {
+		return nil, err
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// loadProfileUnvalidated is loadProfile without validating the profile,
+// for diagnosing it.
+func (c *clientFlags) loadProfileUnvalidated() (*feishubot.Profile, error) {
+	if c.profile == "" && c.webhook != "" {
+		return &feishubot.Profile{Name: commandLineProfile, WebhookURL: c.webhook, Secret: c.secret}, nil
+	}
+	path, err := feishubot.DefaultProfilesPath()
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := feishubot.LoadProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	return profiles.Lookup(c.profile)
+}
+
+// newClient creates a client for the webhook selected by the flags.
+func (c *clientFlags) newClient() (*feishubot.Client, error) {
+	profile, err := c.loadProfile()
+	if err != nil {
+		return nil, err
+	}
+	return profile.NewClient()
+}
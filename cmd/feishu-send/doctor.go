@@ -0,0 +1,409 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Response codes of custom bot webhooks diagnosed by doctor.
+const (
+	codeTokenInvalid     = 19001
+	codeSignMismatch     = 19021
+	codeIPNotAllowed     = 19022
+	codeKeywordNotFound  = 19024
+	codeFrequencyLimited = 11232
+)
+
+// webhookPathPrefix is the path prefix of custom bot webhook URLs.
+const webhookPathPrefix = "/open-apis/bot/v2/hook/"
+
+// feishuHosts are the hosts of custom bot webhooks.
+var feishuHosts = map[string]bool{
+	"open.feishu.cn":     true,
+	"open.larksuite.com": true,
+}
+
+// Statuses of checks.
+const (
+	statusOK      = "ok"
+	statusWarn    = "warn"
+	statusFail    = "fail"
+	statusSkipped = "skipped"
+)
+
+// checkResult is the result of a check of doctor.
+type checkResult struct {
+	Name   string
+	Status string
+	Detail string
+	// Fix is the action fixing a failed check or warning.
+	Fix string
+}
+
+// doctor diagnoses a webhook.
+type doctor struct {
+	profile *feishubot.Profile
+	client  *feishubot.Client
+	// httpClient sends the raw probes, through the proxy of the profile.
+	httpClient feishubot.HTTPClient
+	// rootCAs verify the certificate of the webhook host. If nil, the
+	// system roots are used.
+	rootCAs *x509.CertPool
+	// send posts a test message to check the keyword.
+	send    bool
+	timeout time.Duration
+	now     func() time.Time
+
+	url *url.URL
+	// lastCode is the response code of the last probe, -1 if none.
+	lastCode   int
+	lastHeader http.Header
+}
+
+// runDoctor diagnoses the webhook selected by the flags.
+func runDoctor(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	client := addClientFlags(flags)
+	send := flags.Bool("send", false, "post a test message to check the keyword and delivery")
+	timeout := flags.Duration("timeout", 10*time.Second, "timeout of each check")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: feishu-send doctor [-profile name] [-send]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Diagnoses a webhook: URL syntax, DNS, TLS, connectivity, signature, keyword and rate limit.")
+		fmt.Fprintln(stderr, "Without -send, no message is posted; the keyword is only checked with -send.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	profile, err := client.loadProfileUnvalidated()
+	if err != nil {
+		fmt.Fprintf(stderr, "feishu-send: %v\n", err)
+		return exitError
+	}
+	d, err := newDoctor(profile)
+	if err != nil {
+		fmt.Fprintf(stderr, "feishu-send: %v\n", err)
+		return exitError
+	}
+	d.send = *send
+	d.timeout = *timeout
+
+	fmt.Fprintf(stdout, "Checking %s (%s)\n", profileLabel(profile), maskWebhookURL(profile.WebhookURL))
+	results := d.run(context.Background())
+	status := exitOK
+	for _, result := range results {
+		printCheck(stdout, result)
+		if result.Status == statusFail {
+			status = exitIssues
+		}
+	}
+	return status
+}
+
+// newDoctor creates a doctor of the webhook of a profile.
+func newDoctor(profile *feishubot.Profile) (*doctor, error) {
+	d := &doctor{profile: profile, now: time.Now, timeout: 10 * time.Second, lastCode: -1}
+	if profile.Validate() == nil {
+		client, err := profile.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		client.SetRetry(0, 0)
+		d.client = client
+		d.httpClient = client.HTTPClient
+	}
+	return d, nil
+}
+
+// run runs the checks in order, skipping checks depending on failed ones.
+func (d *doctor) run(ctx context.Context) []checkResult {
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) checkResult
+	}{
+		{"URL syntax", d.checkURL},
+		{"DNS", d.checkDNS},
+		{"TLS", d.checkTLS},
+		{"connectivity", d.checkConnectivity},
+		{"signature", d.checkSignature},
+		{"keyword", d.checkKeyword},
+		{"rate limit", d.checkRateLimit},
+	}
+
+	var results []checkResult
+	failed := ""
+	for _, check := range checks {
+		var result checkResult
+		if failed != "" {
+			result = checkResult{Status: statusSkipped, Detail: failed + " check failed"}
+		} else {
+			checkCtx, cancel := context.WithTimeout(ctx, d.timeout)
+			result = check.run(checkCtx)
+			cancel()
+		}
+		result.Name = check.name
+		if result.Status == statusFail && failed == "" {
+			failed = check.name
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// checkURL checks that the URL is a custom bot webhook URL.
+func (d *doctor) checkURL(context.Context) checkResult {
+	if err := d.profile.Validate(); err != nil {
+		return checkResult{Status: statusFail, Detail: err.Error(),
+			Fix: "copy the webhook URL from the bot settings of the group, it starts with https://open.feishu.cn" + webhookPathPrefix}
+	}
+	u, _ := url.Parse(d.profile.WebhookURL)
+	d.url = u
+
+	if !strings.HasPrefix(u.Path, webhookPathPrefix) || len(u.Path) == len(webhookPathPrefix) {
+		return checkResult{Status: statusFail, Detail: fmt.Sprintf("the path %q is not a webhook path", u.Path),
+			Fix: "the URL must end with " + webhookPathPrefix + "<token>; copy it again from the bot settings"}
+	}
+
+	var details, fixes []string
+	if !feishuHosts[u.Hostname()] {
+		details = append(details, fmt.Sprintf("%s is not a Feishu or Lark host", u.Hostname()))
+		fixes = append(fixes, "ignore this if the URL points to a relay or gateway on purpose")
+	}
+	if u.Scheme != "https" {
+		details = append(details, "the URL is not https")
+		fixes = append(fixes, "use https:// to keep the webhook token secret")
+	}
+	if len(details) > 0 {
+		return checkResult{Status: statusWarn, Detail: strings.Join(details, "; "), Fix: strings.Join(fixes, "; ")}
+	}
+	return checkResult{Status: statusOK, Detail: "custom bot webhook URL"}
+}
+
+// checkDNS resolves the webhook host, or the proxy host with a proxy.
+func (d *doctor) checkDNS(ctx context.Context) checkResult {
+	host, fix := d.url.Hostname(), "check the DNS configuration, e.g. /etc/resolv.conf, and that the host is not blocked"
+	if d.profile.Proxy != "" {
+		proxy, _ := url.Parse(d.profile.Proxy)
+		host, fix = proxy.Hostname(), "check the proxy URL of the profile"
+	}
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return checkResult{Status: statusFail, Detail: fmt.Sprintf("failed to resolve %s: %v", host, err), Fix: fix}
+	}
+	return checkResult{Status: statusOK, Detail: fmt.Sprintf("%s resolves to %s", host, strings.Join(addrs, ", "))}
+}
+
+// checkTLS checks the certificate of the webhook host.
+func (d *doctor) checkTLS(ctx context.Context) checkResult {
+	switch {
+	case d.url.Scheme != "https":
+		return checkResult{Status: statusSkipped, Detail: "the URL is not https"}
+	case d.profile.Proxy != "":
+		return checkResult{Status: statusSkipped, Detail: "requests go through the proxy, checked by the connectivity check"}
+	}
+
+	address := d.url.Host
+	if d.url.Port() == "" {
+		address = net.JoinHostPort(d.url.Hostname(), "443")
+	}
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: d.url.Hostname(), RootCAs: d.rootCAs}}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return checkResult{Status: statusFail, Detail: fmt.Sprintf("TLS handshake with %s failed: %v", address, err),
+			Fix: "check that outbound port 443 is open, that the system CA certificates are installed and that no TLS-intercepting proxy is in the way"}
+	}
+	defer conn.Close()
+
+	cert := conn.(*tls.Conn).ConnectionState().PeerCertificates[0]
+	days := int(cert.NotAfter.Sub(d.now()).Hours() / 24)
+	return checkResult{Status: statusOK, Detail: fmt.Sprintf("certificate of %s valid for %d more days", cert.Subject.CommonName, days)}
+}
+
+// checkConnectivity posts an empty request, which is answered with an
+// error without posting a message, to check that the webhook exists.
+func (d *doctor) checkConnectivity(ctx context.Context) checkResult {
+	start := time.Now()
+	resp, err := d.probe(ctx, map[string]interface{}{})
+	if err != nil {
+		fix := "check firewalls and outbound access to the host"
+		if d.profile.Proxy == "" {
+			fix += ", or set a proxy in the profile"
+		} else {
+			fix += " and the proxy"
+		}
+		return checkResult{Status: statusFail, Detail: err.Error(), Fix: fix}
+	}
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	switch resp.Code {
+	case codeTokenInvalid:
+		return checkResult{Status: statusFail, Detail: fmt.Sprintf("the webhook token is invalid (code %d: %s)", resp.Code, resp.Msg),
+			Fix: "the bot may have been removed from the group; copy the webhook URL again from the bot settings"}
+	case codeIPNotAllowed:
+		return checkResult{Status: statusFail, Detail: fmt.Sprintf("this IP address is not allowed (code %d: %s)", resp.Code, resp.Msg),
+			Fix: "add the outbound IP address of this host to the IP allowlist in the bot security settings"}
+	}
+	return checkResult{Status: statusOK, Detail: fmt.Sprintf("webhook answered in %s", elapsed)}
+}
+
+// checkSignature checks the signature with a signed request without
+// content, which is not posted.
+func (d *doctor) checkSignature(ctx context.Context) checkResult {
+	if d.profile.Secret == "" {
+		if d.lastCode == codeSignMismatch {
+			return checkResult{Status: statusFail, Detail: "the bot requires signed requests but the profile has no secret",
+				Fix: "copy the secret from the signature verification setting of the bot into the profile"}
+		}
+		return checkResult{Status: statusOK, Detail: "the bot accepts unsigned requests"}
+	}
+
+	timestamp := d.now().Unix()
+	sign, err := feishubot.GenSign(d.profile.Secret, timestamp)
+	if err != nil {
+		return checkResult{Status: statusFail, Detail: err.Error()}
+	}
+	resp, err := d.probe(ctx, map[string]interface{}{"timestamp": timestamp, "sign": sign})
+	if err != nil {
+		return checkResult{Status: statusFail, Detail: err.Error()}
+	}
+
+	skew := d.clockSkew()
+	if resp.Code == codeSignMismatch {
+		fix := "copy the secret again from the signature verification setting of the bot"
+		if skew > time.Hour || skew < -time.Hour {
+			fix = "synchronize the clock of this host, e.g. with NTP; signatures are only valid for an hour"
+		}
+		return checkResult{Status: statusFail, Detail: fmt.Sprintf("the signature was rejected (code %d: %s), clock skew %s", resp.Code, resp.Msg, skew), Fix: fix}
+	}
+	if skew > time.Minute || skew < -time.Minute {
+		return checkResult{Status: statusWarn, Detail: fmt.Sprintf("signature accepted, but the clock is off by %s", skew),
+			Fix: "synchronize the clock of this host, e.g. with NTP; signatures are only valid for an hour"}
+	}
+	return checkResult{Status: statusOK, Detail: "signature accepted"}
+}
+
+// checkKeyword posts a test message with -send, adding the keyword of the
+// profile.
+func (d *doctor) checkKeyword(ctx context.Context) checkResult {
+	if !d.send {
+		detail := "needs a test message, run with -send"
+		if d.profile.Keyword != "" {
+			detail = fmt.Sprintf("keyword %q configured; %s", d.profile.Keyword, detail)
+		}
+		return checkResult{Status: statusSkipped, Detail: detail}
+	}
+
+	msg := feishubot.NewTextMessage("feishu-send doctor: test message")
+	resp, err := d.client.Send(ctx, msg)
+	switch {
+	case resp != nil && resp.Code == codeKeywordNotFound && d.profile.Keyword == "":
+		return checkResult{Status: statusFail, Detail: "the bot requires a custom keyword",
+			Fix: "set keyword in the profile to one of the custom keywords in the bot security settings"}
+	case resp != nil && resp.Code == codeKeywordNotFound:
+		return checkResult{Status: statusFail, Detail: fmt.Sprintf("the keyword %q is not one of the keywords of the bot", d.profile.Keyword),
+			Fix: "set keyword in the profile to one of the custom keywords in the bot security settings"}
+	case err != nil:
+		return checkResult{Status: statusFail, Detail: fmt.Sprintf("the test message failed: %v", err)}
+	case d.profile.Keyword != "":
+		return checkResult{Status: statusOK, Detail: fmt.Sprintf("test message with keyword %q posted", d.profile.Keyword)}
+	}
+	return checkResult{Status: statusOK, Detail: "test message posted, no keyword required"}
+}
+
+// checkRateLimit reports the rate limit state of the last response.
+func (d *doctor) checkRateLimit(context.Context) checkResult {
+	if d.lastCode == codeFrequencyLimited {
+		return checkResult{Status: statusFail, Detail: "the webhook is rate limited right now",
+			Fix: "send less often, e.g. with Client.SetRateLimit or batching messages into cards; custom bots allow 5 requests per second and 100 per minute"}
+	}
+	if remaining := d.lastHeader.Get("X-Ogw-Ratelimit-Remaining"); remaining != "" {
+		return checkResult{Status: statusOK, Detail: fmt.Sprintf("%s of %s requests left in the current window",
+			remaining, d.lastHeader.Get("X-Ogw-Ratelimit-Limit"))}
+	}
+	return checkResult{Status: statusOK, Detail: "not rate limited; custom bots allow 5 requests per second and 100 per minute"}
+}
+
+// probe posts a request body to the webhook and decodes the response,
+// recording its code and headers.
+func (d *doctor) probe(ctx context.Context, body map[string]interface{}) (*feishubot.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.profile.WebhookURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	d.lastHeader = resp.Header
+	var apiResp feishubot.Response
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("unexpected response with status %s, not from a Feishu webhook", resp.Status)
+	}
+	d.lastCode = apiResp.Code
+	return &apiResp, nil
+}
+
+// clockSkew returns how far the local clock is ahead of the Date header of
+// the last response, zero if unknown.
+func (d *doctor) clockSkew() time.Duration {
+	date, err := http.ParseTime(d.lastHeader.Get("Date"))
+	if err != nil {
+		return 0
+	}
+	return d.now().Sub(date).Round(time.Second)
+}
+
+// printCheck prints the result of a check with its fix.
+func printCheck(w io.Writer, result checkResult) {
+	symbols := map[string]string{statusOK: "✔", statusWarn: "!", statusFail: "✘", statusSkipped: "-"}
+	fmt.Fprintf(w, "%s %-13s %s\n", symbols[result.Status], result.Name, result.Detail)
+	if result.Fix != "" {
+		fmt.Fprintf(w, "  fix: %s\n", result.Fix)
+	}
+}
+
+// profileLabel names a profile in the output.
+func profileLabel(profile *feishubot.Profile) string {
+	if profile.Name == "" || profile.Name == commandLineProfile {
+		return "webhook"
+	}
+	return fmt.Sprintf("profile %q", profile.Name)
+}
+
+// maskWebhookURL hides all but the last four characters of the token of a
+// webhook URL.
+func maskWebhookURL(webhookURL string) string {
+	i := strings.Index(webhookURL, webhookPathPrefix)
+	if i < 0 {
+		return webhookURL
+	}
+	token := webhookURL[i+len(webhookPathPrefix):]
+	if len(token) <= 4 {
+		return webhookURL
+	}
+	return webhookURL[:i+len(webhookPathPrefix)] + "…" + token[len(token)-4:]
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+// newFeishu starts a TLS webhook server checking the token, the signature
+// with secret, if set, and the keyword, if set, like Feishu.
+func newFeishu(t *testing.T, secret, keyword string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply := func(code int, msg string) {
+			json.NewEncoder(w).Encode(feishubot.Response{Code: code, Msg: msg})
+		}
+		if r.URL.Path != webhookPathPrefix+"token" {
+			reply(codeTokenInvalid, "param invalid: incoming webhook access token invalid")
+			return
+		}
+		var msg feishubot.Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		if secret != "" {
+			sign, err := feishubot.GenSign(secret, msg.Timestamp)
+			require.NoError(t, err)
+			if msg.Sign != sign {
+				reply(codeSignMismatch, "sign match fail or timestamp is not within one hour from current time")
+				return
+			}
+		}
+		if msg.MsgType == "" {
+			reply(9499, "Bad Request")
+			return
+		}
+		if text, _ := msg.Content["text"].(string); keyword != "" && !strings.Contains(text, keyword) {
+			reply(codeKeywordNotFound, "Key Words Not Found")
+			return
+		}
+		reply(0, "success")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// diagnose runs the checks of a profile against a server.
+func diagnose(t *testing.T, server *httptest.Server, profile *feishubot.Profile, send bool) map[string]checkResult {
+	t.Helper()
+	d, err := newDoctor(profile)
+	require.NoError(t, err)
+	d.send = send
+	if d.client != nil {
+		d.client.SetHTTPClient(server.Client())
+		d.httpClient = server.Client()
+		d.rootCAs = x509.NewCertPool()
+		d.rootCAs.AddCert(server.Certificate())
+	}
+
+	results := make(map[string]checkResult)
+	for _, result := range d.run(context.Background()) {
+		results[result.Name] = result
+	}
+	return results
+}
+
+func TestDoctor(t *testing.T) {
+	server := newFeishu(t, "s3cret", "[alert]")
+	profile := &feishubot.Profile{Name: "oncall", WebhookURL: server.URL + webhookPathPrefix + "token", Secret: "s3cret", Keyword: "[alert]"}
+
+	results := diagnose(t, server, profile, true)
+	require.Len(t, results, 7)
+	require.Equal(t, statusWarn, results["URL syntax"].Status)
+	require.Contains(t, results["URL syntax"].Detail, "127.0.0.1 is not a Feishu or Lark host")
+	for _, name := range []string{"DNS", "TLS", "connectivity", "signature", "keyword", "rate limit"} {
+		require.Equal(t, statusOK, results[name].Status, "%s: %s", name, results[name].Detail)
+	}
+	require.Equal(t, `test message with keyword "[alert]" posted`, results["keyword"].Detail)
+
+	results = diagnose(t, server, profile, false)
+	require.Equal(t, statusSkipped, results["keyword"].Status)
+	require.Equal(t, `keyword "[alert]" configured; needs a test message, run with -send`, results["keyword"].Detail)
+}
+
+func TestDoctorFailures(t *testing.T) {
+	server := newFeishu(t, "s3cret", "[alert]")
+	webhook := server.URL + webhookPathPrefix + "token"
+
+	results := diagnose(t, server, &feishubot.Profile{WebhookURL: webhook}, false)
+	require.Equal(t, statusFail, results["signature"].Status)
+	require.Contains(t, results["signature"].Fix, "copy the secret")
+	require.Equal(t, statusSkipped, results["keyword"].Status)
+	require.Equal(t, "signature check failed", results["rate limit"].Detail)
+
+	results = diagnose(t, server, &feishubot.Profile{WebhookURL: webhook, Secret: "wrong"}, false)
+	require.Equal(t, statusFail, results["signature"].Status)
+	require.Contains(t, results["signature"].Detail, "the signature was rejected (code 19021")
+
+	results = diagnose(t, server, &feishubot.Profile{WebhookURL: webhook, Secret: "s3cret"}, true)
+	require.Equal(t, statusOK, results["signature"].Status)
+	require.Equal(t, statusFail, results["keyword"].Status)
+	require.Equal(t, "the bot requires a custom keyword", results["keyword"].Detail)
+
+	results = diagnose(t, server, &feishubot.Profile{WebhookURL: server.URL + webhookPathPrefix + "revoked"}, false)
+	require.Equal(t, statusFail, results["connectivity"].Status)
+	require.Contains(t, results["connectivity"].Fix, "copy the webhook URL again")
+
+	results = diagnose(t, server, &feishubot.Profile{WebhookURL: server.URL + "/hook"}, false)
+	require.Equal(t, statusFail, results["URL syntax"].Status)
+	require.Equal(t, statusSkipped, results["DNS"].Status)
+
+	// The path is checked regardless of the scheme
+	results = diagnose(t, server, &feishubot.Profile{WebhookURL: "http://open.feishu.cn/hook"}, false)
+	require.Equal(t, statusFail, results["URL syntax"].Status)
+	require.Equal(t, `the path "/hook" is not a webhook path`, results["URL syntax"].Detail)
+
+	d, err := newDoctor(&feishubot.Profile{WebhookURL: "http://example.com" + webhookPathPrefix + "token"})
+	require.NoError(t, err)
+	result := d.checkURL(context.Background())
+	require.Equal(t, statusWarn, result.Status)
+	require.Equal(t, "example.com is not a Feishu or Lark host; the URL is not https", result.Detail)
+
+	results = diagnose(t, server, &feishubot.Profile{Name: "empty"}, false)
+	require.Equal(t, statusFail, results["URL syntax"].Status)
+	require.Equal(t, `profile "empty" has no webhook`, results["URL syntax"].Detail)
+}
+
+func TestDoctorCommand(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("FEISHUROBOT_CONFIG", writeFile(t, "config.yaml", "profiles:\n  broken:\n    url: open.feishu.cn/hook\n"))
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitIssues, run([]string{"doctor", "-profile", "broken"}, &stdout, &stderr), stderr.String())
+	require.Contains(t, stdout.String(), `Checking profile "broken"`)
+	require.Contains(t, stdout.String(), "✘ URL syntax")
+	require.Contains(t, stdout.String(), "  fix: copy the webhook URL from the bot settings")
+
+	require.Equal(t, exitError, run([]string{"doctor", "-profile", "missing"}, io.Discard, &stderr))
+	require.Contains(t, stderr.String(), `profile not found: "missing"`)
+}
+
+func TestMaskWebhookURL(t *testing.T) {
+	require.Equal(t, "https://open.feishu.cn/open-apis/bot/v2/hook/…cdef", maskWebhookURL("https://open.feishu.cn/open-apis/bot/v2/hook/0123-abcdef"))
+	require.Equal(t, "https://example.com/hook", maskWebhookURL("https://example.com/hook"))
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// lintResult is the result of linting a file, as printed with -json.
+type lintResult struct {
+	File   string      `json:"file"`
+	Error  string      `json:"error,omitempty"`
+	Issues []lintIssue `json:"issues"`
+}
+
+// lintIssue is an issue of a card, as printed with -json.
+type lintIssue struct {
+	Path    string `json:"path"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// runLint lints card files. It exits with status 1 if issues were found and
+// 2 if files could not be read or parsed.
+func runLint(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	jsonOutput := flags.Bool("json", false, "print results as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: feishu-send lint [-json] card.json...")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, `Checks card JSON, or message JSON with an interactive card, for schema issues. Use "-" to read standard input.`)
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitError
+	}
+
+	status := exitOK
+	results := make([]lintResult, 0, flags.NArg())
+	for _, path := range flags.Args() {
+		result := lintFile(path)
+		results = append(results, result)
+
+		switch {
+		case result.Error != "":
+			status = exitError
+			if !*jsonOutput {
+				fmt.Fprintf(stderr, "%s: %s\n", path, result.Error)
+			}
+		case len(result.Issues) > 0:
+			if status == exitOK {
+				status = exitIssues
+			}
+			if !*jsonOutput {
+				for _, issue := range result.Issues {
+					fmt.Fprintf(stdout, "%s: %s: %s [%s]\n", path, issue.Path, issue.Message, issue.Rule)
+				}
+			}
+		}
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			fmt.Fprintf(stderr, "feishu-send: %v\n", err)
+			return exitError
+		}
+	}
+	return status
+}
+
+// lintFile lints the card of a file.
+func lintFile(path string) lintResult {
+	result := lintResult{File: path, Issues: []lintIssue{}}
+
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	card, prefix, err := parseCardFile(data)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	for _, issue := range card.Lint() {
+		issuePath := prefix
+		if issue.Path != "" {
+			if issuePath != "" {
+				issuePath += "."
+			}
+			issuePath += issue.Path
+		}
+		if issuePath == "" {
+			issuePath = "$"
+		}
+		result.Issues = append(result.Issues, lintIssue{Path: issuePath, Rule: issue.Rule, Message: issue.Message})
+	}
+	return result
+}
+
+// parseCardFile parses card JSON or the card of interactive message JSON,
+// returning the path prefix of the card in the file.
+func parseCardFile(data []byte) (*feishubot.Card, string, error) {
+	var message struct {
+		MsgType feishubot.MsgType `json:"msg_type"`
+		Card    json.RawMessage   `json:"card"`
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, "", syntaxError(data, err)
+	}
+
+	prefix := ""
+	if message.MsgType != "" {
+		if message.MsgType != feishubot.MsgTypeInteractive || len(message.Card) == 0 {
+			return nil, "", fmt.Errorf("message of type %q has no card", message.MsgType)
+		}
+		data, prefix = message.Card, "card"
+	}
+	card, err := feishubot.ParseCard(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return card, prefix, nil
+}
+
+// syntaxError adds the line and column to JSON syntax errors.
+func syntaxError(data []byte, err error) error {
+	var syntax *json.SyntaxError
+	if !errors.As(err, &syntax) {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	before := data[:syntax.Offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	// Offset is just past the offending byte
+	column := len(before) - bytes.LastIndexByte(before, '\n') - 1
+	return fmt.Errorf("invalid JSON at line %d, column %d: %w", line, column, err)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes a file in a temporary directory and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+const validCard = `{
+  "schema": "2.0",
+  "header": {"title": {"tag": "plain_text", "content": "Deploy"}, "template": "blue"},
+  "body": {"elements": [{"tag": "markdown", "content": "done"}]}
+}`
+
+const invalidCard = `{
+  "schema": "2.0",
+  "header": {"title": {"tag": "plain_text", "content": "Deploy"}, "template": "pink"},
+  "body": {"elements": [{"tag": "markdown", "content": "done"}, {"tag": "bogus"}]}
+}`
+
+func TestLint(t *testing.T) {
+	valid := writeFile(t, "valid.json", validCard)
+	invalid := writeFile(t, "invalid.json", invalidCard)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitOK, run([]string{"lint", valid}, &stdout, &stderr))
+	require.Empty(t, stdout.String())
+
+	stdout.Reset()
+	require.Equal(t, exitIssues, run([]string{"lint", valid, invalid}, &stdout, &stderr))
+	require.Equal(t, invalid+`: header.template: unknown header template "pink" [header-template]`+"\n"+
+		invalid+`: body.elements[1]: unknown element tag "bogus" [unknown-tag]`+"\n", stdout.String())
+	require.Empty(t, stderr.String())
+}
+
+func TestLintMessage(t *testing.T) {
+	message := writeFile(t, "message.json", `{"msg_type": "interactive", "card": `+invalidCard+`}`)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitIssues, run([]string{"lint", "-json", message}, &stdout, &stderr))
+
+	var results []lintResult
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+	require.Len(t, results, 1)
+	require.Equal(t, []lintIssue{
+		{Path: "card.header.template", Rule: "header-template", Message: `unknown header template "pink"`},
+		{Path: "card.body.elements[1]", Rule: "unknown-tag", Message: `unknown element tag "bogus"`},
+	}, results[0].Issues)
+
+	text := writeFile(t, "text.json", `{"msg_type": "text", "content": {"text": "hi"}}`)
+	stdout.Reset()
+	require.Equal(t, exitError, run([]string{"lint", text}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), `message of type "text" has no card`)
+}
+
+func TestLintErrors(t *testing.T) {
+	broken := writeFile(t, "broken.json", "{\n  \"schema\": \"2.0\",\n  \"body\": x\n}")
+	invalid := writeFile(t, "invalid.json", invalidCard)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitError, run([]string{"lint", broken, invalid, filepath.Join(t.TempDir(), "missing.json")}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "broken.json: invalid JSON at line 3, column 11")
+	require.Contains(t, stderr.String(), "missing.json: open")
+	require.Contains(t, stdout.String(), "unknown-tag")
+
+	stderr.Reset()
+	require.Equal(t, exitError, run([]string{"lint"}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "Usage: feishu-send lint")
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitError, run([]string{"bogus"}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), `unknown command "bogus"`)
+	require.Contains(t, stderr.String(), "lint")
+}
@@ -0,0 +1,98 @@
+// Command feishu-send works with Feishu messages and cards from the command
+// line.
+//
+// Usage:
+//
+//	feishu-send send [-profile name] [-markdown] [-card card.json] [text...]
+//	feishu-send batch [-profile name] [-json] messages.jsonl
+//	feishu-send lint [-json] card.json...
+//	feishu-send preview [-watch] [-addr host:port] card.json
+//	feishu-send doctor [-profile name] [-send]
+//
+// send sends a text, markdown cards or a card file to a custom bot
+// webhook: the -profile of the profiles file, see feishubot.LoadProfile,
+// the -webhook and -secret, also read from the FEISHU_WEBHOOK_URL and
+// FEISHU_SECRET environment variables, or the default profile.
+//
+// batch sends one message JSON per line of a file, rate limited and with
+// retries, and prints the result of each line, e.g. for backfills and
+// announcements generated by scripts. It exits with status 1 if sending
+// any line failed.
+//
+// lint checks card JSON offline, see Card.Lint, and reports issues with
+// their JSON paths, e.g. in CI to catch broken cards checked into a
+// repository before they fail at runtime.
+//
+// preview serves an approximate HTML preview of a card, see
+// cardpreview.HTML, with its lint issues. With -watch, the page reloads
+// when the file is saved, for designing cards without sending test
+// messages.
+//
+// doctor diagnoses a webhook step by step, checking the URL, DNS, TLS,
+// connectivity, the signature, the custom keyword and the rate limit, and
+// prints a fix for each failed check. The signature is checked with a
+// request without content, which is not posted; checking the keyword posts
+// a test message and is only done with -send.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit statuses.
+const (
+	exitOK     = 0
+	exitIssues = 1
+	exitError  = 2
+)
+
+// command is a subcommand.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdout, stderr io.Writer) int
+}
+
+// commands are the subcommands, in the order of the usage.
+var commands = []command{
+	{name: "send", usage: "send a text, markdown or card message", run: runSend},
+	{name: "batch", usage: "send the messages of a JSONL file", run: runBatch},
+	{name: "lint", usage: "check card JSON files for schema issues", run: runLint},
+	{name: "preview", usage: "serve a local HTML preview of a card file", run: runPreview},
+	{name: "doctor", usage: "diagnose a webhook and suggest fixes", run: runDoctor},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the subcommand named by the first argument and returns the exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		return exitError
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdout, stderr)
+		}
+	}
+	fmt.Fprintf(stderr, "feishu-send: unknown command %q\n", args[0])
+	usage(stderr)
+	return exitError
+}
+
+// usage prints the subcommands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: feishu-send <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "feishu-send <command> -h" for the flags of a command.`)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cium-cc/feishurobot/cardpreview"
+)
+
+// reloadScript reloads the preview when the server reports a change of the
+// card file.
+const reloadScript = `<script>
+new EventSource("/events").onmessage = function() { location.reload(); };
+</script>
+`
+
+// runPreview serves an HTML preview of a card file until interrupted.
+func runPreview(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("preview", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", "localhost:8090", "address to serve the preview on")
+	watch := flags.Bool("watch", false, "reload the preview when the file changes")
+	interval := flags.Duration("interval", 500*time.Millisecond, "interval of checking the file for changes")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: feishu-send preview [-watch] [-addr host:port] card.json")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Serves an approximate HTML preview of card JSON, or message JSON with an interactive card.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitError
+	}
+	path := flags.Arg(0)
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintf(stderr, "feishu-send: %v\n", err)
+		return exitError
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "feishu-send: %v\n", err)
+		return exitError
+	}
+	preview := newPreviewServer(path, *watch)
+	if *watch {
+		go preview.watch(ctx, *interval)
+	}
+	server := &http.Server{Handler: preview, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(stdout, "Serving preview of %s at http://%s/\n", path, listener.Addr())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "feishu-send: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// previewServer serves the preview of a card file, read on each request.
+type previewServer struct {
+	path       string
+	liveReload bool
+
+	mu sync.Mutex
+	// changed is closed and replaced when the file changes.
+	changed chan struct{}
+}
+
+// newPreviewServer creates a server previewing the card file at path.
+// With liveReload, pages reload when watch detects a change.
+func newPreviewServer(path string, liveReload bool) *previewServer {
+	return &previewServer{path: path, liveReload: liveReload, changed: make(chan struct{})}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *previewServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case "/":
+		s.servePreview(w)
+	case "/events":
+		if !s.liveReload {
+			http.NotFound(w, req)
+			return
+		}
+		s.serveEvents(w, req)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// servePreview renders the card file, or the error reading it, followed by
+// its lint issues.
+func (s *previewServer) servePreview(w http.ResponseWriter) {
+	page, err := s.render()
+	if err != nil {
+		page = "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Card preview</title>\n</head>\n<body>\n" +
+			"<pre style=\"color:#f54a45\">" + html.EscapeString(err.Error()) + "</pre>\n</body>\n</html>\n"
+	}
+	if s.liveReload {
+		page = strings.Replace(page, "</body>", reloadScript+"</body>", 1)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	io.WriteString(w, page)
+}
+
+// render returns the preview page of the card file, listing lint issues
+// below the card.
+func (s *previewServer) render() (string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", err
+	}
+	card, _, err := parseCardFile(data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", s.path, err)
+	}
+	page, err := cardpreview.HTML(card)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", s.path, err)
+	}
+
+	if issues := card.Lint(); len(issues) > 0 {
+		var b strings.Builder
+		b.WriteString("<ul style=\"max-width:600px;margin:0 auto;color:#f54a45\">\n")
+		for _, issue := range issues {
+			fmt.Fprintf(&b, "<li>%s [%s]</li>\n", html.EscapeString(issue.String()), html.EscapeString(issue.Rule))
+		}
+		b.WriteString("</ul>\n")
+		page = strings.Replace(page, "</body>", b.String()+"</body>", 1)
+	}
+	return page, nil
+}
+
+// serveEvents sends a server-sent event each time the file changes, until
+// the client disconnects.
+func (s *previewServer) serveEvents(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		s.mu.Lock()
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-req.Context().Done():
+			return
+		case <-changed:
+		}
+		if _, err := io.WriteString(w, "data: reload\n\n"); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// watch checks the modification time and size of the file every interval
+// and notifies event streams of changes, until ctx is done.
+func (s *previewServer) watch(ctx context.Context, interval time.Duration) {
+	last := fileVersion(s.path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if version := fileVersion(s.path); version != last {
+			last = version
+			s.notify()
+		}
+	}
+}
+
+// notify wakes up the event streams waiting for a change.
+func (s *previewServer) notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// fileVersion identifies the version of a file by its modification time
+// and size, empty if the file does not exist.
+func fileVersion(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// getPreview returns the preview page of server.
+func getPreview(t *testing.T, server *previewServer) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	return rec.Body.String()
+}
+
+func TestPreview(t *testing.T) {
+	path := writeFile(t, "card.json", validCard)
+	server := newPreviewServer(path, false)
+
+	page := getPreview(t, server)
+	require.Contains(t, page, "Deploy")
+	require.NotContains(t, page, "EventSource")
+
+	require.NoError(t, os.WriteFile(path, []byte(invalidCard), 0o600))
+	page = getPreview(t, server)
+	require.Contains(t, page, `header.template: unknown header template &#34;pink&#34; [header-template]`)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"schema": "2.0", "body": x}`), 0o600))
+	require.Contains(t, getPreview(t, server), "invalid JSON at line 1, column 27")
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPreviewLiveReload(t *testing.T) {
+	path := writeFile(t, "card.json", validCard)
+	preview := newPreviewServer(path, true)
+	require.Contains(t, getPreview(t, preview), `new EventSource("/events")`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go preview.watch(ctx, 10*time.Millisecond)
+
+	server := httptest.NewServer(preview)
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Make sure the modification time changes even on coarse file systems
+	require.NoError(t, os.WriteFile(path, []byte(invalidCard), 0o600))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "data: reload\n", line)
+}
+
+func TestRunPreviewErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitError, run([]string{"preview"}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "Usage: feishu-send preview")
+
+	stderr.Reset()
+	require.Equal(t, exitError, run([]string{"preview", "missing.json"}, io.Discard, &stderr))
+	require.Contains(t, stderr.String(), "missing.json")
+}
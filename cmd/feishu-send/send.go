@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// maxMarkdownCardBytes is the markdown per card sent with -markdown, below
+// the request size limit of webhooks.
+const maxMarkdownCardBytes = 18000
+
+// runSend sends a text, markdown or card message.
+func runSend(args []string, stdout, stderr io.Writer) int {
+	return runSendFrom(os.Stdin, args, stdout, stderr)
+}
+
+// runSendFrom is runSend reading text from stdin.
+func runSendFrom(stdin io.Reader, args []string, _, stderr io.Writer) int {
+	flags := flag.NewFlagSet("send", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	client := addClientFlags(flags)
+	cardPath := flags.String("card", "", "send the card of a card or message JSON file")
+	markdown := flags.Bool("markdown", false, "send the text as markdown cards")
+	title := flags.String("title", "", "title of markdown cards")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout of sending")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: feishu-send send [-profile name] [-markdown [-title title]] [text...]")
+		fmt.Fprintln(stderr, "       feishu-send send [-profile name] -card card.json")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, `Sends the text of the arguments, or of standard input if there are none or the only one is "-".`)
+		fmt.Fprintln(stderr, "The webhook is the -profile from the profiles file, the -webhook and -secret, or the default profile.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	messages, err := sendMessages(stdin, flags.Args(), *cardPath, *markdown, *title)
+	if err != nil {
+		fmt.Fprintf(stderr, "feishu-send: %v\n", err)
+		return exitError
+	}
+	c, err := client.newClient()
+	if err != nil {
+		fmt.Fprintf(stderr, "feishu-send: %v\n", err)
+		return exitError
+	}
+	c.SetWarningHandler(func(warning string) {
+		fmt.Fprintf(stderr, "feishu-send: warning: %s\n", warning)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	for _, msg := range messages {
+		if _, err := c.Send(ctx, msg); err != nil {
+			fmt.Fprintf(stderr, "feishu-send: failed to send: %v\n", err)
+			return exitError
+		}
+	}
+	return exitOK
+}
+
+// sendMessages returns the messages to send for the arguments.
+func sendMessages(stdin io.Reader, args []string, cardPath string, markdown bool, title string) ([]*feishubot.Message, error) {
+	if cardPath != "" {
+		if len(args) > 0 {
+			return nil, errors.New("-card takes no text arguments")
+		}
+		data, err := os.ReadFile(cardPath)
+		if err != nil {
+			return nil, err
+		}
+		card, _, err := parseCardFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cardPath, err)
+		}
+		return []*feishubot.Message{feishubot.NewInteractiveMessage(card)}, nil
+	}
+
+	text := strings.Join(args, " ")
+	if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read standard input: %w", err)
+		}
+		text = strings.TrimRight(string(data), "\n")
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("no text to send")
+	}
+
+	if !markdown {
+		return []*feishubot.Message{feishubot.NewTextMessage(text)}, nil
+	}
+	var messages []*feishubot.Message
+	for _, card := range feishubot.NewMarkdownCards(title, text, maxMarkdownCardBytes) {
+		messages = append(messages, feishubot.NewInteractiveMessage(card))
+	}
+	return messages, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+// newWebhook starts a webhook server recording the messages it receives.
+func newWebhook(t *testing.T, received *[]*feishubot.Message) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg feishubot.Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		*received = append(*received, &msg)
+		json.NewEncoder(w).Encode(feishubot.Response{Msg: "success"})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// clearEnv unsets the environment variables selecting the webhook.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"FEISHU_PROFILE", "FEISHU_WEBHOOK_URL", "FEISHU_SECRET"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("FEISHUROBOT_CONFIG", writeFile(t, "config.yaml", "profiles: {}\n"))
+}
+
+func TestSendProfile(t *testing.T) {
+	clearEnv(t)
+	var received []*feishubot.Message
+	server := newWebhook(t, &received)
+	t.Setenv("FEISHUROBOT_CONFIG", writeFile(t, "config.yaml", `default: team
+profiles:
+  team:
+    url: `+server.URL+`/team
+  oncall:
+    url: `+server.URL+`/oncall
+    secret: s3cret
+    keyword: "[alert]"
+`))
+
+	var stderr bytes.Buffer
+	require.Equal(t, exitOK, runSendFrom(nil, []string{"-profile", "oncall", "Disk", "full"}, io.Discard, &stderr), stderr.String())
+	require.Len(t, received, 1)
+	require.Equal(t, "Disk full\n[alert]", received[0].Content["text"])
+	require.NotEmpty(t, received[0].Sign)
+
+	require.Equal(t, exitOK, runSendFrom(strings.NewReader("from stdin\n"), nil, io.Discard, &stderr), stderr.String())
+	require.Len(t, received, 2)
+	require.Equal(t, "from stdin", received[1].Content["text"])
+	require.Empty(t, received[1].Sign)
+
+	require.Equal(t, exitError, runSendFrom(nil, []string{"-profile", "missing", "hi"}, io.Discard, &stderr))
+	require.Contains(t, stderr.String(), `profile not found: "missing"`)
+}
+
+func TestSendWebhook(t *testing.T) {
+	clearEnv(t)
+	var received []*feishubot.Message
+	server := newWebhook(t, &received)
+
+	var stderr bytes.Buffer
+	args := []string{"-webhook", server.URL, "-markdown", "-title", "Release notes", "**v1.2.0** is out"}
+	require.Equal(t, exitOK, runSendFrom(nil, args, io.Discard, &stderr), stderr.String())
+	require.Len(t, received, 1)
+	require.Equal(t, feishubot.MsgTypeInteractive, received[0].MsgType)
+
+	card := writeFile(t, "card.json", validCard)
+	require.Equal(t, exitOK, runSendFrom(nil, []string{"-webhook", server.URL, "-card", card}, io.Discard, &stderr), stderr.String())
+	require.Len(t, received, 2)
+	data, err := json.Marshal(received[1].Card)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "Deploy")
+}
+
+func TestSendErrors(t *testing.T) {
+	clearEnv(t)
+
+	var stderr bytes.Buffer
+	require.Equal(t, exitError, runSendFrom(strings.NewReader("\n"), []string{"-webhook", "https://example.com"}, io.Discard, &stderr))
+	require.Contains(t, stderr.String(), "no text to send")
+
+	stderr.Reset()
+	require.Equal(t, exitError, runSendFrom(nil, []string{"hi"}, io.Discard, &stderr))
+	require.Contains(t, stderr.String(), "no profile name given")
+
+	stderr.Reset()
+	require.Equal(t, exitError, runSendFrom(nil, []string{"-card", "card.json", "hi"}, io.Discard, &stderr))
+	require.Contains(t, stderr.String(), "-card takes no text arguments")
+}
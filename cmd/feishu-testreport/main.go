@@ -0,0 +1,61 @@
+// Command feishu-testreport posts a summary card of "go test -json" output
+// read from standard input to a Feishu custom bot webhook, e.g. for nightly
+// test runs:
+//
+//	go test -json ./... | tee test.json | feishu-testreport -title "Nightly tests"
+//
+// The webhook URL and secret are read from the FEISHU_WEBHOOK_URL and
+// FEISHU_SECRET environment variables unless given as flags. The command
+// exits with status 1 if tests failed, so that scripts still fail.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/testreport"
+)
+
+func main() {
+	webhookURL := flag.String("webhook", os.Getenv("FEISHU_WEBHOOK_URL"), "webhook URL of the custom bot")
+	secret := flag.String("secret", os.Getenv("FEISHU_SECRET"), "signing secret of the custom bot")
+	title := flag.String("title", "Test results", "title of the card")
+	onlyFailures := flag.Bool("only-failures", false, "post only if tests failed")
+	flag.Parse()
+
+	if *webhookURL == "" {
+		fatalf("no webhook URL: set FEISHU_WEBHOOK_URL or -webhook")
+	}
+
+	report, err := testreport.Parse(os.Stdin)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if len(report.Packages) == 0 {
+		fatalf("no test results read; pipe the output of go test -json")
+	}
+
+	if !report.OK() || !*onlyFailures {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		client := feishubot.NewClient(*webhookURL, *secret)
+		if _, err := client.Send(ctx, feishubot.NewInteractiveMessage(report.Card(*title))); err != nil {
+			fatalf("failed to post report: %v", err)
+		}
+	}
+
+	fmt.Printf("%d passed, %d failed, %d skipped\n", report.Passed, report.Failed, report.Skipped)
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+// fatalf prints an error and exits with status 2.
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "feishu-testreport: "+format+"\n", args...)
+	os.Exit(2)
+}
@@ -0,0 +1,60 @@
+package feishubot
+
+// NewCollapsiblePanelElement creates a collapsible panel showing a title and
+// hiding its elements until expanded. The title may contain markdown.
+//
+// Example:
+//
+//	panel := feishubot.NewCollapsiblePanelElement("**Stack trace**", false,
+//		feishubot.NewPlainTextElement(stackTrace, feishubot.WithTextSize("notation")),
+//	)
+func NewCollapsiblePanelElement(title string, expanded bool, elements ...CardElement) CardElement {
+	if elements == nil {
+		elements = []CardElement{}
+	}
+
+	return MapElement{
+		"tag":      "collapsible_panel",
+		"expanded": expanded,
+		"header": map[string]interface{}{
+			"title": &CardTitle{Tag: "markdown", Content: title},
+		},
+		"elements": elements,
+	}
+}
+
+// CollapsiblePage is a page of a card created with NewCollapsiblePagesCard.
+type CollapsiblePage struct {
+	// Title is shown when the page is collapsed. It may contain markdown.
+	Title    string
+	Elements []CardElement
+}
+
+// NewCollapsiblePagesCard creates a card holding several pages, e.g. one
+// status summary per service, so they can be sent as a single message
+// instead of one card per page. Each page is a collapsible panel, stacked
+// vertically, with only the first page expanded; readers expand the pages
+// they are interested in. Feishu has no swipeable carousel of cards.
+//
+// Example:
+//
+//	card := feishubot.NewCollapsiblePagesCard("Service status",
+//		feishubot.CollapsiblePage{Title: "✅ api", Elements: []feishubot.CardElement{
+//			feishubot.NewMarkdownElement("p99 120ms, 0 errors"),
+//		}},
+//		feishubot.CollapsiblePage{Title: "❌ worker", Elements: []feishubot.CardElement{
+//			feishubot.NewMarkdownElement("queue backlog 12k"),
+//		}},
+//	)
+func NewCollapsiblePagesCard(title string, pages ...CollapsiblePage) *Card {
+	elements := make([]CardElement, 0, len(pages))
+	for i, page := range pages {
+		elements = append(elements, NewCollapsiblePanelElement(page.Title, i == 0, page.Elements...))
+	}
+
+	card := NewCard("2.0").SetBody(&CardBody{Elements: elements})
+	if title != "" {
+		card.SetHeader(&CardHeader{Title: NewCardTitle(title)})
+	}
+	return card
+}
@@ -0,0 +1,79 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCollapsiblePanelElement(t *testing.T) {
+	data, err := json.Marshal(NewCollapsiblePanelElement("**Details**", true, NewMarkdownElement("body")))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"tag": "collapsible_panel",
+		"expanded": true,
+		"header": {"title": {"tag": "markdown", "content": "**Details**"}},
+		"elements": [{"tag": "markdown", "content": "body"}]
+	}`, string(data))
+
+	data, err = json.Marshal(NewCollapsiblePanelElement("Empty", false))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"tag": "collapsible_panel",
+		"expanded": false,
+		"header": {"title": {"tag": "markdown", "content": "Empty"}},
+		"elements": []
+	}`, string(data))
+}
+
+func TestNewCollapsiblePagesCard(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		pages []CollapsiblePage
+		want  string
+	}{
+		{
+			name:  "pages",
+			title: "Service status",
+			pages: []CollapsiblePage{
+				{Title: "api", Elements: []CardElement{NewMarkdownElement("ok")}},
+				{Title: "worker", Elements: []CardElement{NewMarkdownElement("backlog")}},
+			},
+			want: `{
+				"schema": "2.0",
+				"header": {"title": {"tag": "plain_text", "content": "Service status"}},
+				"body": {"elements": [
+					{
+						"tag": "collapsible_panel",
+						"expanded": true,
+						"header": {"title": {"tag": "markdown", "content": "api"}},
+						"elements": [{"tag": "markdown", "content": "ok"}]
+					},
+					{
+						"tag": "collapsible_panel",
+						"expanded": false,
+						"header": {"title": {"tag": "markdown", "content": "worker"}},
+						"elements": [{"tag": "markdown", "content": "backlog"}]
+					}
+				]}
+			}`,
+		},
+		{
+			name: "no title and pages",
+			want: `{"schema": "2.0", "body": {"elements": []}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := NewCollapsiblePagesCard(tt.title, tt.pages...)
+			require.Empty(t, card.Lint())
+
+			data, err := json.Marshal(card)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package feishubot
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Color tokens for element colors such as text colors, icon colors and
+// background styles. Feishu maps these tokens to matching colors in light
+// and dark theme, unlike hard-coded RGB values.
+const (
+	ColorDefault   = "default"
+	ColorNeutral   = "neutral"
+	ColorGrey      = "grey"
+	ColorBlue      = "blue"
+	ColorWathet    = "wathet"
+	ColorTurquoise = "turquoise"
+	ColorGreen     = "green"
+	ColorLime      = "lime"
+	ColorYellow    = "yellow"
+	ColorOrange    = "orange"
+	ColorRed       = "red"
+	ColorCarmine   = "carmine"
+	ColorViolet    = "violet"
+	ColorPurple    = "purple"
+	ColorIndigo    = "indigo"
+)
+
+// Shade returns the token of a lighter or darker shade of a color token,
+// e.g. Shade(ColorGreen, 500) returns "green-500". Shades are 50 and 100
+// to 900 in steps of 100; they adapt to dark mode like the base tokens.
+func Shade(color string, level int) string {
+	return fmt.Sprintf("%s-%d", color, level)
+}
+
+// SetCustomColor defines a named color with separate values for light and
+// dark theme, given as "rgba(r,g,b,a)" or "#rrggbb". The name can then be
+// used wherever a color token is accepted, for brand colors that have no
+// matching token.
+//
+// Example:
+//
+//	card.SetCustomColor("brand", "rgba(51,112,255,1)", "rgba(76,136,255,1)")
+//	text := feishubot.NewPlainTextElement("ACME", feishubot.WithTextColor("brand"))
+func (c *Card) SetCustomColor(name, light, dark string) *Card {
+	if c.Config == nil {
+		c.Config = make(map[string]interface{})
+	}
+	style, _ := c.Config["style"].(map[string]interface{})
+	if style == nil {
+		style = make(map[string]interface{})
+		c.Config["style"] = style
+	}
+	colors, _ := style["color"].(map[string]interface{})
+	if colors == nil {
+		colors = make(map[string]interface{})
+		style["color"] = colors
+	}
+
+	colors[name] = map[string]interface{}{
+		"light_mode": light,
+		"dark_mode":  dark,
+	}
+	return c
+}
+
+// customColors returns the names of the colors defined in the card config
+// with SetCustomColor.
+func (c *Card) customColors() map[string]bool {
+	names := make(map[string]bool)
+	style, _ := c.Config["style"].(map[string]interface{})
+	colors, _ := style["color"].(map[string]interface{})
+	for name := range colors {
+		names[name] = true
+	}
+	return names
+}
+
+// colorKeys are the element fields holding colors.
+var colorKeys = map[string]bool{
+	"color":            true,
+	"text_color":       true,
+	"background_style": true,
+	"border_color":     true,
+	"bg_color":         true,
+}
+
+// fontColorPattern matches the color of <font> tags in markdown.
+var fontColorPattern = regexp.MustCompile(`<font\s+color\s*=\s*['"]?([^'"\s>]+)`)
+
+// isFixedColor reports whether color is a hard-coded color that does not
+// adapt to dark mode, such as an RGB value, white or black.
+func isFixedColor(color string) bool {
+	color = strings.ToLower(strings.TrimSpace(color))
+	return strings.HasPrefix(color, "#") || strings.HasPrefix(color, "rgb") ||
+		color == "white" || color == "black"
+}
+
+// darkModeIssues returns issues for fixed colors in card, which must
+// marshal to a card JSON object, that are not custom colors.
+func darkModeIssues(card interface{}, custom map[string]bool) []Issue {
+	var issues []Issue
+	report := func(path, color string) {
+		if custom[color] || !isFixedColor(color) {
+			return
+		}
+		issues = append(issues, Issue{
+			Path:    path,
+			Rule:    RuleDarkMode,
+			Message: fmt.Sprintf("color %q does not adapt to dark mode, use a color token or Card.SetCustomColor", color),
+		})
+	}
+
+	err := walkCard(card, func(path string, elem map[string]interface{}) {
+		keys := make([]string, 0, len(elem))
+		for key := range elem {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value, ok := elem[key].(string)
+			if !ok {
+				continue
+			}
+			if colorKeys[key] {
+				report(path+"."+key, value)
+			}
+			if key == "content" {
+				for _, match := range fontColorPattern.FindAllStringSubmatch(value, -1) {
+					report(path+".content", match[1])
+				}
+			}
+		}
+	})
+	if err != nil {
+		return append(issues, Issue{Message: err.Error()})
+	}
+	return issues
+}
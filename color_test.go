@@ -0,0 +1,42 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShade(t *testing.T) {
+	require.Equal(t, "green-500", Shade(ColorGreen, 500))
+	require.Equal(t, "grey-50", Shade(ColorGrey, 50))
+}
+
+func TestCardSetCustomColor(t *testing.T) {
+	card := NewCard("2.0").
+		SetConfig(map[string]any{"update_multi": true}).
+		SetCustomColor("brand", "rgba(51,112,255,1)", "rgba(76,136,255,1)").
+		SetCustomColor("accent", "#ff8800", "#ffaa33")
+
+	got, err := json.Marshal(card.Config)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"update_multi": true,
+		"style": {"color": {
+			"brand": {"light_mode": "rgba(51,112,255,1)", "dark_mode": "rgba(76,136,255,1)"},
+			"accent": {"light_mode": "#ff8800", "dark_mode": "#ffaa33"}
+		}}
+	}`, string(got))
+	require.Equal(t, map[string]bool{"brand": true, "accent": true}, card.customColors())
+
+	require.Empty(t, NewCard("2.0").customColors())
+}
+
+func TestIsFixedColor(t *testing.T) {
+	for _, color := range []string{"#fff", "rgba(0,0,0,1)", "RGB(1,2,3)", "white", " Black "} {
+		require.True(t, isFixedColor(color), color)
+	}
+	for _, color := range []string{"red", "green-500", "default", "brand"} {
+		require.False(t, isFixedColor(color), color)
+	}
+}
@@ -0,0 +1,298 @@
+package feishubot
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WebhookConfig is the configuration of a webhook in a Config.
+type WebhookConfig struct {
+	URL     string `yaml:"url" json:"url"`
+	Secret  string `yaml:"secret" json:"secret,omitempty"`
+	Keyword string `yaml:"keyword" json:"keyword,omitempty"`
+	// RateLimit overrides the rate limit of the config for the webhook.
+	RateLimit *RateLimit `yaml:"rate_limit" json:"rate_limit,omitempty"`
+}
+
+// RetryConfig is the retry policy of a Config, see Client.SetRetry.
+type RetryConfig struct {
+	Max int `yaml:"max" json:"max"`
+	// Backoff is the delay before the first retry, such as "500ms". If
+	// empty, DefaultRetryBackoff is used.
+	Backoff string `yaml:"backoff" json:"backoff,omitempty"`
+}
+
+// Config is a declarative configuration of webhooks and a Router sending
+// to them, for services configured by files. It is loaded from YAML or JSON
+// with LoadConfig, overridden by environment variables with ApplyEnv and by
+// command line flags with RegisterFlags, and turned into a router with
+// Build.
+//
+// Example file:
+//
+//	webhooks:
+//	  team:
+//	    url: https://open.feishu.cn/open-apis/bot/v2/hook/xxx
+//	    secret: ${TEAM_BOT_SECRET}
+//	  oncall:
+//	    url: https://open.feishu.cn/open-apis/bot/v2/hook/yyy
+//	    keyword: "[alert]"
+//	timeout: 10s
+//	rate_limit: {per_second: 5, per_minute: 100}
+//	retry: {max: 3, backoff: 1s}
+//	quiet_hours: {start: "22:00", end: "08:00", timezone: Asia/Shanghai}
+//	routes:
+//	  - match: {severity: critical}
+//	    webhooks: [oncall]
+//	    ignore_quiet_hours: true
+//	    continue: true
+//	  - webhooks: [team]
+type Config struct {
+	Webhooks map[string]*WebhookConfig `yaml:"webhooks" json:"webhooks"`
+	// Timeout is the timeout of requests, such as "10s". If empty, the
+	// timeout of NewClient is used.
+	Timeout string `yaml:"timeout" json:"timeout,omitempty"`
+	// Proxy is the URL of the HTTP proxy requests are sent through.
+	Proxy string `yaml:"proxy" json:"proxy,omitempty"`
+	// RateLimit is the rate limit of each webhook. If nil, DefaultRateLimit
+	// is used; a zero rate limit disables rate limiting.
+	RateLimit  *RateLimit  `yaml:"rate_limit" json:"rate_limit,omitempty"`
+	Retry      RetryConfig `yaml:"retry" json:"retry"`
+	QuietHours *QuietHours `yaml:"quiet_hours" json:"quiet_hours,omitempty"`
+	// Routes are the routes of the router. If empty, messages are sent to
+	// all webhooks.
+	Routes []Route `yaml:"routes" json:"routes,omitempty"`
+}
+
+// LoadConfig reads a YAML or JSON configuration file. Environment
+// variables referenced as $VAR or ${VAR} in the file are expanded.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig parses a YAML or JSON configuration, see LoadConfig.
+func ParseConfig(data []byte) (*Config, error) {
+	var config Config
+	// JSON is valid YAML
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return &config, nil
+}
+
+// ApplyEnv overrides the configuration with environment variables:
+//
+//   - FEISHU_WEBHOOK_<NAME>_URL, FEISHU_WEBHOOK_<NAME>_SECRET and
+//     FEISHU_WEBHOOK_<NAME>_KEYWORD set the webhook NAME, with the name in
+//     upper case and characters other than letters and digits replaced by
+//     underscores. FEISHU_WEBHOOK_<NAME>_URL adds the webhook if there is
+//     none of that name, named NAME in lower case
+//   - FEISHU_WEBHOOK_URL, FEISHU_SECRET and FEISHU_KEYWORD set the only
+//     webhook, or add a webhook named "default" if there is none
+//   - FEISHU_TIMEOUT, FEISHU_PROXY, FEISHU_RETRY_MAX and
+//     FEISHU_RETRY_BACKOFF set the timeout, proxy and retry policy
+//
+// Values are validated by Build.
+func (c *Config) ApplyEnv() error {
+	if c.Webhooks == nil {
+		c.Webhooks = make(map[string]*WebhookConfig)
+	}
+
+	for _, name := range envWebhookNames() {
+		if !c.hasEnvName(name) {
+			c.Webhooks[strings.ToLower(name)] = &WebhookConfig{}
+		}
+	}
+	for _, name := range c.webhookNames() {
+		if c.Webhooks[name] == nil {
+			c.Webhooks[name] = &WebhookConfig{}
+		}
+		applyWebhookEnv(c.Webhooks[name], "FEISHU_WEBHOOK_"+envName(name)+"_URL",
+			"FEISHU_WEBHOOK_"+envName(name)+"_SECRET", "FEISHU_WEBHOOK_"+envName(name)+"_KEYWORD")
+	}
+	if os.Getenv(EnvWebhookURL) != "" && len(c.Webhooks) == 0 {
+		c.Webhooks["default"] = &WebhookConfig{}
+	}
+	if len(c.Webhooks) == 1 {
+		for _, webhook := range c.Webhooks {
+			applyWebhookEnv(webhook, EnvWebhookURL, EnvSecret, EnvKeyword)
+		}
+	}
+
+	if s := os.Getenv(EnvTimeout); s != "" {
+		c.Timeout = s
+	}
+	if s := os.Getenv(EnvProxy); s != "" {
+		c.Proxy = s
+	}
+	if s := os.Getenv(EnvRetryBackoff); s != "" {
+		c.Retry.Backoff = s
+	}
+	if s := os.Getenv(EnvRetryMax); s != "" {
+		max, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("%s is not an integer: %q", EnvRetryMax, s)
+		}
+		c.Retry.Max = max
+	}
+	return nil
+}
+
+// envWebhookNames returns the names of the webhooks with a
+// FEISHU_WEBHOOK_<NAME>_URL environment variable, in their environment form.
+func envWebhookNames() []string {
+	const prefix, suffix = "FEISHU_WEBHOOK_", "_URL"
+	var names []string
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		if value == "" || len(key) <= len(prefix)+len(suffix) || !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, suffix) {
+			continue
+		}
+		names = append(names, key[len(prefix):len(key)-len(suffix)])
+	}
+	return names
+}
+
+// hasEnvName reports whether a webhook has the environment form of a name.
+func (c *Config) hasEnvName(name string) bool {
+	for webhook := range c.Webhooks {
+		if envName(webhook) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyWebhookEnv sets the fields of a webhook from the environment
+// variables, if set.
+func applyWebhookEnv(webhook *WebhookConfig, urlVar, secretVar, keywordVar string) {
+	if s := os.Getenv(urlVar); s != "" {
+		webhook.URL = s
+	}
+	if s := os.Getenv(secretVar); s != "" {
+		webhook.Secret = s
+	}
+	if s := os.Getenv(keywordVar); s != "" {
+		webhook.Keyword = s
+	}
+}
+
+// envName returns the form of a webhook name used in environment
+// variables.
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(name))
+}
+
+// RegisterFlags defines flags overriding the timeout, proxy and retry
+// policy, with the current values as defaults, so that flags override the
+// file and the environment when parsed after LoadConfig and ApplyEnv.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Timeout, "feishu-timeout", c.Timeout, "timeout of Feishu requests, e.g. 10s")
+	fs.StringVar(&c.Proxy, "feishu-proxy", c.Proxy, "URL of the HTTP proxy of Feishu requests")
+	fs.IntVar(&c.Retry.Max, "feishu-retry-max", c.Retry.Max, "retries of failed Feishu sends")
+	fs.StringVar(&c.Retry.Backoff, "feishu-retry-backoff", c.Retry.Backoff, "delay before the first retry of a Feishu send")
+}
+
+// Build validates the configuration and creates a router sending to a
+// client per webhook.
+func (c *Config) Build() (*Router, error) {
+	if len(c.Webhooks) == 0 {
+		return nil, fmt.Errorf("invalid config: no webhooks")
+	}
+
+	timeout := defaultTimeout
+	if c.Timeout != "" {
+		d, err := parseDuration(c.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config: timeout %w", err)
+		}
+		timeout = d
+	}
+	backoff := DefaultRetryBackoff
+	if c.Retry.Backoff != "" {
+		d, err := parseDuration(c.Retry.Backoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config: retry backoff %w", err)
+		}
+		backoff = d
+	}
+	if c.Retry.Max < 0 {
+		return nil, fmt.Errorf("invalid config: negative retry max %d", c.Retry.Max)
+	}
+	var proxy *url.URL
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid config: invalid proxy URL %q", c.Proxy)
+		}
+		proxy = u
+	}
+	if c.QuietHours != nil {
+		if err := c.QuietHours.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+
+	clients := make(map[string]*Client, len(c.Webhooks))
+	for _, name := range c.webhookNames() {
+		webhook := c.Webhooks[name]
+		if webhook == nil || webhook.URL == "" {
+			return nil, fmt.Errorf("invalid config: webhook %q has no URL", name)
+		}
+		if u, err := url.Parse(webhook.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("invalid config: webhook %q has an invalid URL %q", name, webhook.URL)
+		}
+
+		client := NewClient(webhook.URL, webhook.Secret)
+		client.SetKeyword(webhook.Keyword)
+		client.SetRetry(c.Retry.Max, backoff)
+		rateLimit := DefaultRateLimit
+		if webhook.RateLimit != nil {
+			rateLimit = *webhook.RateLimit
+		} else if c.RateLimit != nil {
+			rateLimit = *c.RateLimit
+		}
+		client.SetRateLimit(rateLimit)
+		if timeout != defaultTimeout || proxy != nil {
+			client.SetHTTPClient(newHTTPClient(timeout, proxy))
+		}
+		clients[name] = client
+	}
+
+	routes := c.Routes
+	if len(routes) == 0 {
+		routes = []Route{{Webhooks: c.webhookNames()}}
+	}
+	router, err := NewRouter(clients, routes...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	router.QuietHours = c.QuietHours
+	return router, nil
+}
+
+// webhookNames returns the names of the webhooks, sorted.
+func (c *Config) webhookNames() []string {
+	names := make([]string, 0, len(c.Webhooks))
+	for name := range c.Webhooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,136 @@
+package feishubot
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const configYAML = `webhooks:
+  team:
+    url: https://open.feishu.cn/open-apis/bot/v2/hook/team
+    secret: ${TEAM_BOT_SECRET}
+  on-call:
+    url: https://open.feishu.cn/open-apis/bot/v2/hook/oncall
+    keyword: "[alert]"
+    rate_limit: {per_second: 1}
+timeout: 10s
+rate_limit: {per_second: 5, per_minute: 100}
+retry: {max: 3, backoff: 500ms}
+quiet_hours: {start: "22:00", end: "08:00", timezone: Asia/Shanghai}
+routes:
+  - match: {severity: critical}
+    webhooks: [on-call]
+    ignore_quiet_hours: true
+    continue: true
+  - webhooks: [team]
+`
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("TEAM_BOT_SECRET", "s3cret")
+	path := filepath.Join(t.TempDir(), "feishu.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(configYAML), 0o600))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", config.Webhooks["team"].Secret)
+	require.Equal(t, &RateLimit{PerSecond: 1}, config.Webhooks["on-call"].RateLimit)
+	require.Equal(t, RetryConfig{Max: 3, Backoff: "500ms"}, config.Retry)
+	require.Equal(t, "22:00", config.QuietHours.Start)
+	require.Len(t, config.Routes, 2)
+	require.True(t, config.Routes[0].IgnoreQuietHours)
+
+	router, err := config.Build()
+	require.NoError(t, err)
+	require.Equal(t, []string{"on-call", "team"}, router.Webhooks())
+	require.Equal(t, config.QuietHours, router.QuietHours)
+
+	team := router.Client("team")
+	require.Equal(t, "s3cret", team.Secret)
+	require.Equal(t, 3, team.MaxRetries)
+	require.Equal(t, 500*time.Millisecond, team.RetryBackoff)
+	require.Equal(t, DefaultRateLimit, team.limiter.limit)
+	require.Equal(t, 10*time.Second, team.HTTPClient.(*http.Client).Timeout)
+	require.Equal(t, RateLimit{PerSecond: 1}, router.Client("on-call").limiter.limit)
+	require.Equal(t, "[alert]", router.Client("on-call").Keyword)
+}
+
+func TestParseConfigJSON(t *testing.T) {
+	config, err := ParseConfig([]byte(`{"webhooks": {"team": {"url": "https://example.com/hook"}}, "rate_limit": {}}`))
+	require.NoError(t, err)
+
+	router, err := config.Build()
+	require.NoError(t, err)
+	require.Nil(t, router.Client("team").limiter)
+	require.Equal(t, []Route{{Webhooks: []string{"team"}}}, router.routes)
+
+	_, err = ParseConfig([]byte("webhooks: ["))
+	require.Error(t, err)
+}
+
+func TestConfigApplyEnv(t *testing.T) {
+	config, err := ParseConfig([]byte(configYAML))
+	require.NoError(t, err)
+
+	t.Setenv("FEISHU_WEBHOOK_ON_CALL_URL", "https://example.com/oncall")
+	t.Setenv("FEISHU_WEBHOOK_ON_CALL_SECRET", "oncall-secret")
+	t.Setenv("FEISHU_WEBHOOK_RELEASES_URL", "https://example.com/releases")
+	t.Setenv("FEISHU_WEBHOOK_RELEASES_KEYWORD", "[release]")
+	t.Setenv(EnvWebhookURL, "https://example.com/ignored")
+	t.Setenv(EnvTimeout, "5s")
+	t.Setenv(EnvRetryMax, "1")
+	require.NoError(t, config.ApplyEnv())
+	require.Equal(t, "https://example.com/oncall", config.Webhooks["on-call"].URL)
+	require.Equal(t, "oncall-secret", config.Webhooks["on-call"].Secret)
+	require.Equal(t, "https://open.feishu.cn/open-apis/bot/v2/hook/team", config.Webhooks["team"].URL)
+	require.Equal(t, &WebhookConfig{URL: "https://example.com/releases", Keyword: "[release]"}, config.Webhooks["releases"])
+	require.Len(t, config.Webhooks, 3)
+	require.Equal(t, "5s", config.Timeout)
+	require.Equal(t, 1, config.Retry.Max)
+
+	// Flags override the file and the environment
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	config.RegisterFlags(fs)
+	require.NoError(t, fs.Parse([]string{"-feishu-retry-max", "5"}))
+	require.Equal(t, 5, config.Retry.Max)
+	require.Equal(t, "5s", config.Timeout)
+
+	t.Setenv(EnvRetryMax, "many")
+	require.EqualError(t, config.ApplyEnv(), `FEISHU_RETRY_MAX is not an integer: "many"`)
+}
+
+func TestConfigApplyEnvDefault(t *testing.T) {
+	t.Setenv(EnvWebhookURL, "https://example.com/hook")
+	t.Setenv(EnvSecret, "s3cret")
+
+	var config Config
+	require.NoError(t, config.ApplyEnv())
+	require.Equal(t, &WebhookConfig{URL: "https://example.com/hook", Secret: "s3cret"}, config.Webhooks["default"])
+}
+
+func TestConfigBuildErrors(t *testing.T) {
+	tests := []struct {
+		config string
+		err    string
+	}{
+		{"{}", "invalid config: no webhooks"},
+		{"webhooks: {team: {}}", `invalid config: webhook "team" has no URL`},
+		{"webhooks: {team: {url: example.com}}", `invalid config: webhook "team" has an invalid URL "example.com"`},
+		{"webhooks: {team: {url: https://example.com}}\ntimeout: soon", `invalid config: timeout is not a positive duration: "soon"`},
+		{"webhooks: {team: {url: https://example.com}}\nretry: {max: -1}", "invalid config: negative retry max -1"},
+		{"webhooks: {team: {url: https://example.com}}\nproxy: proxy", `invalid config: invalid proxy URL "proxy"`},
+		{"webhooks: {team: {url: https://example.com}}\nquiet_hours: {start: late, end: early}", `invalid config: invalid quiet hours time "late", want HH:MM`},
+		{"webhooks: {team: {url: https://example.com}}\nroutes: [{webhooks: [ops]}]", `invalid config: route 0 sends to unknown webhook "ops"`},
+	}
+	for _, tt := range tests {
+		config, err := ParseConfig([]byte(tt.config))
+		require.NoError(t, err)
+		_, err = config.Build()
+		require.EqualError(t, err, tt.err, tt.config)
+	}
+}
@@ -0,0 +1,180 @@
+package feishubot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxContactBatch is the maximum number of emails and of mobiles resolved
+// per batch request.
+const maxContactBatch = 50
+
+// ErrUserNotFound is returned when no user of the tenant has the given email
+// or mobile number, or the app may not see them.
+var ErrUserNotFound = errors.New("user not found")
+
+// Mention is a user mention, with the syntax of each message format.
+type Mention struct {
+	OpenID string
+	// Name is the displayed name of text mentions. Feishu shows the user's
+	// current name if empty.
+	Name string
+}
+
+// Text returns the mention for text messages: <at user_id="ou_xxx">Name</at>.
+func (m Mention) Text() string {
+	return fmt.Sprintf(`<at user_id="%s">%s</at>`, m.OpenID, m.Name)
+}
+
+// Markdown returns the mention for card markdown: <at id=ou_xxx></at>.
+func (m Mention) Markdown() string {
+	return fmt.Sprintf("<at id=%s></at>", m.OpenID)
+}
+
+// Element returns the mention as a post element.
+func (m Mention) Element() Element {
+	return NewAtElement(m.OpenID, m.Name)
+}
+
+type contactEntry struct {
+	openID    string
+	expiresAt time.Time
+}
+
+// Contacts resolves emails and mobile numbers to open IDs with the contact
+// API, caching results in memory, so that users can be mentioned by email
+// instead of maintaining lists of open IDs. The app needs the
+// contact:user.id:readonly permission.
+//
+// Example:
+//
+//	contacts := feishubot.NewContacts(api, time.Hour)
+//	alice, err := contacts.AtByEmail(ctx, "alice@example.com")
+//	if err != nil {
+//		return err
+//	}
+//	msg := feishubot.NewTextMessage(alice.Text() + " please review")
+type Contacts struct {
+	client *APIClient
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	cache map[string]contactEntry
+	now   func() time.Time
+}
+
+// NewContacts creates a contacts helper using client, caching resolved open
+// IDs for ttl. A ttl of zero or less disables caching.
+func NewContacts(client *APIClient, ttl time.Duration) *Contacts {
+	return &Contacts{
+		client: client,
+		ttl:    ttl,
+		cache:  make(map[string]contactEntry),
+		now:    time.Now,
+	}
+}
+
+// OpenIDsByEmail resolves emails to open IDs. Emails of unknown users are
+// missing from the result.
+func (c *Contacts) OpenIDsByEmail(ctx context.Context, emails ...string) (map[string]string, error) {
+	return c.resolve(ctx, "emails", "email", emails)
+}
+
+// OpenIDsByMobile resolves mobile numbers to open IDs. Numbers without
+// country code are looked up as Chinese mainland numbers. Numbers of
+// unknown users are missing from the result.
+func (c *Contacts) OpenIDsByMobile(ctx context.Context, mobiles ...string) (map[string]string, error) {
+	return c.resolve(ctx, "mobiles", "mobile", mobiles)
+}
+
+// AtByEmail returns a mention of the user with the given email. The error
+// wraps ErrUserNotFound for unknown users.
+func (c *Contacts) AtByEmail(ctx context.Context, email string) (Mention, error) {
+	ids, err := c.OpenIDsByEmail(ctx, email)
+	if err != nil {
+		return Mention{}, err
+	}
+	openID, ok := ids[email]
+	if !ok {
+		return Mention{}, fmt.Errorf("%w: %s", ErrUserNotFound, email)
+	}
+	return Mention{OpenID: openID}, nil
+}
+
+// AtByMobile returns a mention of the user with the given mobile number. The
+// error wraps ErrUserNotFound for unknown users.
+func (c *Contacts) AtByMobile(ctx context.Context, mobile string) (Mention, error) {
+	ids, err := c.OpenIDsByMobile(ctx, mobile)
+	if err != nil {
+		return Mention{}, err
+	}
+	openID, ok := ids[mobile]
+	if !ok {
+		return Mention{}, fmt.Errorf("%w: %s", ErrUserNotFound, mobile)
+	}
+	return Mention{OpenID: openID}, nil
+}
+
+// resolve returns the open IDs of values, from the cache or with batch
+// requests sending them in the field of the request body.
+func (c *Contacts) resolve(ctx context.Context, field, kind string, values []string) (map[string]string, error) {
+	result := make(map[string]string, len(values))
+	var missing []string
+	seen := make(map[string]bool, len(values))
+
+	c.mu.Lock()
+	now := c.now()
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		if entry, ok := c.cache[kind+":"+value]; ok && now.Before(entry.expiresAt) {
+			result[value] = entry.openID
+		} else {
+			missing = append(missing, value)
+		}
+	}
+	c.mu.Unlock()
+
+	for start := 0; start < len(missing); start += maxContactBatch {
+		end := start + maxContactBatch
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		var data struct {
+			UserList []struct {
+				UserID string `json:"user_id"`
+				Email  string `json:"email"`
+				Mobile string `json:"mobile"`
+			} `json:"user_list"`
+		}
+		body := map[string][]string{field: missing[start:end]}
+		path := "/contact/v3/users/batch_get_id?user_id_type=open_id"
+		if err := c.client.openAPI().callJSON(ctx, http.MethodPost, path, body, &data); err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", field, err)
+		}
+
+		c.mu.Lock()
+		for _, user := range data.UserList {
+			value := user.Email
+			if kind == "mobile" {
+				value = user.Mobile
+			}
+			if user.UserID == "" || value == "" {
+				continue
+			}
+			result[value] = user.UserID
+			if c.ttl > 0 {
+				c.cache[kind+":"+value] = contactEntry{openID: user.UserID, expiresAt: c.now().Add(c.ttl)}
+			}
+		}
+		c.mu.Unlock()
+	}
+	return result, nil
+}
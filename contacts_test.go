@@ -0,0 +1,73 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactsAtByEmail(t *testing.T) {
+	var calls int
+	var gotBody map[string][]string
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/contact/v3/users/batch_get_id", r.URL.Path)
+		require.Equal(t, "open_id", r.URL.Query().Get("user_id_type"))
+		calls++
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"user_list":[{"user_id":"ou_alice","email":"alice@example.com"},{"email":"nobody@example.com"}]}}`))
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+	contacts := NewContacts(client, time.Hour)
+	now := time.Unix(1700000000, 0)
+	contacts.now = func() time.Time { return now }
+
+	alice, err := contacts.AtByEmail(context.Background(), "alice@example.com")
+	require.NoError(t, err)
+	require.Equal(t, Mention{OpenID: "ou_alice"}, alice)
+	require.Equal(t, `<at user_id="ou_alice"></at>`, alice.Text())
+	require.Equal(t, "<at id=ou_alice></at>", alice.Markdown())
+	require.Equal(t, map[string][]string{"emails": {"alice@example.com"}}, gotBody)
+
+	_, err = contacts.AtByEmail(context.Background(), "alice@example.com")
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	now = now.Add(2 * time.Hour)
+	_, err = contacts.AtByEmail(context.Background(), "alice@example.com")
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	_, err = contacts.AtByEmail(context.Background(), "nobody@example.com")
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestContactsBatches(t *testing.T) {
+	var batches []int
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string][]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		batches = append(batches, len(body["mobiles"]))
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"user_list":[]}}`))
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+	contacts := NewContacts(client, 0)
+
+	mobiles := make([]string, 0, maxContactBatch+11)
+	for i := 0; i < maxContactBatch+10; i++ {
+		mobiles = append(mobiles, fmt.Sprintf("+86130%08d", i))
+	}
+	mobiles = append(mobiles, mobiles[0])
+	ids, err := contacts.OpenIDsByMobile(context.Background(), mobiles...)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+	require.Equal(t, []int{maxContactBatch, 10}, batches)
+}
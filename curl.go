@@ -0,0 +1,46 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ToCurl returns a ready-to-run curl command that posts the message to the
+// given webhook URL. It is meant for debugging and for sharing reproducible
+// requests with Feishu support.
+//
+// If a secret is given, the command includes a timestamp and signature
+// computed at call time, exactly as Client.Send would add them. Feishu only
+// accepts signatures within one hour of their timestamp, so the command
+// must be run within that window. Pre-signed messages are kept as-is.
+//
+// Example:
+//
+//	cmd, err := message.ToCurl(webhookURL, secret)
+//	if err != nil {
+//	    // handle error
+//	}
+//	fmt.Println(cmd)
+func (m *Message) ToCurl(webhookURL string, secret string) (string, error) {
+	msgCopy := *m
+	if err := signMessage(&msgCopy, secret); err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(msgCopy)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	return fmt.Sprintf(
+		"curl -X POST -H 'Content-Type: application/json' -d %s %s",
+		shellQuote(string(body)),
+		shellQuote(webhookURL),
+	), nil
+}
+
+// shellQuote quotes s for safe use as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
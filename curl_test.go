@@ -0,0 +1,67 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageToCurl(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *Message
+		secret  string
+		want    string
+	}{
+		{
+			name:    "without secret",
+			message: NewTextMessage("hello"),
+			want:    `curl -X POST -H 'Content-Type: application/json' -d '{"msg_type":"text","content":{"text":"hello"}}' 'https://example.com/hook'`,
+		},
+		{
+			name:    "single quotes escaped",
+			message: NewTextMessage("it's done"),
+			want:    `curl -X POST -H 'Content-Type: application/json' -d '{"msg_type":"text","content":{"text":"it'\''s done"}}' 'https://example.com/hook'`,
+		},
+		{
+			name:    "pre-signed message kept",
+			message: NewTextMessage("hello").WithSignature(1599360473, "abc"),
+			secret:  "secret",
+			want:    `curl -X POST -H 'Content-Type: application/json' -d '{"msg_type":"text","content":{"text":"hello"},"timestamp":1599360473,"sign":"abc"}' 'https://example.com/hook'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.message.ToCurl("https://example.com/hook", tt.secret)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMessageToCurlWithSecret(t *testing.T) {
+	message := NewTextMessage("hello")
+
+	got, err := message.ToCurl("https://example.com/hook", "secret")
+	require.NoError(t, err)
+
+	// Extract the JSON body between -d '...' and the URL
+	prefix := `curl -X POST -H 'Content-Type: application/json' -d '`
+	require.True(t, strings.HasPrefix(got, prefix))
+	body := strings.TrimSuffix(strings.TrimPrefix(got, prefix), `' 'https://example.com/hook'`)
+
+	var msg Message
+	require.NoError(t, json.Unmarshal([]byte(body), &msg))
+	require.NotZero(t, msg.Timestamp)
+
+	wantSign, err := GenSign("secret", msg.Timestamp)
+	require.NoError(t, err)
+	require.Equal(t, wantSign, msg.Sign)
+
+	// The original message must not be modified
+	require.Zero(t, message.Timestamp)
+	require.Empty(t, message.Sign)
+}
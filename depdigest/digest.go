@@ -0,0 +1,221 @@
+package depdigest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// maxPackagesPerGroup is the number of packages listed per group; the rest
+// are counted.
+const maxPackagesPerGroup = 15
+
+// Digest collects open dependency updates. It is safe for concurrent use.
+type Digest struct {
+	mu      sync.Mutex
+	updates map[string]Update
+	// severities are the alert severities by ecosystem-less package name.
+	severities map[string]Severity
+}
+
+// NewDigest creates an empty digest.
+func NewDigest() *Digest {
+	return &Digest{
+		updates:    make(map[string]Update),
+		severities: make(map[string]Severity),
+	}
+}
+
+// Add adds or replaces updates, identified by their URL.
+func (d *Digest) Add(updates ...Update) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, u := range updates {
+		d.updates[u.URL] = u
+	}
+}
+
+// Remove removes the update with the pull request URL, e.g. once merged or
+// closed.
+func (d *Digest) Remove(url string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.updates, url)
+}
+
+// AddPullRequests adds the open update pull requests of Dependabot and
+// Renovate among prs, such as those listed with the GitHub API, and
+// returns the number added.
+//
+// See: https://docs.github.com/en/rest/pulls/pulls#list-pull-requests
+func (d *Digest) AddPullRequests(prs []PullRequest) int {
+	added := 0
+	for i := range prs {
+		if prs[i].State != "" && prs[i].State != "open" {
+			continue
+		}
+		if update, ok := ParsePullRequest(&prs[i]); ok {
+			d.Add(update)
+			added++
+		}
+	}
+	return added
+}
+
+// AddAlerts records the severities of open Dependabot alerts, listed with
+// the GitHub API. Updates of packages with an alert are shown as security
+// updates of the highest severity.
+//
+// See: https://docs.github.com/en/rest/dependabot/alerts
+func (d *Digest) AddAlerts(alerts []DependabotAlert) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, alert := range alerts {
+		if alert.State != "" && alert.State != "open" {
+			continue
+		}
+		name := alert.Dependency.Package.Name
+		severity := Severity(strings.ToLower(alert.SecurityAdvisory.Severity))
+		if current, ok := d.severities[name]; !ok || severity.rank() < current.rank() {
+			d.severities[name] = severity
+		}
+	}
+}
+
+// Updates returns the open updates, with the severities of alerts applied,
+// by repository and number.
+func (d *Digest) Updates() []Update {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	updates := make([]Update, 0, len(d.updates))
+	for _, u := range d.updates {
+		if severity, ok := d.severities[u.Package]; ok {
+			u.Kind = KindSecurity
+			u.Severity = severity
+		}
+		updates = append(updates, u)
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		if updates[i].Repository != updates[j].Repository {
+			return updates[i].Repository < updates[j].Repository
+		}
+		return updates[i].Number < updates[j].Number
+	})
+	return updates
+}
+
+// group is a section of the digest card.
+type group struct {
+	title    string
+	template string
+	match    func(Update) bool
+}
+
+// groups are the sections of the digest card, in order.
+var groups = []group{
+	{title: "🚨 Critical and high severity", match: func(u Update) bool {
+		return u.Kind == KindSecurity && u.Severity.rank() <= 1
+	}},
+	{title: "⚠️ Other security updates", match: func(u Update) bool {
+		return u.Kind == KindSecurity && u.Severity.rank() > 1
+	}},
+	{title: "Major updates", match: func(u Update) bool { return u.Kind == KindMajor }},
+	{title: "Minor updates", match: func(u Update) bool { return u.Kind == KindMinor }},
+	{title: "Patch updates", match: func(u Update) bool { return u.Kind == KindPatch }},
+	{title: "Other updates", match: func(u Update) bool { return u.Kind == KindOther || u.Kind == "" }},
+}
+
+// Card renders the open updates as a digest card, grouped by severity and
+// kind, with one line per package linking its pull requests. The header is
+// red if there are critical or high severity updates.
+func (d *Digest) Card(title string) *feishubot.Card {
+	updates := d.Updates()
+
+	template := feishubot.TemplateBlue
+	var security int
+	for _, u := range updates {
+		if u.Kind == KindSecurity {
+			security++
+			if u.Severity.rank() <= 1 {
+				template = feishubot.TemplateRed
+			} else if template != feishubot.TemplateRed {
+				template = feishubot.TemplateOrange
+			}
+		}
+	}
+
+	builder := feishubot.NewCardBuilder().Header(title, template)
+	if len(updates) == 0 {
+		builder.Markdown("No open dependency updates 🎉")
+		return builder.Build()
+	}
+	builder.Subtitle(fmt.Sprintf("%d open pull requests, %d security updates", len(updates), security))
+
+	for _, g := range groups {
+		var matched []Update
+		for _, u := range updates {
+			if g.match(u) {
+				matched = append(matched, u)
+			}
+		}
+		if len(matched) > 0 {
+			builder.Markdown(groupMarkdown(g.title, matched))
+		}
+	}
+	return builder.Build()
+}
+
+// groupMarkdown renders the updates of a group with one line per package,
+// most pull requests first.
+func groupMarkdown(title string, updates []Update) string {
+	byPackage := make(map[string][]Update)
+	var packages []string
+	for _, u := range updates {
+		if _, ok := byPackage[u.Package]; !ok {
+			packages = append(packages, u.Package)
+		}
+		byPackage[u.Package] = append(byPackage[u.Package], u)
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		a, b := byPackage[packages[i]], byPackage[packages[j]]
+		if a[0].Severity.rank() != b[0].Severity.rank() {
+			return a[0].Severity.rank() < b[0].Severity.rank()
+		}
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return packages[i] < packages[j]
+	})
+
+	lines := []string{fmt.Sprintf("**%s** (%d)", title, len(updates))}
+	for i, name := range packages {
+		if i == maxPackagesPerGroup {
+			lines = append(lines, fmt.Sprintf("- *and %d more packages*", len(packages)-i))
+			break
+		}
+		pkgUpdates := byPackage[name]
+		links := make([]string, 0, len(pkgUpdates))
+		for _, u := range pkgUpdates {
+			links = append(links, fmt.Sprintf("[%s#%d](%s)", u.Repository, u.Number, u.URL))
+		}
+
+		line := "- **" + name + "**"
+		if severity := pkgUpdates[0].Severity; severity != "" {
+			line += " `" + string(severity) + "`"
+		}
+		if first := pkgUpdates[0]; first.From != "" {
+			line += " " + first.From + " → " + first.To
+		} else {
+			line += " → " + first.To
+		}
+		lines = append(lines, line+" · "+strings.Join(links, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
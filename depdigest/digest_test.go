@@ -0,0 +1,108 @@
+package depdigest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+// cardJSON returns the JSON of a card.
+func cardJSON(t *testing.T, card *feishubot.Card) string {
+	t.Helper()
+	data, err := json.Marshal(card)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestDigestAddRemove(t *testing.T) {
+	digest := NewDigest()
+	digest.Add(
+		Update{Package: "lodash", Kind: KindPatch, Repository: "acme/web", Number: 2, URL: "https://github.com/acme/web/pull/2"},
+		Update{Package: "lodash", Kind: KindPatch, Repository: "acme/api", Number: 7, URL: "https://github.com/acme/api/pull/7"},
+	)
+	digest.Add(Update{Package: "lodash", Kind: KindMinor, Repository: "acme/web", Number: 2, URL: "https://github.com/acme/web/pull/2"})
+
+	updates := digest.Updates()
+	require.Len(t, updates, 2)
+	require.Equal(t, "acme/api", updates[0].Repository)
+	require.Equal(t, KindMinor, updates[1].Kind)
+
+	digest.Remove("https://github.com/acme/api/pull/7")
+	require.Len(t, digest.Updates(), 1)
+}
+
+func TestDigestAddPullRequests(t *testing.T) {
+	closed := newPullRequest("dependabot[bot]", "Bump lodash from 4.17.20 to 4.17.21")
+	closed.State = "closed"
+	prs := []PullRequest{
+		newPullRequest("dependabot[bot]", "Bump lodash from 4.17.20 to 4.17.21"),
+		newPullRequest("alice", "Fix login"),
+		closed,
+	}
+
+	digest := NewDigest()
+	require.Equal(t, 1, digest.AddPullRequests(prs))
+	require.Len(t, digest.Updates(), 1)
+}
+
+func TestDigestAddAlerts(t *testing.T) {
+	digest := NewDigest()
+	digest.Add(Update{Package: "axios", Kind: KindPatch, Repository: "acme/web", Number: 3, URL: "https://github.com/acme/web/pull/3"})
+
+	var medium, critical, dismissed DependabotAlert
+	medium.State = "open"
+	medium.Dependency.Package.Name = "axios"
+	medium.SecurityAdvisory.Severity = "medium"
+	critical = medium
+	critical.SecurityAdvisory.Severity = "CRITICAL"
+	dismissed = medium
+	dismissed.Dependency.Package.Name = "lodash"
+	dismissed.State = "dismissed"
+	digest.AddAlerts([]DependabotAlert{medium, critical, dismissed})
+
+	updates := digest.Updates()
+	require.Len(t, updates, 1)
+	require.Equal(t, KindSecurity, updates[0].Kind)
+	require.Equal(t, SeverityCritical, updates[0].Severity)
+}
+
+func TestDigestCard(t *testing.T) {
+	digest := NewDigest()
+	digest.Add(
+		Update{Package: "axios", To: "v1.7.4", Kind: KindSecurity, Severity: SeverityHigh, Repository: "acme/web", Number: 1, URL: "https://github.com/acme/web/pull/1"},
+		Update{Package: "react", To: "v19", Kind: KindMajor, Repository: "acme/web", Number: 2, URL: "https://github.com/acme/web/pull/2"},
+		Update{Package: "lodash", From: "4.17.20", To: "4.17.21", Kind: KindPatch, Repository: "acme/web", Number: 3, URL: "https://github.com/acme/web/pull/3"},
+		Update{Package: "lodash", From: "4.17.20", To: "4.17.21", Kind: KindPatch, Repository: "acme/api", Number: 4, URL: "https://github.com/acme/api/pull/4"},
+	)
+
+	data := cardJSON(t, digest.Card("Dependency updates"))
+	require.Contains(t, data, "Dependency updates")
+	require.Contains(t, data, `"template":"red"`)
+	require.Contains(t, data, "4 open pull requests, 1 security updates")
+	require.Contains(t, data, "🚨 Critical and high severity** (1)")
+	require.Contains(t, data, "**axios** `high` → v1.7.4")
+	require.Contains(t, data, "**Major updates** (1)")
+	require.Contains(t, data, "**Patch updates** (2)")
+	require.Contains(t, data, "**lodash** 4.17.20 → 4.17.21 · [acme/api#4](https://github.com/acme/api/pull/4), [acme/web#3](https://github.com/acme/web/pull/3)")
+	require.NotContains(t, data, "Minor updates")
+	require.Less(t, strings.Index(data, "axios"), strings.Index(data, "react"))
+	require.Less(t, strings.Index(data, "react"), strings.Index(data, "lodash"))
+}
+
+func TestDigestCardEmpty(t *testing.T) {
+	data := cardJSON(t, NewDigest().Card("Dependency updates"))
+	require.Contains(t, data, "No open dependency updates")
+	require.Contains(t, data, `"template":"blue"`)
+}
+
+func TestDigestCardTruncatesGroups(t *testing.T) {
+	digest := NewDigest()
+	for i := 0; i < maxPackagesPerGroup+3; i++ {
+		name := string(rune('a'+i)) + "-pkg"
+		digest.Add(Update{Package: name, To: "1.0.1", From: "1.0.0", Kind: KindPatch, Repository: "acme/web", Number: i, URL: "https://github.com/acme/web/pull/" + name})
+	}
+	require.Contains(t, cardJSON(t, digest.Card("Dependency updates")), "*and 3 more packages*")
+}
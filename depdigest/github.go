@@ -0,0 +1,140 @@
+package depdigest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBodyBytes is the maximum size of webhook request bodies read.
+const maxBodyBytes = 4 << 20
+
+// PullRequest is a GitHub pull request, as listed by the API and sent in
+// pull_request webhooks.
+type PullRequest struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	HTMLURL   string    `json:"html_url"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"created_at"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Base struct {
+		Repo struct {
+			FullName string `json:"full_name"`
+		} `json:"repo"`
+	} `json:"base"`
+}
+
+// DependabotAlert is a Dependabot alert, as listed by the GitHub API.
+type DependabotAlert struct {
+	State      string `json:"state"`
+	Dependency struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+	} `json:"dependency"`
+	SecurityAdvisory struct {
+		Severity string `json:"severity"`
+		Summary  string `json:"summary"`
+	} `json:"security_advisory"`
+	HTMLURL string `json:"html_url"`
+}
+
+// pullRequestEvent is a GitHub pull_request webhook event.
+type pullRequestEvent struct {
+	Action      string      `json:"action"`
+	PullRequest PullRequest `json:"pull_request"`
+}
+
+// Handler is an http.Handler receiving GitHub pull_request webhooks and
+// keeping the open update pull requests of a digest up to date: opened and
+// edited pull requests are added, closed ones removed.
+type Handler struct {
+	digest *Digest
+	secret string
+
+	// OnError is called with errors of handling webhooks. If nil, such
+	// errors are only reported in the response.
+	OnError func(error)
+}
+
+// NewHandler creates a handler updating digest. secret is the secret of
+// the GitHub webhook, used to verify the X-Hub-Signature-256 header of
+// requests; if empty, requests are not verified.
+func NewHandler(digest *Digest, secret string) *Handler {
+	return &Handler{digest: digest, secret: secret}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBodyBytes))
+	if err != nil {
+		h.handleError(fmt.Errorf("failed to read request body: %w", err))
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if h.secret != "" && !validSignature(h.secret, body, req.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	// Other events, such as the ping on webhook creation, are acknowledged
+	if req.Header.Get("X-GitHub-Event") != "pull_request" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	var event pullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.handleError(fmt.Errorf("failed to decode pull request event: %w", err))
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	pr := &event.PullRequest
+	switch event.Action {
+	case "closed":
+		h.digest.Remove(pr.HTMLURL)
+	case "opened", "reopened", "edited", "labeled", "unlabeled", "synchronize":
+		if update, ok := ParsePullRequest(pr); ok {
+			h.digest.Add(update)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// validSignature reports whether signature is the "sha256=" prefixed
+// HMAC-SHA256 of body keyed with secret.
+func validSignature(secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), got)
+}
+
+// handleError reports err to the error handler.
+func (h *Handler) handleError(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+	}
+}
@@ -0,0 +1,84 @@
+package depdigest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const pullRequestPayload = `{
+  "action": "%s",
+  "pull_request": {
+    "number": 12,
+    "title": "Bump lodash from 4.17.20 to 4.17.21",
+    "html_url": "https://github.com/acme/web/pull/12",
+    "state": "open",
+    "created_at": "2026-10-12T08:00:00Z",
+    "user": {"login": "dependabot[bot]"},
+    "labels": [{"name": "dependencies"}],
+    "base": {"repo": {"full_name": "acme/web"}}
+  }
+}`
+
+// sign returns the X-Hub-Signature-256 header of body.
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// post posts a webhook event to handler and returns the status code.
+func post(handler http.Handler, event, body, signature string) int {
+	req := httptest.NewRequest(http.MethodPost, "/github", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestHandler(t *testing.T) {
+	digest := NewDigest()
+	handler := NewHandler(digest, "s3cret")
+
+	opened := strings.Replace(pullRequestPayload, "%s", "opened", 1)
+	require.Equal(t, http.StatusUnauthorized, post(handler, "pull_request", opened, ""))
+	require.Equal(t, http.StatusUnauthorized, post(handler, "pull_request", opened, sign("wrong", opened)))
+	require.Empty(t, digest.Updates())
+
+	require.Equal(t, http.StatusOK, post(handler, "pull_request", opened, sign("s3cret", opened)))
+	updates := digest.Updates()
+	require.Len(t, updates, 1)
+	require.Equal(t, "lodash", updates[0].Package)
+	require.Equal(t, 2026, updates[0].OpenedAt.Year())
+
+	ping := `{"zen": "Keep it logically awesome."}`
+	require.Equal(t, http.StatusOK, post(handler, "ping", ping, sign("s3cret", ping)))
+
+	closed := strings.Replace(pullRequestPayload, "%s", "closed", 1)
+	require.Equal(t, http.StatusOK, post(handler, "pull_request", closed, sign("s3cret", closed)))
+	require.Empty(t, digest.Updates())
+}
+
+func TestHandlerErrors(t *testing.T) {
+	var errs []error
+	handler := NewHandler(NewDigest(), "")
+	handler.OnError = func(err error) { errs = append(errs, err) }
+
+	require.Equal(t, http.StatusBadRequest, post(handler, "pull_request", "{", ""))
+	require.Len(t, errs, 1)
+
+	req := httptest.NewRequest(http.MethodGet, "/github", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	require.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+}
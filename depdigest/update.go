@@ -0,0 +1,179 @@
+// Package depdigest collects the open dependency update pull requests of
+// Renovate and Dependabot and posts them as a periodic digest card, grouped
+// by severity and package, instead of one message per pull request.
+//
+// Updates are collected from GitHub pull_request webhooks with Handler, or
+// from pull requests listed with the GitHub API with
+// Digest.AddPullRequests. Severities come from Dependabot alerts, see
+// Digest.AddAlerts.
+//
+// Example, posting a weekly digest with the schedule package:
+//
+//	digest := depdigest.NewDigest()
+//	http.Handle("/github", depdigest.NewHandler(digest, os.Getenv("GITHUB_WEBHOOK_SECRET")))
+//
+//	scheduler.Add(schedule.Job{
+//		Name: "dependency-digest",
+//		Cron: "0 10 * * mon",
+//		Message: func(schedule.Run) (*feishubot.Message, error) {
+//			return feishubot.NewInteractiveMessage(digest.Card("Dependency updates")), nil
+//		},
+//	})
+package depdigest
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is the kind of an update.
+type Kind string
+
+const (
+	KindSecurity Kind = "security"
+	KindMajor    Kind = "major"
+	KindMinor    Kind = "minor"
+	KindPatch    Kind = "patch"
+	// KindOther is an update of unknown kind, e.g. a Renovate update whose
+	// title does not name the current version.
+	KindOther Kind = "other"
+)
+
+// Severity is the severity of the vulnerability fixed by a security
+// update, as in GitHub advisories.
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+)
+
+// rank orders severities from critical to unknown.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityCritical:
+		return 0
+	case SeverityHigh:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityLow:
+		return 3
+	}
+	return 4
+}
+
+// Update is an open dependency update pull request.
+type Update struct {
+	Package string
+	// From is the current version, empty if unknown.
+	From string
+	To   string
+	Kind Kind
+	// Severity is set for security updates with a known advisory.
+	Severity Severity
+	// Repository is the full name of the repository, "owner/name".
+	Repository string
+	Number     int
+	Title      string
+	URL        string
+	// Bot is "dependabot" or "renovate".
+	Bot      string
+	OpenedAt time.Time
+}
+
+var (
+	// dependabotTitle matches titles such as "Bump lodash from 4.17.20 to
+	// 4.17.21 in /web", with an optional conventional commit prefix.
+	dependabotTitle = regexp.MustCompile(`(?i)^(?:[\w()!/-]+:\s*)?bump (\S+) from (\S+) to (\S+)`)
+	// renovateTitle matches titles such as "Update dependency lodash to
+	// v4.17.21" or "chore(deps): update module golang.org/x/net to v0.26.0
+	// [SECURITY]".
+	renovateTitle = regexp.MustCompile(`(?i)^(?:[\w()!/-]+:\s*)?update (?:dependency |module |docker tag |docker image |helm release |action |package )?(\S+) to (\S+)`)
+	// versionNumbers matches the numbers of a version.
+	versionNumbers = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+)
+
+// ParsePullRequest returns the update of a pull request opened by
+// Dependabot or Renovate, and false for other pull requests and titles it
+// does not recognize, such as grouped updates.
+func ParsePullRequest(pr *PullRequest) (Update, bool) {
+	update := Update{
+		Repository: pr.Base.Repo.FullName,
+		Number:     pr.Number,
+		Title:      pr.Title,
+		URL:        pr.HTMLURL,
+		OpenedAt:   pr.CreatedAt,
+	}
+
+	switch login := strings.ToLower(pr.User.Login); {
+	case strings.HasPrefix(login, "dependabot"):
+		m := dependabotTitle.FindStringSubmatch(pr.Title)
+		if m == nil {
+			return Update{}, false
+		}
+		update.Bot = "dependabot"
+		update.Package, update.From, update.To = m[1], m[2], m[3]
+	case strings.HasPrefix(login, "renovate"):
+		m := renovateTitle.FindStringSubmatch(pr.Title)
+		if m == nil {
+			return Update{}, false
+		}
+		update.Bot = "renovate"
+		update.Package, update.To = m[1], m[2]
+	default:
+		return Update{}, false
+	}
+
+	update.Kind = kindOf(update.From, update.To)
+	for _, label := range pr.Labels {
+		switch name := strings.ToLower(label.Name); name {
+		case "major", "minor", "patch":
+			if update.Kind == KindOther {
+				update.Kind = Kind(name)
+			}
+		case "security":
+			update.Kind = KindSecurity
+		}
+	}
+	if strings.Contains(strings.ToLower(pr.Title), "[security]") {
+		update.Kind = KindSecurity
+	}
+	return update, true
+}
+
+// kindOf returns the kind of an update between two versions. Without the
+// current version, only updates to a bare major version such as "v2", as
+// Renovate names major updates, are known.
+func kindOf(from, to string) Kind {
+	toParts := versionNumbers.FindStringSubmatch(to)
+	if toParts == nil {
+		return KindOther
+	}
+	if from == "" {
+		if toParts[2] == "" {
+			return KindMajor
+		}
+		return KindOther
+	}
+	fromParts := versionNumbers.FindStringSubmatch(from)
+	if fromParts == nil {
+		return KindOther
+	}
+
+	number := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	switch {
+	case number(fromParts[1]) != number(toParts[1]):
+		return KindMajor
+	case number(fromParts[2]) != number(toParts[2]):
+		return KindMinor
+	}
+	return KindPatch
+}
@@ -0,0 +1,108 @@
+package depdigest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newPullRequest returns an open pull request by login with the title.
+func newPullRequest(login, title string, labels ...string) PullRequest {
+	var pr PullRequest
+	pr.Number = 42
+	pr.Title = title
+	pr.HTMLURL = "https://github.com/acme/web/pull/42"
+	pr.State = "open"
+	pr.User.Login = login
+	pr.Base.Repo.FullName = "acme/web"
+	for _, label := range labels {
+		pr.Labels = append(pr.Labels, struct {
+			Name string `json:"name"`
+		}{Name: label})
+	}
+	return pr
+}
+
+func TestParsePullRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   PullRequest
+		ok   bool
+		pkg  string
+		from string
+		to   string
+		kind Kind
+		bot  string
+	}{
+		{
+			name: "dependabot patch",
+			pr:   newPullRequest("dependabot[bot]", "Bump lodash from 4.17.20 to 4.17.21 in /web"),
+			ok:   true, pkg: "lodash", from: "4.17.20", to: "4.17.21", kind: KindPatch, bot: "dependabot",
+		},
+		{
+			name: "dependabot conventional prefix",
+			pr:   newPullRequest("dependabot[bot]", "chore(deps): bump golang.org/x/net from 0.25.0 to 0.26.0"),
+			ok:   true, pkg: "golang.org/x/net", from: "0.25.0", to: "0.26.0", kind: KindMinor, bot: "dependabot",
+		},
+		{
+			name: "dependabot major",
+			pr:   newPullRequest("dependabot[bot]", "Bump github.com/go-redis/redis from v8.11.5 to v9.0.0"),
+			ok:   true, pkg: "github.com/go-redis/redis", from: "v8.11.5", to: "v9.0.0", kind: KindMajor, bot: "dependabot",
+		},
+		{
+			name: "renovate major",
+			pr:   newPullRequest("renovate[bot]", "Update dependency react to v19"),
+			ok:   true, pkg: "react", to: "v19", kind: KindMajor, bot: "renovate",
+		},
+		{
+			name: "renovate label",
+			pr:   newPullRequest("renovate[bot]", "Update module golang.org/x/text to v0.16.0", "minor"),
+			ok:   true, pkg: "golang.org/x/text", to: "v0.16.0", kind: KindMinor, bot: "renovate",
+		},
+		{
+			name: "renovate security",
+			pr:   newPullRequest("renovate[bot]", "chore(deps): update dependency axios to v1.7.4 [SECURITY]"),
+			ok:   true, pkg: "axios", to: "v1.7.4", kind: KindSecurity, bot: "renovate",
+		},
+		{
+			name: "security label",
+			pr:   newPullRequest("dependabot[bot]", "Bump express from 4.18.2 to 4.19.2", "security"),
+			ok:   true, pkg: "express", from: "4.18.2", to: "4.19.2", kind: KindSecurity, bot: "dependabot",
+		},
+		{
+			name: "grouped update",
+			pr:   newPullRequest("dependabot[bot]", "Bump the npm group across 1 directory with 5 updates"),
+		},
+		{
+			name: "human",
+			pr:   newPullRequest("alice", "Bump lodash from 4.17.20 to 4.17.21"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update, ok := ParsePullRequest(&tt.pr)
+			require.Equal(t, tt.ok, ok)
+			if !ok {
+				return
+			}
+			require.Equal(t, tt.pkg, update.Package)
+			require.Equal(t, tt.from, update.From)
+			require.Equal(t, tt.to, update.To)
+			require.Equal(t, tt.kind, update.Kind)
+			require.Equal(t, tt.bot, update.Bot)
+			require.Equal(t, "acme/web", update.Repository)
+			require.Equal(t, 42, update.Number)
+		})
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	require.Equal(t, KindPatch, kindOf("1.2.3", "1.2.4"))
+	require.Equal(t, KindMinor, kindOf("v1.2.3", "v1.3.0"))
+	require.Equal(t, KindMajor, kindOf("1.2.3", "2.0.0"))
+	require.Equal(t, KindMinor, kindOf("1.2", "1.3"))
+	require.Equal(t, KindMajor, kindOf("", "v2"))
+	require.Equal(t, KindOther, kindOf("", "v2.1.0"))
+	require.Equal(t, KindOther, kindOf("abc123", "def456"))
+}
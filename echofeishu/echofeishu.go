@@ -0,0 +1,41 @@
+// Package echofeishu adapts the event receiver of the events package and
+// the error reporting of the httpnotify package to Echo: Receiver mounts a
+// Receiver as an Echo handler, Verify verifies the callbacks of user
+// routes, and Notify reports panics and server errors.
+//
+// It is a separate module, so that the echo dependency is only added to
+// services using it.
+//
+// Example:
+//
+//	e := echo.New()
+//	e.Use(echofeishu.Notify(notifier))
+//	e.POST("/feishu/events", echofeishu.Receiver(receiver))
+//	e.POST("/callbacks/approve", approve, echofeishu.Verify(encryptKey, verificationToken))
+package echofeishu
+
+import (
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/events"
+	"github.com/labstack/echo/v4"
+)
+
+// Receiver returns an Echo handler serving event requests with receiver.
+func Receiver(receiver *events.Receiver) echo.HandlerFunc {
+	return echo.WrapHandler(receiver)
+}
+
+// Verify returns Echo middleware rejecting requests that fail
+// events.VerifyRequest with 401 Unauthorized, for user routes receiving
+// callbacks directly instead of through a Receiver. The request body is
+// restored, so it can still be read by the next handlers.
+func Verify(encryptKey, verificationToken string, opts ...feishubot.VerifyOption) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := events.VerifyRequest(c.Request(), encryptKey, verificationToken, opts...); err != nil {
+				return echo.ErrUnauthorized
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package echofeishu
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cium-cc/feishurobot/events"
+	"github.com/cium-cc/feishurobot/eventtest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const testCallback = `{"token":"token","action":{"value":{"id":"1"}}}`
+
+func TestReceiver(t *testing.T) {
+	receiver := events.NewReceiver("key", "token")
+	received := make(chan string, 1)
+	receiver.OnMessage(func(ctx context.Context, event *events.MessageEvent) error {
+		received <- event.Message.ChatID
+		return nil
+	})
+
+	e := echo.New()
+	e.POST("/", Receiver(receiver))
+	sim := eventtest.NewSimulator(e, "key", "token")
+
+	rec, err := sim.URLVerification("challenge")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"challenge":"challenge"}`, rec.Body.String())
+
+	rec, err = sim.SendMessage(eventtest.NewTextMessage("oc_xxx", "ou_xxx", "hello"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "oc_xxx", <-received)
+}
+
+func TestVerify(t *testing.T) {
+	e := echo.New()
+	e.POST("/callback", func(c echo.Context) error {
+		body, _ := io.ReadAll(c.Request().Body)
+		return c.String(http.StatusOK, string(body))
+	}, Verify("", "token"))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(testCallback)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, testCallback, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(`{"token":"other"}`)))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,60 @@
+package echofeishu
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/cium-cc/feishurobot/httpnotify"
+	"github.com/labstack/echo/v4"
+)
+
+// Notify returns Echo middleware reporting to notifier with the route
+// pattern of Echo, like httpnotify.Notifier.Middleware: panics are
+// recovered, reported and answered with 500 Internal Server Error if no
+// response was written yet, and the sampled share of 5xx responses,
+// including errors returned by handlers, is reported. Panics with
+// http.ErrAbortHandler are not reported.
+//
+// Example:
+//
+//	e := echo.New()
+//	e.Use(echofeishu.Notify(notifier))
+func Notify(notifier *httpnotify.Notifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				notifier.ReportPanic(c.Request(), c.Path(), recovered, debug.Stack())
+				if !c.Response().Committed {
+					err = echo.ErrInternalServerError
+				}
+			}()
+
+			err = next(c)
+			notifier.ReportStatus(c.Request(), c.Path(), status(c, err))
+			return err
+		}
+	}
+}
+
+// status returns the status of a response, or the status the error handler
+// of Echo will answer with if the handler returned an error before writing
+// the response.
+func status(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,80 @@
+package echofeishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/httpnotify"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the cards sent by a notifier as JSON.
+type recorder struct {
+	mu    sync.Mutex
+	cards []string
+}
+
+func (r *recorder) send(_ context.Context, msg *feishubot.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cards = append(r.cards, string(data))
+	return nil
+}
+
+func TestNotify(t *testing.T) {
+	rec := &recorder{}
+	notifier := httpnotify.New(rec.send)
+	notifier.Interval = -1
+	notifier.ServerErrorSampleRate = 1
+
+	e := echo.New()
+	e.Use(Notify(notifier))
+	e.GET("/orders/:id", func(echo.Context) error {
+		panic("boom")
+	})
+	e.GET("/unavailable", func(c echo.Context) error {
+		return c.String(http.StatusServiceUnavailable, "unavailable")
+	})
+	e.GET("/failed", func(echo.Context) error {
+		return errors.New("failed")
+	})
+	e.GET("/missing", func(echo.Context) error {
+		return echo.ErrNotFound
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		notifier.Wait()
+		return w
+	}
+
+	w := serve("/orders/42")
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Len(t, rec.cards, 1)
+	require.Contains(t, rec.cards[0], "Panic on GET /orders/:id")
+	require.Contains(t, rec.cards[0], "boom")
+
+	serve("/unavailable")
+	require.Len(t, rec.cards, 2)
+	require.Contains(t, rec.cards[1], "503 on GET /unavailable")
+
+	w = serve("/failed")
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Len(t, rec.cards, 3)
+	require.Contains(t, rec.cards[2], "500 on GET /failed")
+
+	serve("/missing")
+	require.Len(t, rec.cards, 3)
+}
@@ -0,0 +1,126 @@
+package feishubot
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvWebhookURL   = "FEISHU_WEBHOOK_URL"
+	EnvSecret       = "FEISHU_SECRET"
+	EnvKeyword      = "FEISHU_KEYWORD"
+	EnvTimeout      = "FEISHU_TIMEOUT"
+	EnvProxy        = "FEISHU_PROXY"
+	EnvRetryMax     = "FEISHU_RETRY_MAX"
+	EnvRetryBackoff = "FEISHU_RETRY_BACKOFF"
+)
+
+// defaultTimeout is the timeout of the HTTP client of NewClient.
+const defaultTimeout = 30 * time.Second
+
+// NewClientFromEnv creates a client configured by environment variables,
+// for deployments configured through the environment:
+//
+//   - FEISHU_WEBHOOK_URL: the webhook URL of the custom bot, required
+//   - FEISHU_SECRET: the signing secret of the bot
+//   - FEISHU_KEYWORD: the custom keyword required by the bot, see
+//     Client.Keyword
+//   - FEISHU_TIMEOUT: the timeout of requests, as a duration such as "10s"
+//     or in seconds, 30 seconds by default
+//   - FEISHU_PROXY: the URL of the HTTP proxy requests are sent through
+//   - FEISHU_RETRY_MAX: the number of retries of failed sends, see SetRetry
+//   - FEISHU_RETRY_BACKOFF: the delay before the first retry, as a
+//     duration or in seconds
+//
+// All invalid or missing values are reported in a single error.
+func NewClientFromEnv() (*Client, error) {
+	var problems []string
+
+	webhookURL := os.Getenv(EnvWebhookURL)
+	if webhookURL == "" {
+		problems = append(problems, EnvWebhookURL+" is not set")
+	} else if u, err := url.Parse(webhookURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("%s is not an http(s) URL: %q", EnvWebhookURL, webhookURL))
+	}
+
+	timeout, err := envDuration(EnvTimeout, defaultTimeout)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	backoff, err := envDuration(EnvRetryBackoff, 0)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	var retryMax int
+	if s := os.Getenv(EnvRetryMax); s != "" {
+		retryMax, err = strconv.Atoi(s)
+		if err != nil || retryMax < 0 {
+			problems = append(problems, fmt.Sprintf("%s is not a non-negative integer: %q", EnvRetryMax, s))
+		}
+	}
+
+	var proxy *url.URL
+	if s := os.Getenv(EnvProxy); s != "" {
+		proxy, err = url.Parse(s)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			problems = append(problems, fmt.Sprintf("%s is not a proxy URL: %q", EnvProxy, s))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid client configuration: %s", strings.Join(problems, "; "))
+	}
+
+	client := NewClient(webhookURL, os.Getenv(EnvSecret))
+	client.SetKeyword(os.Getenv(EnvKeyword))
+	client.SetRetry(retryMax, backoff)
+	if timeout != defaultTimeout || proxy != nil {
+		client.SetHTTPClient(newHTTPClient(timeout, proxy))
+	}
+	return client, nil
+}
+
+// envDuration parses a duration environment variable, see parseDuration.
+func envDuration(name string, defaultValue time.Duration) (time.Duration, error) {
+	s := os.Getenv(name)
+	if s == "" {
+		return defaultValue, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s %w", name, err)
+	}
+	return d, nil
+}
+
+// parseDuration parses a positive duration given as a duration such as
+// "10s" or in seconds.
+func parseDuration(s string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("is not a positive duration: %q", s)
+	}
+	return d, nil
+}
+
+// newHTTPClient creates an HTTP client with the timeout, sending through
+// proxy if not nil.
+func newHTTPClient(timeout time.Duration, proxy *url.URL) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if proxy != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(proxy)
+		client.Transport = transport
+	}
+	return client
+}
@@ -0,0 +1,71 @@
+package feishubot
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setEnv sets the client environment variables, unsetting those not given.
+func setEnv(t *testing.T, values map[string]string) {
+	t.Helper()
+	for _, name := range []string{EnvWebhookURL, EnvSecret, EnvKeyword, EnvTimeout, EnvProxy, EnvRetryMax, EnvRetryBackoff} {
+		t.Setenv(name, values[name])
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		EnvWebhookURL:   "https://open.feishu.cn/open-apis/bot/v2/hook/xxx",
+		EnvSecret:       "s3cret",
+		EnvKeyword:      "[alert]",
+		EnvTimeout:      "5s",
+		EnvProxy:        "http://proxy.internal:3128",
+		EnvRetryMax:     "3",
+		EnvRetryBackoff: "0.5",
+	})
+
+	client, err := NewClientFromEnv()
+	require.NoError(t, err)
+	require.Equal(t, "https://open.feishu.cn/open-apis/bot/v2/hook/xxx", client.WebhookURL)
+	require.Equal(t, "s3cret", client.Secret)
+	require.Equal(t, "[alert]", client.Keyword)
+	require.Equal(t, 3, client.MaxRetries)
+	require.Equal(t, 500*time.Millisecond, client.RetryBackoff)
+
+	httpClient := client.HTTPClient.(*http.Client)
+	require.Equal(t, 5*time.Second, httpClient.Timeout)
+	proxy, err := httpClient.Transport.(*http.Transport).Proxy(&http.Request{})
+	require.NoError(t, err)
+	require.Equal(t, "http://proxy.internal:3128", proxy.String())
+}
+
+func TestNewClientFromEnvDefaults(t *testing.T) {
+	setEnv(t, map[string]string{EnvWebhookURL: "https://open.feishu.cn/open-apis/bot/v2/hook/xxx"})
+
+	client, err := NewClientFromEnv()
+	require.NoError(t, err)
+	require.Empty(t, client.Secret)
+	require.Zero(t, client.MaxRetries)
+	require.Equal(t, defaultTimeout, client.HTTPClient.(*http.Client).Timeout)
+	require.Nil(t, client.HTTPClient.(*http.Client).Transport)
+}
+
+func TestNewClientFromEnvErrors(t *testing.T) {
+	setEnv(t, map[string]string{
+		EnvTimeout:      "soon",
+		EnvProxy:        "proxy",
+		EnvRetryMax:     "-1",
+		EnvRetryBackoff: "0",
+	})
+	_, err := NewClientFromEnv()
+	require.EqualError(t, err, `invalid client configuration: FEISHU_WEBHOOK_URL is not set; `+
+		`FEISHU_TIMEOUT is not a positive duration: "soon"; FEISHU_RETRY_BACKOFF is not a positive duration: "0"; `+
+		`FEISHU_RETRY_MAX is not a non-negative integer: "-1"; FEISHU_PROXY is not a proxy URL: "proxy"`)
+
+	setEnv(t, map[string]string{EnvWebhookURL: "open.feishu.cn/hook"})
+	_, err = NewClientFromEnv()
+	require.EqualError(t, err, `invalid client configuration: FEISHU_WEBHOOK_URL is not an http(s) URL: "open.feishu.cn/hook"`)
+}
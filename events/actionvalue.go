@@ -0,0 +1,214 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DecodeValue decodes the callback value of the action into v, a pointer to
+// a struct with json tags. See DecodeActionValues for the conversions
+// applied.
+//
+// Example:
+//
+//	var value struct {
+//		Command string `json:"command"`
+//		BuildID int    `json:"build_id"`
+//	}
+//	if err := action.DecodeValue(&value); err != nil {
+//		return err
+//	}
+func (a *CardAction) DecodeValue(v interface{}) error {
+	if err := DecodeActionValues(a.Action.Value, v); err != nil {
+		return fmt.Errorf("failed to decode action value: %w", err)
+	}
+	return nil
+}
+
+// DecodeFormValue decodes the values of a submitted form into v, a pointer
+// to a struct with json tags matching the names of the form elements. See
+// DecodeActionValues for the conversions applied.
+func (a *CardAction) DecodeFormValue(v interface{}) error {
+	if err := DecodeActionValues(a.Action.FormValue, v); err != nil {
+		return fmt.Errorf("failed to decode form value: %w", err)
+	}
+	return nil
+}
+
+// DecodeActionValues decodes card callback values into v, a pointer to a
+// struct with json tags, like encoding/json but converting between the
+// string-heavy values sent by Feishu and the field types:
+//
+//   - strings such as "42" or "true" are parsed into number and bool fields,
+//     and empty strings leave them unset;
+//   - numbers and bools are formatted into string fields;
+//   - single values are wrapped into slice fields, e.g. a select with one
+//     selected option;
+//   - nested struct fields are converted the same way.
+func DecodeActionValues(values map[string]interface{}, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot decode into %T, must be a non-nil pointer", v)
+	}
+
+	coerced, err := coerceValue(values, rv.Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(coerced)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal values: %w", err)
+	}
+	return nil
+}
+
+// coerceValue converts value into a form that encoding/json decodes into
+// type t.
+func coerceValue(value interface{}, t reflect.Type) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		s, ok := value.(string)
+		if !ok {
+			return value, nil
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, nil
+		}
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return nil, fmt.Errorf("cannot convert %q to %s", s, t)
+		}
+		return json.Number(s), nil
+
+	case reflect.Bool:
+		s, ok := value.(string)
+		if !ok {
+			return value, nil
+		}
+		if strings.TrimSpace(s) == "" {
+			return nil, nil
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to bool", s)
+		}
+		return b, nil
+
+	case reflect.String:
+		switch value := value.(type) {
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64), nil
+		case bool, json.Number:
+			return fmt.Sprint(value), nil
+		}
+		return value, nil
+
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as base64 by encoding/json
+			return value, nil
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			if value == nil {
+				return nil, nil
+			}
+			items = []interface{}{value}
+		}
+		coerced := make([]interface{}, len(items))
+		for i, item := range items {
+			c, err := coerceValue(item, t.Elem())
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			coerced[i] = c
+		}
+		return coerced, nil
+
+	case reflect.Map:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return value, nil
+		}
+		coerced := make(map[string]interface{}, len(m))
+		for k, item := range m {
+			c, err := coerceValue(item, t.Elem())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			coerced[k] = c
+		}
+		return coerced, nil
+
+	case reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok || reflect.PtrTo(t).Implements(unmarshalerType) {
+			return value, nil
+		}
+		return coerceStruct(m, t)
+	}
+	return value, nil
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// coerceStruct converts the values of the fields of struct type t in m.
+// Values without a matching field are kept for encoding/json to ignore.
+func coerceStruct(m map[string]interface{}, t reflect.Type) (map[string]interface{}, error) {
+	coerced := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		coerced[k] = v
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || (field.Anonymous && field.Tag.Get("json") == "") {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		key, ok := fieldKey(m, name)
+		if !ok {
+			continue
+		}
+		c, err := coerceValue(m[key], field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		coerced[key] = c
+	}
+	return coerced, nil
+}
+
+// fieldKey returns the key of m for the field name, matched like
+// encoding/json: exactly, or otherwise case-insensitively.
+func fieldKey(m map[string]interface{}, name string) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+	for key := range m {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
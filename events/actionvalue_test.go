@@ -0,0 +1,147 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type deployForm struct {
+	Service  string   `json:"service"`
+	Replicas int      `json:"replicas"`
+	Ratio    float64  `json:"ratio"`
+	DryRun   bool     `json:"dry_run"`
+	Regions  []string `json:"regions"`
+	BuildIDs []int    `json:"build_ids"`
+	Version  string   `json:"version"`
+	Timeout  *int     `json:"timeout"`
+	Ignored  string   `json:"-"`
+	Target   struct {
+		Port int `json:"port"`
+	} `json:"target"`
+	Labels map[string]int `json:"labels"`
+	deployMeta
+}
+
+type deployMeta struct {
+	Attempt int `json:"attempt"`
+}
+
+func TestDecodeActionValues(t *testing.T) {
+	values := map[string]interface{}{
+		"service":   "api",
+		"replicas":  "3",
+		"ratio":     " 0.5 ",
+		"dry_run":   "true",
+		"regions":   "eu-west",
+		"build_ids": []interface{}{"1", float64(2)},
+		"version":   float64(12),
+		"timeout":   "30",
+		"Ignored":   "x",
+		"target":    map[string]interface{}{"port": "8080"},
+		"labels":    map[string]interface{}{"tier": "1"},
+		"attempt":   "2",
+		"unknown":   "kept",
+	}
+
+	var got deployForm
+	require.NoError(t, DecodeActionValues(values, &got))
+
+	timeout := 30
+	want := deployForm{
+		Service:    "api",
+		Replicas:   3,
+		Ratio:      0.5,
+		DryRun:     true,
+		Regions:    []string{"eu-west"},
+		BuildIDs:   []int{1, 2},
+		Version:    "12",
+		Timeout:    &timeout,
+		Labels:     map[string]int{"tier": 1},
+		deployMeta: deployMeta{Attempt: 2},
+	}
+	want.Target.Port = 8080
+	require.Equal(t, want, got)
+}
+
+func TestDecodeActionValuesEmptyAndCase(t *testing.T) {
+	var got deployForm
+	require.NoError(t, DecodeActionValues(map[string]interface{}{
+		"Replicas": "",
+		"DRY_RUN":  "",
+		"service":  true,
+	}, &got))
+	require.Equal(t, deployForm{Service: "true"}, got)
+}
+
+func TestDecodeActionValuesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		target  interface{}
+		wantErr string
+	}{
+		{
+			name:    "not a number",
+			values:  map[string]interface{}{"replicas": "three"},
+			target:  &deployForm{},
+			wantErr: `replicas: cannot convert "three" to int`,
+		},
+		{
+			name:    "not a bool",
+			values:  map[string]interface{}{"dry_run": "maybe"},
+			target:  &deployForm{},
+			wantErr: `dry_run: cannot convert "maybe" to bool`,
+		},
+		{
+			name:    "slice item",
+			values:  map[string]interface{}{"build_ids": []interface{}{"1", "x"}},
+			target:  &deployForm{},
+			wantErr: `build_ids: [1]: cannot convert "x" to int`,
+		},
+		{
+			name:    "not a pointer",
+			values:  map[string]interface{}{},
+			target:  deployForm{},
+			wantErr: "must be a non-nil pointer",
+		},
+		{
+			name:    "type mismatch",
+			values:  map[string]interface{}{"target": "x"},
+			target:  &deployForm{},
+			wantErr: "failed to unmarshal values",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, DecodeActionValues(tt.values, tt.target), tt.wantErr)
+		})
+	}
+}
+
+func TestCardActionDecode(t *testing.T) {
+	action := &CardAction{Action: Action{
+		Value:     map[string]interface{}{"command": "approve", "build_id": "42"},
+		FormValue: map[string]interface{}{"reason": "LGTM", "notify": []interface{}{"ou_xxx"}},
+	}}
+
+	var value struct {
+		Command string `json:"command"`
+		BuildID int    `json:"build_id"`
+	}
+	require.NoError(t, action.DecodeValue(&value))
+	require.Equal(t, "approve", value.Command)
+	require.Equal(t, 42, value.BuildID)
+
+	var form struct {
+		Reason string   `json:"reason"`
+		Notify []string `json:"notify"`
+	}
+	require.NoError(t, action.DecodeFormValue(&form))
+	require.Equal(t, "LGTM", form.Reason)
+	require.Equal(t, []string{"ou_xxx"}, form.Notify)
+
+	action.Action.Value["build_id"] = "x"
+	require.ErrorContains(t, action.DecodeValue(&value), "failed to decode action value: build_id")
+}
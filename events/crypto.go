@@ -0,0 +1,46 @@
+package events
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// errInvalidCiphertext is returned for encrypted events that cannot be
+// decrypted with the encrypt key.
+var errInvalidCiphertext = errors.New("invalid encrypted event")
+
+// decrypt decrypts an encrypted event: the base64 encoded AES-256-CBC
+// ciphertext, prefixed with its IV, using the SHA-256 of the encrypt key
+// as key.
+func decrypt(encrypted, encryptKey string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encrypted event: %w", err)
+	}
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, errInvalidCiphertext
+	}
+
+	key := sha256.Sum256([]byte(encryptKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	iv, ciphertext := data[:aes.BlockSize], data[aes.BlockSize:]
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	// Remove PKCS#7 padding
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize ||
+		!bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errInvalidCiphertext
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
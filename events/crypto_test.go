@@ -0,0 +1,54 @@
+package events
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// encrypt encrypts plaintext as Feishu does for encrypted events.
+func encrypt(t *testing.T, plaintext, encryptKey string) string {
+	t.Helper()
+
+	key := sha256.Sum256([]byte(encryptKey))
+	block, err := aes.NewCipher(key[:])
+	require.NoError(t, err)
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append([]byte(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	iv := []byte("0123456789abcdef")
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+	return base64.StdEncoding.EncodeToString(append(iv, ciphertext...))
+}
+
+func TestDecrypt(t *testing.T) {
+	plaintext := `{"challenge":"ajls384kdjx98XX","token":"xxxxxx","type":"url_verification"}`
+
+	got, err := decrypt(encrypt(t, plaintext, "key"), "key")
+	require.NoError(t, err)
+	require.Equal(t, plaintext, string(got))
+
+	tests := []struct {
+		name      string
+		encrypted string
+	}{
+		{name: "wrong key", encrypted: encrypt(t, plaintext, "other")},
+		{name: "not base64", encrypted: "!!!"},
+		{name: "too short", encrypted: base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))},
+		{name: "not block aligned", encrypted: base64.StdEncoding.EncodeToString(make([]byte, 40))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decrypt(tt.encrypted, "key")
+			require.Error(t, err)
+		})
+	}
+}
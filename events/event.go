@@ -0,0 +1,206 @@
+// Package events receives events and card callbacks that Feishu sends to
+// apps, such as messages sent to a bot, and lets handlers reply to them.
+//
+// Unlike custom bot webhooks, which can only send messages, receiving events
+// requires an app with event subscriptions configured in the Feishu
+// developer console, pointing at a Receiver.
+//
+// Example:
+//
+//	receiver := events.NewReceiver(encryptKey, verificationToken)
+//	receiver.SetReplier(events.NewWebhookReplier(feishubot.NewClient(webhookURL, secret)))
+//	receiver.OnMessage(func(ctx context.Context, event *events.MessageEvent) error {
+//		return events.Reply(ctx, feishubot.NewTextMessage("pong: "+event.Message.Text()))
+//	})
+//	http.Handle("/feishu/events", receiver)
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Event types handled by Receiver.
+const (
+	// EventTypeMessageReceive is sent when a user sends a message to the bot
+	// or mentions it in a group.
+	EventTypeMessageReceive = "im.message.receive_v1"
+
+	// EventTypeCardAction is sent when a user interacts with a card, e.g.
+	// clicks a button with a callback value.
+	EventTypeCardAction = "card.action.trigger"
+)
+
+// Header is the header of a schema 2.0 event.
+type Header struct {
+	EventID   string `json:"event_id"`
+	EventType string `json:"event_type"`
+	// CreateTime is the event creation time in milliseconds since epoch, or
+	// in seconds for schema 1.0 events.
+	CreateTime string `json:"create_time"`
+	Token      string `json:"token"`
+	AppID      string `json:"app_id"`
+	TenantKey  string `json:"tenant_key"`
+}
+
+// Envelope is an event as sent by Feishu, with the event body left
+// undecoded.
+type Envelope struct {
+	// Schema is "2.0" for schema 2.0 events, or empty for schema 1.0 events,
+	// whose metadata is moved into the Header.
+	Schema string          `json:"schema"`
+	Header Header          `json:"header"`
+	Event  json.RawMessage `json:"event"`
+}
+
+// ParseEnvelope parses the plaintext of an event, see DecryptBody. The
+// metadata of schema 1.0 events, which have no header, is moved into the
+// Header.
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	var envelope struct {
+		Envelope
+		UUID  string `json:"uuid"`
+		Token string `json:"token"`
+		TS    string `json:"ts"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse event: %w", err)
+	}
+
+	if envelope.Schema == "" && envelope.Header.EventType == "" {
+		var event struct {
+			Type      string `json:"type"`
+			AppID     string `json:"app_id"`
+			TenantKey string `json:"tenant_key"`
+		}
+		// Events without a type are left for the raw handlers
+		_ = json.Unmarshal(envelope.Event, &event)
+
+		envelope.Header = Header{
+			EventID:    envelope.UUID,
+			EventType:  event.Type,
+			CreateTime: envelope.TS,
+			Token:      envelope.Token,
+			AppID:      event.AppID,
+			TenantKey:  event.TenantKey,
+		}
+	}
+	return &envelope.Envelope, nil
+}
+
+// UserID holds the IDs of a user.
+type UserID struct {
+	OpenID  string `json:"open_id"`
+	UserID  string `json:"user_id"`
+	UnionID string `json:"union_id"`
+}
+
+// Mention is a user mentioned in a received message.
+type Mention struct {
+	// Key is the placeholder of the mention in the message text, such as
+	// "@_user_1".
+	Key       string `json:"key"`
+	ID        UserID `json:"id"`
+	Name      string `json:"name"`
+	TenantKey string `json:"tenant_key"`
+}
+
+// Sender is the sender of a received message.
+type Sender struct {
+	SenderID UserID `json:"sender_id"`
+	// SenderType is "user" for messages sent by users.
+	SenderType string `json:"sender_type"`
+	TenantKey  string `json:"tenant_key"`
+}
+
+// ReceivedMessage is a message received by the bot.
+type ReceivedMessage struct {
+	MessageID string `json:"message_id"`
+	RootID    string `json:"root_id"`
+	ParentID  string `json:"parent_id"`
+	// CreateTime is the send time in milliseconds since epoch.
+	CreateTime string `json:"create_time"`
+	ChatID     string `json:"chat_id"`
+	// ChatType is "p2p" for direct messages or "group".
+	ChatType    string `json:"chat_type"`
+	MessageType string `json:"message_type"`
+	// Content is the JSON encoded message content, e.g. {"text":"hello"}.
+	Content  string    `json:"content"`
+	Mentions []Mention `json:"mentions"`
+}
+
+// Text returns the text of a text message with mention placeholders such
+// as "@_user_1" removed and surrounding whitespace trimmed, e.g. "/deploy
+// api" for "@_user_1 /deploy api". It returns an empty string for other
+// message types.
+func (m *ReceivedMessage) Text() string {
+	if m.MessageType != "text" {
+		return ""
+	}
+
+	var content struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(m.Content), &content); err != nil {
+		return ""
+	}
+
+	text := content.Text
+	for _, mention := range m.Mentions {
+		text = strings.ReplaceAll(text, mention.Key, "")
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// MessageEvent is the body of an im.message.receive_v1 event.
+type MessageEvent struct {
+	Header  Header          `json:"-"`
+	Sender  Sender          `json:"sender"`
+	Message ReceivedMessage `json:"message"`
+}
+
+// Operator is the user who triggered a card action.
+type Operator struct {
+	OpenID    string `json:"open_id"`
+	UserID    string `json:"user_id"`
+	UnionID   string `json:"union_id"`
+	TenantKey string `json:"tenant_key"`
+}
+
+// Action is the interaction of a card action.
+type Action struct {
+	// Tag is the tag of the element interacted with, e.g. "button".
+	Tag string `json:"tag"`
+	// Name is the name of the element, if set.
+	Name string `json:"name"`
+	// Value is the callback value of the element.
+	Value map[string]interface{} `json:"value"`
+	// Option is the selected option of select elements.
+	Option string `json:"option"`
+	// FormValue holds the values of a submitted form, by element name.
+	FormValue map[string]interface{} `json:"form_value"`
+}
+
+// CardContext identifies the card and chat of a card action.
+type CardContext struct {
+	OpenMessageID string `json:"open_message_id"`
+	OpenChatID    string `json:"open_chat_id"`
+}
+
+// CardAction is the body of a card.action.trigger event.
+type CardAction struct {
+	Header   Header      `json:"-"`
+	Operator Operator    `json:"operator"`
+	Token    string      `json:"token"`
+	Action   Action      `json:"action"`
+	Context  CardContext `json:"context"`
+}
+
+// decodeEvent decodes the event body of envelope into v.
+func decodeEvent(envelope *Envelope, v interface{}) error {
+	if err := json.Unmarshal(envelope.Event, v); err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", envelope.Header.EventType, err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceivedMessageText(t *testing.T) {
+	tests := []struct {
+		name    string
+		message ReceivedMessage
+		want    string
+	}{
+		{
+			name:    "text",
+			message: ReceivedMessage{MessageType: "text", Content: `{"text":"hello"}`},
+			want:    "hello",
+		},
+		{
+			name: "mentions removed",
+			message: ReceivedMessage{
+				MessageType: "text",
+				Content:     `{"text":"@_user_1  /deploy   api @_user_2"}`,
+				Mentions:    []Mention{{Key: "@_user_1"}, {Key: "@_user_2"}},
+			},
+			want: "/deploy api",
+		},
+		{
+			name:    "other message type",
+			message: ReceivedMessage{MessageType: "image", Content: `{"image_key":"img_xxx"}`},
+			want:    "",
+		},
+		{
+			name:    "invalid content",
+			message: ReceivedMessage{MessageType: "text", Content: `{`},
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.message.Text())
+		})
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	data := `{
+		"schema": "2.0",
+		"header": {"event_id": "ev_1", "event_type": "card.action.trigger", "token": "tok"},
+		"event": {
+			"operator": {"open_id": "ou_xxx"},
+			"action": {"tag": "button", "value": {"command": "approve"}},
+			"context": {"open_message_id": "om_xxx", "open_chat_id": "oc_xxx"}
+		}
+	}`
+
+	var envelope Envelope
+	require.NoError(t, json.Unmarshal([]byte(data), &envelope))
+	require.Equal(t, Header{EventID: "ev_1", EventType: EventTypeCardAction, Token: "tok"}, envelope.Header)
+
+	var action CardAction
+	require.NoError(t, decodeEvent(&envelope, &action))
+	want := CardAction{
+		Operator: Operator{OpenID: "ou_xxx"},
+		Action:   Action{Tag: "button", Value: map[string]any{"command": "approve"}},
+		Context:  CardContext{OpenMessageID: "om_xxx", OpenChatID: "oc_xxx"},
+	}
+	if diff := cmp.Diff(want, action); diff != "" {
+		t.Errorf("decodeEvent() mismatch (-want +got):\n%s", diff)
+	}
+
+	envelope.Event = json.RawMessage(`[]`)
+	require.ErrorContains(t, decodeEvent(&envelope, &action), "card.action.trigger")
+}
+
+func TestParseEnvelope(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Header
+	}{
+		{
+			name: "schema 2.0",
+			data: `{"schema":"2.0","header":{"event_id":"ev_1","event_type":"im.chat.disbanded_v1","create_time":"1700000000000","tenant_key":"t"},"event":{}}`,
+			want: Header{EventID: "ev_1", EventType: "im.chat.disbanded_v1", CreateTime: "1700000000000", TenantKey: "t"},
+		},
+		{
+			name: "schema 1.0",
+			data: `{"uuid":"u_1","token":"tok","ts":"1700000000.1","type":"event_callback","event":{"type":"app_open","app_id":"cli_xxx","tenant_key":"t"}}`,
+			want: Header{EventID: "u_1", EventType: "app_open", CreateTime: "1700000000.1", Token: "tok", AppID: "cli_xxx", TenantKey: "t"},
+		},
+		{
+			name: "no event type",
+			data: `{"token":"tok"}`,
+			want: Header{Token: "tok"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := ParseEnvelope([]byte(tt.data))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, envelope.Header)
+		})
+	}
+
+	_, err := ParseEnvelope([]byte(`[]`))
+	require.ErrorContains(t, err, "failed to parse event")
+}
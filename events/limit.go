@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrReceiverBusy is returned when an event is rejected because the
+// receiver runs its maximum number of handlers and its queue is full.
+var ErrReceiverBusy = errors.New("receiver busy")
+
+// limiter bounds the number of concurrent and queued handler executions.
+type limiter struct {
+	// slots holds a token per running handler
+	slots chan struct{}
+	// admitted holds a token per running or queued handler
+	admitted chan struct{}
+}
+
+func newLimiter(maxConcurrent, maxQueued int) *limiter {
+	if maxQueued < 0 {
+		maxQueued = 0
+	}
+	return &limiter{
+		slots:    make(chan struct{}, maxConcurrent),
+		admitted: make(chan struct{}, maxConcurrent+maxQueued),
+	}
+}
+
+// acquire waits for a free slot. It returns ErrReceiverBusy without waiting
+// if the queue is full, or the context error if ctx is done first.
+func (l *limiter) acquire(ctx context.Context) error {
+	select {
+	case l.admitted <- struct{}{}:
+	default:
+		return ErrReceiverBusy
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		<-l.admitted
+		return ctx.Err()
+	}
+}
+
+// release frees a slot acquired with acquire.
+func (l *limiter) release() {
+	<-l.slots
+	<-l.admitted
+}
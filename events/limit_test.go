@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(1, 1)
+	ctx := context.Background()
+
+	require.NoError(t, l.acquire(ctx))
+
+	// The second caller queues until the first releases
+	acquired := make(chan error)
+	go func() { acquired <- l.acquire(ctx) }()
+
+	// Wait for the second caller to be admitted into the queue
+	for len(l.admitted) < 2 {
+		runtime.Gosched()
+	}
+	require.ErrorIs(t, l.acquire(ctx), ErrReceiverBusy)
+
+	l.release()
+	require.NoError(t, <-acquired)
+	l.release()
+	require.Empty(t, l.slots)
+	require.Empty(t, l.admitted)
+}
+
+func TestLimiterCanceled(t *testing.T) {
+	l := newLimiter(1, 1)
+	require.NoError(t, l.acquire(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, l.acquire(ctx), context.Canceled)
+	require.Len(t, l.admitted, 1)
+
+	l.release()
+}
+
+func TestLimiterNoQueue(t *testing.T) {
+	l := newLimiter(1, -1)
+	require.NoError(t, l.acquire(context.Background()))
+	require.ErrorIs(t, l.acquire(context.Background()), ErrReceiverBusy)
+	l.release()
+}
@@ -0,0 +1,150 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Observer receives instrumentation callbacks from a Receiver, to record
+// metrics or traces with the monitoring system of the host service.
+type Observer interface {
+	// EventStarted is called before the handlers of a verified event run.
+	// The returned context is passed to the handlers, e.g. carrying a
+	// tracing span.
+	EventStarted(ctx context.Context, header Header) context.Context
+
+	// EventFinished is called after the handlers of an event returned, with
+	// the context returned by EventStarted, the time the handlers took and
+	// their error, if any.
+	EventFinished(ctx context.Context, header Header, duration time.Duration, err error)
+
+	// RequestRejected is called for requests rejected before their handlers
+	// run: malformed requests, failed verifications and requests rejected
+	// by the concurrency limit or after Shutdown, with the HTTP status
+	// answered.
+	RequestRejected(ctx context.Context, status int, err error)
+}
+
+// ObserverFuncs is an Observer calling the functions that are set.
+type ObserverFuncs struct {
+	OnEventStarted    func(ctx context.Context, header Header) context.Context
+	OnEventFinished   func(ctx context.Context, header Header, duration time.Duration, err error)
+	OnRequestRejected func(ctx context.Context, status int, err error)
+}
+
+// EventStarted implements Observer.
+func (o *ObserverFuncs) EventStarted(ctx context.Context, header Header) context.Context {
+	if o.OnEventStarted == nil {
+		return ctx
+	}
+	return o.OnEventStarted(ctx, header)
+}
+
+// EventFinished implements Observer.
+func (o *ObserverFuncs) EventFinished(ctx context.Context, header Header, duration time.Duration, err error) {
+	if o.OnEventFinished != nil {
+		o.OnEventFinished(ctx, header, duration, err)
+	}
+}
+
+// RequestRejected implements Observer.
+func (o *ObserverFuncs) RequestRejected(ctx context.Context, status int, err error) {
+	if o.OnRequestRejected != nil {
+		o.OnRequestRejected(ctx, status, err)
+	}
+}
+
+// EventTypeStats are the statistics of an event type recorded by Metrics.
+type EventTypeStats struct {
+	// Received is the number of events whose handlers ran.
+	Received int64
+	// Failed is the number of events whose handlers returned an error.
+	Failed int64
+	// HandlerTime is the total time spent in handlers.
+	HandlerTime time.Duration
+	// MaxHandlerTime is the longest time spent handling one event.
+	MaxHandlerTime time.Duration
+}
+
+// MetricsSnapshot is a copy of the statistics recorded by Metrics.
+type MetricsSnapshot struct {
+	// Events holds the statistics by event type.
+	Events map[string]EventTypeStats
+	// SignatureRejections is the number of requests rejected because their
+	// signature, token or timestamp did not verify.
+	SignatureRejections int64
+	// Rejections is the number of rejected requests by HTTP status,
+	// including signature rejections.
+	Rejections map[int]int64
+}
+
+// Metrics is an Observer counting events in memory, for services exposing
+// them without a metrics library, e.g. with expvar.
+type Metrics struct {
+	mu       sync.Mutex
+	snapshot MetricsSnapshot
+}
+
+// NewMetrics creates empty metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{snapshot: MetricsSnapshot{
+		Events:     make(map[string]EventTypeStats),
+		Rejections: make(map[int]int64),
+	}}
+}
+
+// EventStarted implements Observer.
+func (m *Metrics) EventStarted(ctx context.Context, header Header) context.Context {
+	return ctx
+}
+
+// EventFinished implements Observer.
+func (m *Metrics) EventFinished(ctx context.Context, header Header, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.snapshot.Events[header.EventType]
+	stats.Received++
+	if err != nil {
+		stats.Failed++
+	}
+	stats.HandlerTime += duration
+	if duration > stats.MaxHandlerTime {
+		stats.MaxHandlerTime = duration
+	}
+	m.snapshot.Events[header.EventType] = stats
+}
+
+// RequestRejected implements Observer.
+func (m *Metrics) RequestRejected(ctx context.Context, status int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshot.Rejections[status]++
+	if errors.Is(err, feishubot.ErrInvalidEventSignature) || errors.Is(err, feishubot.ErrEventTimestampExpired) {
+		m.snapshot.SignatureRejections++
+	}
+}
+
+// Snapshot returns a copy of the recorded statistics.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := MetricsSnapshot{
+		Events:              make(map[string]EventTypeStats, len(m.snapshot.Events)),
+		SignatureRejections: m.snapshot.SignatureRejections,
+		Rejections:          make(map[int]int64, len(m.snapshot.Rejections)),
+	}
+	for k, v := range m.snapshot.Events {
+		snapshot.Events[k] = v
+	}
+	for k, v := range m.snapshot.Rejections {
+		snapshot.Rejections[k] = v
+	}
+	return snapshot
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+type spanKey struct{}
+
+func TestReceiverObserver(t *testing.T) {
+	receiver, _ := newTestReceiver("")
+
+	var calls []string
+	receiver.SetObserver(&ObserverFuncs{
+		OnEventStarted: func(ctx context.Context, header Header) context.Context {
+			calls = append(calls, "started "+header.EventType)
+			return context.WithValue(ctx, spanKey{}, "span")
+		},
+		OnEventFinished: func(ctx context.Context, header Header, duration time.Duration, err error) {
+			require.Equal(t, "span", ctx.Value(spanKey{}))
+			require.GreaterOrEqual(t, duration, time.Duration(0))
+			calls = append(calls, "finished "+header.EventType+" "+errorString(err))
+		},
+		OnRequestRejected: func(ctx context.Context, status int, err error) {
+			calls = append(calls, http.StatusText(status))
+		},
+	})
+	receiver.OnMessage(func(ctx context.Context, event *MessageEvent) error {
+		require.Equal(t, "span", ctx.Value(spanKey{}))
+		return errors.New("boom")
+	})
+
+	post(receiver, testMessageEvent, nil)
+	post(receiver, testCardAction, nil)
+	post(receiver, strings.Replace(testCardAction, `"token": "token"`, `"token": "x"`, 1), nil)
+	post(receiver, `{`, nil)
+
+	require.Equal(t, []string{
+		"started im.message.receive_v1",
+		"finished im.message.receive_v1 failed to handle message om_xxx: boom",
+		"started card.action.trigger",
+		"finished card.action.trigger ",
+		"Unauthorized",
+		"Bad Request",
+	}, calls)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func TestObserverFuncsNil(t *testing.T) {
+	o := &ObserverFuncs{}
+	ctx := context.Background()
+	require.Equal(t, ctx, o.EventStarted(ctx, Header{}))
+	o.EventFinished(ctx, Header{}, 0, nil)
+	o.RequestRejected(ctx, http.StatusBadRequest, nil)
+}
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	ctx := m.EventStarted(context.Background(), Header{})
+
+	message := Header{EventType: EventTypeMessageReceive}
+	m.EventFinished(ctx, message, 2*time.Second, nil)
+	m.EventFinished(ctx, message, time.Second, errors.New("boom"))
+	m.EventFinished(ctx, Header{EventType: EventTypeCardAction}, time.Second, nil)
+
+	m.RequestRejected(ctx, http.StatusUnauthorized, feishubot.ErrInvalidEventSignature)
+	m.RequestRejected(ctx, http.StatusUnauthorized, feishubot.ErrEventTimestampExpired)
+	m.RequestRejected(ctx, http.StatusServiceUnavailable, ErrReceiverBusy)
+
+	snapshot := m.Snapshot()
+	require.Equal(t, MetricsSnapshot{
+		Events: map[string]EventTypeStats{
+			EventTypeMessageReceive: {Received: 2, Failed: 1, HandlerTime: 3 * time.Second, MaxHandlerTime: 2 * time.Second},
+			EventTypeCardAction:     {Received: 1, HandlerTime: time.Second, MaxHandlerTime: time.Second},
+		},
+		SignatureRejections: 2,
+		Rejections:          map[int]int64{http.StatusUnauthorized: 2, http.StatusServiceUnavailable: 1},
+	}, snapshot)
+
+	// Snapshots are copies
+	snapshot.Rejections[http.StatusUnauthorized] = 0
+	require.Equal(t, int64(2), m.Snapshot().Rejections[http.StatusUnauthorized])
+}
@@ -0,0 +1,336 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// MessageHandler handles a received message.
+type MessageHandler func(ctx context.Context, event *MessageEvent) error
+
+// CardActionHandler handles a card action.
+type CardActionHandler func(ctx context.Context, action *CardAction) error
+
+// EventHandler handles an event registered with Receiver.OnEvent. event is
+// the value returned by the registered newEvent function, decoded from the
+// event body.
+type EventHandler func(ctx context.Context, header Header, event interface{}) error
+
+// RawEventHandler handles an event with its body left undecoded.
+type RawEventHandler func(ctx context.Context, envelope *Envelope) error
+
+// Receiver is an http.Handler receiving events and card callbacks sent by
+// Feishu. It answers the URL verification challenge, decrypts encrypted
+// events, verifies requests and dispatches events to the registered
+// handlers.
+//
+// Handlers run synchronously, before the request is answered; Feishu retries
+// events that are not answered with a 200 status within a few seconds, so
+// long-running work should be done asynchronously. Handlers can answer the
+// originating chat with Reply.
+//
+// Frameworks mount it through their net/http adapters, e.g. gin.WrapH or
+// echo.WrapHandler.
+type Receiver struct {
+	// EncryptKey is the encrypt key of the app, used to decrypt events and
+	// verify their signature. Leave it empty if encryption is disabled.
+	EncryptKey string
+
+	// VerificationToken is the verification token of the app, used to
+	// verify requests that are not signed.
+	VerificationToken string
+
+	// VerifyOptions configure request verification, e.g. the accepted
+	// timestamp skew.
+	VerifyOptions []feishubot.VerifyOption
+
+	// Replier sends the messages passed to Reply by handlers. If nil, Reply
+	// returns ErrNoReplier.
+	Replier Replier
+
+	// ErrorHandler is called with errors returned by handlers and requests
+	// that are rejected. If nil, errors are logged with the standard logger.
+	ErrorHandler func(err error)
+
+	// Observer, if set, is notified of handled events and rejected requests,
+	// for metrics and tracing.
+	Observer Observer
+
+	messageHandlers    []MessageHandler
+	cardActionHandlers []CardActionHandler
+	eventHandlers      map[string][]typedEventHandler
+	rawHandlers        []RawEventHandler
+	limiter            *limiter
+	inFlight           inFlight
+}
+
+type typedEventHandler struct {
+	newEvent func() interface{}
+	handler  EventHandler
+}
+
+// NewReceiver creates a receiver for an app with the given encrypt key and
+// verification token, either of which may be empty if not configured.
+func NewReceiver(encryptKey, verificationToken string) *Receiver {
+	return &Receiver{
+		EncryptKey:        encryptKey,
+		VerificationToken: verificationToken,
+	}
+}
+
+// SetReplier sets the replier used by Reply in handlers.
+func (r *Receiver) SetReplier(replier Replier) {
+	r.Replier = replier
+}
+
+// SetErrorHandler sets the function called with handler and request errors.
+func (r *Receiver) SetErrorHandler(handler func(err error)) {
+	r.ErrorHandler = handler
+}
+
+// SetConcurrencyLimit bounds the number of events handled concurrently to
+// maxConcurrent, with up to maxQueued more events waiting for a free slot.
+// Further events are rejected with 503 Service Unavailable, which Feishu
+// retries later, so that bursts of events cannot exhaust the resources of
+// the host service. Queued events also give up when their request is
+// canceled. A maxConcurrent of zero or less removes the limit.
+//
+// It must be called before the receiver starts serving requests.
+func (r *Receiver) SetConcurrencyLimit(maxConcurrent, maxQueued int) {
+	if maxConcurrent <= 0 {
+		r.limiter = nil
+		return
+	}
+	r.limiter = newLimiter(maxConcurrent, maxQueued)
+}
+
+// SetObserver sets the observer notified of handled events and rejected
+// requests.
+func (r *Receiver) SetObserver(observer Observer) {
+	r.Observer = observer
+}
+
+// OnMessage registers a handler for received messages. Handlers are called
+// in registration order until one returns an error.
+func (r *Receiver) OnMessage(handler MessageHandler) {
+	r.messageHandlers = append(r.messageHandlers, handler)
+}
+
+// OnCardAction registers a handler for card actions. Handlers are called in
+// registration order until one returns an error.
+func (r *Receiver) OnCardAction(handler CardActionHandler) {
+	r.cardActionHandlers = append(r.cardActionHandlers, handler)
+}
+
+// OnEvent registers a handler for events of the given type, for event types
+// not modeled by this package, e.g. "im.chat.member.bot.added_v1". newEvent
+// must return a pointer that the event body can be decoded into with
+// encoding/json; implement json.Unmarshaler for custom decoding.
+//
+// Example:
+//
+//	type BotAdded struct {
+//		ChatID string `json:"chat_id"`
+//		Name   string `json:"name"`
+//	}
+//
+//	receiver.OnEvent("im.chat.member.bot.added_v1",
+//		func() interface{} { return &BotAdded{} },
+//		func(ctx context.Context, header events.Header, event interface{}) error {
+//			added := event.(*BotAdded)
+//			// ...
+//			return nil
+//		})
+func (r *Receiver) OnEvent(eventType string, newEvent func() interface{}, handler EventHandler) {
+	if r.eventHandlers == nil {
+		r.eventHandlers = make(map[string][]typedEventHandler)
+	}
+	r.eventHandlers[eventType] = append(r.eventHandlers[eventType], typedEventHandler{
+		newEvent: newEvent,
+		handler:  handler,
+	})
+}
+
+// OnRawEvent registers a handler for events of types without any other
+// handler, receiving the envelope with the event body left undecoded.
+func (r *Receiver) OnRawEvent(handler RawEventHandler) {
+	r.rawHandlers = append(r.rawHandlers, handler)
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		r.handleError(fmt.Errorf("failed to read request body: %w", err))
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	status, resp := r.Handle(req.Context(), req.Header, body)
+	w.Header().Set("Content-Type", "application/json")
+	if status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", "1")
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(resp)
+}
+
+// Handle processes an event request given by its headers and body, and
+// returns the HTTP status and JSON body to answer with. It is the receiver
+// without the HTTP server wiring, for serverless functions such as AWS
+// Lambda; see HeaderFromMap for headers given as a map.
+//
+// Example, with github.com/aws/aws-lambda-go:
+//
+//	func handler(ctx context.Context, in awsevents.LambdaFunctionURLRequest) (awsevents.LambdaFunctionURLResponse, error) {
+//		status, body := receiver.Handle(ctx, events.HeaderFromMap(in.Headers), []byte(in.Body))
+//		return awsevents.LambdaFunctionURLResponse{StatusCode: status, Body: string(body)}, nil
+//	}
+func (r *Receiver) Handle(ctx context.Context, header http.Header, body []byte) (int, []byte) {
+	if !r.inFlight.begin() {
+		return r.reject(ctx, http.StatusServiceUnavailable, ErrReceiverClosed)
+	}
+	defer r.inFlight.end()
+
+	req, err := ParseRequest(header, body, r.EncryptKey, r.VerificationToken, r.VerifyOptions...)
+	if err != nil {
+		if errors.Is(err, feishubot.ErrInvalidEventSignature) || errors.Is(err, feishubot.ErrEventTimestampExpired) {
+			return r.reject(ctx, http.StatusUnauthorized, err)
+		}
+		return r.reject(ctx, http.StatusBadRequest, err)
+	}
+	if req.Envelope == nil {
+		return http.StatusOK, ChallengeResponse(req.Challenge)
+	}
+	envelope := req.Envelope
+
+	if r.limiter != nil {
+		if err := r.limiter.acquire(ctx); err != nil {
+			err = fmt.Errorf("failed to handle %s event %s: %w", envelope.Header.EventType, envelope.Header.EventID, err)
+			return r.reject(ctx, http.StatusServiceUnavailable, err)
+		}
+		defer r.limiter.release()
+	}
+
+	if err := r.observedDispatch(ctx, envelope); err != nil {
+		r.handleError(err)
+		return http.StatusInternalServerError, errorResponse(err)
+	}
+	return http.StatusOK, []byte(`{}`)
+}
+
+// reject reports a rejected request and returns its response.
+func (r *Receiver) reject(ctx context.Context, status int, err error) (int, []byte) {
+	r.handleError(err)
+	if r.Observer != nil {
+		r.Observer.RequestRejected(ctx, status, err)
+	}
+	return status, errorResponse(err)
+}
+
+// observedDispatch dispatches an event, notifying the observer.
+func (r *Receiver) observedDispatch(ctx context.Context, envelope *Envelope) error {
+	if r.Observer == nil {
+		return r.dispatch(ctx, envelope)
+	}
+
+	ctx = r.Observer.EventStarted(ctx, envelope.Header)
+	start := time.Now()
+	err := r.dispatch(ctx, envelope)
+	r.Observer.EventFinished(ctx, envelope.Header, time.Since(start), err)
+	return err
+}
+
+// HandleEnvelope calls the handlers registered for an event that was
+// already received and verified, e.g. consumed from a message queue fed by
+// a Relay.
+func (r *Receiver) HandleEnvelope(ctx context.Context, envelope *Envelope) error {
+	return r.observedDispatch(ctx, envelope)
+}
+
+// dispatch decodes the event and calls the handlers registered for its
+// type. Events of types without handlers go to the raw event handlers.
+func (r *Receiver) dispatch(ctx context.Context, envelope *Envelope) error {
+	switch eventType := envelope.Header.EventType; {
+	case eventType == EventTypeMessageReceive && len(r.messageHandlers) > 0:
+		var event MessageEvent
+		if err := decodeEvent(envelope, &event); err != nil {
+			return err
+		}
+		event.Header = envelope.Header
+
+		ctx = withReply(ctx, r.Replier, ReplyTarget{
+			ChatID:    event.Message.ChatID,
+			MessageID: event.Message.MessageID,
+		})
+		for _, handler := range r.messageHandlers {
+			if err := handler(ctx, &event); err != nil {
+				return fmt.Errorf("failed to handle message %s: %w", event.Message.MessageID, err)
+			}
+		}
+
+	case eventType == EventTypeCardAction && len(r.cardActionHandlers) > 0:
+		var action CardAction
+		if err := decodeEvent(envelope, &action); err != nil {
+			return err
+		}
+		action.Header = envelope.Header
+
+		ctx = withReply(ctx, r.Replier, ReplyTarget{
+			ChatID:    action.Context.OpenChatID,
+			MessageID: action.Context.OpenMessageID,
+		})
+		for _, handler := range r.cardActionHandlers {
+			if err := handler(ctx, &action); err != nil {
+				return fmt.Errorf("failed to handle card action on message %s: %w", action.Context.OpenMessageID, err)
+			}
+		}
+
+	case len(r.eventHandlers[eventType]) > 0:
+		for _, h := range r.eventHandlers[eventType] {
+			event := h.newEvent()
+			if err := decodeEvent(envelope, event); err != nil {
+				return err
+			}
+			if err := h.handler(ctx, envelope.Header, event); err != nil {
+				return fmt.Errorf("failed to handle %s event %s: %w", eventType, envelope.Header.EventID, err)
+			}
+		}
+
+	default:
+		for _, handler := range r.rawHandlers {
+			if err := handler(ctx, envelope); err != nil {
+				return fmt.Errorf("failed to handle %s event %s: %w", eventType, envelope.Header.EventID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// handleError reports err to the error handler.
+func (r *Receiver) handleError(err error) {
+	if r.ErrorHandler != nil {
+		r.ErrorHandler(err)
+		return
+	}
+	log.Printf("feishubot/events: %v", err)
+}
+
+// errorResponse returns the response body of failed requests.
+func errorResponse(err error) []byte {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return data
+}
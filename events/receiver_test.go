@@ -0,0 +1,296 @@
+package events
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+const testMessageEvent = `{
+	"schema": "2.0",
+	"header": {"event_id": "ev_1", "event_type": "im.message.receive_v1", "token": "token"},
+	"event": {
+		"sender": {"sender_id": {"open_id": "ou_xxx"}, "sender_type": "user"},
+		"message": {
+			"message_id": "om_xxx",
+			"chat_id": "oc_xxx",
+			"chat_type": "group",
+			"message_type": "text",
+			"content": "{\"text\":\"@_user_1 /ping\"}",
+			"mentions": [{"key": "@_user_1", "name": "bot"}]
+		}
+	}
+}`
+
+const testCardAction = `{
+	"schema": "2.0",
+	"header": {"event_id": "ev_2", "event_type": "card.action.trigger", "token": "token"},
+	"event": {
+		"operator": {"open_id": "ou_xxx"},
+		"action": {"tag": "button", "value": {"command": "approve"}},
+		"context": {"open_message_id": "om_card", "open_chat_id": "oc_card"}
+	}
+}`
+
+// post sends an event request to receiver.
+func post(receiver *Receiver, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, req)
+	return rec
+}
+
+// signedHeader returns the signature headers of body.
+func signedHeader(body, encryptKey string) http.Header {
+	timestamp := "1700000000"
+	sum := sha256.Sum256([]byte(timestamp + "nonce" + encryptKey + body))
+	return http.Header{
+		feishubot.HeaderRequestTimestamp: {timestamp},
+		feishubot.HeaderRequestNonce:     {"nonce"},
+		feishubot.HeaderSignature:        {hex.EncodeToString(sum[:])},
+	}
+}
+
+// newTestReceiver creates a receiver with token "token", no timestamp
+// check and the errors it reports.
+func newTestReceiver(encryptKey string) (*Receiver, *[]error) {
+	receiver := NewReceiver(encryptKey, "token")
+	receiver.VerifyOptions = []feishubot.VerifyOption{feishubot.WithTimestampSkew(0)}
+
+	var errs []error
+	receiver.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+	return receiver, &errs
+}
+
+func TestReceiverURLVerification(t *testing.T) {
+	receiver, _ := newTestReceiver("key")
+	challenge := `{"challenge":"ajls384kdjx98XX","token":"token","type":"url_verification"}`
+
+	rec := post(receiver, challenge, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"challenge":"ajls384kdjx98XX"}`, rec.Body.String())
+
+	rec = post(receiver, `{"encrypt":"`+encrypt(t, challenge, "key")+`"}`, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"challenge":"ajls384kdjx98XX"}`, rec.Body.String())
+
+	rec = post(receiver, strings.Replace(challenge, `"token":"token"`, `"token":"other"`, 1), nil)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestReceiverMessage(t *testing.T) {
+	receiver, errs := newTestReceiver("key")
+
+	var replies []string
+	receiver.SetReplier(ReplierFunc(func(ctx context.Context, target ReplyTarget, msg *feishubot.Message) error {
+		replies = append(replies, target.ChatID+"/"+target.MessageID+": "+msg.Content["text"].(string))
+		return nil
+	}))
+
+	var senders []string
+	receiver.OnMessage(func(ctx context.Context, event *MessageEvent) error {
+		senders = append(senders, event.Sender.SenderID.OpenID)
+		require.Equal(t, "ev_1", event.Header.EventID)
+		return Reply(ctx, feishubot.NewTextMessage("pong: "+event.Message.Text()))
+	})
+
+	encrypted := `{"encrypt":"` + encrypt(t, testMessageEvent, "key") + `"}`
+
+	tests := []struct {
+		name   string
+		body   string
+		header http.Header
+	}{
+		{name: "token", body: testMessageEvent},
+		{name: "signed", body: testMessageEvent, header: signedHeader(testMessageEvent, "key")},
+		{name: "encrypted and signed", body: encrypted, header: signedHeader(encrypted, "key")},
+		{name: "encrypted token", body: encrypted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies, senders = nil, nil
+
+			rec := post(receiver, tt.body, tt.header)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			require.JSONEq(t, `{}`, rec.Body.String())
+			require.Equal(t, []string{"ou_xxx"}, senders)
+			require.Equal(t, []string{"oc_xxx/om_xxx: pong: /ping"}, replies)
+			require.Empty(t, *errs)
+		})
+	}
+}
+
+func TestReceiverCardAction(t *testing.T) {
+	receiver, _ := newTestReceiver("")
+
+	var got []string
+	receiver.OnCardAction(func(ctx context.Context, action *CardAction) error {
+		target, _ := ReplyTargetFromContext(ctx)
+		got = append(got, action.Action.Value["command"].(string)+" in "+target.ChatID+"/"+target.MessageID)
+		return nil
+	})
+
+	rec := post(receiver, testCardAction, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []string{"approve in oc_card/om_card"}, got)
+
+	// Events without handlers are acknowledged
+	rec = post(receiver, testMessageEvent, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestReceiverErrors(t *testing.T) {
+	receiver, errs := newTestReceiver("")
+	receiver.OnMessage(func(ctx context.Context, event *MessageEvent) error {
+		return errors.New("boom")
+	})
+
+	tests := []struct {
+		name    string
+		receive func() *httptest.ResponseRecorder
+		code    int
+		err     string
+	}{
+		{
+			name: "method",
+			receive: func() *httptest.ResponseRecorder {
+				rec := httptest.NewRecorder()
+				receiver.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+				return rec
+			},
+			code: http.StatusMethodNotAllowed,
+		},
+		{
+			name:    "invalid json",
+			receive: func() *httptest.ResponseRecorder { return post(receiver, `{`, nil) },
+			code:    http.StatusBadRequest,
+			err:     "failed to parse event",
+		},
+		{
+			name:    "encrypted without key",
+			receive: func() *httptest.ResponseRecorder { return post(receiver, `{"encrypt":"xxx"}`, nil) },
+			code:    http.StatusBadRequest,
+			err:     "no encrypt key is configured",
+		},
+		{
+			name: "wrong token",
+			receive: func() *httptest.ResponseRecorder {
+				return post(receiver, strings.Replace(testMessageEvent, `"token": "token"`, `"token": "other"`, 1), nil)
+			},
+			code: http.StatusUnauthorized,
+			err:  "invalid event signature",
+		},
+		{
+			name:    "handler error",
+			receive: func() *httptest.ResponseRecorder { return post(receiver, testMessageEvent, nil) },
+			code:    http.StatusInternalServerError,
+			err:     "failed to handle message om_xxx: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*errs = nil
+			rec := tt.receive()
+			require.Equal(t, tt.code, rec.Code)
+			if tt.err != "" {
+				require.Len(t, *errs, 1)
+				require.ErrorContains(t, (*errs)[0], tt.err)
+			}
+		})
+	}
+}
+
+func TestReceiverSignatureExpired(t *testing.T) {
+	receiver, _ := newTestReceiver("key")
+	receiver.VerifyOptions = []feishubot.VerifyOption{feishubot.WithTimestampSkew(time.Minute)}
+
+	rec := post(receiver, testMessageEvent, signedHeader(testMessageEvent, "key"))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestReceiverOnEvent(t *testing.T) {
+	receiver, errs := newTestReceiver("")
+
+	type botAdded struct {
+		ChatID string `json:"chat_id"`
+	}
+	var got []string
+	receiver.OnEvent("im.chat.member.bot.added_v1",
+		func() interface{} { return &botAdded{} },
+		func(ctx context.Context, header Header, event interface{}) error {
+			got = append(got, header.EventID+": "+event.(*botAdded).ChatID)
+			return nil
+		})
+	receiver.OnRawEvent(func(ctx context.Context, envelope *Envelope) error {
+		got = append(got, "raw "+envelope.Header.EventType+": "+string(envelope.Event))
+		return nil
+	})
+
+	rec := post(receiver, `{"schema":"2.0","header":{"event_id":"ev_1","event_type":"im.chat.member.bot.added_v1","token":"token"},"event":{"chat_id":"oc_xxx"}}`, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = post(receiver, `{"schema":"2.0","header":{"event_id":"ev_2","event_type":"im.chat.disbanded_v1","token":"token"},"event":{"chat_id":"oc_yyy"}}`, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// Modeled events without handlers are passed raw as well
+	rec = post(receiver, testCardAction, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	require.Len(t, got, 3)
+	require.Equal(t, "ev_1: oc_xxx", got[0])
+	require.Equal(t, `raw im.chat.disbanded_v1: {"chat_id":"oc_yyy"}`, got[1])
+	require.Contains(t, got[2], "raw card.action.trigger: ")
+	require.Empty(t, *errs)
+
+	rec = post(receiver, `{"schema":"2.0","header":{"event_id":"ev_3","event_type":"im.chat.member.bot.added_v1","token":"token"},"event":[]}`, nil)
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.ErrorContains(t, (*errs)[0], "failed to decode im.chat.member.bot.added_v1 event")
+}
+
+func TestReceiverConcurrencyLimit(t *testing.T) {
+	receiver, errs := newTestReceiver("")
+	receiver.SetConcurrencyLimit(1, 0)
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	receiver.OnMessage(func(ctx context.Context, event *MessageEvent) error {
+		close(started)
+		<-unblock
+		return nil
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(receiver, testMessageEvent, nil) }()
+	<-started
+
+	rec := post(receiver, testMessageEvent, nil)
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("Retry-After"))
+	require.ErrorIs(t, (*errs)[0], ErrReceiverBusy)
+
+	// URL verification is not limited
+	rec = post(receiver, `{"challenge":"c","token":"token","type":"url_verification"}`, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	close(unblock)
+	require.Equal(t, http.StatusOK, (<-done).Code)
+
+	receiver.SetConcurrencyLimit(0, 0)
+	require.Nil(t, receiver.limiter)
+}
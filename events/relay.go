@@ -0,0 +1,193 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Headers of messages published by a Relay.
+const (
+	RelayHeaderEventID    = "feishu-event-id"
+	RelayHeaderEventType  = "feishu-event-type"
+	RelayHeaderTenantKey  = "feishu-tenant-key"
+	RelayHeaderCreateTime = "feishu-create-time"
+)
+
+// RelayMessage is an event republished to a message queue by a Relay.
+type RelayMessage struct {
+	Topic string
+	// Key is the event ID, usable for partitioning and deduplication.
+	Key []byte
+	// Value is the JSON encoded Envelope, decrypted.
+	Value []byte
+	// Headers holds the event metadata, see RelayHeaderEventID and others.
+	Headers map[string]string
+}
+
+// Publisher publishes messages to a message queue.
+type Publisher interface {
+	Publish(ctx context.Context, msg *RelayMessage) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface, e.g. to
+// publish with a client of another message queue:
+//
+//	publisher := events.PublisherFunc(func(ctx context.Context, msg *events.RelayMessage) error {
+//		_, err := client.Publish(ctx, msg.Topic, msg.Value)
+//		return err
+//	})
+type PublisherFunc func(ctx context.Context, msg *RelayMessage) error
+
+// Publish calls f.
+func (f PublisherFunc) Publish(ctx context.Context, msg *RelayMessage) error {
+	return f(ctx, msg)
+}
+
+// ChannelPublisher publishes messages to a Go channel, for processing in the
+// same process. Publishing blocks until the message is received or the
+// context is done.
+type ChannelPublisher chan *RelayMessage
+
+// Publish implements Publisher.
+func (c ChannelPublisher) Publish(ctx context.Context, msg *RelayMessage) error {
+	select {
+	case c <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NATSConn is the subset of *nats.Conn used by NATSPublisher.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATSPublisher publishes messages to NATS, using the topic as subject.
+type NATSPublisher struct {
+	Conn NATSConn
+}
+
+// Publish implements Publisher.
+func (p *NATSPublisher) Publish(ctx context.Context, msg *RelayMessage) error {
+	return p.Conn.Publish(msg.Topic, msg.Value)
+}
+
+// KafkaHeader is a header of a Kafka message.
+type KafkaHeader struct {
+	Key   string
+	Value []byte
+}
+
+// KafkaWriter writes messages to Kafka for KafkaPublisher. Kafka clients
+// take messages of their own types, so the writer of a client is wrapped
+// in a few lines, e.g. with kafka-go:
+//
+//	type kafkaWriter struct{ *kafka.Writer }
+//
+//	func (w kafkaWriter) WriteMessage(ctx context.Context, topic string, key, value []byte, headers []events.KafkaHeader) error {
+//		msg := kafka.Message{Topic: topic, Key: key, Value: value}
+//		for _, h := range headers {
+//			msg.Headers = append(msg.Headers, kafka.Header{Key: h.Key, Value: h.Value})
+//		}
+//		return w.WriteMessages(ctx, msg)
+//	}
+type KafkaWriter interface {
+	WriteMessage(ctx context.Context, topic string, key, value []byte, headers []KafkaHeader) error
+}
+
+// KafkaPublisher publishes messages to Kafka, keyed by the event ID so that
+// retries of an event go to the same partition, with the event metadata as
+// headers, sorted by key.
+type KafkaPublisher struct {
+	Writer KafkaWriter
+}
+
+// Publish implements Publisher.
+func (p *KafkaPublisher) Publish(ctx context.Context, msg *RelayMessage) error {
+	keys := make([]string, 0, len(msg.Headers))
+	for key := range msg.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	headers := make([]KafkaHeader, 0, len(keys))
+	for _, key := range keys {
+		headers = append(headers, KafkaHeader{Key: key, Value: []byte(msg.Headers[key])})
+	}
+	return p.Writer.WriteMessage(ctx, msg.Topic, msg.Key, msg.Value, headers)
+}
+
+// Relay republishes received events to a message queue, decoupling event
+// ingestion from processing. Consumers decode the messages with
+// DecodeRelayMessage and can process them with Receiver.HandleEnvelope.
+//
+// Example:
+//
+//	relay := &events.Relay{Publisher: &events.NATSPublisher{Conn: nc}, Topic: "feishu.events"}
+//	receiver := events.NewReceiver(encryptKey, verificationToken)
+//	relay.Register(receiver)
+type Relay struct {
+	Publisher Publisher
+	// Topic is the topic messages are published to.
+	Topic string
+	// TopicFunc, if set, returns the topic of each event instead of Topic,
+	// e.g. TopicByEventType.
+	TopicFunc func(envelope *Envelope) string
+}
+
+// TopicByEventType returns a Relay.TopicFunc publishing events to
+// prefix + "." + event type, e.g. "feishu.im.message.receive_v1".
+func TopicByEventType(prefix string) func(envelope *Envelope) string {
+	return func(envelope *Envelope) string {
+		return prefix + "." + envelope.Header.EventType
+	}
+}
+
+// Register relays the events of the receiver that have no other handler.
+// Register it on a receiver without handlers to relay all events.
+func (r *Relay) Register(receiver *Receiver) {
+	receiver.OnRawEvent(r.Handle)
+}
+
+// Handle publishes an event. It is a RawEventHandler. Errors make the
+// receiver answer with an error, so that Feishu retries the event.
+func (r *Relay) Handle(ctx context.Context, envelope *Envelope) error {
+	value, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	topic := r.Topic
+	if r.TopicFunc != nil {
+		topic = r.TopicFunc(envelope)
+	}
+
+	header := envelope.Header
+	msg := &RelayMessage{
+		Topic: topic,
+		Key:   []byte(header.EventID),
+		Value: value,
+		Headers: map[string]string{
+			RelayHeaderEventID:    header.EventID,
+			RelayHeaderEventType:  header.EventType,
+			RelayHeaderTenantKey:  header.TenantKey,
+			RelayHeaderCreateTime: header.CreateTime,
+		},
+	}
+	if err := r.Publisher.Publish(ctx, msg); err != nil {
+		return fmt.Errorf("failed to publish event %s to %s: %w", header.EventID, topic, err)
+	}
+	return nil
+}
+
+// DecodeRelayMessage decodes the value of a message published by a Relay.
+func DecodeRelayMessage(value []byte) (*Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(value, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode relayed event: %w", err)
+	}
+	return &envelope, nil
+}
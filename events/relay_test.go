@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNATSConn struct {
+	subjects []string
+	err      error
+}
+
+func (c *fakeNATSConn) Publish(subject string, data []byte) error {
+	c.subjects = append(c.subjects, subject)
+	return c.err
+}
+
+func TestRelay(t *testing.T) {
+	published := make(ChannelPublisher, 1)
+	relay := &Relay{Publisher: published, Topic: "feishu.events"}
+
+	receiver, errs := newTestReceiver("")
+	relay.Register(receiver)
+
+	rec := post(receiver, testMessageEvent, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, *errs)
+
+	msg := <-published
+	require.Equal(t, "feishu.events", msg.Topic)
+	require.Equal(t, []byte("ev_1"), msg.Key)
+	require.Equal(t, map[string]string{
+		RelayHeaderEventID:    "ev_1",
+		RelayHeaderEventType:  EventTypeMessageReceive,
+		RelayHeaderTenantKey:  "",
+		RelayHeaderCreateTime: "",
+	}, msg.Headers)
+
+	// Consumers process relayed events with the usual handlers
+	envelope, err := DecodeRelayMessage(msg.Value)
+	require.NoError(t, err)
+
+	consumer := NewReceiver("", "")
+	var got []string
+	consumer.OnMessage(func(ctx context.Context, event *MessageEvent) error {
+		got = append(got, event.Header.EventID+": "+event.Message.Text())
+		return nil
+	})
+	require.NoError(t, consumer.HandleEnvelope(context.Background(), envelope))
+	require.Equal(t, []string{"ev_1: /ping"}, got)
+
+	_, err = DecodeRelayMessage([]byte(`[]`))
+	require.ErrorContains(t, err, "failed to decode relayed event")
+}
+
+func TestRelayNATS(t *testing.T) {
+	conn := &fakeNATSConn{}
+	relay := &Relay{Publisher: &NATSPublisher{Conn: conn}, TopicFunc: TopicByEventType("feishu")}
+
+	envelope := &Envelope{Header: Header{EventID: "ev_1", EventType: EventTypeCardAction}}
+	require.NoError(t, relay.Handle(context.Background(), envelope))
+	require.Equal(t, []string{"feishu.card.action.trigger"}, conn.subjects)
+
+	conn.err = errors.New("connection closed")
+	err := relay.Handle(context.Background(), envelope)
+	require.EqualError(t, err, "failed to publish event ev_1 to feishu.card.action.trigger: connection closed")
+}
+
+// kafkaRecord is a message written to fakeKafkaWriter.
+type kafkaRecord struct {
+	topic   string
+	key     string
+	headers []KafkaHeader
+}
+
+type fakeKafkaWriter struct {
+	records []kafkaRecord
+	err     error
+}
+
+func (w *fakeKafkaWriter) WriteMessage(ctx context.Context, topic string, key, value []byte, headers []KafkaHeader) error {
+	w.records = append(w.records, kafkaRecord{topic: topic, key: string(key), headers: headers})
+	return w.err
+}
+
+func TestRelayKafka(t *testing.T) {
+	writer := &fakeKafkaWriter{}
+	relay := &Relay{Publisher: &KafkaPublisher{Writer: writer}, Topic: "feishu-events"}
+
+	envelope := &Envelope{Header: Header{EventID: "ev_1", EventType: EventTypeCardAction, TenantKey: "t1", CreateTime: "1"}}
+	require.NoError(t, relay.Handle(context.Background(), envelope))
+	require.Equal(t, []kafkaRecord{{
+		topic: "feishu-events",
+		key:   "ev_1",
+		headers: []KafkaHeader{
+			{Key: RelayHeaderCreateTime, Value: []byte("1")},
+			{Key: RelayHeaderEventID, Value: []byte("ev_1")},
+			{Key: RelayHeaderEventType, Value: []byte(EventTypeCardAction)},
+			{Key: RelayHeaderTenantKey, Value: []byte("t1")},
+		},
+	}}, writer.records)
+
+	writer.err = errors.New("leader not available")
+	err := relay.Handle(context.Background(), envelope)
+	require.EqualError(t, err, "failed to publish event ev_1 to feishu-events: leader not available")
+}
+
+func TestChannelPublisherCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, make(ChannelPublisher).Publish(ctx, &RelayMessage{}), context.Canceled)
+}
+
+func TestPublisherFunc(t *testing.T) {
+	var got *RelayMessage
+	publisher := PublisherFunc(func(ctx context.Context, msg *RelayMessage) error {
+		got = msg
+		return nil
+	})
+	msg := &RelayMessage{Topic: "t"}
+	require.NoError(t, publisher.Publish(context.Background(), msg))
+	require.Same(t, msg, got)
+}
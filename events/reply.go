@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+var (
+	// ErrNoReplyTarget is returned by Reply when the context does not come
+	// from a Receiver handler.
+	ErrNoReplyTarget = errors.New("no reply target in context")
+
+	// ErrNoReplier is returned by Reply when the Receiver has no Replier.
+	ErrNoReplier = errors.New("no replier configured")
+)
+
+// ReplyTarget identifies the chat and message an event originates from.
+type ReplyTarget struct {
+	// ChatID is the ID of the chat, e.g. "oc_xxx".
+	ChatID string
+	// MessageID is the ID of the received message, or of the card message
+	// for card actions, e.g. "om_xxx".
+	MessageID string
+}
+
+// Replier sends replies to the chat an event originates from.
+type Replier interface {
+	Reply(ctx context.Context, target ReplyTarget, msg *feishubot.Message) error
+}
+
+// ReplierFunc adapts a function to the Replier interface, e.g. to reply
+// through the Open API.
+type ReplierFunc func(ctx context.Context, target ReplyTarget, msg *feishubot.Message) error
+
+// Reply calls f.
+func (f ReplierFunc) Reply(ctx context.Context, target ReplyTarget, msg *feishubot.Message) error {
+	return f(ctx, target, msg)
+}
+
+// WebhookReplier replies through custom bot webhooks, chosen by chat ID.
+// Since webhooks can only post to the chat they belong to, each chat the
+// bot replies in needs its own webhook client.
+type WebhookReplier struct {
+	// Clients maps chat IDs to the webhook client of the chat.
+	Clients map[string]*feishubot.Client
+	// Default is used for chats without a client in Clients. If nil, replies
+	// to such chats fail.
+	Default *feishubot.Client
+}
+
+// NewWebhookReplier creates a replier sending all replies with client, for
+// bots that only receive events from one chat.
+func NewWebhookReplier(client *feishubot.Client) *WebhookReplier {
+	return &WebhookReplier{Default: client}
+}
+
+// SetClient sets the webhook client used to reply in the chat chatID.
+func (r *WebhookReplier) SetClient(chatID string, client *feishubot.Client) {
+	if r.Clients == nil {
+		r.Clients = make(map[string]*feishubot.Client)
+	}
+	r.Clients[chatID] = client
+}
+
+// Reply sends msg with the webhook client of the target chat.
+func (r *WebhookReplier) Reply(ctx context.Context, target ReplyTarget, msg *feishubot.Message) error {
+	client, ok := r.Clients[target.ChatID]
+	if !ok {
+		client = r.Default
+	}
+	if client == nil {
+		return fmt.Errorf("no webhook configured for chat %s", target.ChatID)
+	}
+
+	if _, err := client.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to reply in chat %s: %w", target.ChatID, err)
+	}
+	return nil
+}
+
+// APIReplier replies through the Open API as the app that received the
+// event, which works in every chat the bot is a member of without
+// configuring webhooks.
+type APIReplier struct {
+	Client *feishubot.APIClient
+}
+
+// NewAPIReplier creates a replier sending replies with client.
+func NewAPIReplier(client *feishubot.APIClient) *APIReplier {
+	return &APIReplier{Client: client}
+}
+
+// Reply sends msg to the target chat.
+func (r *APIReplier) Reply(ctx context.Context, target ReplyTarget, msg *feishubot.Message) error {
+	if _, err := r.Client.Send(ctx, feishubot.ToChat(target.ChatID), msg); err != nil {
+		return fmt.Errorf("failed to reply in chat %s: %w", target.ChatID, err)
+	}
+	return nil
+}
+
+type replyContextKey struct{}
+
+// replyContext is stored in handler contexts by Receiver.
+type replyContext struct {
+	replier Replier
+	target  ReplyTarget
+}
+
+// withReply returns a copy of ctx carrying the replier and reply target.
+func withReply(ctx context.Context, replier Replier, target ReplyTarget) context.Context {
+	return context.WithValue(ctx, replyContextKey{}, &replyContext{replier: replier, target: target})
+}
+
+// ReplyTargetFromContext returns the chat and message the event handled
+// with ctx originates from.
+func ReplyTargetFromContext(ctx context.Context) (ReplyTarget, bool) {
+	rc, ok := ctx.Value(replyContextKey{}).(*replyContext)
+	if !ok {
+		return ReplyTarget{}, false
+	}
+	return rc.target, true
+}
+
+// Reply sends msg to the chat the event handled with ctx originates from,
+// using the Replier of the Receiver. ctx must be, or derive from, the
+// context passed to a Receiver handler.
+//
+// Example:
+//
+//	receiver.OnMessage(func(ctx context.Context, event *events.MessageEvent) error {
+//		if event.Message.Text() == "/ping" {
+//			return events.Reply(ctx, feishubot.NewTextMessage("pong"))
+//		}
+//		return nil
+//	})
+func Reply(ctx context.Context, msg *feishubot.Message) error {
+	rc, ok := ctx.Value(replyContextKey{}).(*replyContext)
+	if !ok {
+		return ErrNoReplyTarget
+	}
+	if rc.replier == nil {
+		return ErrNoReplier
+	}
+	return rc.replier.Reply(ctx, rc.target, msg)
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReply(t *testing.T) {
+	msg := feishubot.NewTextMessage("pong")
+
+	require.ErrorIs(t, Reply(context.Background(), msg), ErrNoReplyTarget)
+
+	ctx := withReply(context.Background(), nil, ReplyTarget{ChatID: "oc_xxx"})
+	require.ErrorIs(t, Reply(ctx, msg), ErrNoReplier)
+
+	var gotTarget ReplyTarget
+	var gotMsg *feishubot.Message
+	replier := ReplierFunc(func(ctx context.Context, target ReplyTarget, msg *feishubot.Message) error {
+		gotTarget, gotMsg = target, msg
+		return nil
+	})
+	target := ReplyTarget{ChatID: "oc_xxx", MessageID: "om_xxx"}
+	ctx = withReply(context.Background(), replier, target)
+
+	require.NoError(t, Reply(ctx, msg))
+	require.Equal(t, target, gotTarget)
+	require.Same(t, msg, gotMsg)
+
+	got, ok := ReplyTargetFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, target, got)
+
+	_, ok = ReplyTargetFromContext(context.Background())
+	require.False(t, ok)
+}
+
+func TestWebhookReplier(t *testing.T) {
+	var got []string
+	newClient := func(name string) *feishubot.Client {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			got = append(got, name+": "+string(body))
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success"}`))
+		}))
+		t.Cleanup(server.Close)
+		return feishubot.NewClient(server.URL, "")
+	}
+
+	replier := NewWebhookReplier(newClient("default"))
+	replier.SetClient("oc_ops", newClient("ops"))
+
+	ctx := context.Background()
+	msg := feishubot.NewTextMessage("pong")
+	require.NoError(t, replier.Reply(ctx, ReplyTarget{ChatID: "oc_ops"}, msg))
+	require.NoError(t, replier.Reply(ctx, ReplyTarget{ChatID: "oc_dev"}, msg))
+	require.Equal(t, []string{
+		`ops: {"msg_type":"text","content":{"text":"pong"}}`,
+		`default: {"msg_type":"text","content":{"text":"pong"}}`,
+	}, got)
+
+	replier.Default = nil
+	require.ErrorContains(t, replier.Reply(ctx, ReplyTarget{ChatID: "oc_dev"}, msg), "no webhook configured for chat oc_dev")
+}
+
+func TestAPIReplier(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/v3/tenant_access_token/internal" {
+			_, _ = w.Write([]byte(`{"code":0,"tenant_access_token":"t","expire":7200}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, r.URL.RawQuery+" "+string(body))
+		_, _ = w.Write([]byte(`{"code":0,"data":{"message_id":"om_reply"}}`))
+	}))
+	defer server.Close()
+
+	client := feishubot.NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+	replier := NewAPIReplier(client)
+
+	require.NoError(t, replier.Reply(context.Background(), ReplyTarget{ChatID: "oc_xxx"}, feishubot.NewTextMessage("pong")))
+	require.Equal(t, []string{
+		`receive_id_type=chat_id {"content":"{\"text\":\"pong\"}","msg_type":"text","receive_id":"oc_xxx"}`,
+	}, got)
+
+	client.AppID = ""
+	err := replier.Reply(context.Background(), ReplyTarget{ChatID: "oc_xxx"}, feishubot.NewTextMessage("pong"))
+	require.ErrorIs(t, err, feishubot.ErrNoAppCredentials)
+}
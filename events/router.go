@@ -0,0 +1,195 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Command is a received message matched by a Router.
+type Command struct {
+	// Name is the matched command name, such as "/deploy", or empty for
+	// regular expression routes and the fallback.
+	Name string
+	// Args are the whitespace separated arguments following the command
+	// name. Double or single quotes group arguments containing spaces. For
+	// regular expression routes, Args are the submatches.
+	Args []string
+	// Text is the message text without mentions.
+	Text string
+	// Event is the received message event.
+	Event *MessageEvent
+}
+
+// Arg returns the i-th argument, or an empty string if there are fewer
+// arguments.
+func (c *Command) Arg(i int) string {
+	if i < 0 || i >= len(c.Args) {
+		return ""
+	}
+	return c.Args[i]
+}
+
+// CommandHandler handles a command.
+type CommandHandler func(ctx context.Context, cmd *Command) error
+
+type route struct {
+	name        string
+	pattern     *regexp.Regexp
+	usage       string
+	description string
+	handler     CommandHandler
+}
+
+// Router dispatches received messages to handlers by command name or
+// regular expression, the usual chat-ops pattern. Routes are tried in
+// registration order; messages matching no route go to the fallback.
+//
+// Example:
+//
+//	router := events.NewRouter()
+//	router.Command("/deploy", "<service> [version]", "Deploy a service", deploy)
+//	router.Command("/help", "", "Show this help", router.HelpHandler)
+//	router.Fallback(router.HelpHandler)
+//	receiver.OnMessage(router.HandleMessage)
+type Router struct {
+	routes   []route
+	fallback CommandHandler
+}
+
+// NewRouter creates an empty router.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Command registers a handler for messages starting with name, such as
+// "/status". usage describes the arguments and description the command for
+// Help. Names are matched case-insensitively.
+func (r *Router) Command(name, usage, description string, handler CommandHandler) {
+	r.routes = append(r.routes, route{
+		name:        name,
+		usage:       usage,
+		description: description,
+		handler:     handler,
+	})
+}
+
+// Regexp registers a handler for messages whose text matches pattern. The
+// submatches are passed as the command arguments.
+func (r *Router) Regexp(pattern *regexp.Regexp, description string, handler CommandHandler) {
+	r.routes = append(r.routes, route{
+		pattern:     pattern,
+		description: description,
+		handler:     handler,
+	})
+}
+
+// Fallback sets the handler for messages matching no route. Without a
+// fallback, such messages are ignored.
+func (r *Router) Fallback(handler CommandHandler) {
+	r.fallback = handler
+}
+
+// HandleMessage dispatches a received message to the matching route. It is
+// a MessageHandler, to be registered with Receiver.OnMessage.
+func (r *Router) HandleMessage(ctx context.Context, event *MessageEvent) error {
+	text := event.Message.Text()
+	if text == "" {
+		return nil
+	}
+
+	name, rest, _ := strings.Cut(text, " ")
+	for _, route := range r.routes {
+		cmd := &Command{Text: text, Event: event}
+		switch {
+		case route.pattern != nil:
+			match := route.pattern.FindStringSubmatch(text)
+			if match == nil {
+				continue
+			}
+			cmd.Args = match[1:]
+		case strings.EqualFold(name, route.name):
+			cmd.Name = route.name
+			cmd.Args = splitArgs(rest)
+		default:
+			continue
+		}
+		return route.handler(ctx, cmd)
+	}
+
+	if r.fallback == nil {
+		return nil
+	}
+	return r.fallback(ctx, &Command{Args: splitArgs(text), Text: text, Event: event})
+}
+
+// Help returns a markdown list of the registered routes with their usage
+// and description.
+func (r *Router) Help() string {
+	lines := make([]string, 0, len(r.routes))
+	for _, route := range r.routes {
+		syntax := route.name
+		if route.pattern != nil {
+			syntax = route.pattern.String()
+		} else if route.usage != "" {
+			syntax += " " + route.usage
+		}
+
+		line := fmt.Sprintf("- `%s`", syntax)
+		if route.description != "" {
+			line += " " + route.description
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HelpHandler replies with Help as a markdown card. It can be registered as
+// a command, such as "/help", or as the fallback.
+func (r *Router) HelpHandler(ctx context.Context, cmd *Command) error {
+	card := feishubot.NewCardBuilder().
+		Header("Commands", feishubot.TemplateBlue).
+		Markdown(r.Help()).
+		Build()
+	return Reply(ctx, feishubot.NewInteractiveMessage(card))
+}
+
+// splitArgs splits s into whitespace separated arguments. Double or single
+// quotes group arguments containing spaces; an unterminated quote extends to
+// the end of s.
+func splitArgs(s string) []string {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+
+	for _, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
@@ -0,0 +1,154 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/require"
+)
+
+// textEvent returns a message event with the given text.
+func textEvent(text string) *MessageEvent {
+	content, _ := json.Marshal(map[string]string{"text": text})
+	return &MessageEvent{Message: ReceivedMessage{
+		MessageType: "text",
+		Content:     string(content),
+		ChatID:      "oc_xxx",
+	}}
+}
+
+func TestRouter(t *testing.T) {
+	var got []*Command
+	record := func(ctx context.Context, cmd *Command) error {
+		got = append(got, cmd)
+		return nil
+	}
+
+	router := NewRouter()
+	router.Command("/deploy", "<service> [version]", "Deploy a service", record)
+	router.Command("/status", "<service>", "Show service status", record)
+	router.Regexp(regexp.MustCompile(`^rollback (\w+) to (v[\d.]+)$`), "Roll back a service", record)
+
+	tests := []struct {
+		name string
+		text string
+		want *Command
+	}{
+		{
+			name: "command with args",
+			text: "/deploy api v1.2",
+			want: &Command{Name: "/deploy", Args: []string{"api", "v1.2"}, Text: "/deploy api v1.2"},
+		},
+		{
+			name: "case insensitive",
+			text: "/STATUS api",
+			want: &Command{Name: "/status", Args: []string{"api"}, Text: "/STATUS api"},
+		},
+		{
+			name: "quoted args",
+			text: `/deploy "api gateway" 'v2 beta'`,
+			want: &Command{Name: "/deploy", Args: []string{"api gateway", "v2 beta"}, Text: `/deploy "api gateway" 'v2 beta'`},
+		},
+		{
+			name: "regexp",
+			text: "rollback api to v1.1",
+			want: &Command{Args: []string{"api", "v1.1"}, Text: "rollback api to v1.1"},
+		},
+		{
+			name: "prefix is not a command",
+			text: "/deployment api",
+		},
+		{
+			name: "no match without fallback",
+			text: "hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			require.NoError(t, router.HandleMessage(context.Background(), textEvent(tt.text)))
+
+			var want []*Command
+			if tt.want != nil {
+				want = []*Command{tt.want}
+			}
+			if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(Command{}, "Event")); diff != "" {
+				t.Errorf("HandleMessage() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	router.Fallback(record)
+	got = nil
+	require.NoError(t, router.HandleMessage(context.Background(), textEvent("hello there")))
+	require.Len(t, got, 1)
+	require.Equal(t, []string{"hello", "there"}, got[0].Args)
+	require.Equal(t, "", got[0].Name)
+	require.Equal(t, "there", got[0].Arg(1))
+	require.Equal(t, "", got[0].Arg(2))
+
+	// Messages without text are ignored
+	got = nil
+	require.NoError(t, router.HandleMessage(context.Background(), &MessageEvent{Message: ReceivedMessage{MessageType: "image"}}))
+	require.Empty(t, got)
+}
+
+func TestRouterHandlerError(t *testing.T) {
+	router := NewRouter()
+	router.Command("/fail", "", "", func(ctx context.Context, cmd *Command) error {
+		return errors.New("boom")
+	})
+	require.EqualError(t, router.HandleMessage(context.Background(), textEvent("/fail")), "boom")
+}
+
+func TestRouterHelp(t *testing.T) {
+	router := NewRouter()
+	router.Command("/deploy", "<service> [version]", "Deploy a service", nil)
+	router.Command("/help", "", "Show this help", router.HelpHandler)
+	router.Regexp(regexp.MustCompile(`^rollback (\w+)$`), "", nil)
+
+	help := "- `/deploy <service> [version]` Deploy a service\n" +
+		"- `/help` Show this help\n" +
+		"- `^rollback (\\w+)$`"
+	require.Equal(t, help, router.Help())
+
+	var reply *feishubot.Message
+	replier := ReplierFunc(func(ctx context.Context, target ReplyTarget, msg *feishubot.Message) error {
+		reply = msg
+		return nil
+	})
+	ctx := withReply(context.Background(), replier, ReplyTarget{ChatID: "oc_xxx"})
+	require.NoError(t, router.HandleMessage(ctx, textEvent("/help")))
+
+	require.Equal(t, feishubot.MsgTypeInteractive, reply.MsgType)
+	data, err := json.Marshal(reply)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "Deploy a service")
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "", want: nil},
+		{in: "a b  c", want: []string{"a", "b", "c"}},
+		{in: `"a b" c`, want: []string{"a b", "c"}},
+		{in: `key="a b"`, want: []string{"key=a b"}},
+		{in: `""`, want: []string{""}},
+		{in: `'unterminated arg`, want: []string{"unterminated arg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			require.Equal(t, tt.want, splitArgs(tt.in))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Request is an event request decoded by ParseRequest.
+type Request struct {
+	// Challenge is set for URL verification requests, which must be
+	// answered with ChallengeResponse.
+	Challenge string
+	// Envelope is set for events.
+	Envelope *Envelope
+}
+
+// ParseRequest decrypts and verifies an event request given by its headers
+// and body, without any HTTP server, e.g. in serverless functions. The URL
+// verification challenge is only checked against the verification token,
+// if given, since Feishu does not sign it.
+//
+// Verification errors wrap feishubot.ErrInvalidEventSignature or
+// feishubot.ErrEventTimestampExpired; other errors are malformed requests.
+//
+// Example:
+//
+//	req, err := events.ParseRequest(header, body, encryptKey, verificationToken)
+//	if err != nil {
+//		return http.StatusUnauthorized, nil
+//	}
+//	if req.Envelope == nil {
+//		return http.StatusOK, events.ChallengeResponse(req.Challenge)
+//	}
+//	// process req.Envelope.Header.EventType and req.Envelope.Event
+func ParseRequest(header http.Header, body []byte, encryptKey, verificationToken string, opts ...feishubot.VerifyOption) (*Request, error) {
+	plaintext, err := DecryptBody(body, encryptKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+	}
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse event: %w", err)
+	}
+
+	if payload.Type == "url_verification" {
+		if verificationToken != "" {
+			if err := feishubot.VerifyEventToken(plaintext, verificationToken); err != nil {
+				return nil, err
+			}
+		}
+		return &Request{Challenge: payload.Challenge}, nil
+	}
+
+	if err := verifyBody(header, body, plaintext, encryptKey, verificationToken, opts); err != nil {
+		return nil, err
+	}
+
+	envelope, err := ParseEnvelope(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{Envelope: envelope}, nil
+}
+
+// ChallengeResponse returns the response body answering a URL verification
+// challenge.
+func ChallengeResponse(challenge string) []byte {
+	data, _ := json.Marshal(map[string]string{"challenge": challenge})
+	return data
+}
+
+// HeaderFromMap converts headers given as a map, as by AWS Lambda and other
+// serverless platforms, to an http.Header with canonical keys.
+func HeaderFromMap(headers map[string]string) http.Header {
+	header := make(http.Header, len(headers))
+	for key, value := range headers {
+		header.Set(key, value)
+	}
+	return header
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequest(t *testing.T) {
+	noSkew := feishubot.WithTimestampSkew(0)
+	challenge := `{"challenge":"c","token":"token","type":"url_verification"}`
+	encrypted := `{"encrypt":"` + encrypt(t, testMessageEvent, "key") + `"}`
+
+	req, err := ParseRequest(nil, []byte(challenge), "key", "token", noSkew)
+	require.NoError(t, err)
+	require.Equal(t, &Request{Challenge: "c"}, req)
+	require.JSONEq(t, `{"challenge":"c"}`, string(ChallengeResponse(req.Challenge)))
+
+	req, err = ParseRequest(signedHeader(encrypted, "key"), []byte(encrypted), "key", "token", noSkew)
+	require.NoError(t, err)
+	require.Empty(t, req.Challenge)
+	require.Equal(t, "ev_1", req.Envelope.Header.EventID)
+
+	tests := []struct {
+		name      string
+		header    http.Header
+		body      string
+		wantErrIs error
+		wantErr   string
+	}{
+		{
+			name:      "wrong challenge token",
+			body:      strings.Replace(challenge, `"token":"token"`, `"token":"x"`, 1),
+			wantErrIs: feishubot.ErrInvalidEventSignature,
+		},
+		{
+			name:      "wrong signature",
+			header:    signedHeader(encrypted, "other"),
+			body:      encrypted,
+			wantErrIs: feishubot.ErrInvalidEventSignature,
+		},
+		{name: "malformed", body: `{`, wantErr: "failed to parse event"},
+		{name: "malformed plaintext", body: `{"encrypt":"` + encrypt(t, `[]`, "key") + `"}`, wantErr: "failed to parse event"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRequest(tt.header, []byte(tt.body), "key", "token", noSkew)
+			if tt.wantErrIs != nil {
+				require.ErrorIs(t, err, tt.wantErrIs)
+			} else {
+				require.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHeaderFromMap(t *testing.T) {
+	header := HeaderFromMap(map[string]string{
+		"x-lark-signature":         "sig",
+		"X-LARK-REQUEST-TIMESTAMP": "1",
+	})
+	require.Equal(t, "sig", header.Get(feishubot.HeaderSignature))
+	require.Equal(t, "1", header.Get(feishubot.HeaderRequestTimestamp))
+}
+
+func TestReceiverHandle(t *testing.T) {
+	receiver, _ := newTestReceiver("key")
+	var got []string
+	receiver.OnMessage(func(ctx context.Context, event *MessageEvent) error {
+		got = append(got, event.Message.Text())
+		return nil
+	})
+
+	encrypted := `{"encrypt":"` + encrypt(t, testMessageEvent, "key") + `"}`
+	headers := map[string]string{}
+	for key, values := range signedHeader(encrypted, "key") {
+		headers[strings.ToLower(key)] = values[0]
+	}
+
+	status, body := receiver.Handle(context.Background(), HeaderFromMap(headers), []byte(encrypted))
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{}`, string(body))
+	require.Equal(t, []string{"/ping"}, got)
+
+	status, body = receiver.Handle(context.Background(), nil, []byte(`{"challenge":"c","token":"token","type":"url_verification"}`))
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"challenge":"c"}`, string(body))
+
+	status, body = receiver.Handle(context.Background(), nil, []byte(`{`))
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, string(body), "failed to parse event")
+}
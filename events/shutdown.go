@@ -0,0 +1,73 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrReceiverClosed is returned for events received after Shutdown.
+var ErrReceiverClosed = errors.New("receiver shut down")
+
+// inFlight tracks the requests being processed by a Receiver, for Shutdown.
+type inFlight struct {
+	mu       sync.Mutex
+	count    int
+	shutdown bool
+	drained  chan struct{}
+}
+
+// begin registers a request. It returns false after shutdown.
+func (f *inFlight) begin() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.shutdown {
+		return false
+	}
+	f.count++
+	return true
+}
+
+// end unregisters a request registered with begin.
+func (f *inFlight) end() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.count--
+	if f.shutdown && f.count == 0 {
+		close(f.drained)
+	}
+}
+
+// Shutdown stops accepting new events and waits for the handlers running
+// or queued to finish, until ctx is done. Events received after Shutdown is
+// called are answered with 503 Service Unavailable, so that Feishu delivers
+// them again, e.g. to another instance during a rolling deploy.
+//
+// Since handlers run before their request is answered, the responses of all
+// drained handlers have been written when Shutdown returns nil. Otherwise it
+// returns the context error and the remaining handlers keep running.
+//
+// Call Shutdown before shutting down the http.Server serving the receiver,
+// which stops waiting for requests once they are answered.
+func (r *Receiver) Shutdown(ctx context.Context) error {
+	f := &r.inFlight
+	f.mu.Lock()
+	if !f.shutdown {
+		f.shutdown = true
+		f.drained = make(chan struct{})
+		if f.count == 0 {
+			close(f.drained)
+		}
+	}
+	drained := f.drained
+	f.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiverShutdown(t *testing.T) {
+	receiver, _ := newTestReceiver("")
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	finished := make(chan int)
+	receiver.OnMessage(func(ctx context.Context, event *MessageEvent) error {
+		close(started)
+		<-unblock
+		return nil
+	})
+
+	go func() { finished <- post(receiver, testMessageEvent, nil).Code }()
+	<-started
+
+	// Shutdown waits for the running handler
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, receiver.Shutdown(ctx), context.DeadlineExceeded)
+
+	// New events are rejected
+	rec := post(receiver, testMessageEvent, nil)
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	shutdown := make(chan error)
+	go func() { shutdown <- receiver.Shutdown(context.Background()) }()
+
+	close(unblock)
+	require.Equal(t, http.StatusOK, <-finished)
+	require.NoError(t, <-shutdown)
+
+	// Shutting down again returns immediately
+	require.NoError(t, receiver.Shutdown(context.Background()))
+}
+
+func TestReceiverShutdownIdle(t *testing.T) {
+	receiver, _ := newTestReceiver("")
+	require.NoError(t, receiver.Shutdown(context.Background()))
+	require.Equal(t, http.StatusServiceUnavailable, post(receiver, testMessageEvent, nil).Code)
+}
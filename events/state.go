@@ -0,0 +1,145 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SessionKey identifies the conversation of a user in a chat.
+type SessionKey struct {
+	ChatID string
+	UserID string
+}
+
+// String returns the key as "chat_id:user_id", for use as a key in external
+// stores.
+func (k SessionKey) String() string {
+	return k.ChatID + ":" + k.UserID
+}
+
+// MessageSessionKey returns the session key of the sender of a message in
+// its chat.
+func MessageSessionKey(event *MessageEvent) SessionKey {
+	return SessionKey{ChatID: event.Message.ChatID, UserID: event.Sender.SenderID.OpenID}
+}
+
+// CardActionSessionKey returns the session key of the operator of a card
+// action in its chat.
+func CardActionSessionKey(action *CardAction) SessionKey {
+	return SessionKey{ChatID: action.Context.OpenChatID, UserID: action.Operator.OpenID}
+}
+
+// Session is the state of a multi-step interaction, such as asking for an
+// environment and then for confirmation.
+type Session struct {
+	// Step is the current step of the interaction, e.g. "confirm".
+	Step string `json:"step"`
+	// Data holds the values collected in previous steps.
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// StateStore stores conversation sessions. Implementations backed by Redis
+// or a database can encode sessions as JSON.
+type StateStore interface {
+	// Load returns the session of key, or nil if there is none or it
+	// expired.
+	Load(ctx context.Context, key SessionKey) (*Session, error)
+	// Save stores the session of key, expiring after ttl. A ttl of zero or
+	// less never expires.
+	Save(ctx context.Context, key SessionKey, session *Session, ttl time.Duration) error
+	// Delete removes the session of key, if any.
+	Delete(ctx context.Context, key SessionKey) error
+}
+
+// memorySweepInterval is the minimum interval between removals of expired
+// sessions from a MemoryStateStore.
+const memorySweepInterval = time.Minute
+
+type memorySession struct {
+	session   Session
+	expiresAt time.Time
+}
+
+// MemoryStateStore is a StateStore keeping sessions in memory, for bots
+// running as a single instance. Sessions are lost on restart.
+type MemoryStateStore struct {
+	mu        sync.Mutex
+	sessions  map[SessionKey]memorySession
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStateStore creates an empty in-memory state store.
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{
+		sessions: make(map[SessionKey]memorySession),
+		now:      time.Now,
+	}
+}
+
+// Load implements StateStore.
+func (s *MemoryStateStore) Load(ctx context.Context, key SessionKey) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.sessions[key]
+	if !ok {
+		return nil, nil
+	}
+	if s.expired(stored) {
+		delete(s.sessions, key)
+		return nil, nil
+	}
+	return copySession(&stored.session), nil
+}
+
+// Save implements StateStore.
+func (s *MemoryStateStore) Save(ctx context.Context, key SessionKey, session *Session, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= memorySweepInterval {
+		for k, stored := range s.sessions {
+			if s.expired(stored) {
+				delete(s.sessions, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	stored := memorySession{session: *copySession(session)}
+	if ttl > 0 {
+		stored.expiresAt = now.Add(ttl)
+	}
+	s.sessions[key] = stored
+	return nil
+}
+
+// Delete implements StateStore.
+func (s *MemoryStateStore) Delete(ctx context.Context, key SessionKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, key)
+	return nil
+}
+
+// expired reports whether a stored session expired.
+func (s *MemoryStateStore) expired(stored memorySession) bool {
+	return !stored.expiresAt.IsZero() && !s.now().Before(stored.expiresAt)
+}
+
+// copySession returns a deep copy of session, so that stored sessions are
+// not modified through the values passed to or returned by the store.
+func copySession(session *Session) *Session {
+	c := &Session{Step: session.Step}
+	if session.Data != nil {
+		c.Data = make(map[string]string, len(session.Data))
+		for k, v := range session.Data {
+			c.Data[k] = v
+		}
+	}
+	return c
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionKey(t *testing.T) {
+	event := &MessageEvent{
+		Sender:  Sender{SenderID: UserID{OpenID: "ou_xxx"}},
+		Message: ReceivedMessage{ChatID: "oc_xxx"},
+	}
+	require.Equal(t, SessionKey{ChatID: "oc_xxx", UserID: "ou_xxx"}, MessageSessionKey(event))
+
+	action := &CardAction{
+		Operator: Operator{OpenID: "ou_yyy"},
+		Context:  CardContext{OpenChatID: "oc_yyy"},
+	}
+	key := CardActionSessionKey(action)
+	require.Equal(t, SessionKey{ChatID: "oc_yyy", UserID: "ou_yyy"}, key)
+	require.Equal(t, "oc_yyy:ou_yyy", key.String())
+}
+
+func TestMemoryStateStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStateStore()
+	store.now = func() time.Time { return now }
+
+	key := SessionKey{ChatID: "oc_xxx", UserID: "ou_xxx"}
+	session, err := store.Load(ctx, key)
+	require.NoError(t, err)
+	require.Nil(t, session)
+
+	saved := &Session{Step: "confirm", Data: map[string]string{"env": "prod"}}
+	require.NoError(t, store.Save(ctx, key, saved, time.Minute))
+
+	// The store keeps its own copy
+	saved.Data["env"] = "dev"
+	session, err = store.Load(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, &Session{Step: "confirm", Data: map[string]string{"env": "prod"}}, session)
+	session.Step = "changed"
+
+	other := SessionKey{ChatID: "oc_xxx", UserID: "ou_yyy"}
+	require.NoError(t, store.Save(ctx, other, &Session{Step: "start"}, 0))
+
+	// Sessions expire after their ttl, sessions without ttl are kept
+	now = now.Add(time.Minute)
+	session, err = store.Load(ctx, key)
+	require.NoError(t, err)
+	require.Nil(t, session)
+	session, err = store.Load(ctx, other)
+	require.NoError(t, err)
+	require.Equal(t, &Session{Step: "start"}, session)
+
+	require.NoError(t, store.Delete(ctx, other))
+	session, err = store.Load(ctx, other)
+	require.NoError(t, err)
+	require.Nil(t, session)
+}
+
+func TestMemoryStateStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStateStore()
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Save(ctx, SessionKey{ChatID: "a"}, &Session{}, time.Second))
+	now = now.Add(2 * memorySweepInterval)
+	require.NoError(t, store.Save(ctx, SessionKey{ChatID: "b"}, &Session{}, 0))
+
+	require.Len(t, store.sessions, 1)
+	require.Contains(t, store.sessions, SessionKey{ChatID: "b"})
+}
@@ -0,0 +1,103 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// maxBodyBytes is the maximum size of event request bodies read.
+const maxBodyBytes = 1 << 20
+
+// VerifyRequest verifies an event or callback request sent by Feishu, by its
+// signature headers if present and an encrypt key is given, otherwise by the
+// verification token in the body, decrypting encrypted bodies first.
+//
+// The request body is read and replaced, so it can still be read by the
+// next handler. This makes VerifyRequest usable from the middleware of any
+// framework exposing the *http.Request; the ginfeishu and echofeishu modules
+// provide it as Gin and Echo middleware.
+func VerifyRequest(req *http.Request, encryptKey, verificationToken string, opts ...feishubot.VerifyOption) error {
+	body, err := readBody(req)
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	plaintext, err := DecryptBody(body, encryptKey)
+	if err != nil {
+		return err
+	}
+	return verifyBody(req.Header, body, plaintext, encryptKey, verificationToken, opts)
+}
+
+// VerifyMiddleware returns net/http middleware rejecting requests that fail
+// VerifyRequest with 401 Unauthorized, for user routes receiving callbacks
+// directly instead of through a Receiver.
+//
+// Example:
+//
+//	verify := events.VerifyMiddleware(encryptKey, verificationToken)
+//	http.Handle("/callbacks/approve", verify(approveHandler))
+func VerifyMiddleware(encryptKey, verificationToken string, opts ...feishubot.VerifyOption) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if err := VerifyRequest(req, encryptKey, verificationToken, opts...); err != nil {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// readBody reads the body of an event request, up to maxBodyBytes.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	return io.ReadAll(io.LimitReader(req.Body, maxBodyBytes))
+}
+
+// DecryptBody returns the plaintext of an event request body, decrypting
+// the body of encrypted events, {"encrypt":"..."}, with the encrypt key.
+// Other bodies are returned unchanged.
+func DecryptBody(body []byte, encryptKey string) ([]byte, error) {
+	var encrypted struct {
+		Encrypt string `json:"encrypt"`
+	}
+	if err := json.Unmarshal(body, &encrypted); err != nil {
+		return nil, fmt.Errorf("failed to parse event: %w", err)
+	}
+	if encrypted.Encrypt == "" {
+		return body, nil
+	}
+	if encryptKey == "" {
+		return nil, errors.New("received encrypted event but no encrypt key is configured")
+	}
+	return decrypt(encrypted.Encrypt, encryptKey)
+}
+
+// verifyBody verifies a request by its signature headers over the raw body
+// if present, otherwise by the verification token in the decrypted body.
+func verifyBody(header http.Header, body, plaintext []byte, encryptKey, verificationToken string, opts []feishubot.VerifyOption) error {
+	if signature := header.Get(feishubot.HeaderSignature); signature != "" && encryptKey != "" {
+		return feishubot.VerifyEventSignature(
+			header.Get(feishubot.HeaderRequestTimestamp),
+			header.Get(feishubot.HeaderRequestNonce),
+			body,
+			signature,
+			encryptKey,
+			opts...,
+		)
+	}
+	if verificationToken == "" {
+		return fmt.Errorf("%w: request is not signed and no verification token is configured", feishubot.ErrInvalidEventSignature)
+	}
+	return feishubot.VerifyEventToken(plaintext, verificationToken)
+}
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyRequest(t *testing.T) {
+	noSkew := feishubot.WithTimestampSkew(0)
+	encrypted := `{"encrypt":"` + encrypt(t, testMessageEvent, "key") + `"}`
+
+	tests := []struct {
+		name    string
+		body    string
+		header  http.Header
+		wantErr string
+	}{
+		{name: "token", body: testMessageEvent},
+		{name: "signed", body: testMessageEvent, header: signedHeader(testMessageEvent, "key")},
+		{name: "encrypted token", body: encrypted},
+		{name: "encrypted and signed", body: encrypted, header: signedHeader(encrypted, "key")},
+		{
+			name:    "wrong signature",
+			body:    testMessageEvent,
+			header:  signedHeader(testMessageEvent, "other"),
+			wantErr: "invalid event signature",
+		},
+		{
+			name:    "wrong token",
+			body:    strings.Replace(testMessageEvent, `"token": "token"`, `"token": "other"`, 1),
+			wantErr: "invalid event signature",
+		},
+		{name: "invalid json", body: `{`, wantErr: "failed to parse event"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(tt.body))
+			for key, values := range tt.header {
+				req.Header[key] = values
+			}
+
+			err := VerifyRequest(req, "key", "token", noSkew)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// The body can be read again
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.body, string(body))
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(testMessageEvent))
+	require.ErrorIs(t, VerifyRequest(req, "", ""), feishubot.ErrInvalidEventSignature)
+}
+
+func TestVerifyMiddleware(t *testing.T) {
+	verify := VerifyMiddleware("", "token")
+	handler := verify(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(testCardAction)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, testCardAction, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(`{"token":"other"}`)))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,182 @@
+package feishubot
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers of event and callback requests sent by Feishu.
+const (
+	HeaderRequestTimestamp = "X-Lark-Request-Timestamp"
+	HeaderRequestNonce     = "X-Lark-Request-Nonce"
+	HeaderSignature        = "X-Lark-Signature"
+)
+
+// DefaultEventTimestampSkew is the maximum difference between the request
+// timestamp and the local clock accepted by default.
+const DefaultEventTimestampSkew = 5 * time.Minute
+
+var (
+	// ErrInvalidEventSignature is returned when the signature or
+	// verification token of an inbound request does not match.
+	ErrInvalidEventSignature = errors.New("invalid event signature")
+
+	// ErrEventTimestampExpired is returned when the timestamp of an inbound
+	// request is missing, malformed or outside the accepted skew, which
+	// protects against replayed requests.
+	ErrEventTimestampExpired = errors.New("event timestamp expired")
+)
+
+// VerifyOption configures the verification of inbound requests.
+type VerifyOption func(config *verifyConfig)
+
+type verifyConfig struct {
+	skew time.Duration
+	now  func() time.Time
+}
+
+// WithTimestampSkew sets the maximum accepted difference between the
+// request timestamp and the local clock. Zero or less disables the check.
+func WithTimestampSkew(skew time.Duration) VerifyOption {
+	return func(config *verifyConfig) {
+		config.skew = skew
+	}
+}
+
+func newVerifyConfig(opts []VerifyOption) *verifyConfig {
+	config := &verifyConfig{
+		skew: DefaultEventTimestampSkew,
+		now:  time.Now,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// checkTimestamp checks a request timestamp in Unix seconds against the
+// accepted skew.
+func (c *verifyConfig) checkTimestamp(timestamp string) error {
+	if c.skew <= 0 {
+		return nil
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %q", ErrEventTimestampExpired, timestamp)
+	}
+	diff := c.now().Sub(time.Unix(seconds, 0))
+	if diff > c.skew || diff < -c.skew {
+		return fmt.Errorf("%w: timestamp is %s off", ErrEventTimestampExpired, diff.Round(time.Second))
+	}
+	return nil
+}
+
+// VerifyEventSignature verifies the signature of an event subscription
+// request, sent by Feishu when an encrypt key is configured: the hex encoded
+// SHA-256 of timestamp + nonce + encryptKey + body, taken from the
+// X-Lark-Request-Timestamp, X-Lark-Request-Nonce and X-Lark-Signature
+// headers. body must be the raw request body.
+//
+// The timestamp must be within DefaultEventTimestampSkew of the local clock
+// unless changed with WithTimestampSkew.
+//
+// Example:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	err := feishubot.VerifyEventSignature(
+//		r.Header.Get(feishubot.HeaderRequestTimestamp),
+//		r.Header.Get(feishubot.HeaderRequestNonce),
+//		body,
+//		r.Header.Get(feishubot.HeaderSignature),
+//		encryptKey,
+//	)
+func VerifyEventSignature(timestamp, nonce string, body []byte, signature, encryptKey string, opts ...VerifyOption) error {
+	if err := newVerifyConfig(opts).checkTimestamp(timestamp); err != nil {
+		return err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(timestamp + nonce + encryptKey))
+	h.Write(body)
+	return compareSignature(hex.EncodeToString(h.Sum(nil)), signature)
+}
+
+// VerifyCardCallbackSignature verifies the signature of a legacy card action
+// callback request: the hex encoded SHA-1 of timestamp + nonce +
+// verificationToken + body, from the same headers as VerifyEventSignature.
+func VerifyCardCallbackSignature(timestamp, nonce string, body []byte, signature, verificationToken string, opts ...VerifyOption) error {
+	if err := newVerifyConfig(opts).checkTimestamp(timestamp); err != nil {
+		return err
+	}
+
+	h := sha1.New()
+	h.Write([]byte(timestamp + nonce + verificationToken))
+	h.Write(body)
+	return compareSignature(hex.EncodeToString(h.Sum(nil)), signature)
+}
+
+// VerifyEventToken verifies the verification token carried in the body of
+// an unencrypted event, in the "token" field of schema 1.0 events and
+// callbacks or the "header.token" field of schema 2.0 events. This is the
+// only check available when no encrypt key is configured.
+func VerifyEventToken(body []byte, verificationToken string) error {
+	var event struct {
+		Token  string `json:"token"`
+		Header struct {
+			Token string `json:"token"`
+		} `json:"header"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("failed to parse event: %w", err)
+	}
+
+	token := event.Header.Token
+	if token == "" {
+		token = event.Token
+	}
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(verificationToken)) != 1 {
+		return ErrInvalidEventSignature
+	}
+	return nil
+}
+
+// VerifyEventRequest verifies an inbound event request using the scheme it
+// was sent with: the SHA-256 signature headers if present and an encrypt key
+// is given, otherwise the verification token in the body. body must be the
+// raw request body, which has already been read from r.
+//
+// Encrypted events can only be verified by signature; their token is inside
+// the encrypted payload.
+func VerifyEventRequest(r *http.Request, body []byte, encryptKey, verificationToken string, opts ...VerifyOption) error {
+	if signature := r.Header.Get(HeaderSignature); signature != "" && encryptKey != "" {
+		return VerifyEventSignature(
+			r.Header.Get(HeaderRequestTimestamp),
+			r.Header.Get(HeaderRequestNonce),
+			body,
+			signature,
+			encryptKey,
+			opts...,
+		)
+	}
+	if verificationToken == "" {
+		return fmt.Errorf("%w: request is not signed and no verification token is configured", ErrInvalidEventSignature)
+	}
+	return VerifyEventToken(body, verificationToken)
+}
+
+// compareSignature compares signatures in constant time.
+func compareSignature(want, got string) error {
+	if subtle.ConstantTimeCompare([]byte(want), []byte(got)) != 1 {
+		return ErrInvalidEventSignature
+	}
+	return nil
+}
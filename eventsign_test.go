@@ -0,0 +1,149 @@
+package feishubot
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fixedNow makes verification use a fixed clock.
+func fixedNow(now time.Time) VerifyOption {
+	return func(config *verifyConfig) {
+		config.now = func() time.Time { return now }
+	}
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerifyEventSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"encrypt":"xxx"}`)
+	valid := sha256Hex("1700000000" + "nonce" + "key" + string(body))
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		opts      []VerifyOption
+		wantErr   error
+	}{
+		{
+			name:      "valid",
+			timestamp: "1700000000",
+			signature: valid,
+		},
+		{
+			name:      "wrong signature",
+			timestamp: "1700000000",
+			signature: sha256Hex("tampered"),
+			wantErr:   ErrInvalidEventSignature,
+		},
+		{
+			name:      "timestamp too old",
+			timestamp: "1699999000",
+			signature: sha256Hex("1699999000" + "nonce" + "key" + string(body)),
+			wantErr:   ErrEventTimestampExpired,
+		},
+		{
+			name:      "timestamp in the future",
+			timestamp: "1700001000",
+			signature: sha256Hex("1700001000" + "nonce" + "key" + string(body)),
+			wantErr:   ErrEventTimestampExpired,
+		},
+		{
+			name:      "larger skew",
+			timestamp: "1699999000",
+			signature: sha256Hex("1699999000" + "nonce" + "key" + string(body)),
+			opts:      []VerifyOption{WithTimestampSkew(time.Hour)},
+		},
+		{
+			name:      "skew check disabled",
+			timestamp: "1",
+			signature: sha256Hex("1" + "nonce" + "key" + string(body)),
+			opts:      []VerifyOption{WithTimestampSkew(0)},
+		},
+		{
+			name:      "malformed timestamp",
+			timestamp: "yesterday",
+			signature: valid,
+			wantErr:   ErrEventTimestampExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]VerifyOption{fixedNow(now)}, tt.opts...)
+			err := VerifyEventSignature(tt.timestamp, "nonce", body, tt.signature, "key", opts...)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyCardCallbackSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"action":{}}`)
+	signature := sha1Hex("1700000000" + "nonce" + "token" + string(body))
+
+	require.NoError(t, VerifyCardCallbackSignature("1700000000", "nonce", body, signature, "token", fixedNow(now)))
+	require.ErrorIs(t, VerifyCardCallbackSignature("1700000000", "nonce", body, signature, "other", fixedNow(now)), ErrInvalidEventSignature)
+}
+
+func TestVerifyEventToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "schema 2.0", body: `{"schema":"2.0","header":{"token":"tok"}}`},
+		{name: "schema 1.0", body: `{"token":"tok","type":"event_callback"}`},
+		{name: "wrong token", body: `{"token":"other"}`, wantErr: true},
+		{name: "no token", body: `{}`, wantErr: true},
+		{name: "invalid json", body: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyEventToken([]byte(tt.body), "tok")
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyEventRequest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := `{"header":{"token":"tok"}}`
+
+	signed := httptest.NewRequest("POST", "/events", strings.NewReader(body))
+	signed.Header.Set(HeaderRequestTimestamp, "1700000000")
+	signed.Header.Set(HeaderRequestNonce, "n")
+	signed.Header.Set(HeaderSignature, sha256Hex("1700000000"+"n"+"key"+body))
+	require.NoError(t, VerifyEventRequest(signed, []byte(body), "key", "", fixedNow(now)))
+	require.ErrorIs(t, VerifyEventRequest(signed, []byte(body), "wrong", "tok", fixedNow(now)), ErrInvalidEventSignature)
+
+	unsigned := httptest.NewRequest("POST", "/events", strings.NewReader(body))
+	require.NoError(t, VerifyEventRequest(unsigned, []byte(body), "key", "tok"))
+	require.ErrorIs(t, VerifyEventRequest(unsigned, []byte(body), "key", ""), ErrInvalidEventSignature)
+}
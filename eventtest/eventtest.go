@@ -0,0 +1,241 @@
+// Package eventtest simulates the event requests sent by Feishu, so that
+// event handlers can be unit-tested through a Receiver without a Feishu
+// tenant.
+//
+// Requests are built like Feishu's: encrypted and signed when an encrypt key
+// is configured, carrying the verification token otherwise.
+//
+// Example:
+//
+//	func TestPing(t *testing.T) {
+//		receiver := newReceiver(encryptKey, verificationToken)
+//		replies := &eventtest.Recorder{}
+//		receiver.SetReplier(replies)
+//
+//		sim := eventtest.NewSimulator(receiver, encryptKey, verificationToken)
+//		rec, err := sim.SendMessage(eventtest.NewTextMessage("oc_xxx", "ou_xxx", "/ping"))
+//		require.NoError(t, err)
+//		require.Equal(t, http.StatusOK, rec.Code)
+//		require.Equal(t, "pong", replies.Replies[0].Message.Content["text"])
+//	}
+package eventtest
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/events"
+)
+
+// Simulator sends fabricated event requests to a handler, usually an
+// events.Receiver.
+type Simulator struct {
+	Handler http.Handler
+	// EncryptKey encrypts and signs requests when set.
+	EncryptKey string
+	// VerificationToken is carried in the header of events.
+	VerificationToken string
+	// AppID and TenantKey are carried in the header of events.
+	AppID     string
+	TenantKey string
+
+	now     func() time.Time
+	eventID int64
+}
+
+// NewSimulator creates a simulator sending requests to handler for an app
+// with the given encrypt key and verification token, either of which may be
+// empty.
+func NewSimulator(handler http.Handler, encryptKey, verificationToken string) *Simulator {
+	return &Simulator{
+		Handler:           handler,
+		EncryptKey:        encryptKey,
+		VerificationToken: verificationToken,
+		AppID:             "cli_test",
+		TenantKey:         "tenant_test",
+		now:               time.Now,
+	}
+}
+
+// SendMessage sends an im.message.receive_v1 event.
+func (s *Simulator) SendMessage(event *events.MessageEvent) (*httptest.ResponseRecorder, error) {
+	return s.SendEvent(events.EventTypeMessageReceive, event)
+}
+
+// SendCardAction sends a card.action.trigger event.
+func (s *Simulator) SendCardAction(action *events.CardAction) (*httptest.ResponseRecorder, error) {
+	if action.Token == "" {
+		copied := *action
+		copied.Token = s.VerificationToken
+		action = &copied
+	}
+	return s.SendEvent(events.EventTypeCardAction, action)
+}
+
+// SendEvent sends a schema 2.0 event of the given type, with event as its
+// JSON encoded body.
+func (s *Simulator) SendEvent(eventType string, event interface{}) (*httptest.ResponseRecorder, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	id := atomic.AddInt64(&s.eventID, 1)
+	return s.Send(&events.Envelope{
+		Schema: "2.0",
+		Header: events.Header{
+			EventID:    "ev_test_" + strconv.FormatInt(id, 10),
+			EventType:  eventType,
+			CreateTime: strconv.FormatInt(s.now().UnixMilli(), 10),
+			Token:      s.VerificationToken,
+			AppID:      s.AppID,
+			TenantKey:  s.TenantKey,
+		},
+		Event: body,
+	})
+}
+
+// URLVerification sends the URL verification challenge sent by Feishu when
+// the request URL is configured.
+func (s *Simulator) URLVerification(challenge string) (*httptest.ResponseRecorder, error) {
+	return s.Send(map[string]string{
+		"challenge": challenge,
+		"token":     s.VerificationToken,
+		"type":      "url_verification",
+	})
+}
+
+// Send sends payload, JSON encoded, as an event request. It is encrypted and
+// signed when an encrypt key is configured.
+func (s *Simulator) Send(payload interface{}) (*httptest.ResponseRecorder, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	if s.EncryptKey != "" {
+		encrypted, err := Encrypt(body, s.EncryptKey)
+		if err != nil {
+			return nil, err
+		}
+		if body, err = json.Marshal(map[string]string{"encrypt": encrypted}); err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if s.EncryptKey != "" {
+		timestamp := strconv.FormatInt(s.now().Unix(), 10)
+		nonce := strconv.FormatInt(atomic.AddInt64(&s.eventID, 1), 10)
+		req.Header.Set(feishubot.HeaderRequestTimestamp, timestamp)
+		req.Header.Set(feishubot.HeaderRequestNonce, nonce)
+		req.Header.Set(feishubot.HeaderSignature, Sign(timestamp, nonce, s.EncryptKey, body))
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, req)
+	return rec, nil
+}
+
+// Encrypt encrypts an event body as Feishu does, with AES-256-CBC using the
+// SHA-256 of the encrypt key, returning the base64 encoded IV and
+// ciphertext.
+func Encrypt(plaintext []byte, encryptKey string) (string, error) {
+	key := sha256.Sum256([]byte(encryptKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	data := make([]byte, aes.BlockSize+len(padded))
+	if _, err := rand.Read(data[:aes.BlockSize]); err != nil {
+		return "", fmt.Errorf("failed to generate IV: %w", err)
+	}
+	cipher.NewCBCEncrypter(block, data[:aes.BlockSize]).CryptBlocks(data[aes.BlockSize:], padded)
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Sign returns the X-Lark-Signature of an event request.
+func Sign(timestamp, nonce, encryptKey string, body []byte) string {
+	sum := sha256.Sum256([]byte(timestamp + nonce + encryptKey + string(body)))
+	return hex.EncodeToString(sum[:])
+}
+
+var messageID int64
+
+// NewTextMessage returns a text message event sent by the user userID in the
+// group chat chatID, with a unique message ID.
+func NewTextMessage(chatID, userID, text string) *events.MessageEvent {
+	content, _ := json.Marshal(map[string]string{"text": text})
+	id := atomic.AddInt64(&messageID, 1)
+	return &events.MessageEvent{
+		Sender: events.Sender{
+			SenderID:   events.UserID{OpenID: userID},
+			SenderType: "user",
+		},
+		Message: events.ReceivedMessage{
+			MessageID:   "om_test_" + strconv.FormatInt(id, 10),
+			ChatID:      chatID,
+			ChatType:    "group",
+			MessageType: "text",
+			Content:     string(content),
+		},
+	}
+}
+
+// NewCardAction returns a button click by the user userID on a card in the
+// chat chatID, with value as the button's callback value.
+func NewCardAction(chatID, userID string, value map[string]interface{}) *events.CardAction {
+	id := atomic.AddInt64(&messageID, 1)
+	return &events.CardAction{
+		Operator: events.Operator{OpenID: userID},
+		Action: events.Action{
+			Tag:   "button",
+			Value: value,
+		},
+		Context: events.CardContext{
+			OpenMessageID: "om_test_" + strconv.FormatInt(id, 10),
+			OpenChatID:    chatID,
+		},
+	}
+}
+
+// Reply is a reply recorded by a Recorder.
+type Reply struct {
+	Target  events.ReplyTarget
+	Message *feishubot.Message
+}
+
+// Recorder is an events.Replier recording replies instead of sending them.
+type Recorder struct {
+	mu      sync.Mutex
+	Replies []Reply
+}
+
+// Reply implements events.Replier.
+func (r *Recorder) Reply(ctx context.Context, target events.ReplyTarget, msg *feishubot.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Replies = append(r.Replies, Reply{Target: target, Message: msg})
+	return nil
+}
@@ -0,0 +1,116 @@
+package eventtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/events"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReceiver(encryptKey string) (*events.Receiver, *Recorder) {
+	receiver := events.NewReceiver(encryptKey, "token")
+	replies := &Recorder{}
+	receiver.SetReplier(replies)
+
+	receiver.OnMessage(func(ctx context.Context, event *events.MessageEvent) error {
+		return events.Reply(ctx, feishubot.NewTextMessage("pong: "+event.Message.Text()))
+	})
+	receiver.OnCardAction(func(ctx context.Context, action *events.CardAction) error {
+		var value struct {
+			Command string `json:"command"`
+		}
+		if err := action.DecodeValue(&value); err != nil {
+			return err
+		}
+		return events.Reply(ctx, feishubot.NewTextMessage(value.Command+" by "+action.Operator.OpenID))
+	})
+	return receiver, replies
+}
+
+func TestSimulator(t *testing.T) {
+	for _, encryptKey := range []string{"", "key"} {
+		t.Run("encrypt key "+encryptKey, func(t *testing.T) {
+			receiver, replies := newTestReceiver(encryptKey)
+			sim := NewSimulator(receiver, encryptKey, "token")
+
+			rec, err := sim.URLVerification("challenge")
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.JSONEq(t, `{"challenge":"challenge"}`, rec.Body.String())
+
+			message := NewTextMessage("oc_xxx", "ou_xxx", "/ping")
+			rec, err = sim.SendMessage(message)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			action := NewCardAction("oc_yyy", "ou_yyy", map[string]interface{}{"command": "approve"})
+			rec, err = sim.SendCardAction(action)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			require.Equal(t, []Reply{
+				{
+					Target:  events.ReplyTarget{ChatID: "oc_xxx", MessageID: message.Message.MessageID},
+					Message: feishubot.NewTextMessage("pong: /ping"),
+				},
+				{
+					Target:  events.ReplyTarget{ChatID: "oc_yyy", MessageID: action.Context.OpenMessageID},
+					Message: feishubot.NewTextMessage("approve by ou_yyy"),
+				},
+			}, replies.Replies)
+		})
+	}
+}
+
+func TestSimulatorRejected(t *testing.T) {
+	receiver, replies := newTestReceiver("key")
+	sim := NewSimulator(receiver, "other", "other")
+
+	rec, err := sim.SendMessage(NewTextMessage("oc_xxx", "ou_xxx", "/ping"))
+	require.NoError(t, err)
+	require.NotEqual(t, http.StatusOK, rec.Code)
+	require.Empty(t, replies.Replies)
+}
+
+func TestSendEvent(t *testing.T) {
+	receiver := events.NewReceiver("", "token")
+	var got []events.Header
+	receiver.OnRawEvent(func(ctx context.Context, envelope *events.Envelope) error {
+		got = append(got, envelope.Header)
+		require.JSONEq(t, `{"chat_id":"oc_xxx"}`, string(envelope.Event))
+		return nil
+	})
+
+	sim := NewSimulator(receiver, "", "token")
+	rec, err := sim.SendEvent("im.chat.disbanded_v1", map[string]string{"chat_id": "oc_xxx"})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	require.Len(t, got, 1)
+	require.Equal(t, "im.chat.disbanded_v1", got[0].EventType)
+	require.Equal(t, "ev_test_1", got[0].EventID)
+	require.Equal(t, "cli_test", got[0].AppID)
+	require.Equal(t, "tenant_test", got[0].TenantKey)
+	require.NotEmpty(t, got[0].CreateTime)
+
+	_, err = sim.SendEvent("x", func() {})
+	require.ErrorContains(t, err, "failed to marshal event")
+}
+
+func TestEncryptAndSign(t *testing.T) {
+	a, err := Encrypt([]byte(`{"a":1}`), "key")
+	require.NoError(t, err)
+	b, err := Encrypt([]byte(`{"a":1}`), "key")
+	require.NoError(t, err)
+	require.NotEqual(t, a, b, "IV must be random")
+
+	// The receiver accepts signatures computed by Sign
+	body, err := json.Marshal(map[string]string{"encrypt": a})
+	require.NoError(t, err)
+	require.NoError(t, feishubot.VerifyEventSignature("1", "n", body, Sign("1", "n", "key", body), "key",
+		feishubot.WithTimestampSkew(0)))
+}
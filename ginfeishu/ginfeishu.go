@@ -0,0 +1,42 @@
+// Package ginfeishu adapts the event receiver of the events package and the
+// error reporting of the httpnotify package to Gin: Receiver mounts a
+// Receiver as a Gin handler, Verify verifies the callbacks of user routes,
+// and Notify reports panics and server errors.
+//
+// It is a separate module, so that the gin dependency is only added to
+// services using it.
+//
+// Example:
+//
+//	router := gin.Default()
+//	router.Use(ginfeishu.Notify(notifier))
+//	router.POST("/feishu/events", ginfeishu.Receiver(receiver))
+//	router.POST("/callbacks/approve", ginfeishu.Verify(encryptKey, verificationToken), approve)
+package ginfeishu
+
+import (
+	"net/http"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/events"
+	"github.com/gin-gonic/gin"
+)
+
+// Receiver returns a Gin handler serving event requests with receiver.
+func Receiver(receiver *events.Receiver) gin.HandlerFunc {
+	return gin.WrapH(receiver)
+}
+
+// Verify returns Gin middleware aborting requests that fail
+// events.VerifyRequest with 401 Unauthorized, for user routes receiving
+// callbacks directly instead of through a Receiver. The request body is
+// restored, so it can still be read by the next handlers.
+func Verify(encryptKey, verificationToken string, opts ...feishubot.VerifyOption) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := events.VerifyRequest(c.Request, encryptKey, verificationToken, opts...); err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
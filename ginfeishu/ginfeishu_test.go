@@ -0,0 +1,61 @@
+package ginfeishu
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cium-cc/feishurobot/events"
+	"github.com/cium-cc/feishurobot/eventtest"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+const testCallback = `{"token":"token","action":{"value":{"id":"1"}}}`
+
+func TestReceiver(t *testing.T) {
+	receiver := events.NewReceiver("key", "token")
+	received := make(chan string, 1)
+	receiver.OnMessage(func(ctx context.Context, event *events.MessageEvent) error {
+		received <- event.Message.ChatID
+		return nil
+	})
+
+	router := gin.New()
+	router.POST("/", Receiver(receiver))
+	sim := eventtest.NewSimulator(router, "key", "token")
+
+	rec, err := sim.URLVerification("challenge")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"challenge":"challenge"}`, rec.Body.String())
+
+	rec, err = sim.SendMessage(eventtest.NewTextMessage("oc_xxx", "ou_xxx", "hello"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "oc_xxx", <-received)
+}
+
+func TestVerify(t *testing.T) {
+	router := gin.New()
+	router.POST("/callback", Verify("", "token"), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(testCallback)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, testCallback, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(`{"token":"other"}`)))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,44 @@
+package ginfeishu
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/cium-cc/feishurobot/httpnotify"
+	"github.com/gin-gonic/gin"
+)
+
+// Notify returns Gin middleware reporting to notifier with the route
+// pattern of Gin, like httpnotify.Notifier.Middleware: panics are
+// recovered, reported and answered with 500 Internal Server Error if no
+// response was written yet, and the sampled share of 5xx responses is
+// reported. Panics with http.ErrAbortHandler are not reported, and passed
+// on to the recovery of Gin.
+//
+// Example:
+//
+//	router := gin.Default()
+//	router.Use(ginfeishu.Notify(notifier))
+func Notify(notifier *httpnotify.Notifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			notifier.ReportPanic(c.Request, c.FullPath(), recovered, debug.Stack())
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}()
+
+		c.Next()
+		notifier.ReportStatus(c.Request, c.FullPath(), c.Writer.Status())
+	}
+}
@@ -0,0 +1,71 @@
+package ginfeishu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/httpnotify"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the cards sent by a notifier as JSON.
+type recorder struct {
+	mu    sync.Mutex
+	cards []string
+}
+
+func (r *recorder) send(_ context.Context, msg *feishubot.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cards = append(r.cards, string(data))
+	return nil
+}
+
+func TestNotify(t *testing.T) {
+	rec := &recorder{}
+	notifier := httpnotify.New(rec.send)
+	notifier.Interval = -1
+	notifier.ServerErrorSampleRate = 1
+
+	router := gin.New()
+	router.Use(Notify(notifier))
+	router.GET("/orders/:id", func(*gin.Context) {
+		panic("boom")
+	})
+	router.GET("/unavailable", func(c *gin.Context) {
+		c.String(http.StatusServiceUnavailable, "unavailable")
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		notifier.Wait()
+		return w
+	}
+
+	w := serve("/orders/42")
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Len(t, rec.cards, 1)
+	require.Contains(t, rec.cards[0], "Panic on GET /orders/:id")
+	require.Contains(t, rec.cards[0], "boom")
+
+	serve("/unavailable")
+	require.Len(t, rec.cards, 2)
+	require.Contains(t, rec.cards[1], "503 on GET /unavailable")
+
+	serve("/ok")
+	require.Len(t, rec.cards, 2)
+}
@@ -0,0 +1,111 @@
+package gitops
+
+import (
+	"encoding/json"
+	"fmt"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// ArgoCDEvent is the body ArgoCDHandler expects from the Argo CD
+// notifications webhook service, produced by a template such as:
+//
+//	service.webhook.feishu: |
+//	  url: http://feishu-gitops:8080/argocd
+//	  headers:
+//	    - name: Content-Type
+//	      value: application/json
+//	template.app-sync-status: |
+//	  webhook:
+//	    feishu:
+//	      method: POST
+//	      body: |
+//	        {
+//	          "app": "{{.app.metadata.name}}",
+//	          "project": "{{.app.spec.project}}",
+//	          "revision": "{{.app.status.sync.revision}}",
+//	          "syncStatus": "{{.app.status.sync.status}}",
+//	          "healthStatus": "{{.app.status.health.status}}",
+//	          "operationPhase": "{{with .app.status.operationState}}{{.phase}}{{end}}",
+//	          "message": {{with .app.status.operationState}}{{toJson .message}}{{else}}""{{end}},
+//	          "url": "{{.context.argocdUrl}}/applications/{{.app.metadata.name}}"
+//	        }
+//
+// See: https://argo-cd.readthedocs.io/en/stable/operator-manual/notifications/services/webhook/
+type ArgoCDEvent struct {
+	App     string `json:"app"`
+	Project string `json:"project"`
+	// Revision is the synced commit.
+	Revision string `json:"revision"`
+	// SyncStatus is "Synced", "OutOfSync" or "Unknown".
+	SyncStatus string `json:"syncStatus"`
+	// HealthStatus is "Healthy", "Progressing", "Degraded", "Suspended",
+	// "Missing" or "Unknown".
+	HealthStatus string `json:"healthStatus"`
+	// OperationPhase is the phase of the last sync operation: "Running",
+	// "Succeeded", "Failed", "Error" or "Terminating".
+	OperationPhase string `json:"operationPhase"`
+	Message        string `json:"message"`
+	// URL is the application page in the Argo CD UI.
+	URL string `json:"url"`
+}
+
+// ToEvent maps the Argo CD event to an Event. Failed sync operations and
+// degraded health take precedence over other states.
+func (e *ArgoCDEvent) ToEvent() *Event {
+	event := &Event{
+		App:      e.App,
+		Status:   argoCDStatus(e),
+		Revision: e.Revision,
+		Message:  e.Message,
+		URL:      e.URL,
+		URLText:  "Open in Argo CD",
+	}
+	for _, kv := range []feishubot.KV{
+		{Label: "Sync", Value: e.SyncStatus},
+		{Label: "Health", Value: e.HealthStatus},
+		{Label: "Project", Value: e.Project},
+	} {
+		if kv.Value != "" {
+			event.Fields = append(event.Fields, kv)
+		}
+	}
+	return event
+}
+
+// argoCDStatus derives the status of an Argo CD event.
+func argoCDStatus(e *ArgoCDEvent) Status {
+	switch {
+	case e.OperationPhase == "Failed" || e.OperationPhase == "Error":
+		return StatusFailed
+	case e.HealthStatus == "Degraded" || e.HealthStatus == "Missing":
+		return StatusDegraded
+	case e.OperationPhase == "Running" || e.HealthStatus == "Progressing":
+		return StatusProgressing
+	case e.SyncStatus == "Synced" && e.HealthStatus == "Healthy":
+		return StatusSucceeded
+	case e.OperationPhase == "Succeeded":
+		return StatusSucceeded
+	}
+	return StatusUnknown
+}
+
+// NewArgoCDHandler creates a handler posting Argo CD notifications with
+// send. See ArgoCDEvent for the webhook template.
+//
+// Example:
+//
+//	client := feishubot.NewClient(webhookURL, secret)
+//	http.Handle("/argocd", gitops.NewArgoCDHandler(feishubot.WebhookSender(client)))
+func NewArgoCDHandler(send feishubot.SendFunc) *Handler {
+	return &Handler{
+		send: send,
+		decode: func(body []byte) (*Event, error) {
+			var event ArgoCDEvent
+			if err := json.Unmarshal(body, &event); err != nil {
+				return nil, fmt.Errorf("failed to decode Argo CD event: %w", err)
+			}
+			return event.ToEvent(), nil
+		},
+	}
+}
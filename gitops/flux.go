@@ -0,0 +1,99 @@
+package gitops
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// FluxEvent is the payload of the generic webhook provider of the Flux
+// notification-controller.
+//
+// See: https://fluxcd.io/flux/components/notification/providers/#generic-webhook
+type FluxEvent struct {
+	InvolvedObject FluxObject `json:"involvedObject"`
+	// Severity is "info" or "error".
+	Severity  string    `json:"severity"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+	// Reason is e.g. "ReconciliationSucceeded", "ReconciliationFailed",
+	// "Progressing" or "HealthCheckFailed".
+	Reason string `json:"reason"`
+	// Metadata holds e.g. the "revision" of the reconciled source.
+	Metadata            map[string]string `json:"metadata"`
+	ReportingController string            `json:"reportingController"`
+	ReportingInstance   string            `json:"reportingInstance"`
+}
+
+// FluxObject is the Flux resource an event is about.
+type FluxObject struct {
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	UID        string `json:"uid"`
+	APIVersion string `json:"apiVersion"`
+}
+
+// ToEvent maps the Flux event to an Event.
+func (e *FluxEvent) ToEvent() *Event {
+	event := &Event{
+		App:      e.InvolvedObject.Name,
+		Status:   fluxStatus(e),
+		Revision: e.Metadata["revision"],
+		Message:  e.Message,
+	}
+	for _, kv := range []feishubot.KV{
+		{Label: "Kind", Value: e.InvolvedObject.Kind},
+		{Label: "Namespace", Value: e.InvolvedObject.Namespace},
+		{Label: "Reason", Value: e.Reason},
+	} {
+		if kv.Value != "" {
+			event.Fields = append(event.Fields, kv)
+		}
+	}
+	return event
+}
+
+// fluxStatus derives the status of a Flux event from its severity and
+// reason.
+func fluxStatus(e *FluxEvent) Status {
+	switch {
+	case e.Severity == "error" || strings.HasSuffix(e.Reason, "Failed"):
+		return StatusFailed
+	case strings.HasSuffix(e.Reason, "Succeeded"):
+		return StatusSucceeded
+	case e.Reason == "Progressing" || e.Reason == "DependencyNotReady":
+		return StatusProgressing
+	}
+	return StatusUnknown
+}
+
+// NewFluxHandler creates a handler posting events of the Flux
+// notification-controller with send. Set Handler.URL to link events to a
+// UI such as Weave GitOps.
+//
+// Example Flux provider:
+//
+//	apiVersion: notification.toolkit.fluxcd.io/v1beta3
+//	kind: Provider
+//	metadata:
+//	  name: feishu
+//	  namespace: flux-system
+//	spec:
+//	  type: generic
+//	  address: http://feishu-gitops.monitoring:8080/flux
+func NewFluxHandler(send feishubot.SendFunc) *Handler {
+	return &Handler{
+		send: send,
+		decode: func(body []byte) (*Event, error) {
+			var event FluxEvent
+			if err := json.Unmarshal(body, &event); err != nil {
+				return nil, fmt.Errorf("failed to decode Flux event: %w", err)
+			}
+			return event.ToEvent(), nil
+		},
+	}
+}
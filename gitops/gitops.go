@@ -0,0 +1,105 @@
+// Package gitops posts GitOps sync and health events of Argo CD and Flux to
+// Feishu as cards showing the application, revision and status, with a link
+// to the UI.
+//
+// ArgoCDHandler receives the Argo CD notifications webhook service with the
+// body template documented on ArgoCDEvent; FluxHandler receives the generic
+// webhook provider of the Flux notification-controller.
+package gitops
+
+import (
+	"fmt"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Status is the outcome of a sync or reconciliation.
+type Status string
+
+const (
+	StatusSucceeded   Status = "succeeded"
+	StatusFailed      Status = "failed"
+	StatusProgressing Status = "progressing"
+	StatusDegraded    Status = "degraded"
+	StatusUnknown     Status = "unknown"
+)
+
+// colors returns the emoji and header template of the status.
+func (s Status) colors() (emoji, template string) {
+	switch s {
+	case StatusSucceeded:
+		return "✅", feishubot.TemplateGreen
+	case StatusFailed:
+		return "❌", feishubot.TemplateRed
+	case StatusDegraded:
+		return "⚠️", feishubot.TemplateOrange
+	case StatusProgressing:
+		return "⏳", feishubot.TemplateBlue
+	}
+	return "ℹ️", feishubot.TemplateGrey
+}
+
+// maxMessageBytes is the length event messages are truncated to.
+const maxMessageBytes = 2000
+
+// Event is a sync or health event of a GitOps application, independent of
+// the tool reporting it.
+type Event struct {
+	// App is the name of the application, Kustomization or HelmRelease.
+	App    string
+	Status Status
+	// Revision is the deployed revision, e.g. a commit hash or
+	// "main@sha1:abc123". Commit hashes are shortened in cards.
+	Revision string
+	// Fields are further details shown as fields, e.g. the sync and health
+	// status or the namespace.
+	Fields  []feishubot.KV
+	Message string
+	// URL links the application in a UI when set.
+	URL string
+	// URLText is the text of the link button. If empty, "Open" is used.
+	URLText string
+}
+
+// Card renders the event.
+func (e *Event) Card() *feishubot.Card {
+	emoji, template := e.Status.colors()
+	builder := feishubot.NewCardBuilder().
+		Header(fmt.Sprintf("%s %s %s", emoji, feishubot.Sanitize(e.App), e.Status), template)
+
+	var fields []feishubot.KV
+	if e.Revision != "" {
+		fields = append(fields, feishubot.KV{Label: "Revision", Value: shortRevision(e.Revision)})
+	}
+	fields = append(fields, e.Fields...)
+	if len(fields) > 0 {
+		builder.Fields(fields)
+	}
+
+	if e.Message != "" {
+		builder.Markdown(feishubot.TruncateText(feishubot.Sanitize(e.Message), maxMessageBytes))
+	}
+
+	if e.URL != "" {
+		text := e.URLText
+		if text == "" {
+			text = "Open"
+		}
+		builder.Actions(feishubot.NewButtonElement(text, "primary", e.URL))
+	}
+	return builder.Build()
+}
+
+// shortRevision shortens the commit hash of a revision to 8 characters,
+// keeping a "branch@sha1:" prefix as used by Flux.
+func shortRevision(revision string) string {
+	prefix, hash := "", revision
+	if i := strings.LastIndex(revision, ":"); i >= 0 {
+		prefix, hash = revision[:i+1], revision[i+1:]
+	}
+	if len(hash) == 40 || len(hash) == 64 {
+		hash = hash[:8]
+	}
+	return prefix + hash
+}
@@ -0,0 +1,102 @@
+package gitops
+
+import (
+	"encoding/json"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventCard(t *testing.T) {
+	event := &Event{
+		App:      "podinfo",
+		Status:   StatusFailed,
+		Revision: "main@sha1:49d1a2b4e0b9bd23b2ef4a56c8bbb2f20e33e9c4",
+		Fields:   []feishubot.KV{{Label: "Namespace", Value: "apps"}},
+		Message:  "health check failed after 5m0s",
+		URL:      "https://gitops.example.com/podinfo",
+	}
+
+	data, err := json.Marshal(event.Card())
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {"title": {"tag": "plain_text", "content": "❌ podinfo failed"}, "template": "red"},
+		"body": {"elements": [
+			{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Revision:** main@sha1:49d1a2b4"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Namespace:** apps"}}
+			]},
+			{"tag": "markdown", "content": "health check failed after 5m0s"},
+			{"tag": "column_set", "columns": [
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Open"}, "type": "primary", "url": "https://gitops.example.com/podinfo", "width": "fill"}
+				]}
+			]}
+		]}
+	}`, string(data))
+}
+
+func TestShortRevision(t *testing.T) {
+	require.Equal(t, "49d1a2b4", shortRevision("49d1a2b4e0b9bd23b2ef4a56c8bbb2f20e33e9c4"))
+	require.Equal(t, "main@sha1:49d1a2b4", shortRevision("main@sha1:49d1a2b4e0b9bd23b2ef4a56c8bbb2f20e33e9c4"))
+	require.Equal(t, "v1.2.0", shortRevision("v1.2.0"))
+}
+
+func TestArgoCDEventToEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		event ArgoCDEvent
+		want  Status
+	}{
+		{name: "synced and healthy", event: ArgoCDEvent{SyncStatus: "Synced", HealthStatus: "Healthy", OperationPhase: "Succeeded"}, want: StatusSucceeded},
+		{name: "sync failed", event: ArgoCDEvent{SyncStatus: "OutOfSync", HealthStatus: "Healthy", OperationPhase: "Failed"}, want: StatusFailed},
+		{name: "degraded", event: ArgoCDEvent{SyncStatus: "Synced", HealthStatus: "Degraded"}, want: StatusDegraded},
+		{name: "running", event: ArgoCDEvent{SyncStatus: "OutOfSync", OperationPhase: "Running"}, want: StatusProgressing},
+		{name: "out of sync", event: ArgoCDEvent{SyncStatus: "OutOfSync", HealthStatus: "Healthy"}, want: StatusUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.event.ToEvent().Status)
+		})
+	}
+
+	event := (&ArgoCDEvent{App: "guestbook", Project: "default", SyncStatus: "Synced", HealthStatus: "Healthy", URL: "https://argocd.example.com/applications/guestbook"}).ToEvent()
+	require.Equal(t, []feishubot.KV{
+		{Label: "Sync", Value: "Synced"},
+		{Label: "Health", Value: "Healthy"},
+		{Label: "Project", Value: "default"},
+	}, event.Fields)
+	require.Equal(t, "Open in Argo CD", event.URLText)
+}
+
+func TestFluxEventToEvent(t *testing.T) {
+	var event FluxEvent
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"involvedObject": {"kind": "Kustomization", "namespace": "flux-system", "name": "apps", "apiVersion": "kustomize.toolkit.fluxcd.io/v1"},
+		"severity": "info",
+		"timestamp": "2024-05-01T12:00:00Z",
+		"message": "Reconciliation finished in 2s",
+		"reason": "ReconciliationSucceeded",
+		"metadata": {"revision": "main@sha1:49d1a2b4e0b9bd23b2ef4a56c8bbb2f20e33e9c4"},
+		"reportingController": "kustomize-controller"
+	}`), &event))
+
+	require.Equal(t, &Event{
+		App:      "apps",
+		Status:   StatusSucceeded,
+		Revision: "main@sha1:49d1a2b4e0b9bd23b2ef4a56c8bbb2f20e33e9c4",
+		Fields: []feishubot.KV{
+			{Label: "Kind", Value: "Kustomization"},
+			{Label: "Namespace", Value: "flux-system"},
+			{Label: "Reason", Value: "ReconciliationSucceeded"},
+		},
+		Message: "Reconciliation finished in 2s",
+	}, event.ToEvent())
+
+	event.Severity = "error"
+	event.Reason = "HealthCheckFailed"
+	require.Equal(t, StatusFailed, event.ToEvent().Status)
+}
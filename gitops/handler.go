@@ -0,0 +1,85 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// maxBodyBytes is the maximum size of event request bodies read.
+const maxBodyBytes = 1 << 20
+
+// Handler is an http.Handler receiving events of a GitOps tool and posting
+// them as cards, created with NewArgoCDHandler or NewFluxHandler.
+//
+// Events that cannot be posted are answered with status 502, so that the
+// tool can retry them.
+type Handler struct {
+	send   feishubot.SendFunc
+	decode func(body []byte) (*Event, error)
+
+	// Filter, if set, is called with each event and posts only events it
+	// returns true for, e.g. to leave out successful reconciliations.
+	Filter func(*Event) bool
+
+	// URL, if set, returns the UI link of events that have none, such as
+	// events of Flux, which has no UI of its own.
+	URL func(*Event) string
+
+	// OnError is called with errors of handling events. If nil, such
+	// errors are only reported in the response.
+	OnError func(error)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBodyBytes))
+	if err != nil {
+		h.handleError(fmt.Errorf("failed to read request body: %w", err))
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	event, err := h.decode(body)
+	if err != nil {
+		h.handleError(err)
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.notify(req.Context(), event); err != nil {
+		h.handleError(err)
+		http.Error(w, "failed to post event", http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// notify posts the card of an event unless it is filtered out.
+func (h *Handler) notify(ctx context.Context, event *Event) error {
+	if h.Filter != nil && !h.Filter(event) {
+		return nil
+	}
+	if event.URL == "" && h.URL != nil {
+		event.URL = h.URL(event)
+	}
+	if err := h.send(ctx, feishubot.NewInteractiveMessage(event.Card())); err != nil {
+		return fmt.Errorf("failed to post event of %s: %w", event.App, err)
+	}
+	return nil
+}
+
+// handleError reports err to the error handler.
+func (h *Handler) handleError(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+	}
+}
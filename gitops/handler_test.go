@@ -0,0 +1,68 @@
+package gitops
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgoCDHandler(t *testing.T) {
+	var sent []*feishubot.Message
+	handler := NewArgoCDHandler(func(_ context.Context, msg *feishubot.Message) error {
+		sent = append(sent, msg)
+		return nil
+	})
+
+	body := `{"app": "guestbook", "revision": "abc", "syncStatus": "Synced", "healthStatus": "Healthy", "operationPhase": "Succeeded", "message": "", "url": "https://argocd.example.com/applications/guestbook"}`
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/argocd", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, sent, 1)
+	require.Equal(t, "✅ guestbook succeeded", sent[0].TypedCard.Header.Title.Content)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/argocd", strings.NewReader("not json")))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/argocd", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestFluxHandler(t *testing.T) {
+	var sent []*Event
+	handler := NewFluxHandler(func(context.Context, *feishubot.Message) error {
+		return nil
+	})
+	handler.Filter = func(event *Event) bool {
+		sent = append(sent, event)
+		return event.Status != StatusSucceeded
+	}
+	handler.URL = func(event *Event) string {
+		return "https://gitops.example.com/" + event.App
+	}
+
+	for _, reason := range []string{"ReconciliationSucceeded", "ReconciliationFailed"} {
+		body := `{"involvedObject": {"kind": "Kustomization", "name": "apps"}, "severity": "info", "reason": "` + reason + `"}`
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/flux", strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+	require.Len(t, sent, 2)
+	require.Empty(t, sent[0].URL)
+	require.Equal(t, "https://gitops.example.com/apps", sent[1].URL)
+
+	var errs []error
+	handler.send = func(context.Context, *feishubot.Message) error { return errors.New("webhook down") }
+	handler.OnError = func(err error) { errs = append(errs, err) }
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/flux", strings.NewReader(`{"involvedObject": {"name": "apps"}, "severity": "error"}`)))
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+	require.EqualError(t, errs[0], "failed to post event of apps: webhook down")
+}
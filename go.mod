@@ -1,8 +1,14 @@
 module github.com/cium-cc/feishurobot
 
-go 1.16
+go 1.18
 
 require (
 	github.com/google/go-cmp v0.6.0
 	github.com/stretchr/testify v1.8.2
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 )
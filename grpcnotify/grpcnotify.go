@@ -0,0 +1,356 @@
+// Package grpcnotify provides gRPC server interceptors tracking the error
+// rate of each method and sending alert cards to Feishu when it crosses a
+// threshold, with the code distribution and example error messages.
+//
+// It is a separate module, so that the grpc dependency is only added to
+// services using it.
+//
+// Example:
+//
+//	notifier := grpcnotify.New(feishubot.WebhookSender(feishubot.NewClient(webhookURL, secret)), &grpcnotify.Options{
+//		Service:   "payments",
+//		Threshold: 0.05,
+//	})
+//	server := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(notifier.UnaryServerInterceptor()),
+//		grpc.ChainStreamInterceptor(notifier.StreamServerInterceptor()),
+//	)
+package grpcnotify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Defaults of Options.
+const (
+	DefaultWindow      = time.Minute
+	DefaultMinRequests = 20
+	DefaultThreshold   = 0.05
+	DefaultInterval    = 10 * time.Minute
+	DefaultTimeout     = 10 * time.Second
+)
+
+// maxExemplars is the number of distinct error messages kept per window.
+const maxExemplars = 3
+
+// maxExemplarBytes is the length exemplar messages are truncated to.
+const maxExemplarBytes = 500
+
+// maxTrackedMethods is the number of methods tracked separately; calls of
+// further methods are tracked together as "other", so that arbitrary method
+// names, e.g. passed to an UnknownServiceHandler, do not grow the state
+// without bound.
+const maxTrackedMethods = 1000
+
+// DefaultCodes are the codes counted as errors by default: those of server
+// faults, rather than of invalid or unauthorized requests.
+var DefaultCodes = []codes.Code{
+	codes.Unknown,
+	codes.DeadlineExceeded,
+	codes.Unimplemented,
+	codes.Internal,
+	codes.Unavailable,
+	codes.DataLoss,
+}
+
+// Options configure a Notifier.
+type Options struct {
+	// Service is shown in the card header, e.g. the name of the service.
+	Service string
+
+	// Window is the duration over which error rates are computed; counts
+	// restart with each window. If zero, DefaultWindow is used.
+	Window time.Duration
+
+	// MinRequests is the number of requests of a method within a window
+	// below which no alert is sent, so that a single failure of a rarely
+	// called method does not alert. If zero, DefaultMinRequests is used.
+	MinRequests int
+
+	// Threshold is the error rate, between 0 and 1, from which an alert is
+	// sent. If zero, DefaultThreshold is used.
+	Threshold float64
+
+	// Codes are the codes counted as errors. If nil, DefaultCodes are used.
+	Codes []codes.Code
+
+	// Interval is the minimum interval between alerts of a method. If zero,
+	// DefaultInterval is used.
+	Interval time.Duration
+
+	// Timeout bounds sending an alert. If zero, DefaultTimeout is used.
+	Timeout time.Duration
+
+	// OnError is called with errors of sending alerts. If nil, such errors
+	// are dropped.
+	OnError func(error)
+}
+
+// Notifier tracks the error rates of gRPC methods and sends alerts. Alerts
+// are sent in the background.
+type Notifier struct {
+	send feishubot.SendFunc
+	opts Options
+	// errorCodes are the codes counted as errors.
+	errorCodes map[codes.Code]bool
+
+	mu      sync.Mutex
+	methods map[string]*methodStats
+	sending sync.WaitGroup
+	now     func() time.Time
+}
+
+// methodStats are the counts of a method in the current window.
+type methodStats struct {
+	windowStart time.Time
+	total       int
+	errors      int
+	codes       map[codes.Code]int
+	exemplars   []string
+	lastAlert   time.Time
+}
+
+// New creates a notifier sending alerts with send. opts may be nil.
+func New(send feishubot.SendFunc, opts *Options) *Notifier {
+	n := &Notifier{
+		send:    send,
+		methods: make(map[string]*methodStats),
+		now:     time.Now,
+	}
+	if opts != nil {
+		n.opts = *opts
+	}
+	if n.opts.Window <= 0 {
+		n.opts.Window = DefaultWindow
+	}
+	if n.opts.MinRequests <= 0 {
+		n.opts.MinRequests = DefaultMinRequests
+	}
+	if n.opts.Threshold <= 0 {
+		n.opts.Threshold = DefaultThreshold
+	}
+	if n.opts.Codes == nil {
+		n.opts.Codes = DefaultCodes
+	}
+	if n.opts.Interval <= 0 {
+		n.opts.Interval = DefaultInterval
+	}
+	if n.opts.Timeout <= 0 {
+		n.opts.Timeout = DefaultTimeout
+	}
+
+	n.errorCodes = make(map[codes.Code]bool, len(n.opts.Codes))
+	for _, code := range n.opts.Codes {
+		n.errorCodes[code] = true
+	}
+	return n
+}
+
+// UnaryServerInterceptor returns an interceptor observing the results of
+// unary calls.
+func (n *Notifier) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		n.Observe(info.FullMethod, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor observing the results of
+// streaming calls.
+func (n *Notifier) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		n.Observe(info.FullMethod, err)
+		return err
+	}
+}
+
+// Observe records the result of a call of method, and sends an alert if
+// the error rate of the method crossed the threshold. It is called by the
+// interceptors, and can be called for results observed elsewhere.
+func (n *Notifier) Observe(method string, err error) {
+	code := status.Code(err)
+	now := n.now()
+
+	n.mu.Lock()
+	stats := n.methods[method]
+	if stats == nil {
+		if len(n.methods) >= maxTrackedMethods {
+			n.forget(now)
+		}
+		if len(n.methods) >= maxTrackedMethods {
+			method = "other"
+			stats = n.methods[method]
+		}
+	}
+	if stats == nil {
+		stats = &methodStats{}
+		n.methods[method] = stats
+	}
+	if now.Sub(stats.windowStart) >= n.opts.Window {
+		stats.windowStart = now
+		stats.total, stats.errors = 0, 0
+		stats.codes = make(map[codes.Code]int)
+		stats.exemplars = nil
+	}
+
+	stats.total++
+	if n.errorCodes[code] {
+		stats.errors++
+		stats.codes[code]++
+		stats.addExemplar(code, err)
+	}
+
+	var alert *Alert
+	if stats.total >= n.opts.MinRequests &&
+		float64(stats.errors)/float64(stats.total) >= n.opts.Threshold &&
+		(stats.lastAlert.IsZero() || now.Sub(stats.lastAlert) >= n.opts.Interval) {
+		stats.lastAlert = now
+		alert = stats.alert(method, now, n.opts.Window)
+	}
+	n.mu.Unlock()
+
+	if alert != nil {
+		n.sendAlert(alert)
+	}
+}
+
+// forget removes the stats of methods whose window has ended and which are
+// not throttled by a recent alert. n.mu must be held.
+func (n *Notifier) forget(now time.Time) {
+	for method, stats := range n.methods {
+		if now.Sub(stats.windowStart) >= n.opts.Window &&
+			(stats.lastAlert.IsZero() || now.Sub(stats.lastAlert) >= n.opts.Interval) {
+			delete(n.methods, method)
+		}
+	}
+}
+
+// addExemplar keeps the message of err if it is not yet kept.
+func (s *methodStats) addExemplar(code codes.Code, err error) {
+	if len(s.exemplars) >= maxExemplars {
+		return
+	}
+	message := code.String() + ": " + feishubot.TruncateText(status.Convert(err).Message(), maxExemplarBytes)
+	for _, exemplar := range s.exemplars {
+		if exemplar == message {
+			return
+		}
+	}
+	s.exemplars = append(s.exemplars, message)
+}
+
+// alert returns the alert of the current window.
+func (s *methodStats) alert(method string, now time.Time, window time.Duration) *Alert {
+	alert := &Alert{
+		Method:    method,
+		Total:     s.total,
+		Errors:    s.errors,
+		Window:    window,
+		Exemplars: append([]string(nil), s.exemplars...),
+		Time:      now,
+	}
+	for code, count := range s.codes {
+		alert.Codes = append(alert.Codes, CodeCount{Code: code, Count: count})
+	}
+	sort.Slice(alert.Codes, func(i, j int) bool {
+		if alert.Codes[i].Count != alert.Codes[j].Count {
+			return alert.Codes[i].Count > alert.Codes[j].Count
+		}
+		return alert.Codes[i].Code < alert.Codes[j].Code
+	})
+	return alert
+}
+
+// sendAlert sends the card of an alert in the background.
+func (n *Notifier) sendAlert(alert *Alert) {
+	msg := feishubot.NewInteractiveMessage(alert.Card(n.opts.Service))
+	n.sending.Add(1)
+	go func() {
+		defer n.sending.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), n.opts.Timeout)
+		defer cancel()
+		if err := n.send(ctx, msg); err != nil && n.opts.OnError != nil {
+			n.opts.OnError(fmt.Errorf("failed to send alert of %s: %w", alert.Method, err))
+		}
+	}()
+}
+
+// Wait waits for alerts being sent, e.g. before the service exits.
+func (n *Notifier) Wait() {
+	n.sending.Wait()
+}
+
+// CodeCount is the number of errors with a code.
+type CodeCount struct {
+	Code  codes.Code
+	Count int
+}
+
+// Alert is the error rate of a method crossing the threshold.
+type Alert struct {
+	// Method is the full method name, "/package.Service/Method", or
+	// "other" for methods beyond the number tracked separately.
+	Method string
+	Total  int
+	Errors int
+	Window time.Duration
+	// Codes are the error codes by decreasing count.
+	Codes []CodeCount
+	// Exemplars are example error messages, prefixed with their code.
+	Exemplars []string
+	Time      time.Time
+}
+
+// Rate returns the error rate of the alert.
+func (a *Alert) Rate() float64 {
+	if a.Total == 0 {
+		return 0
+	}
+	return float64(a.Errors) / float64(a.Total)
+}
+
+// codeBlock wraps text in a fenced code block.
+func codeBlock(text string) string {
+	return "```\n" + strings.ReplaceAll(strings.TrimRight(text, "\n"), "```", "'''") + "\n```"
+}
+
+// Card renders the alert as a card titled with the service name, which may
+// be empty.
+func (a *Alert) Card(service string) *feishubot.Card {
+	builder := feishubot.NewCardBuilder().
+		Header("High error rate on "+a.Method, feishubot.TemplateRed)
+	if service != "" {
+		builder.Subtitle(service)
+	}
+
+	builder.Fields([]feishubot.KV{
+		{Label: "Error rate", Value: fmt.Sprintf("%.1f%% (%d/%d)", a.Rate()*100, a.Errors, a.Total)},
+		{Label: "Window", Value: a.Window.String()},
+	})
+
+	if len(a.Codes) > 0 {
+		lines := make([]string, 0, len(a.Codes))
+		for _, c := range a.Codes {
+			lines = append(lines, fmt.Sprintf("- %s: %d", c.Code, c.Count))
+		}
+		builder.Markdown("**Codes**\n" + strings.Join(lines, "\n"))
+	}
+	if len(a.Exemplars) > 0 {
+		builder.Markdown("**Example errors**\n" + codeBlock(strings.Join(a.Exemplars, "\n")))
+	}
+	builder.Markdown("<font color='grey'>" + a.Time.Format("2006-01-02 15:04:05 MST") + "</font>")
+	return builder.Build()
+}
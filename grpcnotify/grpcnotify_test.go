@@ -0,0 +1,158 @@
+package grpcnotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recorder records the messages sent by a notifier.
+type recorder struct {
+	mu       sync.Mutex
+	messages []*feishubot.Message
+	err      error
+}
+
+func (r *recorder) send(_ context.Context, msg *feishubot.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+	return r.err
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	rec := &recorder{}
+	notifier := New(rec.send, &Options{Service: "payments", MinRequests: 10, Threshold: 0.2})
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	interceptor := notifier.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/payments.v1.Payments/Charge"}
+	call := func(err error) {
+		resp, gotErr := interceptor(context.Background(), "req", info, func(context.Context, interface{}) (interface{}, error) {
+			return "resp", err
+		})
+		require.Equal(t, "resp", resp)
+		require.Equal(t, err, gotErr)
+	}
+
+	for i := 0; i < 7; i++ {
+		call(nil)
+	}
+	// Client errors are not counted
+	call(status.Error(codes.InvalidArgument, "amount must be positive"))
+	call(status.Error(codes.Unavailable, "ledger unavailable"))
+	notifier.Wait()
+	require.Empty(t, rec.messages)
+
+	// 2/10 reaches the threshold
+	call(status.Error(codes.Unavailable, "ledger unavailable"))
+	notifier.Wait()
+	require.Len(t, rec.messages, 1)
+
+	// Throttled within the interval
+	call(errors.New("nil pointer dereference"))
+	notifier.Wait()
+	require.Len(t, rec.messages, 1)
+
+	data, err := json.Marshal(rec.messages[0].TypedCard)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {
+			"title": {"tag": "plain_text", "content": "High error rate on /payments.v1.Payments/Charge"},
+			"subtitle": {"tag": "plain_text", "content": "payments"},
+			"template": "red"
+		},
+		"body": {"elements": [
+			{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Error rate:** 20.0% (2/10)"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Window:** 1m0s"}}
+			]},
+			{"tag": "markdown", "content": "**Codes**\n- Unavailable: 2"},
+			{"tag": "markdown", "content": "**Example errors**\n`+"```"+`\nUnavailable: ledger unavailable\n`+"```"+`"},
+			{"tag": "markdown", "content": "<font color='grey'>2024-05-01 10:00:00 UTC</font>"}
+		]}
+	}`, string(data))
+
+	// A new window after the interval alerts again
+	now = now.Add(DefaultInterval)
+	for i := 0; i < 10; i++ {
+		call(status.Error(codes.Internal, "disk full"))
+	}
+	notifier.Wait()
+	require.Len(t, rec.messages, 2)
+}
+
+func TestAlertCard(t *testing.T) {
+	alert := &Alert{
+		Method: "/svc/M",
+		Total:  40,
+		Errors: 10,
+		Window: time.Minute,
+		Codes: []CodeCount{
+			{Code: codes.Internal, Count: 6},
+			{Code: codes.DeadlineExceeded, Count: 4},
+		},
+		Exemplars: []string{"Internal: a", "DeadlineExceeded: b ```\n**bold**"},
+	}
+	require.InDelta(t, 0.25, alert.Rate(), 1e-9)
+
+	card := alert.Card("")
+	require.Nil(t, card.Header.Subtitle)
+	data, err := json.Marshal(card)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `- Internal: 6\n- DeadlineExceeded: 4`)
+	require.Equal(t, "**Example errors**\n```\nInternal: a\nDeadlineExceeded: b '''\n**bold**\n```",
+		card.Body.Elements[2].(*feishubot.MarkdownElement).Content)
+}
+
+func TestObserveMethodLimit(t *testing.T) {
+	rec := &recorder{}
+	notifier := New(rec.send, &Options{MinRequests: 1})
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	for i := 0; i < maxTrackedMethods+10; i++ {
+		notifier.Observe(fmt.Sprintf("/svc/M%d", i), nil)
+	}
+	require.Len(t, notifier.methods, maxTrackedMethods+1)
+	require.Equal(t, 10, notifier.methods["other"].total)
+
+	// Stats of ended windows are dropped for new methods
+	now = now.Add(DefaultWindow)
+	notifier.Observe("/svc/New", nil)
+	require.Len(t, notifier.methods, 1)
+}
+
+// testStream is a server stream of the stream interceptor tests.
+type testStream struct {
+	grpc.ServerStream
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	rec := &recorder{err: errors.New("rate limited")}
+	var errs []error
+	notifier := New(rec.send, &Options{MinRequests: 1, OnError: func(err error) { errs = append(errs, err) }})
+
+	interceptor := notifier.StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/svc/Watch"}
+	err := interceptor(nil, testStream{}, info, func(interface{}, grpc.ServerStream) error {
+		return status.Error(codes.DataLoss, "corrupt")
+	})
+	require.Equal(t, codes.DataLoss, status.Code(err))
+	notifier.Wait()
+	require.Len(t, rec.messages, 1)
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "failed to send alert of /svc/Watch: rate limited")
+}
@@ -0,0 +1,258 @@
+// Package httpnotify reports panics and server errors of HTTP services to
+// Feishu as cards, with the route, the request ID and the stack trace in a
+// collapsible panel, for instant visibility of production crashes.
+//
+// Notifier.Middleware recovers panics of net/http handlers and optionally
+// samples 5xx responses. The ginfeishu and echofeishu modules provide the
+// same middleware for Gin and Echo, so that this package does not depend on
+// them; in other frameworks, report from their hooks with
+// Notifier.ReportPanic and Notifier.ReportStatus, passing the route pattern
+// of the framework.
+//
+// Example:
+//
+//	notifier := httpnotify.New(feishubot.WebhookSender(feishubot.NewClient(webhookURL, secret)))
+//	notifier.Service = "api-gateway"
+//	notifier.ServerErrorSampleRate = 0.1
+//	http.ListenAndServe(":8080", notifier.Middleware(mux))
+package httpnotify
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Defaults of Notifier.
+const (
+	DefaultInterval        = time.Minute
+	DefaultTimeout         = 10 * time.Second
+	DefaultRequestIDHeader = "X-Request-Id"
+)
+
+// maxStackBytes is the length stack traces are truncated to.
+const maxStackBytes = 8000
+
+// maxThrottledRoutes is the number of routes throttled separately; events
+// of further routes are throttled together, so that requests of random
+// paths do not grow the throttling state without bound.
+const maxThrottledRoutes = 1000
+
+// Event is a panic or server error of a request.
+type Event struct {
+	Method string
+	// Route is the route pattern of the request if known, such as
+	// "/orders/:id", or its path.
+	Route string
+	// URL is the URL of the request, without the query unless
+	// Notifier.IncludeQuery is set.
+	URL string
+	// Status is the response status, 500 for panics.
+	Status int
+	// Panic is the recovered value, nil for server errors.
+	Panic interface{}
+	Stack []byte
+	// RequestID is the ID of the request, to find its logs.
+	RequestID string
+	Time      time.Time
+	// Suppressed is the number of similar events not reported since the
+	// last report of the route.
+	Suppressed int
+}
+
+// Card renders the event as a card titled with the service name, which may
+// be empty.
+func (e *Event) Card(service string) *feishubot.Card {
+	title := fmt.Sprintf("%d on %s %s", e.Status, e.Method, e.Route)
+	template := feishubot.TemplateOrange
+	if e.Panic != nil {
+		title = fmt.Sprintf("Panic on %s %s", e.Method, e.Route)
+		template = feishubot.TemplateRed
+	}
+	builder := feishubot.NewCardBuilder().Header(title, template)
+	if service != "" {
+		builder.Subtitle(service)
+	}
+
+	if e.Panic != nil {
+		builder.Markdown(codeBlock(fmt.Sprint(e.Panic)))
+	}
+	fields := []feishubot.KV{
+		{Label: "Status", Value: strconv.Itoa(e.Status)},
+		{Label: "Time", Value: e.Time.Format("2006-01-02 15:04:05 MST")},
+	}
+	if e.RequestID != "" {
+		fields = append(fields, feishubot.KV{Label: "Request ID", Value: e.RequestID})
+	}
+	if e.URL != "" {
+		fields = append(fields, feishubot.KV{Label: "URL", Value: feishubot.TruncateText(e.URL, 200)})
+	}
+	builder.Fields(fields)
+
+	if len(e.Stack) > 0 {
+		stack := feishubot.TruncateText(string(e.Stack), maxStackBytes)
+		builder.Element(feishubot.NewCollapsiblePanelElement("**Stack trace**", false,
+			feishubot.NewMarkdownElement(codeBlock(stack)),
+		))
+	}
+	if e.Suppressed > 0 {
+		builder.Markdown(fmt.Sprintf("<font color='grey'>%d similar events suppressed since the last report</font>", e.Suppressed))
+	}
+	return builder.Build()
+}
+
+// codeBlock wraps text in a fenced code block.
+func codeBlock(text string) string {
+	return "```\n" + strings.ReplaceAll(strings.TrimRight(text, "\n"), "```", "'''") + "\n```"
+}
+
+// Notifier reports events of HTTP requests. Reports are sent in the
+// background and throttled per route, so that a crash loop does not
+// exhaust the rate limit of the webhook.
+type Notifier struct {
+	send feishubot.SendFunc
+
+	// Service is shown in the card header, e.g. the name of the service.
+	Service string
+
+	// ServerErrorSampleRate is the fraction of 5xx responses reported by
+	// Middleware, between 0 and 1. If zero, only panics are reported.
+	ServerErrorSampleRate float64
+
+	// RequestIDHeader is the header holding the request ID. If empty,
+	// DefaultRequestIDHeader is used.
+	RequestIDHeader string
+
+	// Route returns the route of a request for Middleware. If nil, the
+	// pattern matched by http.ServeMux is used on Go 1.22 and later, or
+	// else the path of the request with segments looking like IDs, such as
+	// numbers and UUIDs, replaced by ":id".
+	Route func(req *http.Request) string
+
+	// IncludeQuery includes the query string in the URL of reports. It is
+	// off by default, as queries may hold tokens or personal data.
+	IncludeQuery bool
+
+	// Interval is the minimum interval between reports of the same kind
+	// and route; events in between are counted in the next report. If
+	// zero, DefaultInterval is used; if negative, reports are not
+	// throttled.
+	Interval time.Duration
+
+	// Timeout bounds sending a report. If zero, DefaultTimeout is used.
+	Timeout time.Duration
+
+	// OnError is called with errors of sending reports. If nil, such errors
+	// are dropped.
+	OnError func(error)
+
+	mu       sync.Mutex
+	reported map[string]*throttle
+	sending  sync.WaitGroup
+	now      func() time.Time
+	rand     func() float64
+}
+
+// throttle is the throttling state of a kind and route.
+type throttle struct {
+	last       time.Time
+	suppressed int
+}
+
+// New creates a notifier reporting events with send.
+func New(send feishubot.SendFunc) *Notifier {
+	return &Notifier{
+		send:     send,
+		reported: make(map[string]*throttle),
+		now:      time.Now,
+		rand:     rand.Float64,
+	}
+}
+
+// Report sends a card of e in the background, unless a report of the same
+// kind and route was sent within the interval.
+func (n *Notifier) Report(e *Event) {
+	if e.Time.IsZero() {
+		e.Time = n.now()
+	}
+	if !n.allow(e) {
+		return
+	}
+
+	timeout := n.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	msg := feishubot.NewInteractiveMessage(e.Card(n.Service))
+
+	n.sending.Add(1)
+	go func() {
+		defer n.sending.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := n.send(ctx, msg); err != nil && n.OnError != nil {
+			n.OnError(fmt.Errorf("failed to report %s %s: %w", e.Method, e.Route, err))
+		}
+	}()
+}
+
+// allow reports whether e is reported, counting it as suppressed if not,
+// and sets the number of events suppressed before it.
+func (n *Notifier) allow(e *Event) bool {
+	interval := n.Interval
+	if interval < 0 {
+		return true
+	}
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+
+	key := fmt.Sprintf("%t %s %s", e.Panic != nil, e.Method, e.Route)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	t := n.reported[key]
+	if t == nil {
+		if len(n.reported) >= maxThrottledRoutes {
+			n.forget(e.Time.Add(-interval))
+		}
+		if len(n.reported) >= maxThrottledRoutes {
+			key = "other"
+			t = n.reported[key]
+		}
+	}
+	if t == nil {
+		t = &throttle{}
+		n.reported[key] = t
+	} else if e.Time.Sub(t.last) < interval {
+		t.suppressed++
+		return false
+	}
+	e.Suppressed = t.suppressed
+	t.last = e.Time
+	t.suppressed = 0
+	return true
+}
+
+// forget removes the throttling state of routes last reported before
+// or at since. n.mu must be held.
+func (n *Notifier) forget(since time.Time) {
+	for key, t := range n.reported {
+		if !t.last.After(since) {
+			delete(n.reported, key)
+		}
+	}
+}
+
+// Wait waits for reports being sent, e.g. before the service exits.
+func (n *Notifier) Wait() {
+	n.sending.Wait()
+}
@@ -0,0 +1,147 @@
+package httpnotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the messages sent by a notifier.
+type recorder struct {
+	mu       sync.Mutex
+	messages []*feishubot.Message
+	err      error
+}
+
+func (r *recorder) send(_ context.Context, msg *feishubot.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+	return r.err
+}
+
+func TestEventCard(t *testing.T) {
+	e := &Event{
+		Method:     "GET",
+		Route:      "/orders/:id",
+		URL:        "/orders/42?expand=items",
+		Status:     500,
+		Panic:      "runtime error: index out of range [3] with length 3",
+		Stack:      []byte("goroutine 1 [running]:\nmain.handler()\n"),
+		RequestID:  "req-1",
+		Time:       time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Suppressed: 2,
+	}
+	data, err := json.Marshal(e.Card("api-gateway"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {
+			"title": {"tag": "plain_text", "content": "Panic on GET /orders/:id"},
+			"subtitle": {"tag": "plain_text", "content": "api-gateway"},
+			"template": "red"
+		},
+		"body": {"elements": [
+			{"tag": "markdown", "content": "`+"```"+`\nruntime error: index out of range [3] with length 3\n`+"```"+`"},
+			{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Status:** 500"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Time:** 2024-05-01 10:00:00 UTC"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Request ID:** req-1"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**URL:** /orders/42?expand=items"}}
+			]},
+			{"tag": "collapsible_panel", "expanded": false,
+				"header": {"title": {"tag": "markdown", "content": "**Stack trace**"}},
+				"elements": [{"tag": "markdown", "content": "`+"```"+`\ngoroutine 1 [running]:\nmain.handler()\n`+"```"+`"}]
+			},
+			{"tag": "markdown", "content": "<font color='grey'>2 similar events suppressed since the last report</font>"}
+		]}
+	}`, string(data))
+
+	card := (&Event{Method: "POST", Route: "/pay", Status: 503, Time: e.Time}).Card("")
+	require.Equal(t, "503 on POST /pay", card.Header.Title.Content)
+	require.Equal(t, feishubot.TemplateOrange, card.Header.Template)
+	require.Nil(t, card.Header.Subtitle)
+}
+
+func TestNotifierThrottle(t *testing.T) {
+	rec := &recorder{}
+	notifier := New(rec.send)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	report := func(route string, panicked bool) {
+		e := &Event{Method: "GET", Route: route, Status: 500}
+		if panicked {
+			e.Panic = "boom"
+		}
+		notifier.Report(e)
+		notifier.Wait()
+	}
+
+	report("/a", true)
+	report("/a", true)
+	report("/a", true)
+	report("/a", false) // other kind
+	report("/b", true)  // other route
+	require.Len(t, rec.messages, 3)
+
+	now = now.Add(time.Minute)
+	report("/a", true)
+	require.Len(t, rec.messages, 4)
+	require.Contains(t, cardText(t, rec.messages[3]), "2 similar events suppressed")
+
+	notifier.Interval = -1
+	report("/a", true)
+	require.Len(t, rec.messages, 5)
+}
+
+func TestNotifierThrottleRoutes(t *testing.T) {
+	rec := &recorder{}
+	notifier := New(rec.send)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	report := func(route string) {
+		notifier.Report(&Event{Method: "GET", Route: route, Status: 500, Panic: "boom"})
+	}
+	for i := 0; i < maxThrottledRoutes+10; i++ {
+		report(fmt.Sprintf("/random/%d", i))
+	}
+	notifier.Wait()
+	// Routes beyond the maximum are throttled together
+	require.Len(t, rec.messages, maxThrottledRoutes+1)
+	require.Len(t, notifier.reported, maxThrottledRoutes+1)
+
+	// Routes not reported within the interval are forgotten
+	now = now.Add(time.Minute)
+	report("/new")
+	notifier.Wait()
+	require.Len(t, rec.messages, maxThrottledRoutes+2)
+	require.Len(t, notifier.reported, 1)
+}
+
+func TestNotifierSendError(t *testing.T) {
+	rec := &recorder{err: errors.New("rate limited")}
+	notifier := New(rec.send)
+	var errs []error
+	notifier.OnError = func(err error) { errs = append(errs, err) }
+
+	notifier.Report(&Event{Method: "GET", Route: "/a", Status: 502})
+	notifier.Wait()
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "failed to report GET /a: rate limited")
+}
+
+func cardText(t *testing.T, msg *feishubot.Message) string {
+	t.Helper()
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	return string(data)
+}
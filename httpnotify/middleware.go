@@ -0,0 +1,146 @@
+package httpnotify
+
+import (
+	"net/http"
+	"runtime/debug"
+)
+
+// Middleware returns a handler recovering panics of next, which are
+// reported and answered with 500 Internal Server Error if no response was
+// written yet, and reporting the sampled share of 5xx responses of next.
+// Panics with http.ErrAbortHandler are not reported, as they abort
+// requests on purpose.
+func (n *Notifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			n.ReportPanic(req, n.route(req), recovered, debug.Stack())
+			if rw.status == 0 {
+				http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}()
+
+		next.ServeHTTP(rw, req)
+		if rw.status >= 500 {
+			n.ReportStatus(req, n.route(req), rw.status)
+		}
+	})
+}
+
+// ReportPanic reports a panic recovered while handling req, for the
+// recovery hooks of frameworks. route is the route pattern of the request,
+// or empty to derive it from its path.
+//
+// Example, with Chi:
+//
+//	defer func() {
+//		if recovered := recover(); recovered != nil {
+//			notifier.ReportPanic(req, chi.RouteContext(req.Context()).RoutePattern(), recovered, debug.Stack())
+//			panic(recovered)
+//		}
+//	}()
+func (n *Notifier) ReportPanic(req *http.Request, route string, recovered interface{}, stack []byte) {
+	e := n.event(req, route, http.StatusInternalServerError)
+	e.Panic = recovered
+	e.Stack = stack
+	n.Report(e)
+}
+
+// ReportStatus reports a response of req with a 5xx status, subject to
+// ServerErrorSampleRate, for framework middleware. route is the route
+// pattern of the request, or empty to derive it from its path. Other
+// statuses are ignored.
+//
+// Example, with Chi:
+//
+//	ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
+//	next.ServeHTTP(ww, req)
+//	notifier.ReportStatus(req, chi.RouteContext(req.Context()).RoutePattern(), ww.Status())
+func (n *Notifier) ReportStatus(req *http.Request, route string, status int) {
+	if status < 500 || n.ServerErrorSampleRate <= 0 || n.rand() >= n.ServerErrorSampleRate {
+		return
+	}
+	n.Report(n.event(req, route, status))
+}
+
+// event returns the event of a request.
+func (n *Notifier) event(req *http.Request, route string, status int) *Event {
+	if route == "" {
+		route = defaultRoute(req)
+	}
+	header := n.RequestIDHeader
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	return &Event{
+		Method:    req.Method,
+		Route:     route,
+		URL:       n.requestURL(req),
+		Status:    status,
+		RequestID: req.Header.Get(header),
+	}
+}
+
+// route returns the route of a request handled by Middleware, or empty to
+// derive it from the request.
+func (n *Notifier) route(req *http.Request) string {
+	if n.Route != nil {
+		return n.Route(req)
+	}
+	return ""
+}
+
+// requestURL returns the URL of a request as reported, without its query
+// unless IncludeQuery is set.
+func (n *Notifier) requestURL(req *http.Request) string {
+	u := *req.URL
+	if !n.IncludeQuery {
+		u.RawQuery = ""
+		u.ForceQuery = false
+	}
+	u.Fragment = ""
+	u.User = nil
+	return u.String()
+}
+
+// responseWriter records the status of a response.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush implements http.Flusher if the underlying writer does.
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
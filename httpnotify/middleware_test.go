@@ -0,0 +1,91 @@
+package httpnotify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	rec := &recorder{}
+	notifier := New(rec.send)
+	notifier.Interval = -1
+	mux := http.NewServeMux()
+	mux.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+	mux.HandleFunc("/partial", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late boom")
+	})
+	mux.HandleFunc("/unavailable", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/abort", func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	handler := notifier.Middleware(mux)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Request-Id", "req-1")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		notifier.Wait()
+		return w
+	}
+
+	w := serve("/panic")
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Len(t, rec.messages, 1)
+	text := cardText(t, rec.messages[0])
+	require.Contains(t, text, "Panic on GET /panic")
+	require.Contains(t, text, "boom")
+	require.Contains(t, text, "req-1")
+	require.Contains(t, text, "middleware_test.go")
+
+	w = serve("/partial")
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, rec.messages, 2)
+
+	// Server errors are not reported unless sampled
+	serve("/unavailable")
+	serve("/ok")
+	require.Len(t, rec.messages, 2)
+
+	notifier.ServerErrorSampleRate = 0.5
+	notifier.rand = func() float64 { return 0.3 }
+	serve("/unavailable")
+	serve("/ok")
+	require.Len(t, rec.messages, 3)
+	require.Contains(t, cardText(t, rec.messages[2]), "503 on GET /unavailable")
+
+	notifier.rand = func() float64 { return 0.7 }
+	serve("/unavailable")
+	require.Len(t, rec.messages, 3)
+
+	notifier.Route = func(*http.Request) string { return "/panic/{id}" }
+	serve("/panic")
+	require.Contains(t, cardText(t, rec.messages[3]), "Panic on GET /panic/{id}")
+
+	// Queries are dropped unless included
+	notifier.Route = nil
+	serve("/panic?token=secret")
+	require.Len(t, rec.messages, 5)
+	require.NotContains(t, cardText(t, rec.messages[4]), "secret")
+
+	notifier.IncludeQuery = true
+	serve("/panic?token=secret")
+	require.Len(t, rec.messages, 6)
+	require.Contains(t, cardText(t, rec.messages[5]), "/panic?token=secret")
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	})
+	require.Len(t, rec.messages, 6)
+}
@@ -0,0 +1,11 @@
+//go:build !go1.22
+
+package httpnotify
+
+import "net/http"
+
+// requestPattern returns empty, as http.ServeMux records the matched
+// pattern since Go 1.22.
+func requestPattern(*http.Request) string {
+	return ""
+}
@@ -0,0 +1,18 @@
+//go:build go1.22
+
+package httpnotify
+
+import (
+	"net/http"
+	"strings"
+)
+
+// requestPattern returns the pattern matched by http.ServeMux without its
+// method, or empty if req was not routed by a ServeMux.
+func requestPattern(req *http.Request) string {
+	pattern := req.Pattern
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		pattern = strings.TrimLeft(pattern[i:], " \t")
+	}
+	return pattern
+}
@@ -0,0 +1,27 @@
+//go:build go1.22
+
+package httpnotify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewarePattern(t *testing.T) {
+	rec := &recorder{}
+	notifier := New(rec.send)
+	handler := notifier.Middleware(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		// As set by http.ServeMux when routing "GET /orders/{name}"
+		req.Pattern = "GET /orders/{name}"
+		panic("boom")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/first", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/second", nil))
+	notifier.Wait()
+	require.Len(t, rec.messages, 1)
+	require.Contains(t, cardText(t, rec.messages[0]), "Panic on GET /orders/{name}")
+}
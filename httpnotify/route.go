@@ -0,0 +1,50 @@
+package httpnotify
+
+import (
+	"net/http"
+	"strings"
+)
+
+// defaultRoute returns the route of a request without Notifier.Route: the
+// pattern matched by http.ServeMux if known, otherwise the path with
+// segments looking like IDs replaced by ":id", so that reports of the same
+// handler are throttled together.
+func defaultRoute(req *http.Request) string {
+	if pattern := requestPattern(req); pattern != "" {
+		return pattern
+	}
+	return normalizePath(req.URL.Path)
+}
+
+// normalizePath replaces the segments of path looking like IDs by ":id".
+func normalizePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isID(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isID reports whether a path segment looks like an ID: a number, a UUID or
+// a hexadecimal hash of at least 8 digits.
+func isID(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	digits, hasDigit := true, false
+	for _, r := range segment {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case r >= 'a' && r <= 'f', r >= 'A' && r <= 'F':
+			digits = false
+		case r == '-' && len(segment) == 36:
+			digits = false
+		default:
+			return false
+		}
+	}
+	return digits || hasDigit && len(segment) >= 8
+}
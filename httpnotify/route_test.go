@@ -0,0 +1,33 @@
+package httpnotify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/", want: "/"},
+		{path: "/orders/42", want: "/orders/:id"},
+		{path: "/orders/42/items/7/", want: "/orders/:id/items/:id/"},
+		{path: "/users/3f2504e0-4f89-11d3-9a0c-0305e82c3301", want: "/users/:id"},
+		{path: "/commits/9fceb02d0ae598e95dc970b74767f19372d61af8", want: "/commits/:id"},
+		{path: "/api/v2/feed", want: "/api/v2/feed"},
+		{path: "/files/deadbeef", want: "/files/deadbeef"},
+		{path: "/cafe/abc123", want: "/cafe/abc123"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, normalizePath(tt.path), tt.path)
+	}
+}
+
+func TestDefaultRoute(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/orders/42?token=secret", nil)
+	require.Equal(t, "/orders/:id", defaultRoute(req))
+}
@@ -0,0 +1,149 @@
+package feishubot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// MaxImageBytes is the maximum size of images uploaded for messages.
+const MaxImageBytes = 10 << 20
+
+// ErrInvalidImage is returned for images that cannot be uploaded, because of
+// their size or format.
+var ErrInvalidImage = errors.New("invalid image")
+
+// imageSignatures maps the leading bytes of the image formats accepted by
+// Feishu to their name.
+var imageSignatures = []struct {
+	prefix string
+	format string
+}{
+	{"\xff\xd8\xff", "jpeg"},
+	{"\x89PNG\r\n\x1a\n", "png"},
+	{"GIF87a", "gif"},
+	{"GIF89a", "gif"},
+	{"II*\x00", "tiff"},
+	{"MM\x00*", "tiff"},
+	{"BM", "bmp"},
+	{"\x00\x00\x01\x00", "ico"},
+}
+
+// DetectImageFormat returns the format of an image accepted by Feishu
+// ("jpeg", "png", "webp", "gif", "tiff", "bmp" or "ico"), detected from its
+// leading bytes, or an empty string for other data.
+func DetectImageFormat(data []byte) string {
+	if len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP" {
+		return "webp"
+	}
+	for _, sig := range imageSignatures {
+		if bytes.HasPrefix(data, []byte(sig.prefix)) {
+			return sig.format
+		}
+	}
+	return ""
+}
+
+// ValidateImage checks that data is a non-empty image of at most
+// MaxImageBytes in a format accepted by Feishu. The returned error wraps
+// ErrInvalidImage.
+func ValidateImage(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: image is empty", ErrInvalidImage)
+	}
+	if len(data) > MaxImageBytes {
+		return fmt.Errorf("%w: image is %d bytes, larger than %d", ErrInvalidImage, len(data), MaxImageBytes)
+	}
+	if DetectImageFormat(data) == "" {
+		return fmt.Errorf("%w: unsupported format, must be JPEG, PNG, WEBP, GIF, TIFF, BMP or ICO", ErrInvalidImage)
+	}
+	return nil
+}
+
+// UploadImage uploads an image for messages with the Open API and returns
+// its image key. The image is validated with ValidateImage first. It needs
+// app credentials, see SetAppCredentials.
+//
+// See: https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/image/create
+func (c *Client) UploadImage(ctx context.Context, r io.Reader) (string, error) {
+	// Read one byte more than allowed to detect oversized images
+	data, err := io.ReadAll(io.LimitReader(r, MaxImageBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+	if err := ValidateImage(data); err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("image_type", "message"); err != nil {
+		return "", fmt.Errorf("failed to write image upload: %w", err)
+	}
+	part, err := w.CreateFormFile("image", "image."+DetectImageFormat(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to write image upload: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("failed to write image upload: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("failed to write image upload: %w", err)
+	}
+
+	var result struct {
+		ImageKey string `json:"image_key"`
+	}
+	if err := c.openAPI().call(ctx, http.MethodPost, "/im/v1/images", w.FormDataContentType(), &body, &result); err != nil {
+		return "", fmt.Errorf("failed to upload image: %w", err)
+	}
+	return result.ImageKey, nil
+}
+
+// NewImageMessageFromReader uploads an image with UploadImage and returns an
+// image message showing it.
+func (c *Client) NewImageMessageFromReader(ctx context.Context, r io.Reader) (*Message, error) {
+	imageKey, err := c.UploadImage(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return NewImageMessage(imageKey), nil
+}
+
+// SendImage uploads an image and sends it as an image message in one call.
+// It needs app credentials, see SetAppCredentials.
+func (c *Client) SendImage(ctx context.Context, r io.Reader) (*Response, error) {
+	msg, err := c.NewImageMessageFromReader(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return c.Send(ctx, msg)
+}
+
+// SendImageFile uploads the image file at path and sends it as an image
+// message in one call. It needs app credentials, see SetAppCredentials.
+//
+// Example:
+//
+//	client := feishubot.NewClient(webhookURL, secret)
+//	client.SetAppCredentials(appID, appSecret)
+//	_, err := client.SendImageFile(ctx, "dashboard.png")
+func (c *Client) SendImageFile(ctx context.Context, path string) (*Response, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer f.Close()
+
+	resp, err := c.SendImage(ctx, f)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send %s: %w", filepath.Base(path), err)
+	}
+	return resp, nil
+}
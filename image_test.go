@@ -0,0 +1,107 @@
+package feishubot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestDetectImageFormat(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{data: "\xff\xd8\xff\xe0", want: "jpeg"},
+		{data: string(testPNG), want: "png"},
+		{data: "RIFF\x00\x00\x00\x00WEBPVP8 ", want: "webp"},
+		{data: "GIF89a", want: "gif"},
+		{data: "II*\x00", want: "tiff"},
+		{data: "BM\x00", want: "bmp"},
+		{data: "\x00\x00\x01\x00\x01", want: "ico"},
+		{data: "RIFF\x00\x00\x00\x00WAVE", want: ""},
+		{data: "<svg>", want: ""},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, DetectImageFormat([]byte(tt.data)), "%q", tt.data)
+	}
+}
+
+func TestValidateImage(t *testing.T) {
+	require.NoError(t, ValidateImage(testPNG))
+	require.ErrorIs(t, ValidateImage(nil), ErrInvalidImage)
+	require.ErrorIs(t, ValidateImage([]byte("<svg>")), ErrInvalidImage)
+
+	large := append(append([]byte{}, testPNG...), make([]byte, MaxImageBytes)...)
+	err := ValidateImage(large)
+	require.ErrorIs(t, err, ErrInvalidImage)
+	require.ErrorContains(t, err, "larger than")
+}
+
+func TestSendImageFile(t *testing.T) {
+	var sent map[string]interface{}
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/im/v1/images":
+			require.Equal(t, "Bearer t-cli_a", r.Header.Get("Authorization"))
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			require.Equal(t, "message", r.FormValue("image_type"))
+			f, header, err := r.FormFile("image")
+			require.NoError(t, err)
+			data, _ := io.ReadAll(f)
+			require.Equal(t, testPNG, data)
+			require.Equal(t, "image.png", header.Filename)
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"image_key":"img_v3_xxx"}}`))
+		case "/hook":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success"}`))
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "chart.png")
+	require.NoError(t, os.WriteFile(path, testPNG, 0o644))
+
+	client := NewClient(server.URL+"/hook", "")
+	client.OpenAPIBaseURL = server.URL
+	client.SetAppCredentials("cli_a", "secret")
+
+	resp, err := client.SendImageFile(context.Background(), path)
+	require.NoError(t, err)
+	require.Equal(t, 0, resp.Code)
+	require.Equal(t, map[string]interface{}{
+		"msg_type": "image",
+		"content":  map[string]interface{}{"image_key": "img_v3_xxx"},
+	}, sent)
+
+	_, err = client.SendImageFile(context.Background(), filepath.Join(t.TempDir(), "missing.png"))
+	require.ErrorContains(t, err, "failed to open image")
+}
+
+func TestUploadImageErrors(t *testing.T) {
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":234001,"msg":"Invalid request param."}`))
+	}))
+
+	client := NewClient("", "")
+	client.OpenAPIBaseURL = server.URL
+
+	_, err := client.UploadImage(context.Background(), bytes.NewReader(testPNG))
+	require.ErrorIs(t, err, ErrNoAppCredentials)
+
+	client.SetAppCredentials("cli_a", "secret")
+	_, err = client.UploadImage(context.Background(), strings.NewReader("not an image"))
+	require.ErrorIs(t, err, ErrInvalidImage)
+
+	_, err = client.NewImageMessageFromReader(context.Background(), bytes.NewReader(testPNG))
+	require.EqualError(t, err, "failed to upload image: API error (code 234001): Invalid request param.")
+}
@@ -0,0 +1,18 @@
+package feishubot
+
+import "strings"
+
+// addKeyword returns the content of a text message with keyword appended on
+// a new line unless the text already contains it. Other content is returned
+// unchanged, since there is no place to add the keyword to it that would
+// not change how the message looks.
+func addKeyword(msgType MsgType, content map[string]interface{}, keyword string) map[string]interface{} {
+	text, ok := content["text"].(string)
+	if msgType != MsgTypeText || !ok || strings.Contains(text, keyword) {
+		return content
+	}
+	if text != "" {
+		text += "\n"
+	}
+	return map[string]interface{}{"text": text + keyword}
+}
@@ -0,0 +1,58 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddKeyword(t *testing.T) {
+	require.Equal(t, map[string]interface{}{"text": "Deploy finished\n[alert]"},
+		addKeyword(MsgTypeText, map[string]interface{}{"text": "Deploy finished"}, "[alert]"))
+	require.Equal(t, map[string]interface{}{"text": "[alert] Deploy finished"},
+		addKeyword(MsgTypeText, map[string]interface{}{"text": "[alert] Deploy finished"}, "[alert]"))
+
+	post := map[string]interface{}{"post": map[string]interface{}{}}
+	require.Equal(t, post, addKeyword(MsgTypePost, post, "[alert]"))
+}
+
+func TestSendKeyword(t *testing.T) {
+	var received []*Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		received = append(received, &msg)
+		json.NewEncoder(w).Encode(SuccessResponse)
+	}))
+	defer server.Close()
+
+	var warnings []string
+	client := NewClient(server.URL, "")
+	client.SetWarningHandler(func(warning string) { warnings = append(warnings, warning) })
+	client.SetKeyword("[alert]")
+	client.SetAutoSplit(20)
+
+	message := NewTextMessage("line one\nline two\nline three")
+	_, err := client.Send(context.Background(), message)
+	require.NoError(t, err)
+	require.Len(t, received, 3)
+	require.Equal(t, "line one\n(1/3)\n[alert]", received[0].Content["text"])
+	require.Equal(t, "line three\n(3/3)\n[alert]", received[2].Content["text"])
+	// The message sent is not modified
+	require.Equal(t, "line one\nline two\nline three", message.Content["text"])
+
+	card := NewCardBuilder().Header("Deploy finished", TemplateGreen).Build()
+	_, err = client.Send(context.Background(), NewInteractiveMessage(card))
+	require.NoError(t, err)
+	require.Equal(t, []string{`interactive message does not contain the keyword "[alert]" required by the bot`}, warnings)
+
+	warnings = nil
+	card = NewCardBuilder().Header("[alert] Deploy finished", TemplateGreen).Build()
+	_, err = client.Send(context.Background(), NewInteractiveMessage(card))
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+}
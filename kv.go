@@ -0,0 +1,97 @@
+package feishubot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KV is a label/value pair used to render "field: value" style reports.
+type KV struct {
+	Label string
+	Value string
+	// URL optionally turns the value into a hyperlink.
+	URL string
+}
+
+// NewKeyValueParagraphs renders label/value pairs as post paragraphs, one
+// line per pair in the form "**Label:** value". The label is rendered as
+// bold text and the value as a text element, or as a link element when URL
+// is set, so that labels and values are shown as is.
+//
+// Example:
+//
+//	content := feishubot.NewPostContent(
+//		"Deploy finished",
+//		feishubot.NewKeyValueParagraphs([]feishubot.KV{
+//			{Label: "Service", Value: "api-gateway"},
+//			{Label: "Version", Value: "v1.4.2"},
+//			{Label: "Logs", Value: "build #123", URL: "https://ci.example.com/123"},
+//		})...,
+//	)
+func NewKeyValueParagraphs(pairs []KV) []Paragraph {
+	paragraphs := make([]Paragraph, 0, len(pairs))
+	for _, kv := range pairs {
+		label := Element{"tag": "text", "text": kv.Label + ": ", "style": []string{"bold"}}
+		value := NewTextElement(kv.Value)
+		if kv.URL != "" {
+			value = NewLinkElement(kv.Value, kv.URL)
+		}
+		paragraphs = append(paragraphs, NewParagraph(label, value))
+	}
+	return paragraphs
+}
+
+// markdown renders the pair as a single markdown line, escaping the label
+// and value, see EscapeMarkdown.
+func (kv KV) markdown() string {
+	if kv.URL != "" {
+		return fmt.Sprintf("**%s:** [%s](%s)", EscapeMarkdown(kv.Label), EscapeMarkdown(kv.Value), markdownURLEscaper.Replace(kv.URL))
+	}
+	return fmt.Sprintf("**%s:** %s", EscapeMarkdown(kv.Label), EscapeMarkdown(kv.Value))
+}
+
+// markdownURLEscaper escapes the characters ending a markdown link target.
+var markdownURLEscaper = strings.NewReplacer(
+	"(", "%28",
+	")", "%29",
+	" ", "%20",
+)
+
+// NewFieldsElement lays out label/value pairs in the given number of columns,
+// each pair rendered as "**Label:** value" markdown, with the label and value
+// escaped, see EscapeMarkdown.
+//
+// One or two columns use a div with full width or short fields; more columns
+// use a column set of equally weighted columns, filled row by row. A columns
+// value of zero or less uses two columns.
+//
+// Example:
+//
+//	fields := feishubot.NewFieldsElement([]feishubot.KV{
+//		{Label: "Service", Value: "api-gateway"},
+//		{Label: "Version", Value: "v1.4.2"},
+//		{Label: "Region", Value: "eu-west"},
+//	}, 3)
+func NewFieldsElement(pairs []KV, columns int) CardElement {
+	if columns <= 0 {
+		columns = 2
+	}
+
+	if columns <= 2 {
+		div := NewDiv(nil)
+		for _, kv := range pairs {
+			div.AddField(NewCardMarkdownTitle(kv.markdown()), columns == 2)
+		}
+		return div
+	}
+
+	set := NewColumnSet()
+	for i := 0; i < columns; i++ {
+		set.Columns = append(set.Columns, NewColumn(1))
+	}
+	for i, kv := range pairs {
+		column := set.Columns[i%columns]
+		column.Elements = append(column.Elements, NewMarkdownElement(kv.markdown()))
+	}
+	return set
+}
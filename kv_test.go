@@ -0,0 +1,120 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyValueParagraphs(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []KV
+		want  []Paragraph
+	}{
+		{
+			name:  "no pairs",
+			pairs: nil,
+			want:  []Paragraph{},
+		},
+		{
+			name: "text and link values",
+			pairs: []KV{
+				{Label: "Service", Value: "api-gateway"},
+				{Label: "Logs", Value: "build #123", URL: "https://ci.example.com/123"},
+			},
+			want: []Paragraph{
+				{{"tag": "text", "text": "Service: ", "style": []string{"bold"}}, {"tag": "text", "text": "api-gateway"}},
+				{{"tag": "text", "text": "Logs: ", "style": []string{"bold"}}, {"tag": "a", "text": "build #123", "href": "https://ci.example.com/123"}},
+			},
+		},
+		{
+			name: "markup in values",
+			pairs: []KV{
+				{Label: "Title", Value: "**[fix](x)** <at user_id=\"all\"></at>"},
+			},
+			want: []Paragraph{
+				{{"tag": "text", "text": "Title: ", "style": []string{"bold"}}, {"tag": "text", "text": "**[fix](x)** <at user_id=\"all\"></at>"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewKeyValueParagraphs(tt.pairs)
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("NewKeyValueParagraphs() diff = %v", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func TestNewFieldsElement(t *testing.T) {
+	pairs := []KV{
+		{Label: "Service", Value: "api"},
+		{Label: "Version", Value: "v1"},
+		{Label: "Region", Value: "eu"},
+		{Label: "Logs", Value: "#1", URL: "https://ci.example.com/1"},
+	}
+
+	tests := []struct {
+		name    string
+		pairs   []KV
+		columns int
+		want    string
+	}{
+		{
+			name:    "one column",
+			pairs:   pairs[:2],
+			columns: 1,
+			want: `{"tag": "div", "fields": [
+				{"is_short": false, "text": {"tag": "lark_md", "content": "**Service:** api"}},
+				{"is_short": false, "text": {"tag": "lark_md", "content": "**Version:** v1"}}
+			]}`,
+		},
+		{
+			name:    "default two columns",
+			pairs:   pairs[:2],
+			columns: 0,
+			want: `{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Service:** api"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Version:** v1"}}
+			]}`,
+		},
+		{
+			name:    "markup in labels and values",
+			pairs:   []KV{{Label: "a*b", Value: "x](y) <font color='red'>z</font>", URL: "https://example.com/a (b)"}},
+			columns: 1,
+			want: `{"tag": "div", "fields": [
+				{"is_short": false, "text": {"tag": "lark_md", "content": "**a&#42;b:** [x&#93;(y) &lt;font color='red'&gt;z&lt;/font&gt;](https://example.com/a%20%28b%29)"}}
+			]}`,
+		},
+		{
+			name:    "three columns",
+			pairs:   pairs,
+			columns: 3,
+			want: `{"tag": "column_set", "columns": [
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "markdown", "content": "**Service:** api"},
+					{"tag": "markdown", "content": "**Logs:** [#1](https://ci.example.com/1)"}
+				]},
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "markdown", "content": "**Version:** v1"}
+				]},
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "markdown", "content": "**Region:** eu"}
+				]}
+			]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(NewFieldsElement(tt.pairs, tt.columns))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
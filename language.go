@@ -0,0 +1,124 @@
+package feishubot
+
+import "strings"
+
+// Languages lists all languages accepted by Feishu for multi-language
+// content, in the order used as the fallback preference.
+var Languages = []Language{
+	LanguageZhCN,
+	LanguageEnUS,
+	LanguageJaJP,
+	LanguageZhHK,
+	LanguageZhTW,
+	LanguageIdID,
+	LanguageViVN,
+	LanguageThTH,
+	LanguagePtBR,
+	LanguageEsES,
+	LanguageKoKR,
+	LanguageDeDE,
+	LanguageFrFR,
+	LanguageItIT,
+	LanguageRuRU,
+	LanguageMsMY,
+}
+
+// fallbackLanguages are tried, in order, when none of the preferred
+// languages is available.
+var fallbackLanguages = []Language{LanguageZhCN, LanguageEnUS}
+
+// ParseLanguage converts a locale such as "zh-CN", "en_US" or "ja" to the
+// matching Language. A bare language code matches the first supported
+// language with that code, e.g. "ja" matches LanguageJaJP and "zh" matches
+// LanguageZhCN. The second result is false if no language matches.
+func ParseLanguage(locale string) (Language, bool) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "-", "_"))
+	if normalized == "" {
+		return "", false
+	}
+
+	for _, lang := range Languages {
+		if string(lang) == normalized {
+			return lang, true
+		}
+	}
+	base := languageBase(Language(normalized))
+	for _, lang := range Languages {
+		if languageBase(lang) == base {
+			return lang, true
+		}
+	}
+
+	return "", false
+}
+
+// SelectLanguage picks the language from available that best matches the
+// preferred languages, in order of preference.
+//
+// An exact match is preferred, then a language sharing the same base code
+// (e.g. zh_hk for a preferred zh_tw). If nothing matches, zh_cn and then
+// en_us are used, and finally the first available language. The second
+// result is false only if available is empty.
+func SelectLanguage(available []Language, preferred ...Language) (Language, bool) {
+	if len(available) == 0 {
+		return "", false
+	}
+
+	for _, want := range preferred {
+		for _, lang := range available {
+			if lang == want {
+				return lang, true
+			}
+		}
+		for _, lang := range available {
+			if languageBase(lang) == languageBase(want) {
+				return lang, true
+			}
+		}
+	}
+	for _, want := range fallbackLanguages {
+		for _, lang := range available {
+			if lang == want {
+				return lang, true
+			}
+		}
+	}
+
+	return available[0], true
+}
+
+// SelectPostContent returns the language version from contents that best
+// matches the preferred languages, following the rules of SelectLanguage.
+// The second result is false if contents is empty.
+//
+// Example:
+//
+//	lang, _ := feishubot.ParseLanguage(user.Locale)
+//	lc, ok := feishubot.SelectPostContent(contents, lang)
+//	if ok {
+//		message := feishubot.NewPostMessage(lc.Language, &lc.Content)
+//	}
+func SelectPostContent(contents []PostLanguageContent, preferred ...Language) (PostLanguageContent, bool) {
+	available := make([]Language, 0, len(contents))
+	for _, lc := range contents {
+		available = append(available, lc.Language)
+	}
+
+	lang, ok := SelectLanguage(available, preferred...)
+	if !ok {
+		return PostLanguageContent{}, false
+	}
+	for _, lc := range contents {
+		if lc.Language == lang {
+			return lc, true
+		}
+	}
+	return PostLanguageContent{}, false
+}
+
+// languageBase returns the language code without the region, e.g. "zh" for
+// zh_cn.
+func languageBase(lang Language) string {
+	base, _, _ := strings.Cut(string(lang), "_")
+	return base
+}
@@ -0,0 +1,115 @@
+package feishubot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   Language
+		wantOK bool
+	}{
+		{"zh_cn", LanguageZhCN, true},
+		{"zh-CN", LanguageZhCN, true},
+		{"en-US", LanguageEnUS, true},
+		{" EN_us ", LanguageEnUS, true},
+		{"ja", LanguageJaJP, true},
+		{"zh", LanguageZhCN, true},
+		{"zh-TW", LanguageZhTW, true},
+		{"en-GB", LanguageEnUS, true},
+		{"xx", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			got, ok := ParseLanguage(tt.locale)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelectLanguage(t *testing.T) {
+	tests := []struct {
+		name      string
+		available []Language
+		preferred []Language
+		want      Language
+		wantOK    bool
+	}{
+		{
+			name:      "exact match",
+			available: []Language{LanguageZhCN, LanguageEnUS, LanguageJaJP},
+			preferred: []Language{LanguageJaJP},
+			want:      LanguageJaJP,
+			wantOK:    true,
+		},
+		{
+			name:      "preference order",
+			available: []Language{LanguageZhCN, LanguageEnUS},
+			preferred: []Language{LanguageKoKR, LanguageEnUS, LanguageZhCN},
+			want:      LanguageEnUS,
+			wantOK:    true,
+		},
+		{
+			name:      "same base language",
+			available: []Language{LanguageEnUS, LanguageZhHK},
+			preferred: []Language{LanguageZhTW},
+			want:      LanguageZhHK,
+			wantOK:    true,
+		},
+		{
+			name:      "fallback to zh_cn",
+			available: []Language{LanguageEnUS, LanguageZhCN},
+			preferred: []Language{LanguageKoKR},
+			want:      LanguageZhCN,
+			wantOK:    true,
+		},
+		{
+			name:      "fallback to en_us",
+			available: []Language{LanguageJaJP, LanguageEnUS},
+			preferred: []Language{LanguageKoKR},
+			want:      LanguageEnUS,
+			wantOK:    true,
+		},
+		{
+			name:      "fallback to first available",
+			available: []Language{LanguageDeDE, LanguageFrFR},
+			want:      LanguageDeDE,
+			wantOK:    true,
+		},
+		{
+			name:   "nothing available",
+			want:   "",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SelectLanguage(tt.available, tt.preferred...)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelectPostContent(t *testing.T) {
+	zh := NewPostLanguageContent(LanguageZhCN, NewPostContent("标题"))
+	en := NewPostLanguageContent(LanguageEnUS, NewPostContent("Title"))
+
+	got, ok := SelectPostContent([]PostLanguageContent{zh, en}, LanguageEnUS)
+	require.True(t, ok)
+	require.Equal(t, "Title", got.Content.Title)
+
+	got, ok = SelectPostContent([]PostLanguageContent{zh, en}, LanguageJaJP)
+	require.True(t, ok)
+	require.Equal(t, "标题", got.Content.Title)
+
+	_, ok = SelectPostContent(nil, LanguageEnUS)
+	require.False(t, ok)
+}
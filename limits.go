@@ -0,0 +1,129 @@
+package feishubot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Documented limits of card content. Feishu rejects cards exceeding them
+// with a generic error.
+const (
+	// MaxCardElements is the maximum number of elements in a card.
+	MaxCardElements = 200
+	// MaxCardNestingDepth is the maximum depth of nested elements, counting
+	// the top-level elements as depth 1.
+	MaxCardNestingDepth = 5
+	// MaxContainerElements is the maximum number of direct children of a
+	// container element such as a column, form or collapsible panel.
+	MaxContainerElements = 50
+	// MaxMarkdownBytes is the maximum length of the content of a markdown
+	// element in bytes.
+	MaxMarkdownBytes = 10000
+	// MaxActionButtons is the maximum number of buttons in an action module.
+	MaxActionButtons = 5
+	// MaxHeaderTextTags is the maximum number of text tags in a card header.
+	MaxHeaderTextTags = 3
+)
+
+// Lint rules for card limits reported in Issue.Rule.
+const (
+	RuleContainerElements = "container-elements"
+	RuleMarkdownLength    = "markdown-length"
+)
+
+// ErrCardLimitExceeded is returned when a card exceeds a documented limit,
+// see Card.Validate.
+var ErrCardLimitExceeded = errors.New("card limit exceeded")
+
+// Validate checks the card against the documented element count, nesting
+// depth, container size and markdown length limits. The returned error wraps
+// ErrCardLimitExceeded and names the path of each offending element, such as
+// "body.elements[3].columns[0]".
+//
+// Client.Send performs the same check when Client.ValidateCards is enabled.
+func (c *Card) Validate() error {
+	return validateCardLimits(c.ToMap())
+}
+
+// validateCardLimits checks card, which must marshal to a card JSON object,
+// against the card limits.
+func validateCardLimits(card interface{}) error {
+	issues := cardLimitIssues(card)
+	if len(issues) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+	return fmt.Errorf("%w: %s", ErrCardLimitExceeded, strings.Join(messages, "; "))
+}
+
+// cardLimitIssues returns the issues of card exceeding the card limits.
+func cardLimitIssues(card interface{}) []Issue {
+	var issues []Issue
+	var elements []string
+	err := walkCard(card, func(path string, elem map[string]interface{}) {
+		if !isCardElement(path, elem) {
+			return
+		}
+
+		depth := 1
+		for _, e := range elements {
+			if strings.HasPrefix(path, e+".") {
+				depth++
+			}
+		}
+		if depth > MaxCardNestingDepth {
+			issues = append(issues, Issue{
+				Path:    path,
+				Rule:    RuleNestingDepth,
+				Message: fmt.Sprintf("element is nested %d levels deep, maximum is %d", depth, MaxCardNestingDepth),
+			})
+		}
+		elements = append(elements, path)
+
+		for _, key := range []string{"elements", "columns"} {
+			if children, ok := elem[key].([]interface{}); ok && len(children) > MaxContainerElements {
+				issues = append(issues, Issue{
+					Path:    path,
+					Rule:    RuleContainerElements,
+					Message: fmt.Sprintf("element has %d %s, maximum is %d", len(children), key, MaxContainerElements),
+				})
+			}
+		}
+
+		if content, ok := elem["content"].(string); ok && elem["tag"] == "markdown" && len(content) > MaxMarkdownBytes {
+			issues = append(issues, Issue{
+				Path:    path,
+				Rule:    RuleMarkdownLength,
+				Message: fmt.Sprintf("markdown content is %d bytes, maximum is %d", len(content), MaxMarkdownBytes),
+			})
+		}
+	})
+	if err != nil {
+		return append(issues, Issue{Message: err.Error()})
+	}
+
+	if len(elements) > MaxCardElements {
+		issues = append(issues, Issue{
+			Rule:    RuleElementCount,
+			Message: fmt.Sprintf("card has %d elements, maximum is %d", len(elements), MaxCardElements),
+		})
+	}
+
+	return issues
+}
+
+// isCardElement reports whether the tagged object at path is a card element,
+// as opposed to a text or icon value or part of the card header or of the
+// header of a container such as a collapsible panel.
+func isCardElement(path string, elem map[string]interface{}) bool {
+	tag := elem["tag"].(string)
+	return !cardValueTags[tag] &&
+		!strings.HasPrefix(path, "header") &&
+		!strings.HasPrefix(path, "i18n_header") &&
+		!strings.Contains(path, ".header.")
+}
@@ -0,0 +1,130 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardLimitIssues(t *testing.T) {
+	manyElements := make([]CardElement, 0, MaxCardElements+1)
+	for i := 0; i <= MaxCardElements; i++ {
+		manyElements = append(manyElements, NewHrCardElement())
+	}
+
+	// Panel header titles are markdown but not elements
+	panels := make([]CardElement, 0, MaxCardElements/2)
+	for i := 0; i < MaxCardElements/2; i++ {
+		panels = append(panels, NewCollapsiblePanelElement("title", false, NewHrCardElement()))
+	}
+
+	crowdedColumn := make([]CardElement, 0, MaxContainerElements+1)
+	for i := 0; i <= MaxContainerElements; i++ {
+		crowdedColumn = append(crowdedColumn, NewHrCardElement())
+	}
+
+	tests := []struct {
+		name string
+		card *Card
+		want []Issue
+	}{
+		{
+			name: "within limits",
+			card: NewCard("2.0").SetBody(&CardBody{Elements: []CardElement{
+				NewMarkdownElement("Hello"),
+				NewColumnSet(NewColumn(1, NewMarkdownElement("column"))).ToElement(),
+			}}),
+		},
+		{
+			name: "too many elements",
+			card: NewCard("2.0").SetBody(&CardBody{Elements: manyElements}),
+			want: []Issue{
+				{Rule: RuleElementCount, Message: "card has 201 elements, maximum is 200"},
+			},
+		},
+		{
+			name: "panels at element limit",
+			card: NewCard("2.0").SetBody(&CardBody{Elements: panels}),
+		},
+		{
+			name: "panels over element limit",
+			card: NewCard("2.0").SetBody(&CardBody{Elements: append(panels, NewHrCardElement())}),
+			want: []Issue{
+				{Rule: RuleElementCount, Message: "card has 201 elements, maximum is 200"},
+			},
+		},
+		{
+			name: "too many container children",
+			card: NewCard("2.0").SetBody(&CardBody{Elements: []CardElement{
+				NewColumnSet(NewColumn(1, crowdedColumn...)).ToElement(),
+			}}),
+			want: []Issue{
+				{Path: "body.elements[0].columns[0]", Rule: RuleContainerElements, Message: "element has 51 elements, maximum is 50"},
+			},
+		},
+		{
+			name: "markdown too long",
+			card: NewCard("2.0").SetBody(&CardBody{Elements: []CardElement{
+				NewMarkdownElement("ok"),
+				NewMarkdownElement(strings.Repeat("x", MaxMarkdownBytes+1)),
+			}}),
+			want: []Issue{
+				{Path: "body.elements[1]", Rule: RuleMarkdownLength, Message: "markdown content is 10001 bytes, maximum is 10000"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cardLimitIssues(tt.card.ToMap())
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("cardLimitIssues() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCardValidate(t *testing.T) {
+	card := NewCard("2.0").SetBody(&CardBody{Elements: []CardElement{
+		NewMarkdownElement("Hello"),
+	}})
+	require.NoError(t, card.Validate())
+
+	card.Body.Elements = append(card.Body.Elements, NewMarkdownElement(strings.Repeat("x", MaxMarkdownBytes+1)))
+	err := card.Validate()
+	require.True(t, errors.Is(err, ErrCardLimitExceeded))
+	require.EqualError(t, err, "card limit exceeded: body.elements[1]: markdown content is 10001 bytes, maximum is 10000")
+}
+
+func TestSendValidateCards(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SuccessResponse)
+	}))
+	defer server.Close()
+
+	message := NewInteractiveMessage(NewCard("2.0").SetBody(&CardBody{Elements: []CardElement{
+		NewMarkdownElement(strings.Repeat("x", MaxMarkdownBytes+1)),
+	}}))
+
+	client := NewClient(server.URL+"/webhook", "")
+	client.SetValidateCards(true)
+
+	_, err := client.Send(context.Background(), message)
+	require.True(t, errors.Is(err, ErrCardLimitExceeded))
+	require.Equal(t, 0, requests)
+
+	client.SetValidateCards(false)
+	_, err = client.Send(context.Background(), message)
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+}
@@ -0,0 +1,312 @@
+package feishubot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Lint rules reported in Issue.Rule.
+const (
+	RuleHeaderTemplate  = "header-template"
+	RuleHeader          = "header"
+	RuleUnknownTag      = "unknown-tag"
+	RuleNestingDepth    = "nesting-depth"
+	RuleElementCount    = "element-count"
+	RuleActionButtons   = "action-buttons"
+	RuleWebhookCallback = "webhook-callback"
+	RuleSpacing         = "spacing"
+	RuleDarkMode        = "dark-mode"
+)
+
+// headerTemplates are the colors accepted by CardHeader.Template.
+var headerTemplates = map[string]bool{
+	TemplateBlue:      true,
+	TemplateWathet:    true,
+	TemplateTurquoise: true,
+	TemplateGreen:     true,
+	TemplateYellow:    true,
+	TemplateOrange:    true,
+	TemplateRed:       true,
+	TemplateCarmine:   true,
+	TemplateViolet:    true,
+	TemplatePurple:    true,
+	TemplateIndigo:    true,
+	TemplateGrey:      true,
+	TemplateDefault:   true,
+}
+
+// textTagColors are the colors accepted by TextTag.Color.
+var textTagColors = map[string]bool{
+	TextTagNeutral:   true,
+	TextTagBlue:      true,
+	TextTagTurquoise: true,
+	TextTagLime:      true,
+	TextTagOrange:    true,
+	TextTagViolet:    true,
+	TextTagIndigo:    true,
+	TextTagWathet:    true,
+	TextTagGreen:     true,
+	TextTagYellow:    true,
+	TextTagRed:       true,
+	TextTagPurple:    true,
+	TextTagCarmine:   true,
+}
+
+// cardElementTags are the known tags of card elements.
+var cardElementTags = map[string]bool{
+	"markdown":              true,
+	"div":                   true,
+	"hr":                    true,
+	"img":                   true,
+	"img_combination":       true,
+	"note":                  true,
+	"column_set":            true,
+	"column":                true,
+	"collapsible_panel":     true,
+	"interactive_container": true,
+	"action":                true,
+	"button":                true,
+	"overflow":              true,
+	"select_static":         true,
+	"multi_select_static":   true,
+	"select_person":         true,
+	"multi_select_person":   true,
+	"select_img":            true,
+	"date_picker":           true,
+	"picker_time":           true,
+	"picker_datetime":       true,
+	"checker":               true,
+	"form":                  true,
+	"input":                 true,
+	"chart":                 true,
+	"table":                 true,
+	"person":                true,
+	"person_list":           true,
+	"avatar":                true,
+	"text_tag":              true,
+}
+
+// cardValueTags are tags of values nested in elements, such as texts and
+// icons, which are not elements themselves.
+var cardValueTags = map[string]bool{
+	"plain_text":    true,
+	"lark_md":       true,
+	"standard_icon": true,
+	"custom_icon":   true,
+}
+
+// schemaV1OnlyTags maps element tags that were removed in schema 2.0 to
+// their replacement.
+var schemaV1OnlyTags = map[string]string{
+	"note":   "a markdown element with notation text size",
+	"action": "NewButtonGroup",
+}
+
+// Issue is a problem found by Card.Lint.
+type Issue struct {
+	// Path is the JSON path of the offending part of the card, such as
+	// "body.elements[2]". It is empty for issues concerning the whole card.
+	Path    string
+	Rule    string
+	Message string
+}
+
+// String returns the issue formatted as "path: message".
+func (i Issue) String() string {
+	if i.Path == "" {
+		return i.Message
+	}
+	return i.Path + ": " + i.Message
+}
+
+// Lint checks the card against known schema rules and returns the issues
+// found, or nil if there are none. It checks header templates, subtitles,
+// icons and text tags, element tags, buttons per action module, direction,
+// padding, margin and spacing values, elements that do not work through
+// custom bot webhooks, hard-coded colors that render poorly in dark theme
+// and the limits checked by Validate.
+//
+// Feishu reports most of these problems with a generic error, so linting the
+// card locally makes them much easier to track down.
+//
+// Example:
+//
+//	for _, issue := range card.Lint() {
+//		log.Printf("card lint: %s", issue)
+//	}
+func (c *Card) Lint() []Issue {
+	var issues []Issue
+
+	if c.Header != nil {
+		issues = append(issues, lintHeader("header", c.Header)...)
+	}
+	for _, lang := range sortedLanguages(c.I18nHeader) {
+		issues = append(issues, lintHeader("i18n_header."+string(lang), c.I18nHeader[lang])...)
+	}
+
+	if c.Body != nil {
+		issues = append(issues, lintSpacing("body", map[string]interface{}{
+			"direction": string(c.Body.Direction),
+			"padding":   string(c.Body.Padding),
+		})...)
+	}
+
+	var callbacks []string
+	err := walkCard(c.ToMap(), func(path string, elem map[string]interface{}) {
+		if !isCardElement(path, elem) {
+			return
+		}
+		tag := elem["tag"].(string)
+		issues = append(issues, lintSpacing(path, elem)...)
+
+		if !cardElementTags[tag] {
+			issues = append(issues, Issue{
+				Path:    path,
+				Rule:    RuleUnknownTag,
+				Message: fmt.Sprintf("unknown element tag %q", tag),
+			})
+		} else if replacement, ok := schemaV1OnlyTags[tag]; ok && c.Schema == "2.0" {
+			issues = append(issues, Issue{
+				Path:    path,
+				Rule:    RuleUnknownTag,
+				Message: fmt.Sprintf("element tag %q is not supported in schema 2.0, use %s instead", tag, replacement),
+			})
+		}
+
+		if tag == "action" {
+			if actions, ok := elem["actions"].([]interface{}); ok && len(actions) > MaxActionButtons {
+				issues = append(issues, Issue{
+					Path:    path,
+					Rule:    RuleActionButtons,
+					Message: fmt.Sprintf("action has %d buttons, maximum is %d", len(actions), MaxActionButtons),
+				})
+			}
+		}
+
+		if callbackElementTags[tag] && !hasPathPrefix(path, callbacks) {
+			callbacks = append(callbacks, path)
+			issues = append(issues, Issue{
+				Path:    path,
+				Rule:    RuleWebhookCallback,
+				Message: fmt.Sprintf("element %q requires interaction callbacks, which custom bot webhooks do not support", tag),
+			})
+		}
+	})
+	if err != nil {
+		return append(issues, Issue{Message: err.Error()})
+	}
+
+	issues = append(issues, darkModeIssues(c.ToMap(), c.customColors())...)
+	return append(issues, cardLimitIssues(c.ToMap())...)
+}
+
+// lintHeader checks the template, subtitle, icon and text tags of a card
+// header.
+func lintHeader(path string, header *CardHeader) []Issue {
+	if header == nil {
+		return nil
+	}
+
+	var issues []Issue
+	if header.Template != "" && !headerTemplates[header.Template] {
+		issues = append(issues, Issue{
+			Path:    path + ".template",
+			Rule:    RuleHeaderTemplate,
+			Message: fmt.Sprintf("unknown header template %q", header.Template),
+		})
+	}
+
+	if header.Subtitle != nil && (header.Title == nil || header.Title.Content == "") {
+		issues = append(issues, Issue{
+			Path:    path + ".subtitle",
+			Rule:    RuleHeader,
+			Message: "subtitle requires a title",
+		})
+	}
+
+	if icon := header.Icon; icon != nil {
+		var message string
+		switch {
+		case icon.Tag == "standard_icon" && icon.Token == "":
+			message = "standard icon requires a token"
+		case icon.Tag == "custom_icon" && icon.ImgKey == "":
+			message = "custom icon requires an img_key"
+		case icon.Tag != "standard_icon" && icon.Tag != "custom_icon":
+			message = fmt.Sprintf("unknown icon tag %q", icon.Tag)
+		}
+		if message != "" {
+			issues = append(issues, Issue{Path: path + ".icon", Rule: RuleHeader, Message: message})
+		}
+	}
+
+	if len(header.TextTagList) > MaxHeaderTextTags {
+		issues = append(issues, Issue{
+			Path:    path + ".text_tag_list",
+			Rule:    RuleHeader,
+			Message: fmt.Sprintf("header has %d text tags, maximum is %d", len(header.TextTagList), MaxHeaderTextTags),
+		})
+	}
+	for i, tag := range header.TextTagList {
+		if tag.Color != "" && !textTagColors[tag.Color] {
+			issues = append(issues, Issue{
+				Path:    fmt.Sprintf("%s.text_tag_list[%d].color", path, i),
+				Rule:    RuleHeader,
+				Message: fmt.Sprintf("unknown text tag color %q", tag.Color),
+			})
+		}
+	}
+
+	return issues
+}
+
+// lintSpacing checks the direction, padding, margin and spacing settings of
+// a card body or element. Feishu silently ignores malformed values.
+func lintSpacing(path string, elem map[string]interface{}) []Issue {
+	checks := []struct {
+		key   string
+		check func(string) error
+	}{
+		{"direction", checkDirection},
+		{"horizontal_spacing", checkSpacing},
+		{"vertical_spacing", checkSpacing},
+		{"padding", func(s string) error { return checkBox(s, 0, maxSpacingPx) }},
+		{"margin", func(s string) error { return checkBox(s, minMarginPx, maxSpacingPx) }},
+	}
+
+	var issues []Issue
+	for _, c := range checks {
+		value, ok := elem[c.key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		if err := c.check(value); err != nil {
+			issues = append(issues, Issue{
+				Path:    path + "." + c.key,
+				Rule:    RuleSpacing,
+				Message: err.Error(),
+			})
+		}
+	}
+	return issues
+}
+
+// hasPathPrefix reports whether path lies inside any of the given paths.
+func hasPathPrefix(path string, paths []string) bool {
+	for _, p := range paths {
+		if strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedLanguages returns the keys of headers in sorted order.
+func sortedLanguages(headers map[Language]*CardHeader) []Language {
+	langs := make([]Language, 0, len(headers))
+	for lang := range headers {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return langs
+}
@@ -0,0 +1,198 @@
+package feishubot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCardLint(t *testing.T) {
+	manyButtons := make([]CardElement, 0, 6)
+	for i := 0; i < 6; i++ {
+		manyButtons = append(manyButtons, NewButtonElement("b", "default", "https://example.com"))
+	}
+
+	manyElements := make([]CardElement, 0, 201)
+	for i := 0; i < 201; i++ {
+		manyElements = append(manyElements, NewHrCardElement())
+	}
+
+	deep := NewColumnSet(NewColumn(1,
+		NewColumnSet(NewColumn(1, NewMarkdownElement("deep"))).ToElement(),
+	)).ToElement()
+
+	tests := []struct {
+		name string
+		card *Card
+		want []Issue
+	}{
+		{
+			name: "valid card",
+			card: NewCard("2.0").
+				SetHeader(&CardHeader{Title: NewCardTitle("Title"), Template: "blue"}).
+				SetBody(&CardBody{Elements: append([]CardElement{
+					NewMarkdownElement("Hello"),
+					NewDivElement(NewCardTitle("text")),
+				}, NewButtonGroup(2, NewButtonElement("Open", "primary", "https://example.com"))...)}),
+		},
+		{
+			name: "unknown header templates",
+			card: NewCard("2.0").
+				SetHeader(&CardHeader{Title: NewCardTitle("Title"), Template: "pink"}).
+				SetI18nHeader(map[Language]*CardHeader{
+					LanguageZhCN: {Title: NewCardTitle("标题"), Template: "blue"},
+					LanguageEnUS: {Title: NewCardTitle("Title"), Template: "Blue"},
+				}),
+			want: []Issue{
+				{Path: "header.template", Rule: RuleHeaderTemplate, Message: `unknown header template "pink"`},
+				{Path: "i18n_header.en_us.template", Rule: RuleHeaderTemplate, Message: `unknown header template "Blue"`},
+			},
+		},
+		{
+			name: "header subtitle, icon and text tags",
+			card: NewCard("2.0").
+				SetHeader((&CardHeader{}).
+					SetSubtitle("sub").
+					SetIcon(&CardIcon{Tag: "standard_icon"}).
+					AddTextTags(
+						NewTextTag("a", TextTagRed),
+						NewTextTag("b", "pink"),
+						NewTextTag("c", ""),
+						NewTextTag("d", TextTagNeutral),
+					)).
+				SetI18nHeader(map[Language]*CardHeader{
+					LanguageEnUS: {Title: NewCardTitle("Title"), Icon: NewCustomIcon("")},
+					LanguageZhCN: {Title: NewCardTitle("标题"), Icon: &CardIcon{Tag: "emoji"}},
+				}),
+			want: []Issue{
+				{Path: "header.subtitle", Rule: RuleHeader, Message: "subtitle requires a title"},
+				{Path: "header.icon", Rule: RuleHeader, Message: "standard icon requires a token"},
+				{Path: "header.text_tag_list", Rule: RuleHeader, Message: "header has 4 text tags, maximum is 3"},
+				{Path: "header.text_tag_list[1].color", Rule: RuleHeader, Message: `unknown text tag color "pink"`},
+				{Path: "i18n_header.en_us.icon", Rule: RuleHeader, Message: "custom icon requires an img_key"},
+				{Path: "i18n_header.zh_cn.icon", Rule: RuleHeader, Message: `unknown icon tag "emoji"`},
+			},
+		},
+		{
+			name: "unknown and removed tags",
+			card: NewCard("2.0").
+				SetBody(&CardBody{Elements: []CardElement{
+					MapElement{"tag": "carousel"},
+					MapElement{"tag": "note", "elements": []any{}},
+					NewActionElement(ActionLayoutDefault, NewButtonElement("Open", "primary", "https://example.com")),
+				}}),
+			want: []Issue{
+				{Path: "body.elements[0]", Rule: RuleUnknownTag, Message: `unknown element tag "carousel"`},
+				{Path: "body.elements[1]", Rule: RuleUnknownTag, Message: `element tag "note" is not supported in schema 2.0, use a markdown element with notation text size instead`},
+				{Path: "body.elements[2]", Rule: RuleUnknownTag, Message: `element tag "action" is not supported in schema 2.0, use NewButtonGroup instead`},
+			},
+		},
+		{
+			name: "malformed spacing",
+			card: NewCard("2.0").
+				SetBody(&CardBody{
+					Direction: "down",
+					Padding:   "12px 12px 12px",
+					Elements: []CardElement{
+						NewColumnSet(NewColumn(1).SetPadding(PaddingXY(4, 8))).
+							SetHorizontalSpacing("8").
+							SetMargin(MarginAll(-120)),
+						NewCollapsiblePanelElement("title", true),
+					},
+				}),
+			want: []Issue{
+				{Path: "body.direction", Rule: RuleSpacing, Message: `invalid direction "down", must be "vertical" or "horizontal"`},
+				{Path: "body.padding", Rule: RuleSpacing, Message: `invalid value "12px 12px 12px", must be one, two or four values of 0-99px`},
+				{Path: "body.elements[0].horizontal_spacing", Rule: RuleSpacing, Message: `invalid spacing "8", must be small, medium, large, extra_large or 0-99px`},
+				{Path: "body.elements[0].margin", Rule: RuleSpacing, Message: `invalid value "-120px", must be one, two or four values of -99-99px`},
+			},
+		},
+		{
+			name: "colors in dark mode",
+			card: NewCard("2.0").
+				SetCustomColor("#brand", "#3370ff", "#4c88ff").
+				SetBody(&CardBody{Elements: []CardElement{
+					NewMarkdownElement("<font color='red'>ok</font> <font color=\"#ff0000\">bad</font> <font color='#brand'>brand</font>"),
+					NewPlainTextElement("text", WithTextColor("white")),
+					NewColumnSet(NewColumn(1).SetBackgroundStyle(Shade(ColorGrey, 100))).SetBackgroundStyle("rgba(0,0,0,1)"),
+				}}),
+			want: []Issue{
+				{Path: "body.elements[0].content", Rule: RuleDarkMode, Message: `color "#ff0000" does not adapt to dark mode, use a color token or Card.SetCustomColor`},
+				{Path: "body.elements[1].text.text_color", Rule: RuleDarkMode, Message: `color "white" does not adapt to dark mode, use a color token or Card.SetCustomColor`},
+				{Path: "body.elements[2].background_style", Rule: RuleDarkMode, Message: `color "rgba(0,0,0,1)" does not adapt to dark mode, use a color token or Card.SetCustomColor`},
+			},
+		},
+		{
+			name: "nesting too deep",
+			card: NewCard("2.0").
+				SetBody(&CardBody{Elements: []CardElement{
+					MapElement{"tag": "collapsible_panel", "elements": []CardElement{deep}},
+				}}),
+			want: []Issue{
+				{
+					Path:    "body.elements[0].elements[0].columns[0].elements[0].columns[0].elements[0]",
+					Rule:    RuleNestingDepth,
+					Message: "element is nested 6 levels deep, maximum is 5",
+				},
+			},
+		},
+		{
+			name: "too many buttons",
+			card: NewCard("1.0").
+				SetBody(&CardBody{Elements: []CardElement{
+					NewActionElement(ActionLayoutFlow, manyButtons...),
+				}}),
+			want: []Issue{
+				{Path: "body.elements[0]", Rule: RuleActionButtons, Message: "action has 6 buttons, maximum is 5"},
+			},
+		},
+		{
+			name: "too many elements",
+			card: NewCard("2.0").SetBody(&CardBody{Elements: manyElements}),
+			want: []Issue{
+				{Rule: RuleElementCount, Message: "card has 201 elements, maximum is 200"},
+			},
+		},
+		{
+			name: "callback elements",
+			card: NewCard("2.0").
+				SetBody(&CardBody{Elements: []CardElement{
+					NewFormElement("form", NewInputElement("Name")),
+					NewSelectPersonElement("Assignee", nil),
+				}}),
+			want: []Issue{
+				{
+					Path:    "body.elements[0]",
+					Rule:    RuleWebhookCallback,
+					Message: `element "form" requires interaction callbacks, which custom bot webhooks do not support`,
+				},
+				{
+					Path:    "body.elements[1]",
+					Rule:    RuleWebhookCallback,
+					Message: `element "select_person" requires interaction callbacks, which custom bot webhooks do not support`,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.card.Lint()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Card.Lint() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIssueString(t *testing.T) {
+	issue := Issue{Path: "body.elements[0]", Rule: RuleUnknownTag, Message: `unknown element tag "x"`}
+	if got, want := issue.String(), `body.elements[0]: unknown element tag "x"`; got != want {
+		t.Errorf("Issue.String() = %q, want %q", got, want)
+	}
+
+	issue = Issue{Rule: RuleElementCount, Message: "too many"}
+	if got, want := issue.String(), "too many"; got != want {
+		t.Errorf("Issue.String() = %q, want %q", got, want)
+	}
+}
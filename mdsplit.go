@@ -0,0 +1,275 @@
+package feishubot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markdownBlock is a paragraph or fenced code block of a markdown text.
+type markdownBlock struct {
+	text string
+	// fence is the fence marker, e.g. "```", if the block is a code block.
+	fence string
+	// open is the opening fence line including the info string, lines are
+	// the code lines and close is the closing fence line, empty if the
+	// block is not closed.
+	open  string
+	lines []string
+	close string
+}
+
+// SplitMarkdown splits markdown into chunks of at most maxBytes bytes each,
+// e.g. to stay within MaxMarkdownBytes per markdown element.
+//
+// Splits are made between paragraphs where possible and fenced code blocks
+// are kept whole. A code block too large for a single chunk is split between
+// lines, closing the fence at the end of each chunk and reopening it with
+// the same info string in the next, so that each chunk renders on its own.
+// Paragraphs too large for a single chunk are split like SplitText does, but
+// without continuation markers.
+//
+// Example:
+//
+//	for _, chunk := range feishubot.SplitMarkdown(releaseNotes, feishubot.MaxMarkdownBytes) {
+//		builder.Markdown(chunk)
+//	}
+func SplitMarkdown(s string, maxBytes int) []string {
+	if len(s) <= maxBytes || maxBytes <= 0 {
+		return []string{s}
+	}
+
+	var (
+		chunks  []string
+		current string
+	)
+	flush := func() {
+		if current != "" {
+			chunks = append(chunks, current)
+			current = ""
+		}
+	}
+
+	for _, block := range parseMarkdownBlocks(s) {
+		if current != "" && len(current)+len("\n\n")+len(block.text) <= maxBytes {
+			current += "\n\n" + block.text
+			continue
+		}
+		flush()
+		if len(block.text) <= maxBytes {
+			current = block.text
+			continue
+		}
+
+		var pieces []string
+		if block.fence != "" {
+			pieces = splitCodeBlock(block, maxBytes)
+		} else {
+			pieces = splitChunks(block.text, maxBytes)
+		}
+		chunks = append(chunks, pieces[:len(pieces)-1]...)
+		current = pieces[len(pieces)-1]
+	}
+	flush()
+
+	return chunks
+}
+
+// NewMarkdownElements splits markdown with SplitMarkdown into chunks of at
+// most MaxMarkdownBytes and returns one markdown element per chunk.
+//
+// Example:
+//
+//	card := feishubot.NewCardBuilder().
+//		Header("Release notes", feishubot.TemplateBlue).
+//		Element(feishubot.NewMarkdownElements(releaseNotes)...).
+//		Build()
+func NewMarkdownElements(s string) []CardElement {
+	chunks := SplitMarkdown(s, MaxMarkdownBytes)
+	elements := make([]CardElement, 0, len(chunks))
+	for _, chunk := range chunks {
+		elements = append(elements, NewMarkdownElement(chunk))
+	}
+	return elements
+}
+
+// NewMarkdownCards splits markdown into cards holding at most maxBytes of
+// markdown each, for text too long for a single message. Each card holds
+// one or more markdown elements created like NewMarkdownElements. When more
+// than one card is needed, titles end with "(i/n)".
+//
+// Example:
+//
+//	for _, card := range feishubot.NewMarkdownCards("Release notes", releaseNotes, 20000) {
+//		if _, err := client.Send(ctx, feishubot.NewInteractiveMessage(card)); err != nil {
+//			return err
+//		}
+//	}
+func NewMarkdownCards(title, s string, maxBytes int) []*Card {
+	elementBytes := MaxMarkdownBytes
+	if maxBytes > 0 && maxBytes < elementBytes {
+		elementBytes = maxBytes
+	}
+
+	var (
+		groups [][]string
+		size   int
+	)
+	for _, chunk := range SplitMarkdown(s, elementBytes) {
+		if len(groups) == 0 || (maxBytes > 0 && size+len(chunk) > maxBytes) {
+			groups = append(groups, nil)
+			size = 0
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], chunk)
+		size += len(chunk)
+	}
+
+	cards := make([]*Card, 0, len(groups))
+	for i, group := range groups {
+		builder := NewCardBuilder()
+		if title != "" {
+			cardTitle := title
+			if len(groups) > 1 {
+				cardTitle = fmt.Sprintf("%s (%d/%d)", title, i+1, len(groups))
+			}
+			builder.Header(cardTitle, TemplateBlue)
+		}
+		for _, chunk := range group {
+			builder.Markdown(chunk)
+		}
+		cards = append(cards, builder.Build())
+	}
+	return cards
+}
+
+// parseMarkdownBlocks splits markdown into paragraphs separated by blank
+// lines and fenced code blocks, which may contain blank lines.
+func parseMarkdownBlocks(s string) []markdownBlock {
+	var (
+		blocks    []markdownBlock
+		paragraph []string
+		code      *markdownBlock
+	)
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, markdownBlock{text: strings.Join(paragraph, "\n")})
+			paragraph = nil
+		}
+	}
+	flushCode := func() {
+		parts := append([]string{code.open}, code.lines...)
+		if code.close != "" {
+			parts = append(parts, code.close)
+		}
+		code.text = strings.Join(parts, "\n")
+		blocks = append(blocks, *code)
+		code = nil
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		if code != nil {
+			if isClosingFence(line, code.fence) {
+				code.close = line
+				flushCode()
+			} else {
+				code.lines = append(code.lines, line)
+			}
+			continue
+		}
+
+		if fence := openingFence(line); fence != "" {
+			flushParagraph()
+			code = &markdownBlock{fence: fence, open: line}
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			flushParagraph()
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+
+	if code != nil {
+		flushCode()
+	}
+	flushParagraph()
+
+	return blocks
+}
+
+// openingFence returns the fence marker if line opens a fenced code block,
+// i.e. starts with at least three backticks or tildes after up to three
+// spaces of indentation.
+func openingFence(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return ""
+	}
+
+	char := trimmed[0]
+	if char != '`' && char != '~' {
+		return ""
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == char {
+		n++
+	}
+	if n < 3 || (char == '`' && strings.ContainsRune(trimmed[n:], '`')) {
+		return ""
+	}
+	return trimmed[:n]
+}
+
+// isClosingFence reports whether line closes a code block opened with fence.
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == "" &&
+		len(line)-len(strings.TrimLeft(line, " ")) <= 3
+}
+
+// splitCodeBlock splits a code block into chunks of at most limit bytes,
+// each a complete code block. If not even the fences fit, the block is split
+// as plain text.
+func splitCodeBlock(block markdownBlock, limit int) []string {
+	closing := block.close
+	if closing == "" {
+		closing = block.fence
+	}
+	overhead := len(block.open) + len("\n") + len("\n") + len(closing)
+	if overhead >= limit {
+		return splitChunks(block.text, limit)
+	}
+
+	var (
+		chunks []string
+		lines  []string
+		size   int
+	)
+	flush := func() {
+		chunks = append(chunks, block.open+"\n"+strings.Join(lines, "\n")+"\n"+closing)
+		lines = nil
+		size = 0
+	}
+
+	for _, line := range block.lines {
+		// A single line longer than a chunk is cut into pieces
+		for _, piece := range splitChunks(line, limit-overhead) {
+			if len(lines) > 0 && overhead+size+len("\n")+len(piece) > limit {
+				flush()
+			}
+			if len(lines) > 0 {
+				size += len("\n")
+			}
+			lines = append(lines, piece)
+			size += len(piece)
+		}
+	}
+	if len(lines) > 0 {
+		flush()
+	}
+	if block.close == "" {
+		// Leave the last chunk unclosed like the original block
+		last := chunks[len(chunks)-1]
+		chunks[len(chunks)-1] = strings.TrimSuffix(last, "\n"+closing)
+	}
+	return chunks
+}
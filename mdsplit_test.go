@@ -0,0 +1,102 @@
+package feishubot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		maxBytes int
+		want     []string
+	}{
+		{
+			name:     "fits",
+			s:        "short",
+			maxBytes: 10,
+			want:     []string{"short"},
+		},
+		{
+			name:     "paragraphs",
+			s:        "first paragraph\n\nsecond\n\n\nthird",
+			maxBytes: 22,
+			want:     []string{"first paragraph", "second\n\nthird"},
+		},
+		{
+			name:     "code block kept whole",
+			s:        "intro\n\n```go\na := 1\n\nb := 2\n```\n\noutro",
+			maxBytes: 30,
+			want:     []string{"intro", "```go\na := 1\n\nb := 2\n```", "outro"},
+		},
+		{
+			name:     "long code block reopened",
+			s:        "```go\nline1\nline2\nline3\nline4\n```",
+			maxBytes: 24,
+			want: []string{
+				"```go\nline1\nline2\n```",
+				"```go\nline3\nline4\n```",
+			},
+		},
+		{
+			name:     "unclosed tilde fence",
+			s:        "~~~~\nline1\nline2\nline3",
+			maxBytes: 21,
+			want: []string{
+				"~~~~\nline1\nline2\n~~~~",
+				"~~~~\nline3",
+			},
+		},
+		{
+			name:     "long paragraph split on lines",
+			s:        "aaaa\nbbbb\ncccc",
+			maxBytes: 9,
+			want:     []string{"aaaa\nbbbb", "cccc"},
+		},
+		{
+			name:     "long code line cut",
+			s:        "```\n" + strings.Repeat("x", 12) + "\n```",
+			maxBytes: 14,
+			want:     []string{"```\nxxxxxx\n```", "```\nxxxxxx\n```"},
+		},
+		{
+			name:     "inline backticks do not open a fence",
+			s:        "```not a fence```\n\ntext",
+			maxBytes: 18,
+			want:     []string{"```not a fence```", "text"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitMarkdown(tt.s, tt.maxBytes)
+			require.Equal(t, tt.want, got)
+			for _, chunk := range got {
+				require.LessOrEqual(t, len(chunk), tt.maxBytes)
+			}
+		})
+	}
+}
+
+func TestNewMarkdownElements(t *testing.T) {
+	paragraph := strings.Repeat("a", MaxMarkdownBytes-10)
+	elements := NewMarkdownElements(paragraph + "\n\n" + paragraph)
+	require.Equal(t, []CardElement{NewMarkdownElement(paragraph), NewMarkdownElement(paragraph)}, elements)
+}
+
+func TestNewMarkdownCards(t *testing.T) {
+	cards := NewMarkdownCards("Notes", "one\n\ntwo\n\nthree", 8)
+	require.Len(t, cards, 2)
+	require.Equal(t, "Notes (1/2)", cards[0].Header.Title.Content)
+	require.Equal(t, []CardElement{NewMarkdownElement("one\n\ntwo")}, cards[0].Body.Elements)
+	require.Equal(t, "Notes (2/2)", cards[1].Header.Title.Content)
+	require.Equal(t, []CardElement{NewMarkdownElement("three")}, cards[1].Body.Elements)
+
+	cards = NewMarkdownCards("", "short", 0)
+	require.Len(t, cards, 1)
+	require.Nil(t, cards[0].Header)
+	require.Equal(t, []CardElement{NewMarkdownElement("short")}, cards[0].Body.Elements)
+}
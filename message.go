@@ -1,5 +1,7 @@
 package feishubot
 
+import "encoding/json"
+
 // MsgType represents the type of message to send.
 type MsgType string
 
@@ -30,17 +32,127 @@ const (
 	// LanguageEnUS represents English.
 	LanguageEnUS Language = "en_us"
 
+	// LanguageJaJP represents Japanese.
+	LanguageJaJP Language = "ja_jp"
+
+	// LanguageZhHK represents Traditional Chinese (Hong Kong).
+	LanguageZhHK Language = "zh_hk"
+
+	// LanguageZhTW represents Traditional Chinese (Taiwan).
+	LanguageZhTW Language = "zh_tw"
+
+	// LanguageIdID represents Indonesian.
+	LanguageIdID Language = "id_id"
+
+	// LanguageViVN represents Vietnamese.
+	LanguageViVN Language = "vi_vn"
+
+	// LanguageThTH represents Thai.
+	LanguageThTH Language = "th_th"
+
+	// LanguagePtBR represents Portuguese (Brazil).
+	LanguagePtBR Language = "pt_br"
+
+	// LanguageEsES represents Spanish.
+	LanguageEsES Language = "es_es"
+
+	// LanguageKoKR represents Korean.
+	LanguageKoKR Language = "ko_kr"
+
+	// LanguageDeDE represents German.
+	LanguageDeDE Language = "de_de"
+
+	// LanguageFrFR represents French.
+	LanguageFrFR Language = "fr_fr"
+
+	// LanguageItIT represents Italian.
+	LanguageItIT Language = "it_it"
+
+	// LanguageRuRU represents Russian.
+	LanguageRuRU Language = "ru_ru"
+
+	// LanguageMsMY represents Malay.
+	LanguageMsMY Language = "ms_my"
+
 	// LanguageJa represents Japanese.
-	LanguageJa Language = "ja"
+	//
+	// Deprecated: Use LanguageJaJP. Feishu does not accept "ja" as a language
+	// key, so this constant now has the same value as LanguageJaJP.
+	LanguageJa = LanguageJaJP
 )
 
 // Message represents a message to be sent to Feishu webhook.
 type Message struct {
-	MsgType   MsgType                `json:"msg_type"`
-	Content   map[string]interface{} `json:"content,omitempty"`
+	MsgType MsgType                `json:"msg_type"`
+	Content map[string]interface{} `json:"content,omitempty"`
+	// Card is the card of an interactive message created with
+	// NewInteractiveMessageFromMap. It is nil for messages created with
+	// NewInteractiveMessage, which keep their card in TypedCard; use CardMap
+	// to get the card of either as a map.
 	Card      map[string]interface{} `json:"card,omitempty"`
 	Timestamp int64                  `json:"timestamp,omitempty"`
 	Sign      string                 `json:"sign,omitempty"`
+
+	// TypedCard is the card of an interactive message created with
+	// NewInteractiveMessage. It is only serialized when the message is
+	// marshaled, so changes made to the card after creating the message
+	// take effect. If set, it takes precedence over Card.
+	TypedCard *Card `json:"-"`
+}
+
+// MarshalJSON encodes the message, serializing TypedCard if set.
+func (m Message) MarshalJSON() ([]byte, error) {
+	type alias Message
+	aux := struct {
+		alias
+		Card interface{} `json:"card,omitempty"`
+	}{alias: alias(m)}
+	if card := m.card(); card != nil {
+		aux.Card = card
+	}
+	return json.Marshal(aux)
+}
+
+// card returns the card of the message, TypedCard or Card, or nil if the
+// message has none.
+func (m *Message) card() interface{} {
+	if m.TypedCard != nil {
+		return m.TypedCard
+	}
+	if m.Card != nil {
+		return m.Card
+	}
+	return nil
+}
+
+// CardMap returns the card of the message as a map: TypedCard converted
+// with Card.ToMap if set, Card otherwise.
+func (m *Message) CardMap() map[string]interface{} {
+	if m.TypedCard != nil {
+		return m.TypedCard.ToMap()
+	}
+	return m.Card
+}
+
+// WithSignature sets an explicit timestamp and signature on the message and
+// returns it for chaining.
+//
+// Client.Send keeps a signature set this way instead of generating its own,
+// so payloads can be signed by a separate service holding the secret and
+// dispatched by a worker that never sees it.
+//
+// Example:
+//
+//	timestamp := time.Now().Unix()
+//	sign, err := feishubot.GenSign(secret, timestamp)
+//	if err != nil {
+//	    // handle error
+//	}
+//	message := feishubot.NewTextMessage("Hello").WithSignature(timestamp, sign)
+func (m *Message) WithSignature(timestamp int64, sign string) *Message {
+	m.Timestamp = timestamp
+	m.Sign = sign
+	return m
 }
 
 // PostContent represents the content of a rich text (post) message.
@@ -109,6 +221,30 @@ func NewEmoticonElement(emojiKey string) Element {
 	}
 }
 
+// NewMdElement creates a markdown element.
+// A markdown element occupies the whole paragraph and cannot be combined
+// with other elements in the same paragraph.
+func NewMdElement(text string) Element {
+	return map[string]interface{}{
+		"tag":  "md",
+		"text": text,
+	}
+}
+
+// NewCodeBlockElement creates a code block element.
+// The language is a highlighting hint such as "GO" or "PLAIN_TEXT";
+// if empty, "PLAIN_TEXT" is used.
+func NewCodeBlockElement(language, text string) Element {
+	if language == "" {
+		language = "PLAIN_TEXT"
+	}
+	return map[string]interface{}{
+		"tag":      "code_block",
+		"language": language,
+		"text":     text,
+	}
+}
+
 // NewParagraph creates a paragraph from elements.
 func NewParagraph(elements ...Element) Paragraph {
 	return Paragraph(elements)
@@ -247,27 +383,106 @@ type Card struct {
 	Config map[string]interface{} `json:"config,omitempty"`
 	Body   *CardBody              `json:"body,omitempty"`
 	Header *CardHeader            `json:"header,omitempty"`
+
+	I18nHeader   map[Language]*CardHeader   `json:"i18n_header,omitempty"`
+	I18nElements map[Language][]CardElement `json:"i18n_elements,omitempty"`
+
+	// RawHeader is a header given as raw JSON, see SetRawHeader. If set, it
+	// is used instead of Header.
+	RawHeader json.RawMessage `json:"-"`
+
+	// UnknownFields holds fields of parsed card JSON not modeled by Card.
+	UnknownFields UnknownFields `json:"-"`
 }
 
 // CardBody represents the body section of a card.
 type CardBody struct {
-	Direction string        `json:"direction,omitempty"`
-	Padding   string        `json:"padding,omitempty"`
+	Direction Direction     `json:"direction,omitempty"`
+	Padding   Padding       `json:"padding,omitempty"`
 	Elements  []CardElement `json:"elements"`
+
+	// UnknownFields holds fields of parsed card JSON not modeled by CardBody.
+	UnknownFields UnknownFields `json:"-"`
 }
 
 // CardHeader represents the header section of a card.
 type CardHeader struct {
-	Title     *CardTitle `json:"title"`
-	Subtitle  *CardTitle `json:"subtitle,omitempty"`
-	Template  string     `json:"template,omitempty"`
-	UiElement *CardTitle `json:"ui_element,omitempty"` // New API field
+	Title       *CardTitle `json:"title"`
+	Subtitle    *CardTitle `json:"subtitle,omitempty"`
+	Template    string     `json:"template,omitempty"`
+	UiElement   *CardTitle `json:"ui_element,omitempty"` // New API field
+	Icon        *CardIcon  `json:"icon,omitempty"`
+	TextTagList []TextTag  `json:"text_tag_list,omitempty"`
+
+	// UnknownFields holds fields of parsed card JSON not modeled by
+	// CardHeader.
+	UnknownFields UnknownFields `json:"-"`
+}
+
+// SetSubtitle sets a plain text subtitle shown below the title. An empty
+// subtitle removes it.
+func (h *CardHeader) SetSubtitle(subtitle string) *CardHeader {
+	h.Subtitle = nil
+	if subtitle != "" {
+		h.Subtitle = NewCardTitle(subtitle)
+	}
+	return h
+}
+
+// SetIcon sets the icon shown before the title.
+func (h *CardHeader) SetIcon(icon *CardIcon) *CardHeader {
+	h.Icon = icon
+	return h
+}
+
+// AddTextTags appends colored tags shown after the title.
+func (h *CardHeader) AddTextTags(tags ...TextTag) *CardHeader {
+	h.TextTagList = append(h.TextTagList, tags...)
+	return h
+}
+
+// Text tag colors for NewTextTag.
+const (
+	TextTagNeutral   = "neutral"
+	TextTagBlue      = "blue"
+	TextTagTurquoise = "turquoise"
+	TextTagLime      = "lime"
+	TextTagOrange    = "orange"
+	TextTagViolet    = "violet"
+	TextTagIndigo    = "indigo"
+	TextTagWathet    = "wathet"
+	TextTagGreen     = "green"
+	TextTagYellow    = "yellow"
+	TextTagRed       = "red"
+	TextTagPurple    = "purple"
+	TextTagCarmine   = "carmine"
+)
+
+// TextTag is a colored pill tag shown in the card header, e.g. "P1" or "PROD".
+type TextTag struct {
+	Tag   string     `json:"tag"`
+	Text  *CardTitle `json:"text"`
+	Color string     `json:"color,omitempty"`
+}
+
+// NewTextTag creates a header tag with the given text and color, such as
+// TextTagRed. An empty color uses the default color.
+func NewTextTag(text, color string) TextTag {
+	return TextTag{
+		Tag:   "text_tag",
+		Text:  NewCardTitle(text),
+		Color: color,
+	}
 }
 
 // CardTitle represents a title element (can be plain_text or lark_md).
 type CardTitle struct {
 	Tag     string `json:"tag"`
 	Content string `json:"content"`
+
+	// UnknownFields holds fields of parsed card JSON not modeled by
+	// CardTitle, such as i18n_content.
+	UnknownFields UnknownFields `json:"-"`
 }
 
 // NewCardTitle creates a plain text title element.
@@ -286,36 +501,47 @@ func NewCardMarkdownTitle(content string) *CardTitle {
 	}
 }
 
-// CardElement represents an element in the card body.
-type CardElement map[string]interface{}
-
 // NewMarkdownElement creates a markdown text element.
 func NewMarkdownElement(content string) CardElement {
-	return map[string]interface{}{
-		"tag":     "markdown",
-		"content": content,
+	return &MarkdownElement{
+		Content: content,
 	}
 }
 
 // NewDivElement creates a div element.
 func NewDivElement(text *CardTitle) CardElement {
-	return map[string]interface{}{
-		"tag":  "div",
-		"text": text,
+	return &Div{
+		Text: text,
 	}
 }
 
+// NewHrCardElement creates a divider (hr) element separating card sections.
+func NewHrCardElement() CardElement {
+	return &HrElement{}
+}
+
 // NewButtonElement creates a button element.
-func NewButtonElement(text, buttonType string, url string) CardElement {
-	return map[string]interface{}{
-		"tag": "button",
-		"text": map[string]interface{}{
-			"tag":     "plain_text",
-			"content": text,
-		},
-		"type": buttonType,
-		"url":  url,
+// Optional settings such as icon, size, width and disabled state are applied
+// with ButtonOption values.
+//
+// Example:
+//
+//	button := feishubot.NewButtonElement("Logs", "default", "https://example.com/logs",
+//		feishubot.WithButtonIcon(feishubot.NewStandardIcon("file-link-text_outlined", "")),
+//		feishubot.WithButtonSize(feishubot.ButtonSizeSmall),
+//	)
+func NewButtonElement(text, buttonType string, url string, opts ...ButtonOption) *ButtonElement {
+	button := &ButtonElement{
+		Text: NewCardTitle(text),
+		Type: buttonType,
+		URL:  url,
 	}
+
+	for _, opt := range opts {
+		opt(button)
+	}
+
+	return button
 }
 
 // NewCard creates a Card with the given parameters.
@@ -343,6 +569,73 @@ func (c *Card) SetHeader(header *CardHeader) *Card {
 	return c
 }
 
+// SetI18nHeader sets a header per language. Feishu shows the header
+// matching the viewer's locale.
+//
+// Example:
+//
+//	card.SetI18nHeader(map[feishubot.Language]*feishubot.CardHeader{
+//		feishubot.LanguageZhCN: {Title: feishubot.NewCardTitle("部署完成")},
+//		feishubot.LanguageEnUS: {Title: feishubot.NewCardTitle("Deploy finished")},
+//	})
+func (c *Card) SetI18nHeader(headers map[Language]*CardHeader) *Card {
+	c.I18nHeader = headers
+	return c
+}
+
+// SetI18nBody sets the body elements per language, producing the
+// i18n_elements structure. Only the elements are localized; layout settings
+// such as direction and padding are taken from the card body set with
+// SetBody.
+func (c *Card) SetI18nBody(bodies map[Language]*CardBody) *Card {
+	c.I18nElements = make(map[Language][]CardElement, len(bodies))
+	for lang, body := range bodies {
+		if body == nil {
+			continue
+		}
+		c.I18nElements[lang] = body.Elements
+	}
+	return c
+}
+
+// SetSummary sets the text shown in the chat list and push notifications
+// instead of the default "[Card]" placeholder.
+func (c *Card) SetSummary(text string) *Card {
+	c.summary()["content"] = text
+	return c
+}
+
+// SetI18nSummary sets the notification summary per language.
+func (c *Card) SetI18nSummary(texts map[Language]string) *Card {
+	c.summary()["i18n_content"] = texts
+	return c
+}
+
+// SetUpdateMulti sets whether the card is shared by all recipients, so that
+// updates made with APIClient.UpdateCard are seen by everyone in the chat.
+// Only shared cards can be updated; schema 2.0 cards are shared by default.
+func (c *Card) SetUpdateMulti(enabled bool) *Card {
+	if c.Config == nil {
+		c.Config = map[string]interface{}{}
+	}
+	c.Config["update_multi"] = enabled
+	return c
+}
+
+// summary returns the summary section of the card config, creating the
+// config and summary as needed.
+func (c *Card) summary() map[string]interface{} {
+	if c.Config == nil {
+		c.Config = map[string]interface{}{}
+	}
+	summary, ok := c.Config["summary"].(map[string]interface{})
+	if !ok {
+		summary = map[string]interface{}{}
+		c.Config["summary"] = summary
+	}
+	return summary
+}
+
 // ToMap converts the Card to a map for JSON serialization.
 func (c *Card) ToMap() map[string]interface{} {
 	result := map[string]interface{}{
@@ -355,9 +648,16 @@ func (c *Card) ToMap() map[string]interface{} {
 	if c.Body != nil {
 		result["body"] = c.Body
 	}
-	if c.Header != nil {
-		result["header"] = c.Header
+	if header := c.header(); header != nil {
+		result["header"] = header
+	}
+	if len(c.I18nHeader) > 0 {
+		result["i18n_header"] = c.I18nHeader
 	}
+	if len(c.I18nElements) > 0 {
+		result["i18n_elements"] = c.I18nElements
+	}
+	addUnknown(result, c.UnknownFields)
 
 	return result
 }
@@ -377,10 +677,14 @@ func (c *Card) ToMap() map[string]interface{} {
 //			},
 //		})
 //	message := feishubot.NewInteractiveMessage(card)
+//
+// The card is serialized when the message is sent, so it can still be
+// modified after creating the message. It is kept in Message.TypedCard,
+// leaving Message.Card nil; see Message.CardMap.
 func NewInteractiveMessage(card *Card) *Message {
 	return &Message{
-		MsgType: MsgTypeInteractive,
-		Card:    card.ToMap(),
+		MsgType:   MsgTypeInteractive,
+		TypedCard: card,
 	}
 }
 
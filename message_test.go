@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewTextMessage(t *testing.T) {
@@ -386,6 +387,21 @@ func TestPostElements(t *testing.T) {
 			elem: NewImageElement("img_key_123"),
 			want: []string{`"tag":"img"`, `"image_key":"img_key_123"`},
 		},
+		{
+			name: "md element",
+			elem: NewMdElement("**bold**"),
+			want: []string{`"tag":"md"`, `"text":"**bold**"`},
+		},
+		{
+			name: "code block element",
+			elem: NewCodeBlockElement("GO", "fmt.Println()"),
+			want: []string{`"tag":"code_block"`, `"language":"GO"`, `"text":"fmt.Println()"`},
+		},
+		{
+			name: "code block element default language",
+			elem: NewCodeBlockElement("", "plain"),
+			want: []string{`"language":"PLAIN_TEXT"`},
+		},
 		{
 			name: "emoticon element",
 			elem: NewEmoticonElement("emoji_key_123"),
@@ -417,6 +433,11 @@ func TestCardElements(t *testing.T) {
 			elem: NewMarkdownElement("Hello **World**"),
 			want: []string{`"tag":"markdown"`, `"content":"Hello **World**"`},
 		},
+		{
+			name: "hr element",
+			elem: NewHrCardElement(),
+			want: []string{`{"tag":"hr"}`},
+		},
 		{
 			name: "button element",
 			elem: NewButtonElement("Click Me", "primary", "https://example.com"),
@@ -467,6 +488,50 @@ func TestCardBuilder(t *testing.T) {
 				}),
 			want: []string{`"schema":"2.0"`, `"tag":"plain_text"`, `"content":"Title"`, `"template":"blue"`, `"content":"Content"`},
 		},
+		{
+			name: "card with header icon and tags",
+			card: NewCard("2.0").
+				SetHeader(&CardHeader{
+					Title:    NewCardTitle("Alert"),
+					Template: "red",
+					Icon:     NewStandardIcon("alarm_outlined", "red"),
+					TextTagList: []TextTag{
+						NewTextTag("P1", "red"),
+						NewTextTag("PROD", "neutral"),
+					},
+				}),
+			want: []string{
+				`"icon":{"tag":"standard_icon","token":"alarm_outlined","color":"red"}`,
+				`"text_tag_list":[{"tag":"text_tag","text":{"tag":"plain_text","content":"P1"},"color":"red"},{"tag":"text_tag","text":{"tag":"plain_text","content":"PROD"},"color":"neutral"}]`,
+			},
+		},
+		{
+			name: "card with i18n header and body",
+			card: NewCard("2.0").
+				SetI18nHeader(map[Language]*CardHeader{
+					LanguageZhCN: {Title: NewCardTitle("部署完成")},
+					LanguageEnUS: {Title: NewCardTitle("Deploy finished")},
+				}).
+				SetI18nBody(map[Language]*CardBody{
+					LanguageZhCN: {Elements: []CardElement{NewMarkdownElement("版本 v1.2.0")}},
+					LanguageEnUS: {Elements: []CardElement{NewMarkdownElement("Version v1.2.0")}},
+				}),
+			want: []string{
+				`"i18n_header":{"en_us":{"title":{"tag":"plain_text","content":"Deploy finished"}},"zh_cn":{"title":{"tag":"plain_text","content":"部署完成"}}}`,
+				`"i18n_elements":{"en_us":[{"tag":"markdown","content":"Version v1.2.0"}],"zh_cn":[{"tag":"markdown","content":"版本 v1.2.0"}]}`,
+			},
+		},
+		{
+			name: "card with summary",
+			card: NewCard("2.0").
+				SetConfig(map[string]any{"update_multi": true}).
+				SetSummary("Deploy finished").
+				SetI18nSummary(map[Language]string{LanguageZhCN: "部署完成"}),
+			want: []string{
+				`"update_multi":true`,
+				`"summary":{"content":"Deploy finished","i18n_content":{"zh_cn":"部署完成"}}`,
+			},
+		},
 		{
 			name: "card with config",
 			card: NewCard("2.0").
@@ -637,7 +702,8 @@ func TestLanguageString(t *testing.T) {
 	}{
 		{"Simplified Chinese", LanguageZhCN, "zh_cn"},
 		{"English", LanguageEnUS, "en_us"},
-		{"Japanese", LanguageJa, "ja"},
+		{"Japanese", LanguageJaJP, "ja_jp"},
+		{"Japanese (deprecated alias)", LanguageJa, "ja_jp"},
 	}
 
 	for _, tt := range tests {
@@ -722,7 +788,7 @@ func TestInteractiveCardMessage(t *testing.T) {
 
 	message := NewInteractiveMessage(card)
 
-	data, err := json.Marshal(message.Card)
+	data, err := json.Marshal(message.CardMap())
 	if err != nil {
 		t.Fatalf("json.Marshal() error = %v", err)
 	}
@@ -744,3 +810,53 @@ func TestInteractiveCardMessage(t *testing.T) {
 		}
 	}
 }
+
+func TestInteractiveMessageLazyCard(t *testing.T) {
+	card := NewCard("2.0").SetBody(&CardBody{
+		Elements: []CardElement{NewMarkdownElement("before")},
+	})
+	message := NewInteractiveMessage(card)
+
+	// Changes after creating the message take effect
+	card.SetHeader(&CardHeader{Title: NewCardTitle("Title")})
+	card.Body.Elements[0].(*MarkdownElement).Content = "after"
+
+	data, err := json.Marshal(message)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"msg_type": "interactive",
+		"card": {
+			"schema": "2.0",
+			"header": {"title": {"tag": "plain_text", "content": "Title"}},
+			"body": {"elements": [{"tag": "markdown", "content": "after"}]}
+		}
+	}`, string(data))
+
+	// Marshaling a message value works the same
+	valueData, err := json.Marshal(*message)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(valueData))
+
+	require.Nil(t, message.Card)
+	require.Equal(t, card.ToMap(), message.CardMap())
+	require.Equal(t, map[string]interface{}{"k": "v"}, NewInteractiveMessageFromMap(map[string]interface{}{"k": "v"}).CardMap())
+
+	// Messages without a card omit the field
+	data, err = json.Marshal(NewTextMessage("hello"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"msg_type":"text","content":{"text":"hello"}}`, string(data))
+}
+
+func TestCardHeaderSetters(t *testing.T) {
+	header := (&CardHeader{Title: NewCardTitle("Title")}).
+		SetSubtitle("sub").
+		SetIcon(NewStandardIcon("info_outlined", "")).
+		AddTextTags(NewTextTag("P1", TextTagRed)).
+		AddTextTags(NewTextTag("PROD", TextTagNeutral))
+
+	require.Equal(t, NewCardTitle("sub"), header.Subtitle)
+	require.Equal(t, NewStandardIcon("info_outlined", ""), header.Icon)
+	require.Equal(t, []TextTag{NewTextTag("P1", "red"), NewTextTag("PROD", "neutral")}, header.TextTagList)
+
+	require.Nil(t, header.SetSubtitle("").Subtitle)
+}
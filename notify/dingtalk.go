@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// dingTalkMaxBytes is the maximum size of DingTalk markdown messages.
+const dingTalkMaxBytes = 20000
+
+// dingTalkColors are the font colors of the levels.
+var dingTalkColors = map[Level]string{
+	LevelSuccess: "#2EA121",
+	LevelWarning: "#FF8800",
+	LevelError:   "#F54A45",
+}
+
+// DingTalk is a Notifier posting notifications as markdown to a DingTalk
+// custom robot webhook.
+//
+// See: https://open.dingtalk.com/document/robots/custom-robot-access
+type DingTalk struct {
+	// WebhookURL is the URL of the robot, with its access token.
+	WebhookURL string
+	// Secret signs requests if the robot has signing enabled.
+	Secret     string
+	HTTPClient feishubot.HTTPClient
+
+	now func() time.Time
+}
+
+// NewDingTalk creates a notifier posting to the robot webhook URL, such as
+// "https://oapi.dingtalk.com/robot/send?access_token=...", signing requests
+// with secret unless it is empty. The default HTTP client has a 30 second
+// timeout.
+func NewDingTalk(webhookURL, secret string) *DingTalk {
+	return &DingTalk{
+		WebhookURL: webhookURL,
+		Secret:     secret,
+		HTTPClient: defaultHTTPClient(),
+		now:        time.Now,
+	}
+}
+
+// Notify implements Notifier. Messages beyond the DingTalk limit of 20000
+// bytes are truncated.
+func (d *DingTalk) Notify(ctx context.Context, n *Notification) error {
+	title := n.Title
+	if color := dingTalkColors[n.Level]; color != "" {
+		title = `<font color="` + color + `">` + title + "</font>"
+	}
+	// The title is shown in notifications and the conversation list
+	summary := n.Title
+	if summary == "" {
+		summary = feishubot.TruncateText(n.Text, 64)
+	}
+	body := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": summary,
+			"text":  feishubot.TruncateText(n.markdown(title), dingTalkMaxBytes),
+		},
+	}
+
+	webhookURL := d.WebhookURL
+	if d.Secret != "" {
+		webhookURL = d.signedURL()
+	}
+	if err := postWebhook(ctx, d.HTTPClient, webhookURL, body); err != nil {
+		return fmt.Errorf("failed to notify via DingTalk: %w", err)
+	}
+	return nil
+}
+
+// signedURL returns the webhook URL with the timestamp and signature
+// parameters: the base64 encoded HMAC-SHA256 of the timestamp in
+// milliseconds and the secret, separated by a newline, keyed with the
+// secret.
+func (d *DingTalk) signedURL() string {
+	now := time.Now
+	if d.now != nil {
+		now = d.now
+	}
+	timestamp := strconv.FormatInt(now().UnixMilli(), 10)
+
+	mac := hmac.New(sha256.New, []byte(d.Secret))
+	mac.Write([]byte(timestamp + "\n" + d.Secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	separator := "?"
+	if strings.Contains(d.WebhookURL, "?") {
+		separator = "&"
+	}
+	return d.WebhookURL + separator + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(sign)
+}
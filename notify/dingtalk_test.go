@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDingTalk(t *testing.T) {
+	var (
+		query url.Values
+		body  map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if query.Get("access_token") != "t" {
+			_, _ = w.Write([]byte(`{"errcode":300001,"errmsg":"token is not exist"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	notifier := NewDingTalk(server.URL+"/robot/send?access_token=t", "SEC123")
+	notifier.now = func() time.Time { return time.UnixMilli(1700000000123) }
+	require.NoError(t, notifier.Notify(context.Background(), &Notification{
+		Title: "Backup finished",
+		Text:  "12 GB in 4m",
+		Level: LevelSuccess,
+	}))
+
+	mac := hmac.New(sha256.New, []byte("SEC123"))
+	mac.Write([]byte("1700000000123\nSEC123"))
+	require.Equal(t, "1700000000123", query.Get("timestamp"))
+	require.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), query.Get("sign"))
+	require.Equal(t, map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]interface{}{
+			"title": "Backup finished",
+			"text":  "### <font color=\"#2EA121\">Backup finished</font>\n12 GB in 4m",
+		},
+	}, body)
+
+	// Unsigned, titled by the text
+	notifier = NewDingTalk(server.URL+"/robot/send?access_token=t", "")
+	require.NoError(t, notifier.Notify(context.Background(), &Notification{Text: "Disk usage at 91%"}))
+	require.Empty(t, query.Get("sign"))
+	require.Equal(t, "Disk usage at 91%", body["markdown"].(map[string]interface{})["title"])
+
+	notifier = NewDingTalk(server.URL+"/robot/send?access_token=x", "")
+	err := notifier.Notify(context.Background(), &Notification{Text: "hi"})
+	require.EqualError(t, err, "failed to notify via DingTalk: API error (code 300001): token is not exist")
+}
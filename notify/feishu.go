@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Feishu is a Notifier posting notifications to Feishu as cards.
+type Feishu struct {
+	send feishubot.SendFunc
+}
+
+// NewFeishu creates a notifier posting cards with send.
+func NewFeishu(send feishubot.SendFunc) *Feishu {
+	return &Feishu{send: send}
+}
+
+// Notify implements Notifier.
+func (f *Feishu) Notify(ctx context.Context, n *Notification) error {
+	if err := f.send(ctx, feishubot.NewInteractiveMessage(n.Card())); err != nil {
+		return fmt.Errorf("failed to notify via Feishu: %w", err)
+	}
+	return nil
+}
+
+// Card renders the notification as a Feishu card, with a header colored by
+// level and the links as buttons.
+func (n *Notification) Card() *feishubot.Card {
+	builder := feishubot.NewCardBuilder()
+	if n.Title != "" {
+		template, ok := levelTemplates[n.Level]
+		if !ok {
+			template = feishubot.TemplateBlue
+		}
+		builder.Header(n.Title, template)
+	}
+	if n.Text != "" {
+		builder.Markdown(n.Text)
+	}
+	if len(n.Fields) > 0 {
+		builder.Fields(n.Fields)
+	}
+	if len(n.Links) > 0 {
+		buttons := make([]feishubot.CardElement, 0, len(n.Links))
+		for i, link := range n.Links {
+			buttonType := "default"
+			if i == 0 {
+				buttonType = "primary"
+			}
+			buttons = append(buttons, feishubot.NewButtonElement(link.Text, buttonType, link.URL))
+		}
+		builder.Actions(buttons...)
+	}
+	return builder.Build()
+}
+
+// levelTemplates are the card header colors of the levels.
+var levelTemplates = map[Level]string{
+	LevelInfo:    feishubot.TemplateBlue,
+	LevelSuccess: feishubot.TemplateGreen,
+	LevelWarning: feishubot.TemplateOrange,
+	LevelError:   feishubot.TemplateRed,
+}
@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func testNotification() *Notification {
+	return &Notification{
+		Title: "Deploy failed",
+		Text:  "**api-gateway** v1.4.2 rolled back",
+		Level: LevelError,
+		Fields: []feishubot.KV{
+			{Label: "Cluster", Value: "prod"},
+			{Label: "Build", Value: "#42", URL: "https://ci.example.com/42"},
+		},
+		Links: []Link{
+			{Text: "Logs", URL: "https://logs.example.com"},
+			{Text: "Runbook", URL: "https://wiki.example.com/runbook"},
+		},
+	}
+}
+
+func TestFeishu(t *testing.T) {
+	var sent *feishubot.Message
+	notifier := NewFeishu(func(_ context.Context, msg *feishubot.Message) error {
+		sent = msg
+		return nil
+	})
+	require.NoError(t, notifier.Notify(context.Background(), testNotification()))
+
+	data, err := json.Marshal(sent)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"msg_type": "interactive",
+		"card": {
+			"schema": "2.0",
+			"header": {"title": {"tag": "plain_text", "content": "Deploy failed"}, "template": "red"},
+			"body": {"elements": [
+				{"tag": "markdown", "content": "**api-gateway** v1.4.2 rolled back"},
+				{"tag": "div", "fields": [
+					{"is_short": true, "text": {"tag": "lark_md", "content": "**Cluster:** prod"}},
+					{"is_short": true, "text": {"tag": "lark_md", "content": "**Build:** [#42](https://ci.example.com/42)"}}
+				]},
+				{"tag": "column_set", "columns": [
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+						{"tag": "button", "text": {"tag": "plain_text", "content": "Logs"}, "type": "primary", "url": "https://logs.example.com", "width": "fill"}
+					]},
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+						{"tag": "button", "text": {"tag": "plain_text", "content": "Runbook"}, "type": "default", "url": "https://wiki.example.com/runbook", "width": "fill"}
+					]}
+				]}
+			]}
+		}
+	}`, string(data))
+
+	notifier = NewFeishu(func(context.Context, *feishubot.Message) error {
+		return errors.New("rate limited")
+	})
+	err = notifier.Notify(context.Background(), &Notification{Text: "hi"})
+	require.EqualError(t, err, "failed to notify via Feishu: rate limited")
+}
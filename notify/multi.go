@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// MultiError is returned by MultiNotifier when some notifiers fail.
+type MultiError struct {
+	Errors []error
+}
+
+// Error implements error, joining the messages of the errors.
+func (e *MultiError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors, for errors.Is and errors.As.
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// MultiNotifier is a Notifier fanning notifications out to several
+// notifiers, e.g. one per provider used in an organization.
+type MultiNotifier struct {
+	notifiers []Notifier
+}
+
+// NewMultiNotifier creates a notifier sending to all notifiers.
+func NewMultiNotifier(notifiers ...Notifier) *MultiNotifier {
+	return &MultiNotifier{notifiers: notifiers}
+}
+
+// Add adds notifiers to fan out to. It must not be called concurrently
+// with Notify.
+func (m *MultiNotifier) Add(notifiers ...Notifier) {
+	m.notifiers = append(m.notifiers, notifiers...)
+}
+
+// Notify implements Notifier. The notifiers are called concurrently, and a
+// failing notifier does not keep the notification from the others; their
+// errors are returned as a *MultiError, in the order of the notifiers.
+func (m *MultiNotifier) Notify(ctx context.Context, n *Notification) error {
+	errs := make([]error, len(m.notifiers))
+	var wg sync.WaitGroup
+	for i, notifier := range m.notifiers {
+		wg.Add(1)
+		go func(i int, notifier Notifier) {
+			defer wg.Done()
+			errs[i] = notifier.Notify(ctx, n)
+		}(i, notifier)
+	}
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) > 0 {
+		return &MultiError{Errors: failed}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+func TestMultiNotifier(t *testing.T) {
+	var calls int32
+	ok := NotifierFunc(func(context.Context, *Notification) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	failing := func(provider string) Notifier {
+		return NotifierFunc(func(context.Context, *Notification) error {
+			atomic.AddInt32(&calls, 1)
+			return fmt.Errorf("failed to notify via %s: %w", provider, errUnavailable)
+		})
+	}
+
+	notifier := NewMultiNotifier(ok, ok)
+	require.NoError(t, notifier.Notify(context.Background(), &Notification{Text: "hi"}))
+	require.Equal(t, int32(2), calls)
+
+	notifier.Add(failing("WeCom"), ok, failing("DingTalk"))
+	err := notifier.Notify(context.Background(), &Notification{Text: "hi"})
+	require.Equal(t, int32(7), calls)
+	require.EqualError(t, err, "failed to notify via WeCom: unavailable; failed to notify via DingTalk: unavailable")
+	require.ErrorIs(t, err, errUnavailable)
+
+	var multiErr *MultiError
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors, 2)
+
+	require.NoError(t, NewMultiNotifier().Notify(context.Background(), &Notification{}))
+}
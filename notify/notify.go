@@ -0,0 +1,110 @@
+// Package notify sends notifications to several instant messaging
+// providers through a common Notifier interface, for organizations using
+// more than one of them. A Notification is rendered for each provider:
+// as a card on Feishu, and as markdown on WeCom and DingTalk.
+//
+// Example:
+//
+//	notifier := notify.NewMultiNotifier(
+//		notify.NewFeishu(feishubot.WebhookSender(feishubot.NewClient(feishuURL, feishuSecret))),
+//		notify.NewWeCom(wecomURL),
+//		notify.NewDingTalk(dingtalkURL, dingtalkSecret),
+//	)
+//	err := notifier.Notify(ctx, &notify.Notification{
+//		Title: "Deploy finished",
+//		Text:  "**api-gateway** v1.4.2 is live",
+//		Level: notify.LevelSuccess,
+//	})
+package notify
+
+import (
+	"context"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Level is the severity of a notification, rendered as its color.
+type Level int
+
+const (
+	LevelInfo Level = iota
+	LevelSuccess
+	LevelWarning
+	LevelError
+)
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelSuccess:
+		return "success"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	}
+	return "info"
+}
+
+// Link is a link shown below a notification.
+type Link struct {
+	Text string
+	URL  string
+}
+
+// Notification is a message in a form that every provider can render.
+type Notification struct {
+	Title string
+	// Text is the body in markdown, limited to bold, italics, links and
+	// lists, which all providers support.
+	Text  string
+	Level Level
+	// Fields are label/value pairs shown below the text.
+	Fields []feishubot.KV
+	// Links are shown last, as buttons where supported.
+	Links []Link
+}
+
+// Notifier sends notifications to an instant messaging provider.
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface.
+type NotifierFunc func(ctx context.Context, n *Notification) error
+
+// Notify calls f.
+func (f NotifierFunc) Notify(ctx context.Context, n *Notification) error {
+	return f(ctx, n)
+}
+
+// markdown renders n as markdown for providers without cards, with the
+// title rendered by title.
+func (n *Notification) markdown(title string) string {
+	var b strings.Builder
+	if n.Title != "" {
+		b.WriteString("### " + title + "\n")
+	}
+	if n.Text != "" {
+		b.WriteString(n.Text + "\n")
+	}
+	if len(n.Fields) > 0 {
+		b.WriteString("\n")
+		for _, kv := range n.Fields {
+			if kv.URL != "" {
+				b.WriteString("**" + kv.Label + ":** [" + kv.Value + "](" + kv.URL + ")\n")
+				continue
+			}
+			b.WriteString("**" + kv.Label + ":** " + kv.Value + "\n")
+		}
+	}
+	if len(n.Links) > 0 {
+		links := make([]string, 0, len(n.Links))
+		for _, link := range n.Links {
+			links = append(links, "["+link.Text+"]("+link.URL+")")
+		}
+		b.WriteString("\n" + strings.Join(links, " | ") + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
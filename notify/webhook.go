@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// maxResponseBytes is the maximum size of webhook responses read.
+const maxResponseBytes = 64 << 10
+
+// defaultHTTPClient returns the HTTP client of new notifiers.
+func defaultHTTPClient() feishubot.HTTPClient {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// postWebhook posts body as JSON to a WeCom or DingTalk webhook, which both
+// answer with an error code and message.
+func postWebhook(ctx context.Context, client feishubot.HTTPClient, url string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("failed to unmarshal response (status %d): %w", resp.StatusCode, err)
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("API error (code %d): %s", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// wecomMaxBytes is the maximum size of WeCom markdown messages.
+const wecomMaxBytes = 4096
+
+// wecomColors are the WeCom font colors of the levels: "info" is green and
+// "warning" orange.
+var wecomColors = map[Level]string{
+	LevelSuccess: "info",
+	LevelWarning: "warning",
+	LevelError:   "warning",
+}
+
+// WeCom is a Notifier posting notifications as markdown to a WeCom (WeChat
+// Work) group bot webhook.
+//
+// See: https://developer.work.weixin.qq.com/document/path/91770
+type WeCom struct {
+	// WebhookURL is the URL of the group bot, with its key.
+	WebhookURL string
+	HTTPClient feishubot.HTTPClient
+}
+
+// NewWeCom creates a notifier posting to the group bot webhook URL, such as
+// "https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=...". The default
+// HTTP client has a 30 second timeout.
+func NewWeCom(webhookURL string) *WeCom {
+	return &WeCom{WebhookURL: webhookURL, HTTPClient: defaultHTTPClient()}
+}
+
+// Notify implements Notifier. Messages beyond the WeCom limit of 4096 bytes
+// are truncated.
+func (w *WeCom) Notify(ctx context.Context, n *Notification) error {
+	title := n.Title
+	if color := wecomColors[n.Level]; color != "" {
+		title = `<font color="` + color + `">` + title + "</font>"
+	}
+	body := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"content": feishubot.TruncateText(n.markdown(title), wecomMaxBytes),
+		},
+	}
+	if err := postWebhook(ctx, w.HTTPClient, w.WebhookURL, body); err != nil {
+		return fmt.Errorf("failed to notify via WeCom: %w", err)
+	}
+	return nil
+}
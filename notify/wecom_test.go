@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeCom(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "k", r.URL.Query().Get("key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if strings.Contains(body["markdown"].(map[string]interface{})["content"].(string), "invalid") {
+			_, _ = w.Write([]byte(`{"errcode":93000,"errmsg":"invalid webhook url"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	notifier := NewWeCom(server.URL + "/cgi-bin/webhook/send?key=k")
+	require.NoError(t, notifier.Notify(context.Background(), testNotification()))
+	require.Equal(t, map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]interface{}{
+			"content": "### <font color=\"warning\">Deploy failed</font>\n" +
+				"**api-gateway** v1.4.2 rolled back\n" +
+				"\n" +
+				"**Cluster:** prod\n" +
+				"**Build:** [#42](https://ci.example.com/42)\n" +
+				"\n" +
+				"[Logs](https://logs.example.com) | [Runbook](https://wiki.example.com/runbook)",
+		},
+	}, body)
+
+	require.NoError(t, notifier.Notify(context.Background(), &Notification{Text: strings.Repeat("x", 5000)}))
+	require.LessOrEqual(t, len(body["markdown"].(map[string]interface{})["content"].(string)), wecomMaxBytes)
+
+	err := notifier.Notify(context.Background(), &Notification{Title: "invalid"})
+	require.EqualError(t, err, "failed to notify via WeCom: API error (code 93000): invalid webhook url")
+}
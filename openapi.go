@@ -0,0 +1,233 @@
+package feishubot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Domains of the Open API, see Client.SetDomain and APIClient.SetDomain.
+const (
+	// DomainFeishu is the domain of Feishu, used by default.
+	DomainFeishu = "https://open.feishu.cn"
+	// DomainLark is the domain of Lark, the international version of
+	// Feishu.
+	DomainLark = "https://open.larksuite.com"
+)
+
+// DefaultOpenAPIBaseURL is the base URL of the Feishu Open API.
+const DefaultOpenAPIBaseURL = DomainFeishu + "/open-apis"
+
+// OpenAPIBaseURL returns the base URL of the Open API served at domain, such
+// as DomainLark or the domain of a private deployment. A domain without
+// scheme uses https.
+func OpenAPIBaseURL(domain string) string {
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	return strings.TrimSuffix(domain, "/") + "/open-apis"
+}
+
+// tokenRefreshMargin is how long before its expiry a tenant access token is
+// refreshed.
+const tokenRefreshMargin = 5 * time.Minute
+
+// ErrNoAppCredentials is returned by operations that need the Open API, such
+// as uploading images, when no app credentials are configured.
+var ErrNoAppCredentials = errors.New("no app credentials configured")
+
+// tokenCache caches the tenant access token of an app.
+type tokenCache struct {
+	mu        sync.Mutex
+	key       string
+	token     string
+	expiresAt time.Time
+	now       func() time.Time
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{now: time.Now}
+}
+
+// openAPI calls the Open API as an app. It is built from the fields of
+// Client and APIClient on each call, so that changes to them take effect.
+type openAPI struct {
+	appID      string
+	appSecret  string
+	baseURL    string
+	httpClient HTTPClient
+	// tokens caches tokens across calls; if nil, a token is requested for
+	// each call.
+	tokens *tokenCache
+}
+
+// url returns the URL of an Open API path such as "/im/v1/images".
+func (a *openAPI) url(path string) string {
+	base := a.baseURL
+	if base == "" {
+		base = DefaultOpenAPIBaseURL
+	}
+	return strings.TrimSuffix(base, "/") + path
+}
+
+// tenantAccessToken returns a tenant access token of the app, cached until
+// shortly before it expires.
+func (a *openAPI) tenantAccessToken(ctx context.Context) (string, error) {
+	if a.appID == "" || a.appSecret == "" {
+		return "", ErrNoAppCredentials
+	}
+	if a.tokens == nil {
+		token, _, err := a.fetchTenantAccessToken(ctx)
+		return token, err
+	}
+
+	cache := a.tokens
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	key := a.appID + "\x00" + a.appSecret + "\x00" + a.url("")
+	if cache.key == key && cache.now().Before(cache.expiresAt) {
+		return cache.token, nil
+	}
+
+	token, expire, err := a.fetchTenantAccessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	cache.key = key
+	cache.token = token
+	cache.expiresAt = cache.now().Add(expire - tokenRefreshMargin)
+	return token, nil
+}
+
+// fetchTenantAccessToken requests a new tenant access token and returns it
+// with its lifetime.
+func (a *openAPI) fetchTenantAccessToken(ctx context.Context) (string, time.Duration, error) {
+	body, err := json.Marshal(map[string]string{
+		"app_id":     a.appID,
+		"app_secret": a.appSecret,
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to marshal token request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url("/auth/v3/tenant_access_token/internal"), bytes.NewReader(body))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	var resp struct {
+		Code              int    `json:"code"`
+		Msg               string `json:"msg"`
+		TenantAccessToken string `json:"tenant_access_token"`
+		Expire            int    `json:"expire"`
+	}
+	if err := a.doJSON(req, &resp); err != nil {
+		return "", 0, fmt.Errorf("failed to get tenant access token: %w", err)
+	}
+	if resp.Code != 0 {
+		return "", 0, fmt.Errorf("failed to get tenant access token: API error (code %d): %s", resp.Code, resp.Msg)
+	}
+	return resp.TenantAccessToken, time.Duration(resp.Expire) * time.Second, nil
+}
+
+// openAPIResponse is the envelope of Open API responses.
+type openAPIResponse struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// call sends an authenticated Open API request and decodes the data of the
+// response into data, if not nil.
+func (a *openAPI) call(ctx context.Context, method, path, contentType string, body io.Reader, data interface{}) error {
+	token, err := a.tenantAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.url(path), body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	var resp openAPIResponse
+	if err := a.doJSON(req, &resp); err != nil {
+		return err
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("API error (code %d): %s", resp.Code, resp.Msg)
+	}
+	if data != nil && len(resp.Data) > 0 {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			return fmt.Errorf("failed to unmarshal response data: %w", err)
+		}
+	}
+	return nil
+}
+
+// callJSON is like call with a JSON encoded request body.
+func (a *openAPI) callJSON(ctx context.Context, method, path string, body, data interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return a.call(ctx, method, path, "application/json; charset=utf-8", bytes.NewReader(payload), data)
+}
+
+// doJSON sends req with the HTTP client and decodes the JSON response body
+// into v.
+func (a *openAPI) doJSON(req *http.Request, v interface{}) error {
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if err := json.Unmarshal(respBody, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response (status %d): %w", resp.StatusCode, err)
+	}
+	return nil
+}
+
+// SetAppCredentials sets the ID and secret of a Feishu app, used to call the
+// Open API for operations that custom bot webhooks do not support, such as
+// uploading images. The app needs the corresponding permissions, e.g.
+// im:resource for image uploads.
+func (c *Client) SetAppCredentials(appID, appSecret string) {
+	c.AppID = appID
+	c.AppSecret = appSecret
+}
+
+// SetDomain sets the domain of the Open API used with the app credentials,
+// e.g. DomainLark for Lark apps. See OpenAPIBaseURL.
+func (c *Client) SetDomain(domain string) {
+	c.OpenAPIBaseURL = OpenAPIBaseURL(domain)
+}
+
+// openAPI returns the Open API caller of the client's app.
+func (c *Client) openAPI() *openAPI {
+	return &openAPI{
+		appID:      c.AppID,
+		appSecret:  c.AppSecret,
+		baseURL:    c.OpenAPIBaseURL,
+		httpClient: c.HTTPClient,
+		tokens:     c.tokens,
+	}
+}
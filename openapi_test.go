@@ -0,0 +1,139 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newOpenAPIServer returns a server issuing tenant access tokens, counting
+// token requests, with handler serving the other paths.
+func newOpenAPIServer(t *testing.T, handler http.Handler) (*httptest.Server, *int) {
+	t.Helper()
+
+	tokenRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v3/tenant_access_token/internal", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		tokenRequests++
+		if req["app_secret"] != "secret" {
+			_, _ = w.Write([]byte(`{"code":10014,"msg":"app secret invalid"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"ok","tenant_access_token":"t-` + req["app_id"] + `","expire":7200}`))
+	})
+	if handler != nil {
+		mux.Handle("/", handler)
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &tokenRequests
+}
+
+func TestTenantAccessToken(t *testing.T) {
+	server, tokenRequests := newOpenAPIServer(t, nil)
+
+	client := NewClient("", "")
+	client.OpenAPIBaseURL = server.URL
+	now := time.Unix(1700000000, 0)
+	client.tokens.now = func() time.Time { return now }
+
+	_, err := client.openAPI().tenantAccessToken(context.Background())
+	require.ErrorIs(t, err, ErrNoAppCredentials)
+
+	client.SetAppCredentials("cli_a", "secret")
+	token, err := client.openAPI().tenantAccessToken(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "t-cli_a", token)
+
+	// Cached until shortly before expiry
+	now = now.Add(time.Hour)
+	_, err = client.openAPI().tenantAccessToken(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, *tokenRequests)
+
+	now = now.Add(time.Hour)
+	_, err = client.openAPI().tenantAccessToken(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, *tokenRequests)
+
+	// Changed credentials get a new token
+	client.SetAppCredentials("cli_b", "secret")
+	token, err = client.openAPI().tenantAccessToken(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "t-cli_b", token)
+
+	client.SetAppCredentials("cli_b", "wrong")
+	_, err = client.openAPI().tenantAccessToken(context.Background())
+	require.EqualError(t, err, "failed to get tenant access token: API error (code 10014): app secret invalid")
+}
+
+func TestTenantAccessTokenWithoutCache(t *testing.T) {
+	server, tokenRequests := newOpenAPIServer(t, nil)
+
+	client := &Client{HTTPClient: http.DefaultClient, OpenAPIBaseURL: server.URL}
+	client.SetAppCredentials("cli_a", "secret")
+	for i := 0; i < 2; i++ {
+		token, err := client.openAPI().tenantAccessToken(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "t-cli_a", token)
+	}
+	require.Equal(t, 2, *tokenRequests)
+}
+
+func TestCallOpenAPI(t *testing.T) {
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer t-cli_a", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/ok":
+			_, _ = w.Write([]byte(`{"code":0,"msg":"ok","data":{"value":"x"}}`))
+		case "/error":
+			_, _ = w.Write([]byte(`{"code":99991663,"msg":"token invalid"}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`<html>`))
+		}
+	}))
+
+	client := NewClient("", "")
+	client.OpenAPIBaseURL = server.URL + "/"
+	client.SetAppCredentials("cli_a", "secret")
+
+	var data struct {
+		Value string `json:"value"`
+	}
+	require.NoError(t, client.openAPI().call(context.Background(), http.MethodGet, "/ok", "", nil, &data))
+	require.Equal(t, "x", data.Value)
+
+	err := client.openAPI().call(context.Background(), http.MethodGet, "/error", "", nil, nil)
+	require.EqualError(t, err, "API error (code 99991663): token invalid")
+
+	err = client.openAPI().call(context.Background(), http.MethodGet, "/html", "", nil, nil)
+	require.ErrorContains(t, err, "failed to unmarshal response (status 502)")
+}
+
+func TestOpenAPIURL(t *testing.T) {
+	client := NewClient("", "")
+	require.Equal(t, "https://open.feishu.cn/open-apis/im/v1/images", client.openAPI().url("/im/v1/images"))
+}
+
+func TestSetDomain(t *testing.T) {
+	require.Equal(t, "https://open.larksuite.com/open-apis", OpenAPIBaseURL(DomainLark))
+	require.Equal(t, "https://open.example.com/open-apis", OpenAPIBaseURL("open.example.com/"))
+	require.Equal(t, "http://localhost:8080/open-apis", OpenAPIBaseURL("http://localhost:8080"))
+
+	client := NewClient("", "")
+	client.SetDomain(DomainLark)
+	require.Equal(t, "https://open.larksuite.com/open-apis/im/v1/images", client.openAPI().url("/im/v1/images"))
+
+	api := NewAPIClient("cli_a", "secret")
+	api.SetDomain(DomainLark)
+	require.Equal(t, "https://open.larksuite.com/open-apis/im/v1/messages", api.openAPI().url("/im/v1/messages"))
+}
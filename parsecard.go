@@ -0,0 +1,121 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedCardSchema is returned by ParseCard for card JSON that uses
+// neither schema 1.0 nor schema 2.0.
+var ErrUnsupportedCardSchema = errors.New("unsupported card schema")
+
+// ParseCard parses card JSON, such as a card exported from the Feishu card
+// builder, into a Card so that it can be modified programmatically before
+// sending.
+//
+// Elements are decoded with DecodeCardElement into typed elements such as
+// *MarkdownElement or *Div where possible. Elements with tags unknown to this
+// package, or with fields the typed elements do not model, are kept as-is
+// in a MapElement. Fields of the card, body, header and texts that this
+// package does not model are kept in UnknownFields, so that a parsed card
+// marshals back without losing them.
+//
+// Cards in the schema 1.0 format, with elements at the top level and no
+// schema field, are converted to schema 2.0. Use Card.ToV1Map to convert
+// back.
+//
+// Example:
+//
+//	card, err := feishubot.ParseCard(designerJSON)
+//	if err != nil {
+//		return err
+//	}
+//	card.Header.Title = feishubot.NewCardTitle("Build #42 passed")
+//	message := feishubot.NewInteractiveMessage(card)
+func ParseCard(data []byte) (*Card, error) {
+	var card Card
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, fmt.Errorf("failed to parse card: %w", err)
+	}
+
+	switch {
+	case card.Schema == "2.0":
+	case isCardSchemaV1(card.Schema):
+		var v1 struct {
+			Elements []json.RawMessage `json:"elements"`
+		}
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return nil, fmt.Errorf("failed to parse card: %w", err)
+		}
+		elements, err := decodeCardElements(v1.Elements)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse card: %w", err)
+		}
+		elements = convertV1Elements(elements)
+		for lang, elements := range card.I18nElements {
+			card.I18nElements[lang] = convertV1Elements(elements)
+		}
+
+		card.Schema = "2.0"
+		if elements != nil {
+			card.Body = &CardBody{Elements: elements}
+		}
+	default:
+		return nil, fmt.Errorf("failed to parse card: %w %q", ErrUnsupportedCardSchema, card.Schema)
+	}
+
+	return &card, nil
+}
+
+// UnmarshalJSON decodes the card, including the elements of all languages,
+// keeping unknown fields.
+func (c *Card) UnmarshalJSON(data []byte) error {
+	type alias Card
+	aux := struct {
+		*alias
+		I18nElements map[Language][]json.RawMessage `json:"i18n_elements"`
+	}{alias: (*alias)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.I18nElements = nil
+	if aux.I18nElements != nil {
+		c.I18nElements = make(map[Language][]CardElement, len(aux.I18nElements))
+		for lang, raws := range aux.I18nElements {
+			elements, err := decodeCardElements(raws)
+			if err != nil {
+				return err
+			}
+			c.I18nElements[lang] = elements
+		}
+	}
+
+	unknown, err := unknownFields(data, cardFields)
+	c.UnknownFields = unknown
+	return err
+}
+
+// UnmarshalJSON decodes the card body, including its elements, keeping
+// unknown fields.
+func (b *CardBody) UnmarshalJSON(data []byte) error {
+	type alias CardBody
+	aux := struct {
+		*alias
+		Elements []json.RawMessage `json:"elements"`
+	}{alias: (*alias)(b)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	elements, err := decodeCardElements(aux.Elements)
+	if err != nil {
+		return err
+	}
+	b.Elements = elements
+
+	unknown, err := unknownFields(data, cardBodyFields)
+	b.UnknownFields = unknown
+	return err
+}
@@ -0,0 +1,139 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCard(t *testing.T) {
+	data := []byte(`{
+		"schema": "2.0",
+		"config": {"update_multi": true},
+		"header": {
+			"title": {"tag": "plain_text", "content": "Deploy"},
+			"template": "blue"
+		},
+		"body": {
+			"elements": [
+				{"tag": "markdown", "content": "**Status:** ok"},
+				{"tag": "div", "text": {"tag": "lark_md", "content": "details"}},
+				{"tag": "column_set", "columns": [
+					{"tag": "column", "elements": [
+						{"tag": "div", "text": {"tag": "plain_text", "content": "nested"}}
+					]}
+				]},
+				{"tag": "custom_widget", "foo": "bar"}
+			]
+		}
+	}`)
+
+	card, err := ParseCard(data)
+	require.NoError(t, err)
+
+	require.Equal(t, "2.0", card.Schema)
+	require.Equal(t, true, card.Config["update_multi"])
+	require.Equal(t, "Deploy", card.Header.Title.Content)
+	require.Equal(t, "blue", card.Header.Template)
+	require.Len(t, card.Body.Elements, 4)
+	require.Equal(t, []CardElement{
+		&MarkdownElement{Content: "**Status:** ok"},
+		&Div{Text: &CardTitle{Tag: "lark_md", Content: "details"}},
+		&ColumnSet{Columns: []*Column{{
+			Elements: []CardElement{
+				&Div{Text: &CardTitle{Tag: "plain_text", Content: "nested"}},
+			},
+		}}},
+		MapElement{"tag": "custom_widget", "foo": "bar"},
+	}, card.Body.Elements)
+
+	// Parsed cards can be modified and serialized again
+	card.Header.Title = NewCardTitle("Deploy finished")
+	out, err := json.Marshal(NewInteractiveMessage(card))
+	require.NoError(t, err)
+	require.Contains(t, string(out), `"content":"Deploy finished"`)
+	require.Contains(t, string(out), `"foo":"bar"`)
+}
+
+func TestParseCardErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{
+			name: "invalid json",
+			data: `{"schema":`,
+		},
+		{
+			name:    "unknown schema",
+			data:    `{"schema":"3.0"}`,
+			wantErr: ErrUnsupportedCardSchema,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCard([]byte(tt.data))
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr))
+			}
+		})
+	}
+}
+
+func TestParseCardPreservesUnknownFields(t *testing.T) {
+	data := []byte(`{
+		"schema": "2.0",
+		"card_link": {"url": "https://example.com"},
+		"header": {
+			"title": {"tag": "plain_text", "content": "Deploy", "i18n_content": {"zh_cn": "部署"}},
+			"padding": "12px"
+		},
+		"body": {
+			"vertical_spacing": "8px",
+			"elements": [
+				{"tag": "markdown", "content": "old"},
+				{"tag": "future_widget", "foo": "bar"}
+			]
+		}
+	}`)
+
+	card, err := ParseCard(data)
+	require.NoError(t, err)
+
+	card.Body.Elements[0] = NewMarkdownElement("new")
+	card.Header.Template = TemplateGreen
+
+	got, err := json.Marshal(card)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"card_link": {"url": "https://example.com"},
+		"header": {
+			"title": {"tag": "plain_text", "content": "Deploy", "i18n_content": {"zh_cn": "部署"}},
+			"template": "green",
+			"padding": "12px"
+		},
+		"body": {
+			"vertical_spacing": "8px",
+			"elements": [
+				{"tag": "markdown", "content": "new"},
+				{"tag": "future_widget", "foo": "bar"}
+			]
+		}
+	}`, string(got))
+
+	got, err = json.Marshal(card.ToMap())
+	require.NoError(t, err)
+	require.Contains(t, string(got), `"card_link":{"url":"https://example.com"}`)
+
+	// Modeled fields take precedence over unknown fields of the same name
+	card.UnknownFields["schema"] = json.RawMessage(`"9.9"`)
+	got, err = json.Marshal(card)
+	require.NoError(t, err)
+	require.Contains(t, string(got), `"schema":"2.0"`)
+}
@@ -0,0 +1,76 @@
+package feishubot
+
+// PostBuilder builds a rich text (post) message with one or more language
+// versions through chained calls.
+//
+// Example:
+//
+//	message := feishubot.NewPost().
+//		Lang(feishubot.LanguageZhCN).
+//		Title("部署完成").
+//		Paragraph(feishubot.NewTextElement("服务已更新")).
+//		Lang(feishubot.LanguageEnUS).
+//		Title("Deploy finished").
+//		Paragraph(feishubot.NewTextElement("The service has been updated")).
+//		Build()
+type PostBuilder struct {
+	contents []PostLanguageContent
+	current  int
+}
+
+// NewPost creates a new PostBuilder.
+func NewPost() *PostBuilder {
+	return &PostBuilder{current: -1}
+}
+
+// Lang selects the language that subsequent Title and Paragraph calls apply
+// to. Selecting a language again continues its existing content.
+func (b *PostBuilder) Lang(lang Language) *PostBuilder {
+	for i, lc := range b.contents {
+		if lc.Language == lang {
+			b.current = i
+			return b
+		}
+	}
+
+	b.contents = append(b.contents, PostLanguageContent{Language: lang})
+	b.current = len(b.contents) - 1
+	return b
+}
+
+// Title sets the title of the current language.
+// If no language was selected yet, LanguageZhCN is used.
+func (b *PostBuilder) Title(title string) *PostBuilder {
+	b.section().Content.Title = title
+	return b
+}
+
+// Paragraph appends a paragraph made of the given elements to the current
+// language. If no language was selected yet, LanguageZhCN is used.
+func (b *PostBuilder) Paragraph(elements ...Element) *PostBuilder {
+	section := b.section()
+	section.Content.Content = append(section.Content.Content, NewParagraph(elements...))
+	return b
+}
+
+// Paragraphs appends existing paragraphs to the current language, e.g. those
+// returned by NewKeyValueParagraphs.
+func (b *PostBuilder) Paragraphs(paragraphs ...Paragraph) *PostBuilder {
+	section := b.section()
+	section.Content.Content = append(section.Content.Content, paragraphs...)
+	return b
+}
+
+// Build creates the post message from all language versions.
+func (b *PostBuilder) Build() *Message {
+	return NewPostMessageMultiLanguage(b.contents...)
+}
+
+// section returns the current language section, selecting LanguageZhCN if
+// no language was selected yet.
+func (b *PostBuilder) section() *PostLanguageContent {
+	if b.current < 0 {
+		b.Lang(LanguageZhCN)
+	}
+	return &b.contents[b.current]
+}
@@ -0,0 +1,71 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *PostBuilder
+		want    *Message
+	}{
+		{
+			name: "multiple languages",
+			builder: NewPost().
+				Lang(LanguageZhCN).
+				Title("标题").
+				Paragraph(NewTextElement("内容")).
+				Lang(LanguageEnUS).
+				Title("Title").
+				Paragraph(NewTextElement("Content"), NewLinkElement("link", "https://example.com")),
+			want: NewPostMessageMultiLanguage(
+				NewPostLanguageContent(LanguageZhCN, NewPostContent("标题", NewParagraph(NewTextElement("内容")))),
+				NewPostLanguageContent(LanguageEnUS, NewPostContent("Title", NewParagraph(
+					NewTextElement("Content"),
+					NewLinkElement("link", "https://example.com"),
+				))),
+			),
+		},
+		{
+			name: "default language",
+			builder: NewPost().
+				Title("Title").
+				Paragraph(NewTextElement("Content")),
+			want: NewPostMessage(LanguageZhCN, NewPostContent("Title", NewParagraph(NewTextElement("Content")))),
+		},
+		{
+			name: "reselecting a language continues it",
+			builder: NewPost().
+				Lang(LanguageEnUS).
+				Paragraph(NewTextElement("one")).
+				Lang(LanguageZhCN).
+				Paragraph(NewTextElement("一")).
+				Lang(LanguageEnUS).
+				Paragraphs(NewKeyValueParagraphs([]KV{{Label: "two", Value: "2"}})...),
+			want: NewPostMessageMultiLanguage(
+				NewPostLanguageContent(LanguageEnUS, NewPostContent("",
+					NewParagraph(NewTextElement("one")),
+					NewParagraph(Element{"tag": "text", "text": "two: ", "style": []string{"bold"}}, NewTextElement("2")),
+				)),
+				NewPostLanguageContent(LanguageZhCN, NewPostContent("", NewParagraph(NewTextElement("一")))),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.builder.Build()
+			require.Equal(t, tt.want.MsgType, got.MsgType)
+
+			gotBytes, err := json.Marshal(got.Content)
+			require.NoError(t, err)
+			wantBytes, err := json.Marshal(tt.want.Content)
+			require.NoError(t, err)
+			require.JSONEq(t, string(wantBytes), string(gotBytes))
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package feishubot
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrProfileNotFound is returned when loading a profile that is not
+// defined in the profiles file.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Profile is a named webhook configuration of a profiles file, so that
+// webhook URLs and secrets live in a file instead of in code and shell
+// history.
+type Profile struct {
+	// Name is the name of the profile in the file.
+	Name string `yaml:"-"`
+	// WebhookURL is the webhook URL of the custom bot.
+	WebhookURL string `yaml:"url"`
+	// Secret is the signing secret of the bot, if any.
+	Secret string `yaml:"secret"`
+	// Keyword is the custom keyword required by the bot, see
+	// Client.Keyword.
+	Keyword string `yaml:"keyword"`
+	// Proxy is the URL of the HTTP proxy requests are sent through. If
+	// empty, the proxy of the environment is used.
+	Proxy string `yaml:"proxy"`
+}
+
+// Profiles are the profiles of a profiles file.
+//
+// Example file:
+//
+//	default: team
+//	profiles:
+//	  team:
+//	    url: https://open.feishu.cn/open-apis/bot/v2/hook/xxx
+//	    secret: ${TEAM_BOT_SECRET}
+//	  oncall:
+//	    url: https://open.feishu.cn/open-apis/bot/v2/hook/yyy
+//	    keyword: "[alert]"
+//	    proxy: http://proxy.internal:3128
+type Profiles struct {
+	// Default is the name of the profile used when no name is given. If
+	// empty, a file with a single profile uses that profile.
+	Default  string              `yaml:"default"`
+	Profiles map[string]*Profile `yaml:"profiles"`
+}
+
+// DefaultProfilesPath returns the path of the profiles file: the
+// FEISHUROBOT_CONFIG environment variable if set, otherwise
+// feishurobot/config.yaml in $XDG_CONFIG_HOME or ~/.config.
+func DefaultProfilesPath() (string, error) {
+	if path := os.Getenv("FEISHUROBOT_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate profiles file: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "feishurobot", "config.yaml"), nil
+}
+
+// LoadProfiles reads a profiles file. Environment variables referenced as
+// $VAR or ${VAR} in the file are expanded, e.g. to keep secrets out of it.
+func LoadProfiles(path string) (*Profiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+	return ParseProfiles(data)
+}
+
+// ParseProfiles parses the YAML of a profiles file, see LoadProfiles.
+func ParseProfiles(data []byte) (*Profiles, error) {
+	var profiles Profiles
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles: %w", err)
+	}
+	for name, profile := range profiles.Profiles {
+		if profile == nil {
+			profile = &Profile{}
+			profiles.Profiles[name] = profile
+		}
+		profile.Name = name
+	}
+	if profiles.Default != "" && profiles.Profiles[profiles.Default] == nil {
+		return nil, fmt.Errorf("failed to parse profiles: default %w: %q", ErrProfileNotFound, profiles.Default)
+	}
+	return &profiles, nil
+}
+
+// Profile returns the profile with the name, or the default profile if the
+// name is empty. The profile is validated, see Profile.Validate.
+func (p *Profiles) Profile(name string) (*Profile, error) {
+	profile, err := p.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Lookup is Profile without validating the profile, e.g. to diagnose it.
+func (p *Profiles) Lookup(name string) (*Profile, error) {
+	if name == "" {
+		name = p.Default
+	}
+	if name == "" {
+		if len(p.Profiles) != 1 {
+			return nil, fmt.Errorf("no profile name given and no default profile among %s", strings.Join(p.Names(), ", "))
+		}
+		for n := range p.Profiles {
+			name = n
+		}
+	}
+
+	profile, ok := p.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrProfileNotFound, name)
+	}
+	return profile, nil
+}
+
+// Names returns the names of the profiles, sorted.
+func (p *Profiles) Names() []string {
+	names := make([]string, 0, len(p.Profiles))
+	for name := range p.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadProfile returns the profile with the name, or the default profile if
+// the name is empty, from the file at DefaultProfilesPath.
+//
+// Example:
+//
+//	profile, err := feishubot.LoadProfile("oncall")
+//	if err != nil {
+//		return err
+//	}
+//	client, err := profile.NewClient()
+func LoadProfile(name string) (*Profile, error) {
+	path, err := DefaultProfilesPath()
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := LoadProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	return profiles.Profile(name)
+}
+
+// Validate checks that the profile has a valid webhook URL and, if set, a
+// valid proxy URL.
+func (p *Profile) Validate() error {
+	if p.WebhookURL == "" {
+		return fmt.Errorf("profile %q has no webhook", p.Name)
+	}
+	if u, err := url.Parse(p.WebhookURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("profile %q has an invalid webhook URL %q", p.Name, p.WebhookURL)
+	}
+	if _, err := p.proxyURL(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewClient creates a client for the webhook of the profile, sending
+// through its proxy and adding its keyword.
+func (p *Profile) NewClient() (*Client, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	client := NewClient(p.WebhookURL, p.Secret)
+	client.SetKeyword(p.Keyword)
+	proxy, err := p.proxyURL()
+	if err != nil {
+		return nil, err
+	}
+	if proxy != nil {
+		client.SetHTTPClient(newHTTPClient(defaultTimeout, proxy))
+	}
+	return client, nil
+}
+
+// proxyURL parses the proxy URL, nil if no proxy is set.
+func (p *Profile) proxyURL() (*url.URL, error) {
+	if p.Proxy == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(p.Proxy)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("profile %q has an invalid proxy URL %q", p.Name, p.Proxy)
+	}
+	return u, nil
+}
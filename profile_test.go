@@ -0,0 +1,110 @@
+package feishubot
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const profilesYAML = `default: team
+profiles:
+  team:
+    url: https://open.feishu.cn/open-apis/bot/v2/hook/team
+    secret: ${TEAM_BOT_SECRET}
+  oncall:
+    url: https://open.feishu.cn/open-apis/bot/v2/hook/oncall
+    keyword: "[alert]"
+    proxy: http://proxy.internal:3128
+  broken:
+    url: open.feishu.cn/hook
+`
+
+func TestParseProfiles(t *testing.T) {
+	t.Setenv("TEAM_BOT_SECRET", "s3cret")
+
+	profiles, err := ParseProfiles([]byte(profilesYAML))
+	require.NoError(t, err)
+	require.Equal(t, []string{"broken", "oncall", "team"}, profiles.Names())
+
+	team, err := profiles.Profile("")
+	require.NoError(t, err)
+	require.Equal(t, &Profile{Name: "team", WebhookURL: "https://open.feishu.cn/open-apis/bot/v2/hook/team", Secret: "s3cret"}, team)
+
+	oncall, err := profiles.Profile("oncall")
+	require.NoError(t, err)
+	require.Equal(t, "[alert]", oncall.Keyword)
+
+	_, err = profiles.Profile("missing")
+	require.ErrorIs(t, err, ErrProfileNotFound)
+	_, err = profiles.Profile("broken")
+	require.EqualError(t, err, `profile "broken" has an invalid webhook URL "open.feishu.cn/hook"`)
+	broken, err := profiles.Lookup("broken")
+	require.NoError(t, err)
+	require.Equal(t, "open.feishu.cn/hook", broken.WebhookURL)
+
+	profiles.Default = ""
+	_, err = profiles.Profile("")
+	require.EqualError(t, err, "no profile name given and no default profile among broken, oncall, team")
+
+	_, err = ParseProfiles([]byte("default: missing\nprofiles:\n  team:\n    url: https://example.com\n"))
+	require.ErrorIs(t, err, ErrProfileNotFound)
+	_, err = ParseProfiles([]byte("profiles: ["))
+	require.Error(t, err)
+}
+
+func TestProfileSingle(t *testing.T) {
+	profiles, err := ParseProfiles([]byte("profiles:\n  only:\n    url: https://example.com/hook\n  empty:\n"))
+	require.NoError(t, err)
+	_, err = profiles.Profile("empty")
+	require.EqualError(t, err, `profile "empty" has no webhook`)
+
+	delete(profiles.Profiles, "empty")
+	profile, err := profiles.Profile("")
+	require.NoError(t, err)
+	require.Equal(t, "only", profile.Name)
+}
+
+func TestProfileNewClient(t *testing.T) {
+	profile := &Profile{Name: "oncall", WebhookURL: "https://example.com/hook", Secret: "s3cret", Keyword: "[alert]", Proxy: "http://proxy.internal:3128"}
+	client, err := profile.NewClient()
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/hook", client.WebhookURL)
+	require.Equal(t, "s3cret", client.Secret)
+	require.Equal(t, "[alert]", client.Keyword)
+
+	transport := client.HTTPClient.(*http.Client).Transport.(*http.Transport)
+	proxy, err := transport.Proxy(&http.Request{})
+	require.NoError(t, err)
+	require.Equal(t, "http://proxy.internal:3128", proxy.String())
+
+	profile.Proxy = "::bad"
+	_, err = profile.NewClient()
+	require.EqualError(t, err, `profile "oncall" has an invalid proxy URL "::bad"`)
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FEISHUROBOT_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	path, err := DefaultProfilesPath()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "feishurobot", "config.yaml"), path)
+
+	_, err = LoadProfile("oncall")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(profilesYAML), 0o600))
+	profile, err := LoadProfile("oncall")
+	require.NoError(t, err)
+	require.Equal(t, "oncall", profile.Name)
+
+	t.Setenv("FEISHUROBOT_CONFIG", filepath.Join(dir, "other.yaml"))
+	path, err = DefaultProfilesPath()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "other.yaml"), path)
+}
@@ -0,0 +1,114 @@
+package feishubot
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	defaultProgressWidth  = 10
+	defaultProgressFilled = "▓"
+	defaultProgressEmpty  = "░"
+)
+
+// ProgressBarOption configures a progress bar rendered by ProgressBar.
+type ProgressBarOption func(bar *progressBar)
+
+type progressBar struct {
+	width    int
+	filled   string
+	empty    string
+	color    string
+	colorSet bool
+}
+
+// WithProgressWidth sets the number of segments in the bar. The default is 10.
+func WithProgressWidth(width int) ProgressBarOption {
+	return func(bar *progressBar) {
+		if width > 0 {
+			bar.width = width
+		}
+	}
+}
+
+// WithProgressChars sets the strings used for filled and empty segments,
+// e.g. "🟩" and "⬜". The defaults are "▓" and "░".
+func WithProgressChars(filled, empty string) ProgressBarOption {
+	return func(bar *progressBar) {
+		bar.filled = filled
+		bar.empty = empty
+	}
+}
+
+// WithProgressColor sets the markdown font color of the filled segments,
+// e.g. "red". An empty color renders the bar without a font tag.
+func WithProgressColor(color string) ProgressBarOption {
+	return func(bar *progressBar) {
+		bar.color = color
+		bar.colorSet = true
+	}
+}
+
+// ProgressBar renders percent (0-100, clamped) as a textual progress bar
+// followed by the rounded percentage, for use in markdown card elements.
+//
+// Unless WithProgressColor is given, the filled segments are blue while in
+// progress and green once complete, so bars look the same across cards.
+//
+// Example:
+//
+//	feishubot.NewMarkdownElement("Backup " + feishubot.ProgressBar(52))
+//	// Backup <font color='blue'>▓▓▓▓▓</font>░░░░░ 52%
+func ProgressBar(percent float64, opts ...ProgressBarOption) string {
+	bar := &progressBar{
+		width:  defaultProgressWidth,
+		filled: defaultProgressFilled,
+		empty:  defaultProgressEmpty,
+	}
+	for _, opt := range opts {
+		opt(bar)
+	}
+
+	if math.IsNaN(percent) || percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+
+	color := bar.color
+	if !bar.colorSet {
+		color = "blue"
+		if percent == 100 {
+			color = "green"
+		}
+	}
+
+	filled := int(math.Round(percent / 100 * float64(bar.width)))
+	if filled == bar.width && percent < 100 {
+		// Only a finished task shows a full bar
+		filled--
+	}
+
+	var b strings.Builder
+	if filled > 0 {
+		segments := strings.Repeat(bar.filled, filled)
+		if color != "" {
+			segments = fmt.Sprintf("<font color='%s'>%s</font>", color, segments)
+		}
+		b.WriteString(segments)
+	}
+	b.WriteString(strings.Repeat(bar.empty, bar.width-filled))
+	fmt.Fprintf(&b, " %d%%", int(math.Round(percent)))
+
+	return b.String()
+}
+
+// ProgressBarOf renders done out of total as a progress bar, e.g. for
+// "3 of 8 replicas updated". A non-positive total renders as 0%.
+func ProgressBarOf(done, total int, opts ...ProgressBarOption) string {
+	if total <= 0 {
+		return ProgressBar(0, opts...)
+	}
+	return ProgressBar(float64(done)/float64(total)*100, opts...)
+}
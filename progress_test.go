@@ -0,0 +1,75 @@
+package feishubot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		name    string
+		percent float64
+		opts    []ProgressBarOption
+		want    string
+	}{
+		{
+			name:    "in progress",
+			percent: 52,
+			want:    "<font color='blue'>▓▓▓▓▓</font>░░░░░ 52%",
+		},
+		{
+			name:    "complete",
+			percent: 100,
+			want:    "<font color='green'>▓▓▓▓▓▓▓▓▓▓</font> 100%",
+		},
+		{
+			name:    "empty",
+			percent: 0,
+			want:    "░░░░░░░░░░ 0%",
+		},
+		{
+			name:    "clamped",
+			percent: 150,
+			want:    "<font color='green'>▓▓▓▓▓▓▓▓▓▓</font> 100%",
+		},
+		{
+			name:    "negative",
+			percent: -5,
+			want:    "░░░░░░░░░░ 0%",
+		},
+		{
+			name:    "nan",
+			percent: math.NaN(),
+			want:    "░░░░░░░░░░ 0%",
+		},
+		{
+			name:    "custom width and chars without color",
+			percent: 33.4,
+			opts: []ProgressBarOption{
+				WithProgressWidth(6),
+				WithProgressChars("#", "-"),
+				WithProgressColor(""),
+			},
+			want: "##---- 33%",
+		},
+		{
+			name:    "custom color",
+			percent: 90,
+			opts:    []ProgressBarOption{WithProgressWidth(4), WithProgressColor("red")},
+			want:    "<font color='red'>▓▓▓</font>░ 90%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ProgressBar(tt.percent, tt.opts...))
+		})
+	}
+}
+
+func TestProgressBarOf(t *testing.T) {
+	require.Equal(t, "<font color='blue'>▓▓▓▓</font>░░░░░░ 38%", ProgressBarOf(3, 8))
+	require.Equal(t, "░░░░░░░░░░ 0%", ProgressBarOf(1, 0))
+}
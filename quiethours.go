@@ -0,0 +1,108 @@
+package feishubot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours is a daily period in which routes of a Router do not send
+// messages, such as nights and weekends, except routes ignoring quiet
+// hours.
+type QuietHours struct {
+	// Start and End are the times of day ("22:00") the period starts and
+	// ends. If End is not after Start, the period spans midnight. If both
+	// are empty, the whole day is quiet.
+	Start string `yaml:"start" json:"start"`
+	End   string `yaml:"end" json:"end"`
+	// Days are the weekdays ("sat", "sunday") the period starts on. If
+	// empty, it starts every day.
+	Days []string `yaml:"days" json:"days"`
+	// Timezone is the IANA time zone of the times. If empty, the local time
+	// zone is used.
+	Timezone string `yaml:"timezone" json:"timezone"`
+}
+
+// Validate checks the times, days and time zone.
+func (q *QuietHours) Validate() error {
+	if (q.Start == "") != (q.End == "") {
+		return fmt.Errorf("quiet hours need both a start and an end")
+	}
+	if q.Start != "" {
+		if _, err := parseTimeOfDay(q.Start); err != nil {
+			return err
+		}
+		if _, err := parseTimeOfDay(q.End); err != nil {
+			return err
+		}
+	}
+	for _, day := range q.Days {
+		if _, err := parseWeekday(day); err != nil {
+			return err
+		}
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("invalid quiet hours time zone: %w", err)
+	}
+	return nil
+}
+
+// Contains reports whether t is within the quiet hours. Invalid quiet hours
+// contain no time; see Validate.
+func (q *QuietHours) Contains(t time.Time) bool {
+	if q.Validate() != nil {
+		return false
+	}
+	loc, _ := time.LoadLocation(q.Timezone)
+	t = t.In(loc)
+	if q.Start == "" {
+		return q.startsOn(t.Weekday())
+	}
+
+	start, _ := parseTimeOfDay(q.Start)
+	end, _ := parseTimeOfDay(q.End)
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return minute >= start && minute < end && q.startsOn(t.Weekday())
+	}
+	// The period spans midnight: before the end, it started the day before
+	if minute >= start {
+		return q.startsOn(t.Weekday())
+	}
+	return minute < end && q.startsOn((t.Weekday()+6)%7)
+}
+
+// startsOn reports whether the period starts on the weekday.
+func (q *QuietHours) startsOn(weekday time.Weekday) bool {
+	if len(q.Days) == 0 {
+		return true
+	}
+	for _, day := range q.Days {
+		if d, err := parseWeekday(day); err == nil && d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTimeOfDay parses "15:04" into minutes since midnight.
+func parseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quiet hours time %q, want HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseWeekday parses an English weekday name or its three letter
+// abbreviation.
+func parseWeekday(s string) (time.Weekday, error) {
+	name := strings.ToLower(s)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || name == full[:3] {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid quiet hours day %q", s)
+}
@@ -0,0 +1,45 @@
+package feishubot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuietHours(t *testing.T) {
+	// 2026-10-16 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	nights := &QuietHours{Start: "22:00", End: "08:00", Timezone: "UTC"}
+	require.NoError(t, nights.Validate())
+	require.True(t, nights.Contains(at(16, 23, 0)))
+	require.True(t, nights.Contains(at(17, 7, 59)))
+	require.False(t, nights.Contains(at(17, 8, 0)))
+	require.False(t, nights.Contains(at(16, 21, 59)))
+
+	// Friday and Saturday nights only
+	weekendNights := &QuietHours{Start: "22:00", End: "08:00", Days: []string{"fri", "Saturday"}, Timezone: "UTC"}
+	require.True(t, weekendNights.Contains(at(16, 23, 0)))
+	require.True(t, weekendNights.Contains(at(18, 7, 0)))
+	require.False(t, weekendNights.Contains(at(19, 7, 0)))
+	require.False(t, weekendNights.Contains(at(15, 23, 0)))
+
+	lunch := &QuietHours{Start: "12:00", End: "13:30", Timezone: "Asia/Shanghai"}
+	require.True(t, lunch.Contains(at(16, 4, 30)))
+	require.False(t, lunch.Contains(at(16, 12, 30)))
+
+	weekends := &QuietHours{Days: []string{"sat", "sun"}, Timezone: "UTC"}
+	require.True(t, weekends.Contains(at(18, 12, 0)))
+	require.False(t, weekends.Contains(at(16, 12, 0)))
+}
+
+func TestQuietHoursValidate(t *testing.T) {
+	require.EqualError(t, (&QuietHours{Start: "22:00"}).Validate(), "quiet hours need both a start and an end")
+	require.EqualError(t, (&QuietHours{Start: "10pm", End: "08:00"}).Validate(), `invalid quiet hours time "10pm", want HH:MM`)
+	require.EqualError(t, (&QuietHours{Days: []string{"someday"}}).Validate(), `invalid quiet hours day "someday"`)
+	require.Error(t, (&QuietHours{Timezone: "Mars/Olympus"}).Validate())
+	require.False(t, (&QuietHours{Start: "10pm", End: "08:00"}).Contains(time.Now()))
+}
@@ -0,0 +1,88 @@
+package feishubot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit limits the requests per second and per minute. Zero values
+// are unlimited.
+type RateLimit struct {
+	PerSecond int `yaml:"per_second" json:"per_second"`
+	PerMinute int `yaml:"per_minute" json:"per_minute"`
+}
+
+// DefaultRateLimit is the documented rate limit of custom bots.
+var DefaultRateLimit = RateLimit{PerSecond: 5, PerMinute: 100}
+
+// rateLimiter delays requests exceeding a RateLimit within sliding windows
+// of a second and a minute.
+type rateLimiter struct {
+	limit RateLimit
+	now   func() time.Time
+
+	mu sync.Mutex
+	// sent are the times of the requests within the last minute, oldest
+	// first.
+	sent []time.Time
+}
+
+// newRateLimiter creates a limiter, nil if limit is unlimited.
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.PerSecond <= 0 && limit.PerMinute <= 0 {
+		return nil
+	}
+	return &rateLimiter{limit: limit, now: time.Now}
+}
+
+// wait waits until a request is allowed and records it, or returns the
+// error of ctx if it is done first.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		delay := l.delay(now)
+		if delay <= 0 {
+			l.sent = append(l.sent, now)
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns how long a request at now has to wait, forgetting requests
+// older than a minute.
+func (l *rateLimiter) delay(now time.Time) time.Duration {
+	i := 0
+	for i < len(l.sent) && !l.sent[i].After(now.Add(-time.Minute)) {
+		i++
+	}
+	l.sent = l.sent[i:]
+	return l.limit.Delay(l.sent, now)
+}
+
+// Delay returns how long a request at now has to wait to stay within the
+// limit, given the times of earlier requests, oldest first. Requests older
+// than a minute may be omitted.
+func (l RateLimit) Delay(sent []time.Time, now time.Time) time.Duration {
+	var delay time.Duration
+	if n := l.PerMinute; n > 0 && len(sent) >= n {
+		delay = sent[len(sent)-n].Add(time.Minute).Sub(now)
+	}
+	if n := l.PerSecond; n > 0 && len(sent) >= n {
+		if d := sent[len(sent)-n].Add(time.Second).Sub(now); d > delay {
+			delay = d
+		}
+	}
+	return delay
+}
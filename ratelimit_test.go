@@ -0,0 +1,75 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterDelay(t *testing.T) {
+	start := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(RateLimit{PerSecond: 2, PerMinute: 3})
+
+	require.Zero(t, limiter.delay(start))
+	limiter.sent = append(limiter.sent, start, start.Add(100*time.Millisecond))
+	require.Equal(t, 800*time.Millisecond, limiter.delay(start.Add(200*time.Millisecond)))
+	require.Zero(t, limiter.delay(start.Add(time.Second)))
+
+	limiter.sent = append(limiter.sent, start.Add(time.Second))
+	require.Equal(t, 58*time.Second, limiter.delay(start.Add(2*time.Second)))
+	require.Zero(t, limiter.delay(start.Add(time.Minute)))
+	require.Len(t, limiter.sent, 2)
+
+	require.Nil(t, newRateLimiter(RateLimit{}))
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	start := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	limit := RateLimit{PerSecond: 1, PerMinute: 2}
+
+	require.Zero(t, limit.Delay(nil, start))
+	require.Equal(t, 900*time.Millisecond, limit.Delay([]time.Time{start}, start.Add(100*time.Millisecond)))
+	require.Equal(t, 55*time.Second, limit.Delay([]time.Time{start, start.Add(2 * time.Second)}, start.Add(5*time.Second)))
+	require.Zero(t, RateLimit{}.Delay([]time.Time{start, start}, start))
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(RateLimit{PerMinute: 1})
+	limiter.now = func() time.Time { return now }
+
+	require.NoError(t, limiter.wait(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, limiter.wait(ctx), context.DeadlineExceeded)
+}
+
+func TestSendRateLimit(t *testing.T) {
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		json.NewEncoder(w).Encode(SuccessResponse)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+	client.SetRateLimit(RateLimit{PerSecond: 20})
+	for i := 0; i < 21; i++ {
+		_, err := client.Send(context.Background(), NewTextMessage("hi"))
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, times[20].Sub(times[0]), 900*time.Millisecond)
+
+	client.SetRateLimit(RateLimit{PerMinute: 1})
+	_, err := client.Send(context.Background(), NewTextMessage("hi"))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Send(ctx, NewTextMessage("hi"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,86 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// errInvalidRawJSON is returned when marshaling raw card JSON that is not
+// valid JSON.
+var errInvalidRawJSON = errors.New("invalid raw card JSON")
+
+// RawCardElement is a card element given as raw JSON, see
+// NewRawCardElement.
+type RawCardElement json.RawMessage
+
+// NewRawCardElement creates an element from raw JSON, such as a module
+// copied from the Feishu card builder. It is an escape hatch for card
+// features this package does not model yet, letting the rest of the card
+// use the typed builders.
+//
+// The JSON is embedded as-is; invalid JSON makes marshaling the card fail.
+//
+// Example:
+//
+//	body := &feishubot.CardBody{Elements: []feishubot.CardElement{
+//		feishubot.NewMarkdownElement("**Owner**"),
+//		feishubot.NewRawCardElement(json.RawMessage(`{"tag":"person","user_id":"ou_xxx","size":"small"}`)),
+//	}}
+func NewRawCardElement(raw json.RawMessage) CardElement {
+	return RawCardElement(raw)
+}
+
+// Tag returns the "tag" field of the raw JSON, or an empty string if it has
+// none or is not a JSON object.
+func (e RawCardElement) Tag() string {
+	var head struct {
+		Tag string `json:"tag"`
+	}
+	if err := json.Unmarshal(e, &head); err != nil {
+		return ""
+	}
+	return head.Tag
+}
+
+// MarshalJSON returns the raw JSON.
+func (e RawCardElement) MarshalJSON() ([]byte, error) {
+	if !json.Valid(e) {
+		return nil, errInvalidRawJSON
+	}
+	return e, nil
+}
+
+// SetRawHeader sets the header from raw JSON, replacing any header set with
+// SetHeader. Like NewRawCardElement, it allows using header features this
+// package does not model yet.
+func (c *Card) SetRawHeader(raw json.RawMessage) *Card {
+	c.Header = nil
+	c.RawHeader = raw
+	return c
+}
+
+// MarshalJSON encodes the card, using RawHeader as the header if set, and
+// including unknown fields.
+func (c *Card) MarshalJSON() ([]byte, error) {
+	type alias Card
+	aux := struct {
+		*alias
+		Header interface{} `json:"header,omitempty"`
+	}{alias: (*alias)(c)}
+	if header := c.header(); header != nil {
+		aux.Header = header
+	}
+	return marshalWithUnknown(aux, c.UnknownFields)
+}
+
+// header returns the header of the card, Header or RawHeader, or nil if the
+// card has none.
+func (c *Card) header() interface{} {
+	if c.RawHeader != nil {
+		return RawCardElement(c.RawHeader)
+	}
+	if c.Header != nil {
+		return c.Header
+	}
+	return nil
+}
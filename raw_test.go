@@ -0,0 +1,62 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawCardElement(t *testing.T) {
+	element := NewRawCardElement(json.RawMessage(`{"tag":"person","user_id":"ou_xxx"}`))
+	require.Equal(t, "person", element.Tag())
+	require.Equal(t, "", NewRawCardElement(json.RawMessage(`[1]`)).Tag())
+
+	card := NewCard("2.0").SetBody(&CardBody{Elements: []CardElement{
+		NewMarkdownElement("**Owner**"),
+		element,
+	}})
+
+	data, err := json.Marshal(NewInteractiveMessage(card))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"msg_type": "interactive",
+		"card": {
+			"schema": "2.0",
+			"body": {"elements": [
+				{"tag": "markdown", "content": "**Owner**"},
+				{"tag": "person", "user_id": "ou_xxx"}
+			]}
+		}
+	}`, string(data))
+
+	card.Body.Elements = append(card.Body.Elements, NewRawCardElement(json.RawMessage(`{"tag":`)))
+	_, err = json.Marshal(NewInteractiveMessage(card))
+	require.ErrorIs(t, err, errInvalidRawJSON)
+}
+
+func TestCardSetRawHeader(t *testing.T) {
+	card := NewCard("2.0").
+		SetHeader(&CardHeader{Title: NewCardTitle("replaced")}).
+		SetRawHeader(json.RawMessage(`{"title":{"tag":"plain_text","content":"Raw"},"future_field":true}`))
+
+	want := `{
+		"schema": "2.0",
+		"header": {"title": {"tag": "plain_text", "content": "Raw"}, "future_field": true}
+	}`
+
+	data, err := json.Marshal(card)
+	require.NoError(t, err)
+	require.JSONEq(t, want, string(data))
+
+	data, err = json.Marshal(card.ToMap())
+	require.NoError(t, err)
+	require.JSONEq(t, want, string(data))
+
+	data, err = json.Marshal(card.ToV1Map())
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"elements": [],
+		"header": {"title": {"tag": "plain_text", "content": "Raw"}, "future_field": true}
+	}`, string(data))
+}
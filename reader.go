@@ -0,0 +1,69 @@
+package feishubot
+
+import (
+	"fmt"
+	"io"
+)
+
+// readerLookahead is the number of bytes read past the limit so that a
+// mention tag or markdown link crossing the limit is detected and dropped
+// as a whole instead of being cut in half.
+const readerLookahead = 1024
+
+// readTruncated reads at most maxBytes (plus a small lookahead) from r and
+// returns the content truncated to maxBytes with TruncateText. The rest of
+// the reader is left unread.
+func readTruncated(r io.Reader, maxBytes int) (string, error) {
+	if maxBytes < 0 {
+		return "", fmt.Errorf("invalid max size %d", maxBytes)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, int64(maxBytes)+readerLookahead))
+	if err != nil {
+		return "", fmt.Errorf("failed to read content: %w", err)
+	}
+
+	return TruncateText(string(data), maxBytes), nil
+}
+
+// NewTextMessageFromReader creates a text message from the content of r.
+//
+// At most maxBytes are used; longer content is truncated with TruncateText
+// and the remainder of r is never read, so large log files can be streamed
+// into a message without buffering them first.
+//
+// Example:
+//
+//	f, err := os.Open("/var/log/app.log")
+//	if err != nil {
+//	    // handle error
+//	}
+//	defer f.Close()
+//	message, err := feishubot.NewTextMessageFromReader(f, 16*1024)
+func NewTextMessageFromReader(r io.Reader, maxBytes int) (*Message, error) {
+	text, err := readTruncated(r, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	return NewTextMessage(text), nil
+}
+
+// NewTextElementFromReader creates a post text element from the content of r,
+// truncated to at most maxBytes. See NewTextMessageFromReader.
+func NewTextElementFromReader(r io.Reader, maxBytes int) (Element, error) {
+	text, err := readTruncated(r, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	return NewTextElement(text), nil
+}
+
+// NewMarkdownElementFromReader creates a card markdown element from the
+// content of r, truncated to at most maxBytes. See NewTextMessageFromReader.
+func NewMarkdownElementFromReader(r io.Reader, maxBytes int) (CardElement, error) {
+	content, err := readTruncated(r, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	return NewMarkdownElement(content), nil
+}
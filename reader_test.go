@@ -0,0 +1,89 @@
+package feishubot
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// errReader is a reader that always fails.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestNewTextMessageFromReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        io.Reader
+		maxBytes int
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "short content",
+			r:        strings.NewReader("hello"),
+			maxBytes: 100,
+			want:     "hello",
+		},
+		{
+			name:     "long content truncated",
+			r:        strings.NewReader(strings.Repeat("a", 10000)),
+			maxBytes: 10,
+			want:     "aaaaaaa...",
+		},
+		{
+			name:     "mention crossing the limit dropped",
+			r:        strings.NewReader(`error <at user_id="ou_xxx">Tom</at>` + strings.Repeat("a", 5000)),
+			maxBytes: 20,
+			want:     "error ...",
+		},
+		{
+			name:     "negative max size",
+			r:        strings.NewReader("hello"),
+			maxBytes: -1,
+			wantErr:  true,
+		},
+		{
+			name:     "read error",
+			r:        errReader{},
+			maxBytes: 100,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTextMessageFromReader(tt.r, tt.maxBytes)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, MsgTypeText, got.MsgType)
+			require.Equal(t, tt.want, got.Content["text"])
+		})
+	}
+}
+
+func TestReaderDoesNotConsumeRemainder(t *testing.T) {
+	r := strings.NewReader(strings.Repeat("a", 100000))
+
+	_, err := NewMarkdownElementFromReader(r, 10)
+	require.NoError(t, err)
+	require.Equal(t, 100000-10-readerLookahead, r.Len())
+}
+
+func TestElementsFromReader(t *testing.T) {
+	elem, err := NewTextElementFromReader(strings.NewReader("hello world"), 8)
+	require.NoError(t, err)
+	require.Equal(t, NewTextElement("hello..."), elem)
+
+	cardElem, err := NewMarkdownElementFromReader(strings.NewReader("**hello** world"), 12)
+	require.NoError(t, err)
+	require.Equal(t, NewMarkdownElement("**hello**..."), cardElem)
+}
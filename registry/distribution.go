@@ -0,0 +1,65 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DistributionEnvelope is the body of notifications of the Docker
+// distribution registry, holding one or more events.
+//
+// See: https://distribution.github.io/distribution/about/notifications/
+type DistributionEnvelope struct {
+	Events []DistributionEvent `json:"events"`
+}
+
+// DistributionEvent is an event of a DistributionEnvelope.
+type DistributionEvent struct {
+	ID string `json:"id"`
+	// Action is "push", "pull", "mount" or "delete".
+	Action string `json:"action"`
+	Target struct {
+		MediaType  string `json:"mediaType"`
+		Digest     string `json:"digest"`
+		Repository string `json:"repository"`
+		URL        string `json:"url"`
+		Tag        string `json:"tag"`
+	} `json:"target"`
+	Request struct {
+		// Host is the externally accessible host of the registry.
+		Host string `json:"host"`
+	} `json:"request"`
+	Actor struct {
+		Name string `json:"name"`
+	} `json:"actor"`
+}
+
+// ToEvents maps the pushes of tagged manifests of the envelope to events.
+// Blob pushes, pulls and other actions yield no events.
+func (e *DistributionEnvelope) ToEvents() []*Event {
+	var events []*Event
+	for _, event := range e.Events {
+		if event.Action != "push" || event.Target.Tag == "" {
+			continue
+		}
+		events = append(events, &Event{
+			Kind:       KindPush,
+			Registry:   event.Request.Host,
+			Repository: event.Target.Repository,
+			Tag:        event.Target.Tag,
+			Digest:     event.Target.Digest,
+			Operator:   event.Actor.Name,
+		})
+	}
+	return events
+}
+
+// decodeDistribution decodes a Docker distribution registry notification
+// body.
+func decodeDistribution(body []byte) ([]*Event, error) {
+	var envelope DistributionEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode registry notification: %w", err)
+	}
+	return envelope.ToEvents(), nil
+}
@@ -0,0 +1,126 @@
+package registry
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// maxBodyBytes is the maximum size of webhook request bodies read.
+const maxBodyBytes = 1 << 20
+
+// Handler is an http.Handler receiving registry webhooks and posting their
+// events as cards, created with NewHarborHandler or NewDistributionHandler.
+//
+// Events that cannot be posted are answered with status 502, so that the
+// registry can retry them.
+type Handler struct {
+	send   feishubot.SendFunc
+	decode func(body []byte) ([]*Event, error)
+
+	// Authorization, if set, is the value required in the Authorization
+	// header of requests, such as the auth header of a Harbor webhook
+	// policy.
+	Authorization string
+
+	// Filter, if set, is called with each event and posts only events it
+	// returns true for, e.g. to post only scans finding critical
+	// vulnerabilities.
+	Filter func(*Event) bool
+
+	// URL, if set, returns the link to the image in the registry UI.
+	URL func(*Event) string
+
+	// OnError is called with errors of handling webhooks. If nil, such
+	// errors are only reported in the response.
+	OnError func(error)
+}
+
+// NewHarborHandler creates a handler posting push and scan events of Harbor
+// webhooks with send.
+//
+// Example:
+//
+//	client := feishubot.NewClient(webhookURL, secret)
+//	handler := registry.NewHarborHandler(feishubot.WebhookSender(client))
+//	handler.Authorization = os.Getenv("HARBOR_WEBHOOK_AUTH")
+//	http.Handle("/harbor", handler)
+func NewHarborHandler(send feishubot.SendFunc) *Handler {
+	return &Handler{send: send, decode: decodeHarbor}
+}
+
+// NewDistributionHandler creates a handler posting image pushes of Docker
+// distribution registry notifications with send.
+//
+// Example registry configuration:
+//
+//	notifications:
+//	  endpoints:
+//	    - name: feishu
+//	      url: http://feishu-registry:8080/registry
+//	      timeout: 5s
+//	      threshold: 5
+//	      backoff: 10s
+func NewDistributionHandler(send feishubot.SendFunc) *Handler {
+	return &Handler{send: send, decode: decodeDistribution}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Authorization != "" && subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), []byte(h.Authorization)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBodyBytes))
+	if err != nil {
+		h.handleError(fmt.Errorf("failed to read request body: %w", err))
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	events, err := h.decode(body)
+	if err != nil {
+		h.handleError(err)
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.notify(req.Context(), events); err != nil {
+		h.handleError(err)
+		http.Error(w, "failed to post event", http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// notify posts the cards of the events not filtered out.
+func (h *Handler) notify(ctx context.Context, events []*Event) error {
+	for _, event := range events {
+		if h.Filter != nil && !h.Filter(event) {
+			continue
+		}
+		if event.URL == "" && h.URL != nil {
+			event.URL = h.URL(event)
+		}
+		if err := h.send(ctx, feishubot.NewInteractiveMessage(event.Card())); err != nil {
+			return fmt.Errorf("failed to post event of %s: %w", event.Image(), err)
+		}
+	}
+	return nil
+}
+
+// handleError reports err to the error handler.
+func (h *Handler) handleError(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+	}
+}
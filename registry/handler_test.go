@@ -0,0 +1,62 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarborHandler(t *testing.T) {
+	var sent []*feishubot.Message
+	handler := NewHarborHandler(func(_ context.Context, msg *feishubot.Message) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	handler.Authorization = "Bearer s3cret"
+
+	req := httptest.NewRequest(http.MethodPost, "/harbor", strings.NewReader(harborScanPayload))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Empty(t, sent)
+
+	req = httptest.NewRequest(http.MethodPost, "/harbor", strings.NewReader(harborScanPayload))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, sent, 1)
+
+	handler.Filter = func(event *Event) bool { return event.Scan != nil && event.Scan.Severity == SeverityCritical }
+	req = httptest.NewRequest(http.MethodPost, "/harbor", strings.NewReader(harborScanPayload))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, sent, 1)
+}
+
+func TestDistributionHandler(t *testing.T) {
+	var sent []*feishubot.Message
+	handler := NewDistributionHandler(func(_ context.Context, msg *feishubot.Message) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	handler.URL = func(event *Event) string { return "https://registry-ui.example.com/" + event.Repository }
+
+	body := `{"events": [{"action": "push", "target": {"repository": "team/api", "tag": "v1"}, "request": {"host": "registry.example.com"}}]}`
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/registry", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, sent, 1)
+	require.Equal(t, "📦 team/api:v1 pushed", sent[0].TypedCard.Header.Title.Content)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/registry", strings.NewReader("[")))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,147 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Harbor webhook event types handled by HarborEvent.ToEvents.
+const (
+	HarborPushArtifact      = "PUSH_ARTIFACT"
+	HarborScanningCompleted = "SCANNING_COMPLETED"
+	HarborScanningFailed    = "SCANNING_FAILED"
+)
+
+// HarborEvent is the payload of Harbor webhooks in the default format.
+//
+// See: https://goharbor.io/docs/main/working-with-projects/project-configuration/configure-webhooks/
+type HarborEvent struct {
+	Type string `json:"type"`
+	// OccurAt is the time of the event in seconds since epoch.
+	OccurAt   int64           `json:"occur_at"`
+	Operator  string          `json:"operator"`
+	EventData HarborEventData `json:"event_data"`
+}
+
+// HarborEventData is the data of a HarborEvent.
+type HarborEventData struct {
+	Resources  []HarborResource `json:"resources"`
+	Repository HarborRepository `json:"repository"`
+}
+
+// HarborResource is an artifact of a HarborEvent.
+type HarborResource struct {
+	Digest string `json:"digest"`
+	Tag    string `json:"tag"`
+	// ResourceURL is the pull reference, e.g.
+	// "harbor.example.com/library/nginx:1.25".
+	ResourceURL string `json:"resource_url"`
+	// ScanOverview holds the scan report by report MIME type.
+	ScanOverview map[string]HarborScanOverview `json:"scan_overview"`
+}
+
+// HarborRepository is the repository of a HarborEvent.
+type HarborRepository struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	RepoFullName string `json:"repo_full_name"`
+	RepoType     string `json:"repo_type"`
+}
+
+// HarborScanOverview is a vulnerability scan report of a HarborResource.
+type HarborScanOverview struct {
+	ScanStatus string `json:"scan_status"`
+	// Severity is the highest severity found, e.g. "High" or "None".
+	Severity string `json:"severity"`
+	Scanner  struct {
+		Name    string `json:"name"`
+		Vendor  string `json:"vendor"`
+		Version string `json:"version"`
+	} `json:"scanner"`
+	Summary struct {
+		Total   int            `json:"total"`
+		Fixable int            `json:"fixable"`
+		Summary map[string]int `json:"summary"`
+	} `json:"summary"`
+}
+
+// ToEvents maps the Harbor event to one Event per artifact. Events of other
+// types than pushes and scans yield no events.
+func (h *HarborEvent) ToEvents() []*Event {
+	var kind Kind
+	switch h.Type {
+	case HarborPushArtifact, "pushImage":
+		kind = KindPush
+	case HarborScanningCompleted, "scanningCompleted":
+		kind = KindScan
+	case HarborScanningFailed, "scanningFailed":
+		kind = KindScanFailed
+	default:
+		return nil
+	}
+
+	repository := h.EventData.Repository.RepoFullName
+	if repository == "" {
+		repository = h.EventData.Repository.Namespace + "/" + h.EventData.Repository.Name
+	}
+
+	events := make([]*Event, 0, len(h.EventData.Resources))
+	for _, resource := range h.EventData.Resources {
+		event := &Event{
+			Kind:       kind,
+			Registry:   registryHost(resource.ResourceURL),
+			Repository: repository,
+			Tag:        resource.Tag,
+			Digest:     resource.Digest,
+			Operator:   h.Operator,
+		}
+		if kind == KindScan {
+			event.Scan = harborScanSummary(resource.ScanOverview)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// harborScanSummary returns the summary of the first scan report, by MIME
+// type, or nil if there is none.
+func harborScanSummary(overview map[string]HarborScanOverview) *ScanSummary {
+	if len(overview) == 0 {
+		return nil
+	}
+	mimeTypes := make([]string, 0, len(overview))
+	for mimeType := range overview {
+		mimeTypes = append(mimeTypes, mimeType)
+	}
+	sort.Strings(mimeTypes)
+
+	report := overview[mimeTypes[0]]
+	return &ScanSummary{
+		Scanner:  report.Scanner.Name,
+		Severity: report.Severity,
+		Total:    report.Summary.Total,
+		Fixable:  report.Summary.Fixable,
+		Counts:   report.Summary.Summary,
+	}
+}
+
+// registryHost returns the host of an image reference such as
+// "harbor.example.com/library/nginx:1.25".
+func registryHost(reference string) string {
+	host, _, ok := strings.Cut(reference, "/")
+	if !ok {
+		return ""
+	}
+	return host
+}
+
+// decodeHarbor decodes a Harbor webhook body.
+func decodeHarbor(body []byte) ([]*Event, error) {
+	var event HarborEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode Harbor event: %w", err)
+	}
+	return event.ToEvents(), nil
+}
@@ -0,0 +1,197 @@
+// Package registry posts container registry events to Feishu as cards:
+// image pushes and vulnerability scan results of Harbor, and image pushes
+// of registries based on the Docker distribution registry.
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Kind is the kind of a registry event.
+type Kind string
+
+const (
+	KindPush       Kind = "push"
+	KindScan       Kind = "scan"
+	KindScanFailed Kind = "scan_failed"
+)
+
+// Severities of vulnerabilities, from highest to lowest.
+const (
+	SeverityCritical = "Critical"
+	SeverityHigh     = "High"
+	SeverityMedium   = "Medium"
+	SeverityLow      = "Low"
+	SeverityUnknown  = "Unknown"
+)
+
+// severityOrder lists severities from highest to lowest.
+var severityOrder = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityUnknown}
+
+// Event is a push or scan of an image, independent of the registry.
+type Event struct {
+	Kind Kind
+	// Registry is the host of the registry, e.g. "harbor.example.com".
+	Registry string
+	// Repository is the image repository, e.g. "library/nginx".
+	Repository string
+	Tag        string
+	Digest     string
+	// Operator is the user or robot account that pushed the image.
+	Operator string
+	// Scan is the vulnerability scan result of scan events.
+	Scan *ScanSummary
+	// URL links the image in the registry UI when set.
+	URL string
+}
+
+// ScanSummary summarizes the vulnerabilities found by a scan.
+type ScanSummary struct {
+	Scanner string
+	// Severity is the highest severity found, or "None".
+	Severity string
+	Total    int
+	Fixable  int
+	// Counts holds the number of vulnerabilities by severity.
+	Counts map[string]int
+}
+
+// Image returns the reference of the image, e.g.
+// "harbor.example.com/library/nginx:1.25", using the digest if the event
+// has no tag.
+func (e *Event) Image() string {
+	image := e.Repository
+	if e.Registry != "" {
+		image = e.Registry + "/" + image
+	}
+	switch {
+	case e.Tag != "":
+		return image + ":" + e.Tag
+	case e.Digest != "":
+		return image + "@" + e.Digest
+	}
+	return image
+}
+
+// Card renders the event.
+func (e *Event) Card() *feishubot.Card {
+	emoji, title, template := "📦", "pushed", feishubot.TemplateBlue
+	switch e.Kind {
+	case KindScan:
+		emoji, title, template = "🛡️", "scanned", scanTemplate(e.Scan)
+	case KindScanFailed:
+		emoji, title, template = "⚠️", "scan failed", feishubot.TemplateGrey
+	}
+
+	name := e.Repository
+	if e.Tag != "" {
+		name += ":" + e.Tag
+	}
+	builder := feishubot.NewCardBuilder().
+		Header(fmt.Sprintf("%s %s %s", emoji, feishubot.Sanitize(name), title), template)
+
+	fields := []feishubot.KV{{Label: "Image", Value: e.Image()}}
+	if e.Digest != "" {
+		fields = append(fields, feishubot.KV{Label: "Digest", Value: shortDigest(e.Digest)})
+	}
+	if e.Operator != "" {
+		fields = append(fields, feishubot.KV{Label: "Pushed by", Value: e.Operator})
+	}
+	if e.Scan != nil && e.Scan.Scanner != "" {
+		fields = append(fields, feishubot.KV{Label: "Scanner", Value: e.Scan.Scanner})
+	}
+	builder.Fields(fields)
+
+	if e.Kind == KindScan && e.Scan != nil {
+		builder.Markdown(e.Scan.markdown())
+	}
+
+	if e.URL != "" {
+		builder.Actions(feishubot.NewButtonElement("Open", "primary", e.URL))
+	}
+	return builder.Build()
+}
+
+// markdown renders the CVE counts by severity, e.g.
+// "**12 vulnerabilities** (5 fixable)\nCritical 1 · High 3 · Low 8".
+func (s *ScanSummary) markdown() string {
+	if s.Total == 0 {
+		return "No vulnerabilities found"
+	}
+
+	line := fmt.Sprintf("**%d vulnerabilities**", s.Total)
+	if s.Fixable > 0 {
+		line += fmt.Sprintf(" (%d fixable)", s.Fixable)
+	}
+
+	var counts []string
+	for _, severity := range sortedSeverities(s.Counts) {
+		if n := s.Counts[severity]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%s %d", severity, n))
+		}
+	}
+	if len(counts) > 0 {
+		line += "\n" + strings.Join(counts, " · ")
+	}
+	return line
+}
+
+// sortedSeverities returns the severities of counts from highest to lowest,
+// followed by unknown names in alphabetical order.
+func sortedSeverities(counts map[string]int) []string {
+	severities := make([]string, 0, len(counts))
+	for _, severity := range severityOrder {
+		if _, ok := counts[severity]; ok {
+			severities = append(severities, severity)
+		}
+	}
+	var other []string
+	for severity := range counts {
+		if severityRank(severity) < 0 {
+			other = append(other, severity)
+		}
+	}
+	sort.Strings(other)
+	return append(severities, other...)
+}
+
+// severityRank returns the position of severity in severityOrder, or -1.
+func severityRank(severity string) int {
+	for i, s := range severityOrder {
+		if strings.EqualFold(s, severity) {
+			return i
+		}
+	}
+	return -1
+}
+
+// scanTemplate returns the header template of a scan by its highest
+// severity.
+func scanTemplate(scan *ScanSummary) string {
+	if scan == nil {
+		return feishubot.TemplateGrey
+	}
+	switch severityRank(scan.Severity) {
+	case 0:
+		return feishubot.TemplateRed
+	case 1:
+		return feishubot.TemplateOrange
+	case 2:
+		return feishubot.TemplateYellow
+	}
+	return feishubot.TemplateGreen
+}
+
+// shortDigest shortens a digest such as "sha256:0123..." to 12 hex
+// characters.
+func shortDigest(digest string) string {
+	algorithm, hex, ok := strings.Cut(digest, ":")
+	if !ok || len(hex) <= 12 {
+		return digest
+	}
+	return algorithm + ":" + hex[:12]
+}
@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"encoding/json"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+const harborScanPayload = `{
+	"type": "SCANNING_COMPLETED",
+	"occur_at": 1700000000,
+	"operator": "auto",
+	"event_data": {
+		"resources": [{
+			"digest": "sha256:954b378c375d852eb3c63ab88978f640b4348b01c1b3456a024a81536dafbbf4",
+			"tag": "1.25",
+			"resource_url": "harbor.example.com/library/nginx:1.25",
+			"scan_overview": {
+				"application/vnd.security.vulnerability.report; version=1.1": {
+					"scan_status": "Success",
+					"severity": "High",
+					"scanner": {"name": "Trivy", "vendor": "Aqua Security", "version": "v0.50.1"},
+					"summary": {"total": 12, "fixable": 5, "summary": {"High": 3, "Low": 8, "Medium": 1}}
+				}
+			}
+		}],
+		"repository": {"name": "nginx", "namespace": "library", "repo_full_name": "library/nginx", "repo_type": "public"}
+	}
+}`
+
+func TestHarborEventToEvents(t *testing.T) {
+	var harbor HarborEvent
+	require.NoError(t, json.Unmarshal([]byte(harborScanPayload), &harbor))
+
+	require.Equal(t, []*Event{{
+		Kind:       KindScan,
+		Registry:   "harbor.example.com",
+		Repository: "library/nginx",
+		Tag:        "1.25",
+		Digest:     "sha256:954b378c375d852eb3c63ab88978f640b4348b01c1b3456a024a81536dafbbf4",
+		Operator:   "auto",
+		Scan: &ScanSummary{
+			Scanner:  "Trivy",
+			Severity: "High",
+			Total:    12,
+			Fixable:  5,
+			Counts:   map[string]int{"High": 3, "Low": 8, "Medium": 1},
+		},
+	}}, harbor.ToEvents())
+
+	harbor.Type = "DELETE_ARTIFACT"
+	require.Empty(t, harbor.ToEvents())
+}
+
+func TestEventCard(t *testing.T) {
+	var harbor HarborEvent
+	require.NoError(t, json.Unmarshal([]byte(harborScanPayload), &harbor))
+	event := harbor.ToEvents()[0]
+	event.URL = "https://harbor.example.com/harbor/projects/1/repositories/nginx"
+
+	data, err := json.Marshal(event.Card())
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {"title": {"tag": "plain_text", "content": "🛡️ library/nginx:1.25 scanned"}, "template": "orange"},
+		"body": {"elements": [
+			{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Image:** harbor.example.com/library/nginx:1.25"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Digest:** sha256:954b378c375d"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Pushed by:** auto"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Scanner:** Trivy"}}
+			]},
+			{"tag": "markdown", "content": "**12 vulnerabilities** (5 fixable)\nHigh 3 · Medium 1 · Low 8"},
+			{"tag": "column_set", "columns": [
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Open"}, "type": "primary", "url": "https://harbor.example.com/harbor/projects/1/repositories/nginx", "width": "fill"}
+				]}
+			]}
+		]}
+	}`, string(data))
+}
+
+func TestEventCardEscapesOperatorOnce(t *testing.T) {
+	event := &Event{Repository: "library/nginx", Tag: "1.25", Operator: "<at id=all></at>"}
+
+	div := event.Card().Body.Elements[0].(*feishubot.Div)
+	require.Equal(t, "**Pushed by:** &lt;at id=all&gt;&lt;/at&gt;", div.Fields[1].Text.Content)
+}
+
+func TestDistributionEnvelopeToEvents(t *testing.T) {
+	var envelope DistributionEnvelope
+	require.NoError(t, json.Unmarshal([]byte(`{"events": [
+		{"id": "1", "action": "push", "target": {"mediaType": "application/octet-stream", "digest": "sha256:aaa", "repository": "team/api"}, "request": {"host": "registry.example.com"}},
+		{"id": "2", "action": "push", "target": {"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "sha256:bbb", "repository": "team/api", "tag": "v1.4.2"}, "request": {"host": "registry.example.com"}, "actor": {"name": "ci-bot"}},
+		{"id": "3", "action": "pull", "target": {"repository": "team/api", "tag": "v1.4.2"}}
+	]}`), &envelope))
+
+	require.Equal(t, []*Event{{
+		Kind:       KindPush,
+		Registry:   "registry.example.com",
+		Repository: "team/api",
+		Tag:        "v1.4.2",
+		Digest:     "sha256:bbb",
+		Operator:   "ci-bot",
+	}}, envelope.ToEvents())
+}
+
+func TestEventImage(t *testing.T) {
+	require.Equal(t, "r.example.com/team/api:v1", (&Event{Registry: "r.example.com", Repository: "team/api", Tag: "v1"}).Image())
+	require.Equal(t, "team/api@sha256:abc", (&Event{Repository: "team/api", Digest: "sha256:abc"}).Image())
+}
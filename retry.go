@@ -0,0 +1,99 @@
+package feishubot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryBackoff is the delay before the first retry if
+// Client.RetryBackoff is zero.
+const DefaultRetryBackoff = time.Second
+
+// MaxRetryDelay caps the doubling delay between retries, unless the retry
+// backoff is longer.
+const MaxRetryDelay = time.Minute
+
+// codeFrequencyLimited is the response code of requests exceeding the rate
+// limit of a bot.
+const codeFrequencyLimited = 11232
+
+// retryableError is an error of a request worth retrying.
+type retryableError struct {
+	err error
+	// after is the delay requested by the server, zero if none.
+	after time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+
+func (e *retryableError) Unwrap() error { return e.err }
+
+// postWithRetry posts a marshalled message, retrying according to
+// MaxRetries and RetryBackoff.
+func (c *Client) postWithRetry(ctx context.Context, body []byte) (*Response, error) {
+	backoff := c.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.wait(ctx); err != nil {
+				return nil, fmt.Errorf("failed to wait for rate limit: %w", err)
+			}
+		}
+		resp, err := c.post(ctx, body)
+		var retryable *retryableError
+		if !errors.As(err, &retryable) {
+			return resp, err
+		}
+		if attempt >= c.MaxRetries {
+			return resp, retryable.err
+		}
+
+		delay := retryDelay(backoff, attempt)
+		if retryable.after > delay {
+			delay = retryable.after
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, retryable.err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns the delay before the retry after attempt, doubling
+// backoff per attempt up to MaxRetryDelay.
+func retryDelay(backoff time.Duration, attempt int) time.Duration {
+	delay := backoff
+	for i := 0; i < attempt && delay < MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > MaxRetryDelay && backoff < MaxRetryDelay {
+		delay = MaxRetryDelay
+	}
+	return delay
+}
+
+// isRetryableStatus reports whether requests failing with the HTTP status
+// are worth retrying.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// retryAfter returns the delay of a Retry-After header in seconds, zero if
+// there is none.
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
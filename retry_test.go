@@ -0,0 +1,108 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer returns a webhook server answering with the given
+// responses in turn, then with success, and the number of requests.
+func newFlakyServer(t *testing.T, responses ...func(w http.ResponseWriter)) (*httptest.Server, *int) {
+	t.Helper()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= len(responses) {
+			responses[calls-1](w)
+			return
+		}
+		json.NewEncoder(w).Encode(SuccessResponse)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestSendRetry(t *testing.T) {
+	server, calls := newFlakyServer(t,
+		func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+		func(w http.ResponseWriter) {
+			json.NewEncoder(w).Encode(Response{Code: codeFrequencyLimited, Msg: "frequency limited"})
+		},
+	)
+
+	client := NewClient(server.URL, "")
+	_, err := client.Send(context.Background(), NewTextMessage("hi"))
+	require.Error(t, err)
+	require.Equal(t, 1, *calls)
+
+	*calls = 0
+	client.SetRetry(2, time.Millisecond)
+	resp, err := client.Send(context.Background(), NewTextMessage("hi"))
+	require.NoError(t, err)
+	require.Equal(t, 0, resp.Code)
+	require.Equal(t, 3, *calls)
+}
+
+func TestSendRetryGivesUp(t *testing.T) {
+	limited := func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(Response{Code: codeFrequencyLimited, Msg: "frequency limited"})
+	}
+	server, calls := newFlakyServer(t, limited, limited, limited)
+
+	client := NewClient(server.URL, "")
+	client.SetRetry(1, time.Millisecond)
+	resp, err := client.Send(context.Background(), NewTextMessage("hi"))
+	require.EqualError(t, err, "API error (code 11232): frequency limited")
+	require.Equal(t, codeFrequencyLimited, resp.Code)
+	require.Equal(t, 2, *calls)
+}
+
+func TestSendNoRetry(t *testing.T) {
+	server, calls := newFlakyServer(t, func(w http.ResponseWriter) {
+		json.NewEncoder(w).Encode(Response{Code: 19021, Msg: "sign match fail"})
+	})
+
+	client := NewClient(server.URL, "")
+	client.SetRetry(3, time.Millisecond)
+	_, err := client.Send(context.Background(), NewTextMessage("hi"))
+	require.EqualError(t, err, "API error (code 19021): sign match fail")
+	require.Equal(t, 1, *calls)
+}
+
+func TestSendRetryContext(t *testing.T) {
+	server, calls := newFlakyServer(t, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	client := NewClient(server.URL, "")
+	client.SetRetry(3, time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Send(ctx, NewTextMessage("hi"))
+	require.ErrorContains(t, err, "failed to unmarshal response")
+	require.Equal(t, 1, *calls)
+}
+
+func TestRetryAfter(t *testing.T) {
+	require.Equal(t, 2*time.Second, retryAfter(http.Header{"Retry-After": []string{"2"}}))
+	require.Zero(t, retryAfter(http.Header{"Retry-After": []string{"Wed, 21 Oct 2026 07:28:00 GMT"}}))
+	require.Zero(t, retryAfter(http.Header{}))
+}
+
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, time.Second, retryDelay(time.Second, 0))
+	require.Equal(t, 4*time.Second, retryDelay(time.Second, 2))
+	require.Equal(t, 32*time.Second, retryDelay(time.Second, 5))
+	require.Equal(t, MaxRetryDelay, retryDelay(time.Second, 6))
+	require.Equal(t, MaxRetryDelay, retryDelay(time.Second, 100))
+	require.Equal(t, MaxRetryDelay, retryDelay(time.Millisecond, 1000))
+	require.Equal(t, 2*time.Minute, retryDelay(2*time.Minute, 10))
+}
@@ -0,0 +1,154 @@
+package feishubot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Errors returned by Router.Send.
+var (
+	// ErrNoRoute is returned for messages matching no route.
+	ErrNoRoute = errors.New("no route matches")
+	// ErrQuietHours is returned for messages not sent to any webhook
+	// because of quiet hours.
+	ErrQuietHours = errors.New("quiet hours")
+)
+
+// Route sends messages with matching labels to webhooks of a Router.
+type Route struct {
+	// Match are the labels a message must have, with the same values. An
+	// empty Match matches all messages.
+	Match map[string]string `yaml:"match" json:"match"`
+	// Webhooks are the names of the webhooks messages are sent to.
+	Webhooks []string `yaml:"webhooks" json:"webhooks"`
+	// Continue also applies the following routes to matching messages.
+	// Otherwise the first matching route is the only one applied.
+	Continue bool `yaml:"continue" json:"continue"`
+	// IgnoreQuietHours sends matching messages during quiet hours, e.g.
+	// critical alerts.
+	IgnoreQuietHours bool `yaml:"ignore_quiet_hours" json:"ignore_quiet_hours"`
+}
+
+// matches reports whether the route matches a message with the labels.
+func (r *Route) matches(labels map[string]string) bool {
+	for name, value := range r.Match {
+		if v, ok := labels[name]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Router sends messages to webhooks chosen by their labels, such as their
+// team or severity, like the routes of Alertmanager, keeping quiet hours.
+//
+// Example:
+//
+//	router, err := feishubot.NewRouter(map[string]*feishubot.Client{
+//		"oncall": feishubot.NewClient(oncallURL, oncallSecret),
+//		"team":   feishubot.NewClient(teamURL, teamSecret),
+//	},
+//		feishubot.Route{Match: map[string]string{"severity": "critical"}, Webhooks: []string{"oncall"}, Continue: true, IgnoreQuietHours: true},
+//		feishubot.Route{Webhooks: []string{"team"}},
+//	)
+//	router.QuietHours = &feishubot.QuietHours{Start: "22:00", End: "08:00"}
+//	err = router.Send(ctx, map[string]string{"severity": "critical"}, msg)
+type Router struct {
+	clients map[string]*Client
+	routes  []Route
+
+	// QuietHours, if set, is the period routes not ignoring quiet hours do
+	// not send messages in.
+	QuietHours *QuietHours
+
+	now func() time.Time
+}
+
+// NewRouter creates a router sending with the clients, by webhook name,
+// according to routes tried in order.
+func NewRouter(clients map[string]*Client, routes ...Route) (*Router, error) {
+	for i, route := range routes {
+		if len(route.Webhooks) == 0 {
+			return nil, fmt.Errorf("route %d has no webhooks", i)
+		}
+		for _, name := range route.Webhooks {
+			if clients[name] == nil {
+				return nil, fmt.Errorf("route %d sends to unknown webhook %q", i, name)
+			}
+		}
+	}
+	return &Router{clients: clients, routes: routes, now: time.Now}, nil
+}
+
+// Client returns the client of the named webhook, nil if there is none.
+func (r *Router) Client(name string) *Client {
+	return r.clients[name]
+}
+
+// Webhooks returns the names of the webhooks, sorted.
+func (r *Router) Webhooks() []string {
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Send sends msg to the webhooks of the routes matching labels, once per
+// webhook. During quiet hours, only routes ignoring quiet hours are
+// applied; if that leaves no webhook, an error wrapping ErrQuietHours is
+// returned. Messages matching no route return an error wrapping
+// ErrNoRoute.
+//
+// Sending continues with the remaining webhooks after a failure; the
+// returned error names the webhooks that failed.
+func (r *Router) Send(ctx context.Context, labels map[string]string, msg *Message) error {
+	quiet := r.QuietHours != nil && r.QuietHours.Contains(r.now())
+
+	var (
+		webhooks []string
+		matched  bool
+		seen     = make(map[string]bool)
+	)
+	for i := range r.routes {
+		route := &r.routes[i]
+		if !route.matches(labels) {
+			continue
+		}
+		matched = true
+		if !quiet || route.IgnoreQuietHours {
+			for _, name := range route.Webhooks {
+				if !seen[name] {
+					seen[name] = true
+					webhooks = append(webhooks, name)
+				}
+			}
+		}
+		if !route.Continue {
+			break
+		}
+	}
+
+	switch {
+	case !matched:
+		return fmt.Errorf("failed to route message with labels %v: %w", labels, ErrNoRoute)
+	case len(webhooks) == 0:
+		return fmt.Errorf("message with labels %v not sent: %w", labels, ErrQuietHours)
+	}
+
+	var failures []string
+	for _, name := range webhooks {
+		if _, err := r.clients[name].Send(ctx, msg); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to send to %d of %d webhooks: %s", len(failures), len(webhooks), strings.Join(failures, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newRouterServer starts a webhook server recording the paths messages are
+// posted to, failing posts to /broken.
+func newRouterServer(t *testing.T, paths *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*paths = append(*paths, r.URL.Path)
+		if r.URL.Path == "/broken" {
+			json.NewEncoder(w).Encode(Response{Code: 9499, Msg: "Bad Request"})
+			return
+		}
+		json.NewEncoder(w).Encode(SuccessResponse)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRouter(t *testing.T) {
+	var paths []string
+	server := newRouterServer(t, &paths)
+	router, err := NewRouter(map[string]*Client{
+		"oncall": NewClient(server.URL+"/oncall", ""),
+		"team":   NewClient(server.URL+"/team", ""),
+		"db":     NewClient(server.URL+"/db", ""),
+	},
+		Route{Match: map[string]string{"severity": "critical"}, Webhooks: []string{"oncall"}, Continue: true, IgnoreQuietHours: true},
+		Route{Match: map[string]string{"team": "db"}, Webhooks: []string{"db", "oncall"}},
+		Route{Webhooks: []string{"team"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"db", "oncall", "team"}, router.Webhooks())
+	require.NotNil(t, router.Client("team"))
+
+	ctx := context.Background()
+	msg := NewTextMessage("disk full")
+	require.NoError(t, router.Send(ctx, map[string]string{"severity": "critical", "team": "db"}, msg))
+	require.Equal(t, []string{"/oncall", "/db"}, paths)
+
+	paths = nil
+	require.NoError(t, router.Send(ctx, map[string]string{"severity": "warning"}, msg))
+	require.Equal(t, []string{"/team"}, paths)
+
+	// Quiet hours all day
+	router.QuietHours = &QuietHours{}
+	router.now = func() time.Time { return time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC) }
+	paths = nil
+	require.NoError(t, router.Send(ctx, map[string]string{"severity": "critical"}, msg))
+	require.Equal(t, []string{"/oncall"}, paths)
+	require.ErrorIs(t, router.Send(ctx, map[string]string{"severity": "warning"}, msg), ErrQuietHours)
+}
+
+func TestRouterErrors(t *testing.T) {
+	var paths []string
+	server := newRouterServer(t, &paths)
+	clients := map[string]*Client{
+		"team":   NewClient(server.URL+"/team", ""),
+		"broken": NewClient(server.URL+"/broken", ""),
+	}
+
+	_, err := NewRouter(clients, Route{Webhooks: []string{"missing"}})
+	require.EqualError(t, err, `route 0 sends to unknown webhook "missing"`)
+	_, err = NewRouter(clients, Route{})
+	require.EqualError(t, err, "route 0 has no webhooks")
+
+	router, err := NewRouter(clients, Route{Match: map[string]string{"team": "web"}, Webhooks: []string{"broken", "team"}})
+	require.NoError(t, err)
+	require.ErrorIs(t, router.Send(context.Background(), map[string]string{"team": "db"}, NewTextMessage("hi")), ErrNoRoute)
+
+	err = router.Send(context.Background(), map[string]string{"team": "web"}, NewTextMessage("hi"))
+	require.EqualError(t, err, "failed to send to 1 of 2 webhooks: broken: API error (code 9499): Bad Request")
+	require.Equal(t, []string{"/broken", "/team"}, paths)
+}
@@ -0,0 +1,136 @@
+package feishubot
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// mentionTagPattern matches the opening of <at> and </at> tags, in any case
+// and with optional whitespace, so that they can be neutralized.
+var mentionTagPattern = regexp.MustCompile(`(?i)<(\s*/?\s*at\b)`)
+
+// Sanitize makes text originating from external users safe to embed in a
+// message.
+//
+// It escapes <at> mention tags so that untrusted input such as commit messages
+// cannot @ mention users or the whole group, and strips control characters
+// (except newline and tab) as well as bidirectional formatting characters
+// that could be used to disguise the displayed text.
+//
+// Example:
+//
+//	text := fmt.Sprintf("New commit: %s", feishubot.Sanitize(commit.Message))
+//	message := feishubot.NewTextMessage(text)
+func Sanitize(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r) {
+			return -1
+		}
+		return r
+	}, text)
+
+	return mentionTagPattern.ReplaceAllString(text, "&lt;$1")
+}
+
+// markdownEscaper replaces characters with a meaning in card markdown by
+// HTML entities, which card markdown renders as the characters.
+var markdownEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"*", "&#42;",
+	"_", "&#95;",
+	"~", "&#126;",
+	"`", "&#96;",
+	"[", "&#91;",
+	"]", "&#93;",
+	"\\", "&#92;",
+)
+
+// EscapeMarkdown escapes text to be shown literally within a line of card
+// markdown, such as markdown elements and lark_md texts, so that untrusted
+// input cannot add emphasis, links, <font> or <at> tags, or break the
+// surrounding markup.
+//
+// Example:
+//
+//	builder.Markdown("**Branch:** " + feishubot.EscapeMarkdown(branch))
+func EscapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// sanitizedPostTags are the tags of post elements whose text is sanitized:
+// plain text, markdown and link texts, all of which render <at> tags.
+var sanitizedPostTags = map[interface{}]bool{
+	"text": true,
+	"md":   true,
+	"a":    true,
+}
+
+// sanitizeContent returns a copy of the message content with Sanitize applied
+// to the text of text messages and to the title and the text, markdown and
+// link elements of post messages. Other message types are returned
+// unchanged.
+func sanitizeContent(msgType MsgType, content map[string]interface{}) map[string]interface{} {
+	switch msgType {
+	case MsgTypeText:
+		text, ok := content["text"].(string)
+		if !ok {
+			return content
+		}
+		return map[string]interface{}{"text": Sanitize(text)}
+
+	case MsgTypePost:
+		post, ok := content["post"].(map[string]interface{})
+		if !ok {
+			return content
+		}
+		sanitizedPost := make(map[string]interface{}, len(post))
+		for lang, v := range post {
+			sanitizedPost[lang] = sanitizePostLanguage(v)
+		}
+		return map[string]interface{}{"post": sanitizedPost}
+	}
+
+	return content
+}
+
+// sanitizePostLanguage sanitizes a single language section of a post message.
+func sanitizePostLanguage(v interface{}) interface{} {
+	section, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	result := make(map[string]interface{}, len(section))
+	for k, v := range section {
+		result[k] = v
+	}
+	if title, ok := section["title"].(string); ok {
+		result["title"] = Sanitize(title)
+	}
+
+	paragraphs, ok := section["content"].([][]map[string]interface{})
+	if !ok {
+		return result
+	}
+	sanitized := make([][]map[string]interface{}, 0, len(paragraphs))
+	for _, p := range paragraphs {
+		paragraph := make([]map[string]interface{}, 0, len(p))
+		for _, e := range p {
+			if text, ok := e["text"].(string); ok && sanitizedPostTags[e["tag"]] {
+				e = copyElement(e)
+				e["text"] = Sanitize(text)
+			}
+			paragraph = append(paragraph, e)
+		}
+		sanitized = append(sanitized, paragraph)
+	}
+	result["content"] = sanitized
+
+	return result
+}
@@ -0,0 +1,127 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "plain text unchanged",
+			text: "fix: handle nil pointer",
+			want: "fix: handle nil pointer",
+		},
+		{
+			name: "mention all escaped",
+			text: `<at user_id="all">所有人</at> merged`,
+			want: `&lt;at user_id="all">所有人&lt;/at> merged`,
+		},
+		{
+			name: "mixed case mention escaped",
+			text: `< AT user_id="ou_xxx">Tom< / At>`,
+			want: `&lt; AT user_id="ou_xxx">Tom&lt; / At>`,
+		},
+		{
+			name: "other tags kept",
+			text: "<b>bold</b> <attr>",
+			want: "<b>bold</b> <attr>",
+		},
+		{
+			name: "control characters stripped",
+			text: "line1\nline2\tend\x00\x1b[31m\r",
+			want: "line1\nline2\tend[31m",
+		},
+		{
+			name: "bidi characters stripped",
+			text: "safe‮txt.exe",
+			want: "safetxt.exe",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sanitize(tt.text)
+			if got != tt.want {
+				t.Errorf("Sanitize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendSanitizeText(t *testing.T) {
+	var capturedMsg Message
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedMsg = Message{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&capturedMsg))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(SuccessResponse)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/webhook", "")
+	client.SetSanitizeText(true)
+
+	t.Run("text message", func(t *testing.T) {
+		message := NewTextMessage(`<at user_id="all">x</at>`)
+		_, err := client.Send(context.Background(), message)
+		require.NoError(t, err)
+
+		require.Equal(t, `&lt;at user_id="all">x&lt;/at>`, capturedMsg.Content["text"])
+		// The original message must not be modified
+		require.Equal(t, `<at user_id="all">x</at>`, message.Content["text"])
+	})
+
+	t.Run("post message", func(t *testing.T) {
+		message := NewPostMessage(LanguageZhCN, NewPostContent(
+			"Title",
+			NewParagraph(
+				NewTextElement(`<at user_id="all">x</at>`),
+				NewAtElement("ou_xxx", "Tom"),
+			),
+			NewParagraph(NewMdElement(`**Author:** <at user_id="all"></at>`)),
+			NewParagraph(NewLinkElement(`<AT user_id="all">docs</AT>`, "https://example.com")),
+		))
+		original, err := json.Marshal(message)
+		require.NoError(t, err)
+
+		_, err = client.Send(context.Background(), message)
+		require.NoError(t, err)
+
+		paragraph := capturedMsg.Content["post"].(map[string]any)["zh_cn"].(map[string]any)["content"].([]any)[0].([]any)
+		require.Equal(t, `&lt;at user_id="all">x&lt;/at>`, paragraph[0].(map[string]any)["text"])
+		require.Equal(t, "ou_xxx", paragraph[1].(map[string]any)["user_id"])
+
+		content := capturedMsg.Content["post"].(map[string]any)["zh_cn"].(map[string]any)["content"].([]any)
+		md := content[1].([]any)[0].(map[string]any)
+		require.Equal(t, `**Author:** &lt;at user_id="all">&lt;/at>`, md["text"])
+		link := content[2].([]any)[0].(map[string]any)
+		require.Equal(t, `&lt;AT user_id="all">docs&lt;/AT>`, link["text"])
+		require.Equal(t, "https://example.com", link["href"])
+
+		// The original message must not be modified
+		after, err := json.Marshal(message)
+		require.NoError(t, err)
+		require.JSONEq(t, string(original), string(after))
+	})
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	require.Equal(t, "plain text #1", EscapeMarkdown("plain text #1"))
+	require.Equal(t,
+		"&#42;&#42;bold&#42;&#42; &#95;it&#95; &#126;&#126;s&#126;&#126; &#96;code&#96; &#91;a&#93;(b) &#92;",
+		EscapeMarkdown("**bold** _it_ ~~s~~ `code` [a](b) \\"))
+	require.Equal(t, `&lt;at id=all&gt;&lt;/at&gt; &amp;lt;`, EscapeMarkdown("<at id=all></at> &lt;"))
+}
@@ -0,0 +1,135 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the YAML configuration of a scheduler.
+//
+// Example:
+//
+//	timezone: Asia/Shanghai
+//	holidays: [2024-10-01, 2024-10-02, 2024-10-03]
+//	jobs:
+//	  - name: standup
+//	    cron: "25 9 * * mon-fri"
+//	    skip_holidays: true
+//	    text: "Standup in 5 minutes"
+//	  - name: weekly-report
+//	    cron: "0 16 * * fri"
+//	    title: "Weekly report"
+//	    markdown: "Please submit your report for the week of {{.Time.Format \"Jan 2\"}}"
+type Config struct {
+	// Timezone is the default IANA time zone of jobs. If empty, the local
+	// time zone is used.
+	Timezone string `yaml:"timezone"`
+	// Holidays are dates ("2006-01-02") on which jobs with skip_holidays
+	// are skipped.
+	Holidays []string    `yaml:"holidays"`
+	Jobs     []JobConfig `yaml:"jobs"`
+}
+
+// JobConfig is the YAML configuration of a job. Exactly one of Text,
+// Markdown and Card must be set.
+type JobConfig struct {
+	Name string `yaml:"name"`
+	Cron string `yaml:"cron"`
+	// Timezone overrides the time zone of the config.
+	Timezone     string `yaml:"timezone"`
+	SkipHolidays bool   `yaml:"skip_holidays"`
+
+	// Text is a text message template, see Text.
+	Text string `yaml:"text"`
+	// Title and Markdown are the templates of a markdown card, see
+	// Markdown.
+	Title    string `yaml:"title"`
+	Markdown string `yaml:"markdown"`
+	// Card is a card JSON with variables, see Card.
+	Card string `yaml:"card"`
+}
+
+// ParseConfig parses a YAML scheduler configuration.
+func ParseConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule config: %w", err)
+	}
+	return &config, nil
+}
+
+// NewFromConfig creates a scheduler sending messages with send, with the
+// time zone, holidays and jobs of the config.
+func NewFromConfig(send feishubot.SendFunc, config *Config) (*Scheduler, error) {
+	s := New(send)
+	if config.Timezone != "" {
+		loc, err := time.LoadLocation(config.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %w", err)
+		}
+		s.Location = loc
+	}
+
+	if len(config.Holidays) > 0 {
+		holidays := make(map[string]bool, len(config.Holidays))
+		for _, date := range config.Holidays {
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				return nil, fmt.Errorf("invalid holiday %q: %w", date, err)
+			}
+			holidays[date] = true
+		}
+		s.Holiday = func(t time.Time) bool {
+			return holidays[t.Format("2006-01-02")]
+		}
+	}
+
+	for _, jc := range config.Jobs {
+		job, err := jc.job()
+		if err != nil {
+			return nil, err
+		}
+		if err := s.Add(job); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// job returns the job of the config.
+func (c JobConfig) job() (Job, error) {
+	job := Job{Name: c.Name, Cron: c.Cron, SkipHolidays: c.SkipHolidays}
+	if c.Timezone != "" {
+		loc, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return Job{}, fmt.Errorf("job %q: invalid timezone: %w", c.Name, err)
+		}
+		job.Location = loc
+	}
+
+	set := 0
+	for _, s := range []string{c.Text, c.Markdown, c.Card} {
+		if s != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return Job{}, fmt.Errorf("job %q: exactly one of text, markdown and card is required", c.Name)
+	}
+
+	var err error
+	switch {
+	case c.Text != "":
+		job.Message, err = Text(c.Text)
+	case c.Markdown != "":
+		job.Message, err = Markdown(c.Title, c.Markdown)
+	default:
+		job.Message = Card([]byte(c.Card))
+	}
+	if err != nil {
+		return Job{}, fmt.Errorf("job %q: %w", c.Name, err)
+	}
+	return job, nil
+}
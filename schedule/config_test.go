@@ -0,0 +1,84 @@
+package schedule
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+timezone: Asia/Shanghai
+holidays: [2024-10-01]
+jobs:
+  - name: standup
+    cron: "25 9 * * mon-fri"
+    skip_holidays: true
+    text: "Standup in 5 minutes"
+  - name: weekly-report
+    cron: "0 16 * * fri"
+    timezone: Europe/Berlin
+    title: "Weekly report"
+    markdown: "Report for the week of {{.Time.Format \"Jan 2\"}} is due"
+  - name: oncall
+    cron: "@weekly"
+    card: '{"schema": "2.0", "body": {"elements": [{"tag": "markdown", "content": "On-call handover ${date} (${weekday})"}]}}'
+`
+
+func TestNewFromConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(testConfig))
+	require.NoError(t, err)
+
+	scheduler, err := NewFromConfig(nil, config)
+	require.NoError(t, err)
+	require.Equal(t, "Asia/Shanghai", scheduler.Location.String())
+	require.True(t, scheduler.Holiday(time.Date(2024, 10, 1, 9, 25, 0, 0, scheduler.Location)))
+	require.False(t, scheduler.Holiday(time.Date(2024, 10, 8, 9, 25, 0, 0, scheduler.Location)))
+	require.Len(t, scheduler.entries, 3)
+	require.True(t, scheduler.entries[0].job.SkipHolidays)
+	require.Equal(t, "Europe/Berlin", scheduler.entries[1].job.Location.String())
+
+	at := time.Date(2024, 5, 3, 16, 0, 0, 0, time.UTC)
+	msg, err := scheduler.entries[1].job.Message(Run{Job: "weekly-report", Time: at})
+	require.NoError(t, err)
+	data, err := json.Marshal(msg.TypedCard)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {"title": {"tag": "plain_text", "content": "Weekly report"}, "template": "blue"},
+		"body": {"elements": [{"tag": "markdown", "content": "Report for the week of May 3 is due"}]}
+	}`, string(data))
+
+	msg, err = scheduler.entries[2].job.Message(Run{Job: "oncall", Time: at})
+	require.NoError(t, err)
+	require.Equal(t, "On-call handover 2024-05-03 (Friday)",
+		msg.Card["body"].(map[string]interface{})["elements"].([]interface{})[0].(map[string]interface{})["content"])
+}
+
+func TestNewFromConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{name: "timezone", config: "timezone: Mars/Olympus", want: "invalid timezone"},
+		{name: "holiday", config: "holidays: [tomorrow]", want: `invalid holiday "tomorrow"`},
+		{name: "no message", config: "jobs: [{name: a, cron: '@daily'}]", want: `job "a": exactly one of text, markdown and card is required`},
+		{name: "two messages", config: "jobs: [{name: a, cron: '@daily', text: x, markdown: y}]", want: "exactly one of"},
+		{name: "template", config: "jobs: [{name: a, cron: '@daily', text: '{{.Job'}]", want: "failed to parse text template"},
+		{name: "cron", config: "jobs: [{name: a, cron: 'soon', text: x}]", want: "invalid cron expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseConfig([]byte(tt.config))
+			require.NoError(t, err)
+			_, err = NewFromConfig(nil, config)
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	_, err := ParseConfig([]byte("jobs: {"))
+	require.Error(t, err)
+}
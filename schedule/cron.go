@@ -0,0 +1,206 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCron is returned for cron expressions that cannot be parsed.
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// maxSearchYears bounds the search of Cron.Next for expressions that never
+// match, such as "0 0 30 2 *".
+const maxSearchYears = 5
+
+// cronDescriptors maps the supported @ descriptors to expressions.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describes a field of cron expressions.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	dayField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// weekdayField allows 7 for Sunday, folded into 0 when parsing.
+	weekdayField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Cron is a parsed cron expression.
+type Cron struct {
+	minute, hour, day, month, weekday uint64
+	// dayStar and weekdayStar record unrestricted day fields: if both day
+	// fields are restricted, a time matches if either matches.
+	dayStar, weekdayStar bool
+}
+
+// ParseCron parses a standard five-field cron expression ("minute hour
+// day-of-month month day-of-week"). Fields support *, lists, ranges and
+// steps, e.g. "*/15", "1-5" or "mon,wed,fri", and month and weekday names.
+// The descriptors @yearly, @monthly, @weekly, @daily and @hourly are
+// supported too.
+func ParseCron(expr string) (*Cron, error) {
+	spec := strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w %q: expected 5 fields, got %d", ErrInvalidCron, expr, len(fields))
+	}
+
+	var c Cron
+	var err error
+	parsers := []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{minuteField, &c.minute},
+		{hourField, &c.hour},
+		{dayField, &c.day},
+		{monthField, &c.month},
+		{weekdayField, &c.weekday},
+	}
+	for i, p := range parsers {
+		if *p.bits, err = parseCronField(fields[i], p.field); err != nil {
+			return nil, fmt.Errorf("%w %q: %v", ErrInvalidCron, expr, err)
+		}
+	}
+	if c.weekday&(1<<7) != 0 {
+		c.weekday = c.weekday&^(1<<7) | 1
+	}
+	c.dayStar = fields[2] == "*" || fields[2] == "?"
+	c.weekdayStar = fields[4] == "*" || fields[4] == "?"
+	return &c, nil
+}
+
+// parseCronField parses a field into a bit set of the matching values.
+func parseCronField(s string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepPart, field.name)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			lo, hi = field.min, field.max
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = field.value(from); err != nil {
+				return 0, err
+			}
+			if hi, err = field.value(to); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangePart, field.name)
+			}
+		default:
+			var err error
+			if lo, err = field.value(rangePart); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if hasStep {
+				hi = field.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a number or name of the field.
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field", s, f.name)
+	}
+	return v, nil
+}
+
+// Next returns the first time after t matching the expression, in the
+// location of t, or the zero time if there is none within 5 years.
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + maxSearchYears
+
+wrap:
+	for t.Year() <= limit {
+		for c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			if t.Month() == time.January {
+				continue wrap
+			}
+		}
+		for !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			if t.Day() == 1 {
+				continue wrap
+			}
+		}
+		for c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if t.Hour() == 0 {
+				continue wrap
+			}
+		}
+		for c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			if t.Minute() == 0 {
+				continue wrap
+			}
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether the day of t matches the day-of-month and
+// day-of-week fields.
+func (c *Cron) dayMatches(t time.Time) bool {
+	day := c.day&(1<<uint(t.Day())) != 0
+	weekday := c.weekday&(1<<uint(t.Weekday())) != 0
+	if c.dayStar || c.weekdayStar {
+		return day && weekday
+	}
+	return day || weekday
+}
@@ -0,0 +1,60 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNext(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	// Wednesday
+	from := time.Date(2024, 5, 1, 10, 30, 15, 0, shanghai)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2024, 5, 1, 10, 31, 0, 0, shanghai)},
+		{expr: "*/15 * * * *", want: time.Date(2024, 5, 1, 10, 45, 0, 0, shanghai)},
+		{expr: "25 9 * * mon-fri", want: time.Date(2024, 5, 2, 9, 25, 0, 0, shanghai)},
+		{expr: "0 16 * * FRI", want: time.Date(2024, 5, 3, 16, 0, 0, 0, shanghai)},
+		{expr: "0 10 * * 7", want: time.Date(2024, 5, 5, 10, 0, 0, 0, shanghai)},
+		{expr: "0 9 1,15 * *", want: time.Date(2024, 5, 15, 9, 0, 0, 0, shanghai)},
+		{expr: "0 9 15 * mon", want: time.Date(2024, 5, 6, 9, 0, 0, 0, shanghai)},
+		{expr: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, shanghai)},
+		{expr: "30 10 1 may *", want: time.Date(2025, 5, 1, 10, 30, 0, 0, shanghai)},
+		{expr: "0 8-18/4 * * *", want: time.Date(2024, 5, 1, 12, 0, 0, 0, shanghai)},
+		{expr: "@monthly", want: time.Date(2024, 6, 1, 0, 0, 0, 0, shanghai)},
+		{expr: "@hourly", want: time.Date(2024, 5, 1, 11, 0, 0, 0, shanghai)},
+		{expr: "0 0 31 2 *", want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, cron.Next(from))
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * * fun",
+	} {
+		_, err := ParseCron(expr)
+		require.ErrorIs(t, err, ErrInvalidCron, expr)
+	}
+}
@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Must returns fn, panicking if err is not nil, for use in variable
+// initializations like template.Must.
+func Must(fn MessageFunc, err error) MessageFunc {
+	if err != nil {
+		panic(err)
+	}
+	return fn
+}
+
+// Text returns a MessageFunc of a text message with the given template,
+// executed with text/template on the Run, e.g.
+// "Weekly report for {{.Time.Format \"Jan 2\"}} is due".
+func Text(text string) (MessageFunc, error) {
+	tmpl, err := template.New("text").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text template: %w", err)
+	}
+	return func(run Run) (*feishubot.Message, error) {
+		text, err := execute(tmpl, run)
+		if err != nil {
+			return nil, err
+		}
+		return feishubot.NewTextMessage(text), nil
+	}, nil
+}
+
+// Markdown returns a MessageFunc of a card with the given title and
+// markdown, both templates executed like the template of Text.
+func Markdown(title, markdown string) (MessageFunc, error) {
+	titleTmpl, err := template.New("title").Option("missingkey=error").Parse(title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse title template: %w", err)
+	}
+	markdownTmpl, err := template.New("markdown").Option("missingkey=error").Parse(markdown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse markdown template: %w", err)
+	}
+	return func(run Run) (*feishubot.Message, error) {
+		title, err := execute(titleTmpl, run)
+		if err != nil {
+			return nil, err
+		}
+		content, err := execute(markdownTmpl, run)
+		if err != nil {
+			return nil, err
+		}
+		builder := feishubot.NewCardBuilder().Markdown(content)
+		if title != "" {
+			builder.Header(title, feishubot.TemplateBlue)
+		}
+		return feishubot.NewInteractiveMessage(builder.Build()), nil
+	}, nil
+}
+
+// Card returns a MessageFunc of a card given as JSON, such as exported from
+// the Feishu card builder tool, with the variables ${job}, ${date}
+// ("2006-01-02"), ${time} ("15:04") and ${weekday} ("Monday") substituted
+// with SubstituteCardVariables.
+func Card(cardJSON []byte) MessageFunc {
+	return func(run Run) (*feishubot.Message, error) {
+		card, err := feishubot.SubstituteCardVariables(cardJSON, map[string]interface{}{
+			"job":     run.Job,
+			"date":    run.Time.Format("2006-01-02"),
+			"time":    run.Time.Format("15:04"),
+			"weekday": run.Time.Weekday().String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return feishubot.NewInteractiveMessageFromMap(card), nil
+	}
+}
+
+// execute executes a template on a run.
+func execute(tmpl *template.Template, run Run) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, run); err != nil {
+		return "", fmt.Errorf("failed to execute %s template: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
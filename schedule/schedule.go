@@ -0,0 +1,284 @@
+// Package schedule sends messages to Feishu on cron schedules, such as
+// standup reminders or weekly report prompts, with time zones, skipping of
+// holidays and graceful shutdown. Jobs are added programmatically or loaded
+// from YAML, see ParseConfig.
+//
+// Example:
+//
+//	scheduler := schedule.New(feishubot.WebhookSender(feishubot.NewClient(webhookURL, secret)))
+//	scheduler.Location, _ = time.LoadLocation("Asia/Shanghai")
+//	err := scheduler.Add(schedule.Job{
+//		Name:         "standup",
+//		Cron:         "25 9 * * mon-fri",
+//		SkipHolidays: true,
+//		Message:      schedule.Must(schedule.Text("Standup in 5 minutes")),
+//	})
+//	scheduler.Start()
+//	defer scheduler.Shutdown(context.Background())
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// DefaultTimeout is the default timeout of sending a scheduled message.
+const DefaultTimeout = 30 * time.Second
+
+// ErrSchedulerStarted is returned when adding jobs to a started scheduler.
+var ErrSchedulerStarted = errors.New("scheduler already started")
+
+// Run is a scheduled run of a job.
+type Run struct {
+	// Job is the name of the job.
+	Job string
+	// Time is the scheduled time in the location of the job.
+	Time time.Time
+}
+
+// MessageFunc returns the message of a run, see Text, Markdown and Card. A
+// nil message skips the run, e.g. when a check finds nothing to report.
+type MessageFunc func(run Run) (*feishubot.Message, error)
+
+// Job is a message sent on a cron schedule.
+type Job struct {
+	Name string
+	// Cron is the schedule, see ParseCron.
+	Cron string
+	// Location is the time zone of the schedule. If nil, the location of
+	// the scheduler is used.
+	Location *time.Location
+	// SkipHolidays skips runs on days Scheduler.Holiday reports as
+	// holidays.
+	SkipHolidays bool
+	Message      MessageFunc
+	// Send, if set, sends the messages of the job instead of the sender of
+	// the scheduler, e.g. to post to another chat.
+	Send feishubot.SendFunc
+}
+
+// entry is a job added to a scheduler.
+type entry struct {
+	job  Job
+	cron *Cron
+	next time.Time
+}
+
+// Scheduler sends the messages of jobs when they are due.
+type Scheduler struct {
+	send feishubot.SendFunc
+
+	// Location is the default time zone of jobs. If nil, time.Local is
+	// used.
+	Location *time.Location
+
+	// Holiday, if set, reports whether the date of t is a holiday, on which
+	// runs of jobs with SkipHolidays are skipped. It is called with the
+	// scheduled time in the location of the job.
+	Holiday func(t time.Time) bool
+
+	// Timeout bounds sending a message. If zero, DefaultTimeout is used.
+	Timeout time.Duration
+
+	// OnError is called with errors of runs. If nil, such errors are
+	// ignored.
+	OnError func(run Run, err error)
+
+	mu      sync.Mutex
+	entries []*entry
+	started bool
+	stop    chan struct{}
+	done    chan struct{}
+	running sync.WaitGroup
+
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// New creates a scheduler sending messages with send.
+func New(send feishubot.SendFunc) *Scheduler {
+	return &Scheduler{
+		send:  send,
+		now:   time.Now,
+		after: time.After,
+	}
+}
+
+// Add adds a job. Jobs must be added before Start.
+func (s *Scheduler) Add(job Job) error {
+	if job.Message == nil {
+		return fmt.Errorf("job %q has no message", job.Name)
+	}
+	cron, err := ParseCron(job.Cron)
+	if err != nil {
+		return fmt.Errorf("job %q: %w", job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return ErrSchedulerStarted
+	}
+	s.entries = append(s.entries, &entry{job: job, cron: cron})
+	return nil
+}
+
+// Start starts sending the messages of the jobs in the background.
+// Starting a started scheduler does nothing.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.loop()
+}
+
+// Shutdown stops scheduling runs and waits for messages being sent, until
+// ctx is done. It returns the context error if sends are still running.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return nil
+	}
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	done := s.done
+	s.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		<-done
+		s.running.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Next returns the next scheduled run of each job, in the order added,
+// skipping no holidays.
+func (s *Scheduler) Next() []Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	runs := make([]Run, 0, len(s.entries))
+	for _, e := range s.entries {
+		runs = append(runs, Run{Job: e.job.Name, Time: e.cron.Next(now.In(s.location(e.job)))})
+	}
+	return runs
+}
+
+// loop runs due jobs until the scheduler is stopped.
+func (s *Scheduler) loop() {
+	defer close(s.done)
+
+	now := s.now()
+	for _, e := range s.entries {
+		e.next = e.cron.Next(now.In(s.location(e.job)))
+	}
+
+	for {
+		var earliest time.Time
+		for _, e := range s.entries {
+			if !e.next.IsZero() && (earliest.IsZero() || e.next.Before(earliest)) {
+				earliest = e.next
+			}
+		}
+		var wake <-chan time.Time
+		if !earliest.IsZero() {
+			wake = s.after(earliest.Sub(s.now()))
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-wake:
+		}
+
+		now := s.now()
+		for _, e := range s.entries {
+			if e.next.IsZero() || e.next.After(now) {
+				continue
+			}
+			s.dispatch(e.job, e.next)
+			e.next = e.cron.Next(now.In(s.location(e.job)))
+		}
+	}
+}
+
+// dispatch sends the message of a run in the background, unless the run
+// falls on a holiday skipped by the job.
+func (s *Scheduler) dispatch(job Job, at time.Time) {
+	if job.SkipHolidays && s.Holiday != nil && s.Holiday(at) {
+		return
+	}
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+
+		run := Run{Job: job.Name, Time: at}
+		if err := s.runJob(job, run); err != nil && s.OnError != nil {
+			s.OnError(run, err)
+		}
+	}()
+}
+
+// runJob builds and sends the message of a run.
+func (s *Scheduler) runJob(job Job, run Run) error {
+	msg, err := job.Message(run)
+	if err != nil {
+		return fmt.Errorf("failed to build message of job %q: %w", job.Name, err)
+	}
+	if msg == nil {
+		return nil
+	}
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	send := job.Send
+	if send == nil {
+		send = s.send
+	}
+	if err := send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send message of job %q: %w", job.Name, err)
+	}
+	return nil
+}
+
+// location returns the time zone of a job.
+func (s *Scheduler) location(job Job) *time.Location {
+	switch {
+	case job.Location != nil:
+		return job.Location
+	case s.Location != nil:
+		return s.Location
+	}
+	return time.Local
+}
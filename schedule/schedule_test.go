@@ -0,0 +1,123 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock advanced by the scheduler's timers, so that tests
+// run instantly. Timers never fire once wakeups are used up.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	wakeups int
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.wakeups == 0 {
+		return nil
+	}
+	c.wakeups--
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestScheduler(t *testing.T) {
+	var mu sync.Mutex
+	var runs []string
+	sent := make(chan struct{}, 10)
+	scheduler := New(func(_ context.Context, msg *feishubot.Message) error {
+		mu.Lock()
+		runs = append(runs, msg.Content["text"].(string))
+		mu.Unlock()
+		sent <- struct{}{}
+		return nil
+	})
+	scheduler.Location = time.UTC
+	scheduler.Holiday = func(t time.Time) bool {
+		return t.Format("2006-01-02") == "2024-05-02"
+	}
+
+	// Wednesday
+	// Wakes up on Wednesday and Thursday morning, Thursday afternoon and
+	// Friday morning
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), wakeups: 4}
+	scheduler.now = clock.Now
+	scheduler.after = clock.After
+
+	require.NoError(t, scheduler.Add(Job{
+		Name:         "standup",
+		Cron:         "30 9 * * mon-fri",
+		SkipHolidays: true,
+		Message:      Must(Text("{{.Job}} {{.Time.Format \"Mon 15:04\"}}")),
+	}))
+	require.NoError(t, scheduler.Add(Job{
+		Name:    "report",
+		Cron:    "0 16 * * thu",
+		Message: Must(Text("{{.Job}} {{.Time.Format \"Mon 15:04\"}}")),
+	}))
+	require.Equal(t, []Run{
+		{Job: "standup", Time: time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)},
+		{Job: "report", Time: time.Date(2024, 5, 2, 16, 0, 0, 0, time.UTC)},
+	}, scheduler.Next())
+
+	scheduler.Start()
+	for i := 0; i < 3; i++ {
+		<-sent
+	}
+	require.NoError(t, scheduler.Shutdown(context.Background()))
+	require.ErrorIs(t, scheduler.Add(Job{Name: "late", Cron: "@daily", Message: Must(Text("x"))}), ErrSchedulerStarted)
+
+	require.ElementsMatch(t, []string{"standup Wed 09:30", "report Thu 16:00", "standup Fri 09:30"}, runs)
+}
+
+func TestSchedulerErrors(t *testing.T) {
+	errs := make(chan error, 1)
+	scheduler := New(func(context.Context, *feishubot.Message) error {
+		return errors.New("webhook down")
+	})
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), wakeups: 1}
+	scheduler.now = clock.Now
+	scheduler.after = clock.After
+	scheduler.OnError = func(run Run, err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+
+	require.NoError(t, scheduler.Add(Job{Name: "ping", Cron: "@hourly", Message: Must(Text("ping"))}))
+	scheduler.Start()
+	err := <-errs
+	require.NoError(t, scheduler.Shutdown(context.Background()))
+	require.EqualError(t, err, `failed to send message of job "ping": webhook down`)
+
+	require.ErrorIs(t, New(nil).Add(Job{Name: "bad", Cron: "* *", Message: Must(Text("x"))}), ErrInvalidCron)
+	require.EqualError(t, New(nil).Add(Job{Name: "empty", Cron: "@daily"}), `job "empty" has no message`)
+}
+
+func TestSchedulerSkipsNilMessages(t *testing.T) {
+	scheduler := New(func(context.Context, *feishubot.Message) error {
+		return errors.New("unexpected send")
+	})
+	job := Job{Name: "check", Cron: "@hourly", Message: func(Run) (*feishubot.Message, error) {
+		return nil, nil
+	}}
+	require.NoError(t, scheduler.runJob(job, Run{Job: job.Name}))
+}
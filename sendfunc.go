@@ -0,0 +1,27 @@
+package feishubot
+
+import "context"
+
+// SendFunc sends a message to Feishu, whether through a custom bot webhook
+// or an app, see WebhookSender and APISender. The integrations in the
+// subpackages, such as alertmanager and schedule, send their messages
+// through a SendFunc, so that the same sender can be shared between them.
+type SendFunc func(ctx context.Context, msg *Message) error
+
+// WebhookSender returns a SendFunc posting messages with a custom bot
+// webhook client.
+func WebhookSender(client *Client) SendFunc {
+	return func(ctx context.Context, msg *Message) error {
+		_, err := client.Send(ctx, msg)
+		return err
+	}
+}
+
+// APISender returns a SendFunc sending messages to the recipient with an app
+// client.
+func APISender(client *APIClient, to Recipient) SendFunc {
+	return func(ctx context.Context, msg *Message) error {
+		_, err := client.Send(ctx, to, msg)
+		return err
+	}
+}
@@ -0,0 +1,32 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSender(t *testing.T) {
+	var received []Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		received = append(received, msg)
+		if msg.Content["text"] == "fail" {
+			json.NewEncoder(w).Encode(Response{Code: 9499, Msg: "Bad Request"})
+			return
+		}
+		json.NewEncoder(w).Encode(Response{Msg: "success"})
+	}))
+	defer server.Close()
+
+	send := WebhookSender(NewClient(server.URL, ""))
+	require.NoError(t, send(context.Background(), NewTextMessage("hello")))
+	require.EqualError(t, send(context.Background(), NewTextMessage("fail")), "API error (code 9499): Bad Request")
+	require.Len(t, received, 2)
+	require.Equal(t, "hello", received[0].Content["text"])
+}
@@ -0,0 +1,95 @@
+package feishubot
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultSendInterval is the default interval between messages sent by
+// APIClient.SendToMany, keeping below the rate limit of the send message
+// API of 50 requests per second.
+const DefaultSendInterval = 25 * time.Millisecond
+
+// SendFailure is a recipient a message could not be sent to.
+type SendFailure struct {
+	Recipient Recipient
+	Err       error
+}
+
+// SendToManyResult reports the outcome of APIClient.SendToMany per
+// recipient.
+type SendToManyResult struct {
+	// Sent holds the sent messages by recipient.
+	Sent map[Recipient]*SentMessage
+	// Failed holds the recipients sending failed for, in order.
+	Failed []SendFailure
+	// Unsent holds the recipients not attempted because the context was
+	// done, in order.
+	Unsent []Recipient
+}
+
+// Remaining returns the recipients that did not receive the message, failed
+// ones first, so that an interrupted or partially failed broadcast can be
+// resumed by passing them to SendToMany again.
+func (r *SendToManyResult) Remaining() []Recipient {
+	remaining := make([]Recipient, 0, len(r.Failed)+len(r.Unsent))
+	for _, failure := range r.Failed {
+		remaining = append(remaining, failure.Recipient)
+	}
+	return append(remaining, r.Unsent...)
+}
+
+// SendToMany sends msg to each recipient in turn, e.g. for announcements
+// sent as direct messages to every user, waiting SendInterval between
+// messages to stay within rate limits. Sending continues after failures,
+// which are reported in the result.
+//
+// The returned error is only non-nil if the message is invalid, or if the
+// context is done before all recipients were attempted; the result then
+// lists the recipients not attempted in Unsent.
+//
+// Example:
+//
+//	recipients := []feishubot.Recipient{feishubot.ToOpenID("ou_a"), feishubot.ToOpenID("ou_b")}
+//	result, err := api.SendToMany(ctx, msg, recipients)
+//	for _, failure := range result.Failed {
+//		log.Printf("failed to notify %s: %v", failure.Recipient.ID, failure.Err)
+//	}
+//	// Later, retry failed and unsent recipients:
+//	result, err = api.SendToMany(ctx, msg, result.Remaining())
+func (c *APIClient) SendToMany(ctx context.Context, msg *Message, recipients []Recipient) (*SendToManyResult, error) {
+	result := &SendToManyResult{Sent: make(map[Recipient]*SentMessage, len(recipients))}
+	if _, err := c.messageContent(msg); err != nil {
+		result.Unsent = append(result.Unsent, recipients...)
+		return result, err
+	}
+
+	interval := c.SendInterval
+	if interval <= 0 {
+		interval = DefaultSendInterval
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for i, to := range recipients {
+		select {
+		case <-ctx.Done():
+			result.Unsent = append(result.Unsent, recipients[i:]...)
+			return result, ctx.Err()
+		case <-timer.C:
+		}
+
+		sent, err := c.Send(ctx, to, msg)
+		timer.Reset(interval)
+		if err != nil {
+			if ctx.Err() != nil {
+				result.Unsent = append(result.Unsent, recipients[i:]...)
+				return result, ctx.Err()
+			}
+			result.Failed = append(result.Failed, SendFailure{Recipient: to, Err: err})
+			continue
+		}
+		result.Sent[to] = sent
+	}
+	return result, nil
+}
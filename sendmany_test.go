@@ -0,0 +1,78 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIClientSendToMany(t *testing.T) {
+	var got []string
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		got = append(got, body["receive_id"])
+		if body["receive_id"] == "ou_left" {
+			_, _ = w.Write([]byte(`{"code":230013,"msg":"Bot has NO availability to this user."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"message_id":"om_` + body["receive_id"] + `"}}`))
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+	client.SendInterval = time.Millisecond
+
+	recipients := []Recipient{ToOpenID("ou_a"), ToOpenID("ou_left"), ToChat("oc_b")}
+	result, err := client.SendToMany(context.Background(), NewTextMessage("hello"), recipients)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ou_a", "ou_left", "oc_b"}, got)
+	require.Equal(t, map[Recipient]*SentMessage{
+		ToOpenID("ou_a"): {MessageID: "om_ou_a"},
+		ToChat("oc_b"):   {MessageID: "om_oc_b"},
+	}, result.Sent)
+	require.Len(t, result.Failed, 1)
+	require.Equal(t, ToOpenID("ou_left"), result.Failed[0].Recipient)
+	require.ErrorContains(t, result.Failed[0].Err, "code 230013")
+	require.Empty(t, result.Unsent)
+	require.Equal(t, []Recipient{ToOpenID("ou_left")}, result.Remaining())
+}
+
+// cancelAfterSend is an HTTPClient canceling a context after the first
+// message is sent.
+type cancelAfterSend struct {
+	client HTTPClient
+	cancel context.CancelFunc
+}
+
+func (c cancelAfterSend) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if req.URL.Path == "/im/v1/messages" {
+		c.cancel()
+	}
+	return resp, err
+}
+
+func TestAPIClientSendToManyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, _ := newOpenAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"message_id":"om_xxx"}}`))
+	}))
+
+	client := NewAPIClient("cli_a", "secret")
+	client.BaseURL = server.URL
+	client.SetHTTPClient(cancelAfterSend{client: client.HTTPClient, cancel: cancel})
+	client.SendInterval = time.Hour
+
+	recipients := []Recipient{ToOpenID("ou_a"), ToOpenID("ou_b"), ToOpenID("ou_c")}
+	result, err := client.SendToMany(ctx, NewTextMessage("hello"), recipients)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, map[Recipient]*SentMessage{ToOpenID("ou_a"): {MessageID: "om_xxx"}}, result.Sent)
+	require.Equal(t, []Recipient{ToOpenID("ou_b"), ToOpenID("ou_c")}, result.Remaining())
+}
@@ -0,0 +1,131 @@
+// Package signature signs and verifies Feishu requests, covering both
+// directions in one place:
+//
+//   - outgoing custom bot webhook messages are signed with the webhook
+//     secret: an HMAC of the timestamp and secret, see SignOutgoing;
+//   - incoming app events and card callbacks are verified with the app's
+//     encrypt key or verification token, see VerifyIncomingEvent.
+//
+// The two schemes use different credentials, represented by the distinct
+// types WebhookSecret and AppKeys so that they cannot be mixed up.
+//
+// Example:
+//
+//	secret := signature.WebhookSecret(os.Getenv("FEISHU_WEBHOOK_SECRET"))
+//	msg, err := signature.SignOutgoingMessage(feishubot.NewTextMessage("hi"), secret, time.Now())
+//
+//	keys := signature.AppKeys{EncryptKey: encryptKey, VerificationToken: verificationToken}
+//	if err := signature.VerifyIncomingEvent(keys, r.Header, body); err != nil {
+//		http.Error(w, "forbidden", http.StatusForbidden)
+//		return
+//	}
+package signature
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/events"
+)
+
+// ErrInvalidSignature is returned when a signature or verification token
+// does not match. It is feishubot.ErrInvalidEventSignature, so that errors
+// of both packages can be checked with either.
+var ErrInvalidSignature = feishubot.ErrInvalidEventSignature
+
+// WebhookSecret is the signing secret of a custom bot webhook, shown in the
+// bot's security settings. It signs outgoing messages only.
+type WebhookSecret string
+
+// AppKeys are the event subscription credentials of an app, shown in the
+// developer console. They verify incoming events and callbacks only.
+type AppKeys struct {
+	// EncryptKey encrypts events and signs them with SHA-256, if set.
+	EncryptKey string
+	// VerificationToken is carried in unencrypted events and signs legacy
+	// card callbacks with SHA-1.
+	VerificationToken string
+}
+
+// SignOutgoing returns the signature of a custom bot webhook message sent at
+// timestamp, in seconds since epoch.
+func SignOutgoing(secret WebhookSecret, timestamp int64) (string, error) {
+	return feishubot.GenSign(string(secret), timestamp)
+}
+
+// SignOutgoingMessage signs a custom bot webhook message sent at now, setting
+// its timestamp and signature, and returns it.
+func SignOutgoingMessage(msg *feishubot.Message, secret WebhookSecret, now time.Time) (*feishubot.Message, error) {
+	timestamp := now.Unix()
+	sign, err := SignOutgoing(secret, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return msg.WithSignature(timestamp, sign), nil
+}
+
+// VerifyOutgoing verifies the signature of a custom bot webhook message, for
+// services relaying or mocking webhooks.
+func VerifyOutgoing(secret WebhookSecret, timestamp int64, sign string) error {
+	want, err := SignOutgoing(secret, timestamp)
+	if err != nil {
+		return err
+	}
+	return compare(want, sign)
+}
+
+// VerifyIncomingEvent verifies an incoming event or callback request given
+// by its headers and raw body: by the SHA-256 signature headers if present
+// and an encrypt key is set, otherwise by the verification token in the
+// body, decrypted if needed. URL verification challenges are checked
+// against the verification token only, as Feishu does not sign them, so
+// they are rejected if no verification token is set.
+//
+// If an encrypt key is set, Feishu encrypts all requests, so plaintext
+// bodies are rejected.
+func VerifyIncomingEvent(keys AppKeys, header http.Header, body []byte, opts ...feishubot.VerifyOption) error {
+	if keys.EncryptKey != "" && !isEncrypted(body) {
+		return fmt.Errorf("%w: request is not encrypted but an encrypt key is configured", ErrInvalidSignature)
+	}
+	req, err := events.ParseRequest(header, body, keys.EncryptKey, keys.VerificationToken, opts...)
+	if err != nil {
+		return err
+	}
+	if req.Envelope == nil && keys.VerificationToken == "" {
+		return fmt.Errorf("%w: URL verification is not signed and no verification token is configured", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// isEncrypted reports whether body is an encrypted event.
+func isEncrypted(body []byte) bool {
+	var encrypted struct {
+		Encrypt string `json:"encrypt"`
+	}
+	return json.Unmarshal(body, &encrypted) == nil && encrypted.Encrypt != ""
+}
+
+// VerifyIncomingCardCallback verifies a legacy card action callback request,
+// signed with SHA-1 using the verification token.
+func VerifyIncomingCardCallback(keys AppKeys, header http.Header, body []byte, opts ...feishubot.VerifyOption) error {
+	return feishubot.VerifyCardCallbackSignature(
+		header.Get(feishubot.HeaderRequestTimestamp),
+		header.Get(feishubot.HeaderRequestNonce),
+		body,
+		header.Get(feishubot.HeaderSignature),
+		keys.VerificationToken,
+		opts...,
+	)
+}
+
+// compare compares signatures in constant time.
+func compare(want, got string) error {
+	if subtle.ConstantTimeCompare([]byte(want), []byte(got)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
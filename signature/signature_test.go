@@ -0,0 +1,107 @@
+package signature
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/cium-cc/feishurobot/eventtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignOutgoing(t *testing.T) {
+	want, err := feishubot.GenSign("secret", 1700000000)
+	require.NoError(t, err)
+
+	got, err := SignOutgoing("secret", 1700000000)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	require.NoError(t, VerifyOutgoing("secret", 1700000000, got))
+	require.ErrorIs(t, VerifyOutgoing("other", 1700000000, got), ErrInvalidSignature)
+	require.ErrorIs(t, VerifyOutgoing("secret", 1700000001, got), feishubot.ErrInvalidEventSignature)
+}
+
+func TestSignOutgoingMessage(t *testing.T) {
+	msg, err := SignOutgoingMessage(feishubot.NewTextMessage("hi"), "secret", time.Unix(1700000000, 0))
+	require.NoError(t, err)
+	require.Equal(t, int64(1700000000), msg.Timestamp)
+	require.NoError(t, VerifyOutgoing("secret", msg.Timestamp, msg.Sign))
+}
+
+func TestVerifyIncomingEvent(t *testing.T) {
+	keys := AppKeys{EncryptKey: "key", VerificationToken: "token"}
+	noSkew := feishubot.WithTimestampSkew(0)
+	event := []byte(`{"schema":"2.0","header":{"event_id":"ev_1","event_type":"x","token":"token"},"event":{}}`)
+
+	challenge := []byte(`{"type":"url_verification","challenge":"c","token":"token"}`)
+	forgedChallenge := []byte(`{"type":"url_verification","challenge":"c"}`)
+	encrypted, err := eventtest.Encrypt(event, "key")
+	require.NoError(t, err)
+	encryptedBody := []byte(`{"encrypt":"` + encrypted + `"}`)
+	encrypted, err = eventtest.Encrypt(forgedChallenge, "key")
+	require.NoError(t, err)
+	encryptedChallenge := []byte(`{"encrypt":"` + encrypted + `"}`)
+	signed := http.Header{}
+	signed.Set(feishubot.HeaderRequestTimestamp, "1")
+	signed.Set(feishubot.HeaderRequestNonce, "n")
+	signed.Set(feishubot.HeaderSignature, eventtest.Sign("1", "n", "key", encryptedBody))
+
+	tests := []struct {
+		name    string
+		keys    AppKeys
+		header  http.Header
+		body    []byte
+		wantErr bool
+	}{
+		{name: "token", keys: AppKeys{VerificationToken: "token"}, body: event},
+		{name: "signed", keys: keys, header: signed, body: encryptedBody},
+		{name: "encrypted token", keys: keys, body: encryptedBody},
+		{name: "wrong token", keys: AppKeys{VerificationToken: "other"}, body: event, wantErr: true},
+		{name: "wrong key", keys: AppKeys{EncryptKey: "other"}, header: signed, body: []byte(`{"a":1}`), wantErr: true},
+		{name: "no keys", body: event, wantErr: true},
+		{name: "plaintext with encrypt key", keys: keys, body: event, wantErr: true},
+		{name: "challenge", keys: AppKeys{VerificationToken: "token"}, body: challenge},
+		{name: "challenge without token", body: forgedChallenge, wantErr: true},
+		{name: "plaintext challenge with encrypt key", keys: AppKeys{EncryptKey: "key"}, body: forgedChallenge, wantErr: true},
+		{name: "encrypted challenge without token", keys: AppKeys{EncryptKey: "key"}, body: encryptedChallenge, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyIncomingEvent(tt.keys, tt.header, tt.body, noSkew)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidSignature)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestVerifyIncomingCardCallback(t *testing.T) {
+	body := []byte(`{"open_id":"ou_xxx","action":{"value":{}}}`)
+	sum := sha1Hex("1" + "n" + "token" + string(body))
+	header := http.Header{}
+	header.Set(feishubot.HeaderRequestTimestamp, "1")
+	header.Set(feishubot.HeaderRequestNonce, "n")
+	header.Set(feishubot.HeaderSignature, sum)
+
+	noSkew := feishubot.WithTimestampSkew(0)
+	require.NoError(t, VerifyIncomingCardCallback(AppKeys{VerificationToken: "token"}, header, body, noSkew))
+	require.ErrorIs(t, VerifyIncomingCardCallback(AppKeys{VerificationToken: "x"}, header, body, noSkew), ErrInvalidSignature)
+
+	// SHA-256 signatures of events are not accepted as card callbacks
+	sha256Sum := sha256.Sum256([]byte("1" + "n" + "token" + string(body)))
+	header.Set(feishubot.HeaderSignature, hex.EncodeToString(sha256Sum[:]))
+	require.ErrorIs(t, VerifyIncomingCardCallback(AppKeys{VerificationToken: "token"}, header, body, noSkew), ErrInvalidSignature)
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,276 @@
+package slack
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Card converts the payload to a Feishu card. The first header block
+// becomes the card header, colored like the first colored attachment.
+// Text is shown only if there are no blocks, as Slack does, and is always
+// used as the summary shown in notifications.
+func (p *Payload) Card() *feishubot.Card {
+	builder := feishubot.NewCardBuilder()
+
+	title := ""
+	blocks := p.Blocks
+	for i, block := range blocks {
+		if block.Type == "header" && block.Text != nil {
+			title = block.Text.Text
+			blocks = append(blocks[:i:i], blocks[i+1:]...)
+			break
+		}
+	}
+	if title != "" {
+		template := feishubot.TemplateBlue
+		for _, attachment := range p.Attachments {
+			if attachment.Color != "" {
+				template = colorTemplate(attachment.Color)
+				break
+			}
+		}
+		builder.Header(title, template)
+	}
+
+	if len(p.Blocks) == 0 && p.Text != "" {
+		if p.Mrkdwn != nil && !*p.Mrkdwn {
+			builder.Markdown(escapeText(p.Text))
+		} else {
+			builder.Markdown(Markdown(p.Text))
+		}
+	}
+	addBlocks(builder, blocks)
+	separate := len(blocks) > 0 || (len(p.Blocks) == 0 && p.Text != "")
+	for i, attachment := range p.Attachments {
+		if i > 0 || separate {
+			builder.Divider()
+		}
+		addAttachment(builder, &attachment)
+	}
+
+	card := builder.Build()
+	summary := p.Text
+	if summary == "" && len(p.Attachments) > 0 {
+		summary = p.Attachments[0].Fallback
+	}
+	if summary != "" {
+		card.SetSummary(feishubot.TruncateText(plainText(summary), 120))
+	}
+	return card
+}
+
+// Message converts the payload to an interactive message, see Card.
+func (p *Payload) Message() *feishubot.Message {
+	return feishubot.NewInteractiveMessage(p.Card())
+}
+
+// addBlocks appends the elements of Block Kit blocks. Unsupported blocks
+// are dropped.
+func addBlocks(builder *feishubot.CardBuilder, blocks []Block) {
+	for _, block := range blocks {
+		switch block.Type {
+		case "header":
+			if block.Text != nil {
+				builder.Markdown("**" + block.Text.markdown() + "**")
+			}
+		case "section":
+			addSection(builder, &block)
+		case "divider":
+			builder.Divider()
+		case "context":
+			parts := make([]string, 0, len(block.Elements))
+			for _, element := range block.Elements {
+				if text := elementMarkdown(&element); text != "" {
+					parts = append(parts, text)
+				}
+			}
+			if len(parts) > 0 {
+				builder.Markdown("<font color='grey'>" + strings.Join(parts, " · ") + "</font>")
+			}
+		case "actions":
+			var buttons []feishubot.CardElement
+			for _, element := range block.Elements {
+				if button := linkButton(&element); button != nil {
+					buttons = append(buttons, button)
+				}
+			}
+			if len(buttons) > 0 {
+				builder.Actions(buttons...)
+			}
+		case "image":
+			alt := block.AltText
+			if block.Title != nil && block.Title.Text != "" {
+				alt = block.Title.Text
+			}
+			if alt == "" {
+				alt = "Image"
+			}
+			builder.Markdown("[" + escapeText(alt) + "](" + block.ImageURL + ")")
+		}
+	}
+}
+
+// addSection appends a section block as a div with its text, fields as
+// short fields, and a link button accessory.
+func addSection(builder *feishubot.CardBuilder, block *Block) {
+	var div *feishubot.Div
+	if text := block.Text.markdown(); text != "" {
+		div = feishubot.NewDiv(feishubot.NewCardMarkdownTitle(text))
+	} else {
+		div = feishubot.NewDiv(nil)
+	}
+	for _, field := range block.Fields {
+		div.AddField(feishubot.NewCardMarkdownTitle(field.markdown()), true)
+	}
+	if block.Accessory != nil {
+		if button := linkButton(block.Accessory); button != nil {
+			div.SetExtra(button)
+		}
+	}
+	if div.Text == nil && len(div.Fields) == 0 {
+		return
+	}
+	builder.Element(div)
+}
+
+// addAttachment appends a legacy attachment: its pretext, title, text,
+// fields, image, blocks and footer.
+func addAttachment(builder *feishubot.CardBuilder, attachment *Attachment) {
+	if attachment.Pretext != "" {
+		builder.Markdown(Markdown(attachment.Pretext))
+	}
+	if attachment.Title != "" {
+		title := escapeText(attachment.Title)
+		if attachment.TitleLink != "" {
+			title = "[" + title + "](" + attachment.TitleLink + ")"
+		}
+		title = "**" + title + "**"
+		if color := attachment.Color; color != "" {
+			title = "<font color='" + colorTemplate(color) + "'>" + title + "</font>"
+		}
+		builder.Markdown(title)
+	}
+	if attachment.Text != "" {
+		builder.Markdown(Markdown(attachment.Text))
+	}
+	if len(attachment.Fields) > 0 {
+		div := feishubot.NewDiv(nil)
+		for _, field := range attachment.Fields {
+			text := Markdown(field.Value)
+			if field.Title != "" {
+				text = "**" + escapeText(field.Title) + "**\n" + text
+			}
+			div.AddField(feishubot.NewCardMarkdownTitle(text), field.Short)
+		}
+		builder.Element(div)
+	}
+	if attachment.ImageURL != "" {
+		builder.Markdown("[Image](" + attachment.ImageURL + ")")
+	}
+	addBlocks(builder, attachment.Blocks)
+	if attachment.Footer != "" {
+		builder.Markdown("<font color='grey'>" + Markdown(attachment.Footer) + "</font>")
+	}
+}
+
+// elementMarkdown returns a context element as markdown: the text of text
+// objects, and images as links.
+func elementMarkdown(element *Element) string {
+	switch element.Type {
+	case "image":
+		alt := element.AltText
+		if alt == "" {
+			alt = "Image"
+		}
+		return "[" + escapeText(alt) + "](" + element.ImageURL + ")"
+	case "mrkdwn", "plain_text":
+		return element.Text.markdown()
+	}
+	return ""
+}
+
+// linkButton returns a link button as a card button, or nil for other
+// elements, which need a Slack app to handle their actions.
+func linkButton(element *Element) feishubot.CardElement {
+	if element.Type != "button" || element.URL == "" || element.Text == nil {
+		return nil
+	}
+	buttonType := "default"
+	switch element.Style {
+	case "primary":
+		buttonType = "primary"
+	case "danger":
+		buttonType = "danger"
+	}
+	return feishubot.NewButtonElement(element.Text.Text, buttonType, element.URL)
+}
+
+// plainText strips the markup of mrkdwn for notification summaries.
+func plainText(mrkdwn string) string {
+	text := linkPattern.ReplaceAllStringFunc(mrkdwn, func(link string) string {
+		link = link[1 : len(link)-1]
+		if i := strings.IndexByte(link, '|'); i >= 0 {
+			return link[i+1:]
+		}
+		return strings.TrimPrefix(link, "!")
+	})
+	return entityReplacer.Replace(strings.NewReplacer("*", "", "_", "", "~", "", "`", "").Replace(text))
+}
+
+// colorTemplate returns the card template closest to an attachment color:
+// "good", "warning", "danger" or a hex color such as "#36a64f".
+func colorTemplate(color string) string {
+	switch color {
+	case "good":
+		return feishubot.TemplateGreen
+	case "warning":
+		return feishubot.TemplateOrange
+	case "danger":
+		return feishubot.TemplateRed
+	}
+
+	hex := strings.TrimPrefix(color, "#")
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return feishubot.TemplateBlue
+	}
+	r, g, b := float64(value>>16), float64(value>>8&0xff), float64(value&0xff)
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	if max-min < 32 {
+		return feishubot.TemplateGrey
+	}
+
+	var hue float64
+	switch max {
+	case r:
+		hue = 60 * (g - b) / (max - min)
+	case g:
+		hue = 60 * (2 + (b-r)/(max-min))
+	default:
+		hue = 60 * (4 + (r-g)/(max-min))
+	}
+	if hue < 0 {
+		hue += 360
+	}
+
+	switch {
+	case hue < 15 || hue >= 345:
+		return feishubot.TemplateRed
+	case hue < 40:
+		return feishubot.TemplateOrange
+	case hue < 70:
+		return feishubot.TemplateYellow
+	case hue < 165:
+		return feishubot.TemplateGreen
+	case hue < 195:
+		return feishubot.TemplateTurquoise
+	case hue < 250:
+		return feishubot.TemplateBlue
+	case hue < 290:
+		return feishubot.TemplatePurple
+	}
+	return feishubot.TemplateCarmine
+}
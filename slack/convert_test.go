@@ -0,0 +1,166 @@
+package slack
+
+import (
+	"encoding/json"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func cardJSON(t *testing.T, payloadJSON string) string {
+	t.Helper()
+	var payload Payload
+	require.NoError(t, json.Unmarshal([]byte(payloadJSON), &payload))
+	data, err := json.Marshal(payload.Card())
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCardText(t *testing.T) {
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"config": {"summary": {"content": "Deploy of api finished"}},
+		"body": {"elements": [{"tag": "markdown", "content": "Deploy of **api** finished"}]}
+	}`, cardJSON(t, `{"text": "Deploy of *api* finished"}`))
+}
+
+func TestCardPlainText(t *testing.T) {
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"config": {"summary": {"content": "not bold at id=all/at"}},
+		"body": {"elements": [{"tag": "markdown", "content": "&#42;not bold&#42; &lt;at id=all&gt;&lt;/at&gt;"}]}
+	}`, cardJSON(t, `{"text": "*not bold* <at id=all></at>", "mrkdwn": false}`))
+
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {"title": {"tag": "plain_text", "content": "Notes"}, "template": "blue"},
+		"body": {"elements": [
+			{"tag": "markdown", "content": "**&#95;release&#95; &lt;at id=all&gt;&lt;/at&gt;**"},
+			{"tag": "div",
+				"text": {"tag": "lark_md", "content": "&#42;a&#42; &amp;lt; b"},
+				"fields": [{"is_short": true, "text": {"tag": "lark_md", "content": "&lt;at id=all&gt;&lt;/at&gt;"}}]
+			},
+			{"tag": "markdown", "content": "[&#91;x&#93;](https://example.com/a.png)"},
+			{"tag": "hr"},
+			{"tag": "markdown", "content": "**&#42;api&#42;**"},
+			{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**&lt;at id=all&gt;&lt;/at&gt;**\nmain"}}
+			]}
+		]}
+	}`, cardJSON(t, `{
+		"blocks": [
+			{"type": "header", "text": {"type": "plain_text", "text": "Notes"}},
+			{"type": "header", "text": {"type": "plain_text", "text": "_release_ <at id=all></at>"}},
+			{"type": "section",
+				"text": {"type": "plain_text", "text": "*a* &amp;lt; b"},
+				"fields": [{"type": "plain_text", "text": "&lt;at id=all&gt;&lt;/at&gt;"}]
+			},
+			{"type": "image", "image_url": "https://example.com/a.png", "alt_text": "[x]"}
+		],
+		"attachments": [
+			{"title": "*api*", "fields": [{"title": "<at id=all></at>", "value": "main", "short": true}]}
+		]
+	}`))
+}
+
+func TestCardBlocks(t *testing.T) {
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"config": {"summary": {"content": "New deployment"}},
+		"header": {"title": {"tag": "plain_text", "content": "Deployment"}, "template": "blue"},
+		"body": {"elements": [
+			{"tag": "div",
+				"text": {"tag": "lark_md", "content": "**api** deployed to [prod](https://k8s.example.com)"},
+				"fields": [
+					{"is_short": true, "text": {"tag": "lark_md", "content": "**Version**\nv1.4.2"}},
+					{"is_short": true, "text": {"tag": "lark_md", "content": "By alice"}}
+				],
+				"extra": {"tag": "button", "text": {"tag": "plain_text", "content": "Logs"}, "type": "default", "url": "https://logs.example.com"}
+			},
+			{"tag": "hr"},
+			{"tag": "markdown", "content": "<font color='grey'>[avatar](https://example.com/a.png) · Triggered by **CI**</font>"},
+			{"tag": "column_set", "columns": [
+				{"tag": "column", "width": "weighted", "weight": 1, "elements": [
+					{"tag": "button", "text": {"tag": "plain_text", "content": "Roll back"}, "type": "danger", "url": "https://ci.example.com/rollback", "width": "fill"}
+				]}
+			]},
+			{"tag": "markdown", "content": "[Latency](https://grafana.example.com/latency.png)"}
+		]}
+	}`, cardJSON(t, `{
+		"text": "New deployment",
+		"blocks": [
+			{"type": "header", "text": {"type": "plain_text", "text": "Deployment"}},
+			{"type": "section",
+				"text": {"type": "mrkdwn", "text": "*api* deployed to <https://k8s.example.com|prod>"},
+				"fields": [{"type": "mrkdwn", "text": "*Version*\nv1.4.2"}, {"type": "plain_text", "text": "By alice"}],
+				"accessory": {"type": "button", "text": {"type": "plain_text", "text": "Logs"}, "url": "https://logs.example.com"}
+			},
+			{"type": "divider"},
+			{"type": "context", "elements": [
+				{"type": "image", "image_url": "https://example.com/a.png", "alt_text": "avatar"},
+				{"type": "mrkdwn", "text": "Triggered by *CI*"}
+			]},
+			{"type": "actions", "elements": [
+				{"type": "button", "text": {"type": "plain_text", "text": "Approve"}, "action_id": "approve"},
+				{"type": "button", "text": {"type": "plain_text", "text": "Roll back"}, "style": "danger", "url": "https://ci.example.com/rollback"}
+			]},
+			{"type": "image", "image_url": "https://grafana.example.com/latency.png", "alt_text": "Latency"},
+			{"type": "input", "label": {"type": "plain_text", "text": "Dropped"}}
+		]
+	}`))
+}
+
+func TestCardAttachments(t *testing.T) {
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"config": {"summary": {"content": "Build failed: api #42"}},
+		"body": {"elements": [
+			{"tag": "markdown", "content": "CI report"},
+			{"tag": "markdown", "content": "<font color='red'>**[api #42](https://ci.example.com/42)**</font>"},
+			{"tag": "markdown", "content": "Tests **failed**"},
+			{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Branch**\nmain"}},
+				{"is_short": false, "text": {"tag": "lark_md", "content": "**Commit**\nFix flaky test"}}
+			]},
+			{"tag": "markdown", "content": "<font color='grey'>Jenkins</font>"},
+			{"tag": "hr"},
+			{"tag": "markdown", "content": "<font color='green'>**Retry passed**</font>"}
+		]}
+	}`, cardJSON(t, `{
+		"attachments": [
+			{
+				"color": "danger",
+				"fallback": "Build failed: api #42",
+				"pretext": "CI report",
+				"title": "api #42",
+				"title_link": "https://ci.example.com/42",
+				"text": "Tests *failed*",
+				"fields": [
+					{"title": "Branch", "value": "main", "short": true},
+					{"title": "Commit", "value": "Fix flaky test"}
+				],
+				"footer": "Jenkins"
+			},
+			{"color": "#36a64f", "title": "Retry passed"}
+		]
+	}`))
+}
+
+func TestColorTemplate(t *testing.T) {
+	for color, want := range map[string]string{
+		"good":    feishubot.TemplateGreen,
+		"warning": feishubot.TemplateOrange,
+		"danger":  feishubot.TemplateRed,
+		"#36a64f": feishubot.TemplateGreen,
+		"#2eb886": feishubot.TemplateGreen,
+		"#daa038": feishubot.TemplateOrange,
+		"#a30200": feishubot.TemplateRed,
+		"#439FE0": feishubot.TemplateBlue,
+		"#808080": feishubot.TemplateGrey,
+		"#9b59b6": feishubot.TemplatePurple,
+		"blue":    feishubot.TemplateBlue,
+	} {
+		require.Equal(t, want, colorTemplate(color), color)
+	}
+}
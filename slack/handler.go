@@ -0,0 +1,103 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// maxBodyBytes is the maximum size of webhook request bodies read.
+const maxBodyBytes = 1 << 20
+
+// errNoText is returned for payloads without text, blocks or attachments.
+var errNoText = errors.New("payload has no text, blocks or attachments")
+
+// Handler is an http.Handler accepting Slack incoming-webhook payloads and
+// posting them to Feishu as cards, see Payload.Card.
+//
+// Like Slack, it accepts payloads as a JSON body or in the payload field of
+// a form, and answers with "ok", "invalid_payload" or "no_text". Payloads
+// that cannot be posted are answered with status 502.
+type Handler struct {
+	send feishubot.SendFunc
+
+	// OnError is called with errors of handling payloads. If nil, such
+	// errors are only reported in the response.
+	OnError func(error)
+}
+
+// NewHandler creates a handler posting payloads with send.
+//
+// Example:
+//
+//	client := feishubot.NewClient(webhookURL, secret)
+//	http.Handle("/slack", slack.NewHandler(feishubot.WebhookSender(client)))
+func NewHandler(send feishubot.SendFunc) *Handler {
+	return &Handler{send: send}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := readPayload(req)
+	if err != nil {
+		h.handleError(err)
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+		return
+	}
+	if payload.Text == "" && len(payload.Blocks) == 0 && len(payload.Attachments) == 0 {
+		h.handleError(errNoText)
+		http.Error(w, "no_text", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.send(req.Context(), payload.Message()); err != nil {
+		h.handleError(fmt.Errorf("failed to post payload: %w", err))
+		http.Error(w, "failed to post payload", http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok"))
+}
+
+// readPayload decodes the payload of a request, given as a JSON body or as
+// the payload field of a URL encoded form.
+func readPayload(req *http.Request) (*Payload, error) {
+	data, err := io.ReadAll(io.LimitReader(req.Body, maxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" {
+		form, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse form: %w", err)
+		}
+		data = []byte(form.Get("payload"))
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return &payload, nil
+}
+
+// handleError reports err to the error handler.
+func (h *Handler) handleError(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+	}
+}
@@ -0,0 +1,64 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	var sent []*feishubot.Message
+	var sendErr error
+	handler := NewHandler(func(_ context.Context, msg *feishubot.Message) error {
+		sent = append(sent, msg)
+		return sendErr
+	})
+	var errs []error
+	handler.OnError = func(err error) { errs = append(errs, err) }
+
+	serve := func(method, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/slack", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "application/json", `{"text": "Hello *world*"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "ok", rec.Body.String())
+	require.Len(t, sent, 1)
+	require.Equal(t, feishubot.MsgTypeInteractive, sent[0].MsgType)
+
+	form := url.Values{"payload": {`{"text": "From a form"}`}}.Encode()
+	rec = serve(http.MethodPost, "application/x-www-form-urlencoded", form)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, sent, 2)
+
+	rec = serve(http.MethodPost, "application/json", `{"text":`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, "invalid_payload\n", rec.Body.String())
+
+	rec = serve(http.MethodPost, "application/json", `{"username": "bot"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, "no_text\n", rec.Body.String())
+
+	rec = serve(http.MethodGet, "", "")
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	require.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+
+	sendErr = errors.New("rate limited")
+	rec = serve(http.MethodPost, "application/json", `{"text": "hi"}`)
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+
+	require.Len(t, errs, 3)
+	require.ErrorIs(t, errs[1], errNoText)
+	require.EqualError(t, errs[2], "failed to post payload: rate limited")
+}
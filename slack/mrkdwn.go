@@ -0,0 +1,90 @@
+package slack
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// codePattern matches code blocks and inline code, which are kept as-is.
+	codePattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]+`")
+	// linkPattern matches links, mentions and special commands.
+	linkPattern = regexp.MustCompile(`<([^<>\n]+)>`)
+	// placeholderPattern matches the placeholders of protected spans.
+	placeholderPattern = regexp.MustCompile("\x00([0-9]+)\x00")
+
+	strikePattern = regexp.MustCompile(`(^|[^\w~])~([^~\n]+)~`)
+	boldPattern   = regexp.MustCompile(`(^|[^\w*])\*([^*\n]+)\*`)
+	italicPattern = regexp.MustCompile(`(^|[^\w_])_([^_\n]+)_`)
+)
+
+// entityReplacer unescapes the entities escaped in Slack text.
+var entityReplacer = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">")
+
+// tagEscaper escapes decoded text again for Feishu markdown, which renders
+// the entities as the characters, so that escaped tags such as
+// &lt;at id=all&gt; are shown rather than interpreted.
+var tagEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Markdown converts Slack mrkdwn to Feishu card markdown: *bold*, _italic_
+// and ~strike~ become **bold**, *italic* and ~~strike~~, <url|text> links
+// become [text](url), and <!here> and <!channel> mention everyone. User and
+// channel mentions cannot be resolved to Feishu users and are shown by
+// their label or ID. Code is kept as-is; tags in the text, which Slack
+// escapes as entities, are shown rather than interpreted by Feishu.
+func Markdown(mrkdwn string) string {
+	// Protect code and links from the emphasis conversion
+	var spans []string
+	protect := func(span string) string {
+		spans = append(spans, span)
+		return "\x00" + strconv.Itoa(len(spans)-1) + "\x00"
+	}
+	s := codePattern.ReplaceAllStringFunc(mrkdwn, func(code string) string {
+		if strings.HasPrefix(code, "```") {
+			code = "```\n" + strings.Trim(strings.TrimSuffix(strings.TrimPrefix(code, "```"), "```"), "\n") + "\n```"
+		}
+		return protect(entityReplacer.Replace(code))
+	})
+	s = linkPattern.ReplaceAllStringFunc(s, func(link string) string {
+		return protect(convertLink(link[1 : len(link)-1]))
+	})
+
+	s = strikePattern.ReplaceAllString(s, "$1~~$2~~")
+	s = boldPattern.ReplaceAllString(s, "$1**$2**")
+	s = italicPattern.ReplaceAllString(s, "$1*$2*")
+	s = tagEscaper.Replace(entityReplacer.Replace(s))
+
+	return placeholderPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		i, _ := strconv.Atoi(strings.Trim(placeholder, "\x00"))
+		return spans[i]
+	})
+}
+
+// convertLink converts the content of a <...> span.
+func convertLink(link string) string {
+	target, label := link, ""
+	if i := strings.IndexByte(link, '|'); i >= 0 {
+		target, label = link[:i], link[i+1:]
+	}
+	target, label = entityReplacer.Replace(target), tagEscaper.Replace(entityReplacer.Replace(label))
+
+	switch {
+	case strings.HasPrefix(target, "@"), strings.HasPrefix(target, "#"):
+		if label != "" {
+			return target[:1] + strings.TrimPrefix(label, target[:1])
+		}
+		return tagEscaper.Replace(target)
+	case target == "!here", target == "!channel", target == "!everyone":
+		return "<at id=all></at>"
+	case strings.HasPrefix(target, "!"):
+		// User groups and dates, shown by their fallback text
+		if label != "" {
+			return label
+		}
+		return tagEscaper.Replace(target[1:])
+	case label == "":
+		return "[" + tagEscaper.Replace(strings.TrimPrefix(target, "mailto:")) + "](" + target + ")"
+	}
+	return "[" + label + "](" + target + ")"
+}
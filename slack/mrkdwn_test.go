@@ -0,0 +1,37 @@
+package slack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		mrkdwn string
+		want   string
+	}{
+		{name: "emphasis", mrkdwn: "*bold* _italic_ ~strike~", want: "**bold** *italic* ~~strike~~"},
+		{name: "nested", mrkdwn: "*bold _and italic_*", want: "**bold *and italic***"},
+		{name: "identifiers", mrkdwn: "set max_retry_count to 2*3*4", want: "set max_retry_count to 2*3*4"},
+		{name: "link", mrkdwn: "See <https://example.com/a_b_c|the *docs*>", want: "See [the *docs*](https://example.com/a_b_c)"},
+		{name: "bare link", mrkdwn: "<https://example.com>", want: "[https://example.com](https://example.com)"},
+		{name: "mailto", mrkdwn: "<mailto:ops@example.com>", want: "[ops@example.com](mailto:ops@example.com)"},
+		{name: "bold link", mrkdwn: "*<https://ci.example.com/42|Build #42>* failed", want: "**[Build #42](https://ci.example.com/42)** failed"},
+		{name: "mentions", mrkdwn: "<!here> <@U024BE7LH> <@U024BE7LH|bob> <#C024BE7LR|general>", want: "<at id=all></at> @U024BE7LH @bob #general"},
+		{name: "subteam", mrkdwn: "<!subteam^SAZ94GDB8|@oncall>", want: "@oncall"},
+		{name: "date", mrkdwn: "<!date^1392734382^{date}|Feb 18, 2014>", want: "Feb 18, 2014"},
+		{name: "entities", mrkdwn: "a &lt; b &amp;&amp; c &gt; d", want: "a &lt; b &amp;&amp; c &gt; d"},
+		{name: "escaped tags", mrkdwn: "&lt;at id=all&gt;&lt;/at&gt; &lt;font color='red'&gt;x&lt;/font&gt;", want: "&lt;at id=all&gt;&lt;/at&gt; &lt;font color='red'&gt;x&lt;/font&gt;"},
+		{name: "escaped tag in link", mrkdwn: "<https://example.com|&lt;at id=all&gt;&lt;/at&gt;>", want: "[&lt;at id=all&gt;&lt;/at&gt;](https://example.com)"},
+		{name: "inline code", mrkdwn: "run `make *all*` now", want: "run `make *all*` now"},
+		{name: "code block", mrkdwn: "```if a &lt; b {\n\t*x* = 1\n}```", want: "```\nif a < b {\n\t*x* = 1\n}\n```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Markdown(tt.mrkdwn))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Package slack accepts Slack incoming-webhook payloads and forwards them to
+// Feishu, so that tools that can only notify a "Slack webhook URL" can post
+// to Feishu unchanged: point them at a Handler instead of
+// hooks.slack.com.
+//
+// Payloads are converted to cards: Block Kit blocks and legacy attachments
+// are rendered with the closest card elements, and Slack mrkdwn is
+// converted to Feishu markdown, see Markdown, while plain text is escaped
+// so that it is shown as is. Interactive elements other than link buttons,
+// and images, which cards can only show once uploaded to Feishu, are
+// rendered as links or dropped.
+package slack
+
+import (
+	"encoding/json"
+	"strings"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Payload is a Slack incoming-webhook payload.
+//
+// See: https://api.slack.com/messaging/webhooks
+type Payload struct {
+	// Text is the message, or the notification fallback if there are
+	// blocks.
+	Text        string       `json:"text"`
+	Blocks      []Block      `json:"blocks"`
+	Attachments []Attachment `json:"attachments"`
+	// Mrkdwn disables the markdown of Text when false.
+	Mrkdwn *bool `json:"mrkdwn"`
+}
+
+// Text is a Block Kit text object.
+type Text struct {
+	// Type is "plain_text" or "mrkdwn".
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// markdown returns the text as Feishu markdown: mrkdwn is converted, plain
+// text escaped.
+func (t *Text) markdown() string {
+	if t == nil {
+		return ""
+	}
+	if t.Type == "mrkdwn" {
+		return Markdown(t.Text)
+	}
+	return escapeText(t.Text)
+}
+
+// escapeText returns plain Slack text as Feishu markdown showing it as is,
+// with its entities decoded and its markup escaped, see
+// feishubot.EscapeMarkdown.
+func escapeText(text string) string {
+	return feishubot.EscapeMarkdown(entityReplacer.Replace(text))
+}
+
+// Block is a Block Kit layout block. Only the fields of the supported block
+// types are decoded: header, section, divider, context, actions and image.
+//
+// See: https://api.slack.com/reference/block-kit/blocks
+type Block struct {
+	Type   string `json:"type"`
+	Text   *Text  `json:"text"`
+	Fields []Text `json:"fields"`
+	// Accessory is the element shown next to a section.
+	Accessory *Element `json:"accessory"`
+	// Elements are the elements of context and actions blocks.
+	Elements []Element `json:"elements"`
+	// ImageURL, AltText and Title are the fields of image blocks.
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+	Title    *Text  `json:"title"`
+}
+
+// Element is a Block Kit element of a context or actions block, or a
+// section accessory: a text object, an image or a button.
+type Element struct {
+	Type string `json:"type"`
+	// Text is the text of text objects and buttons.
+	Text     *Text  `json:"-"`
+	URL      string `json:"url"`
+	Style    string `json:"style"`
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+// UnmarshalJSON decodes the element, whose text field is a string for text
+// objects and a text object for buttons.
+func (e *Element) UnmarshalJSON(data []byte) error {
+	type alias Element
+	aux := struct {
+		*alias
+		Text json.RawMessage `json:"text"`
+	}{alias: (*alias)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	text := strings.TrimSpace(string(aux.Text))
+	switch {
+	case text == "" || text == "null":
+	case strings.HasPrefix(text, "{"):
+		e.Text = &Text{}
+		return json.Unmarshal(aux.Text, e.Text)
+	default:
+		e.Text = &Text{Type: e.Type}
+		return json.Unmarshal(aux.Text, &e.Text.Text)
+	}
+	return nil
+}
+
+// Attachment is a legacy message attachment, still sent by many tools.
+//
+// See: https://api.slack.com/reference/messaging/attachments
+type Attachment struct {
+	// Color is "good", "warning", "danger" or a hex color.
+	Color     string            `json:"color"`
+	Fallback  string            `json:"fallback"`
+	Pretext   string            `json:"pretext"`
+	Title     string            `json:"title"`
+	TitleLink string            `json:"title_link"`
+	Text      string            `json:"text"`
+	Fields    []AttachmentField `json:"fields"`
+	ImageURL  string            `json:"image_url"`
+	Footer    string            `json:"footer"`
+	Blocks    []Block           `json:"blocks"`
+}
+
+// AttachmentField is a field of an attachment.
+type AttachmentField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
@@ -0,0 +1,328 @@
+//go:build go1.21
+
+// Package slogfeishu provides a log/slog handler that posts log records to
+// Feishu as interactive cards, e.g. to get errors of a service into the
+// chat of its team.
+//
+// Records are batched into one card per interval, with the header colored
+// by the highest level and attributes shown as fields. Repeated messages are
+// counted instead of repeated, and records beyond a per-card maximum are
+// dropped and counted, so that an error storm posts a few summary cards
+// instead of exhausting the rate limit of the webhook.
+//
+// Example:
+//
+//	handler := slogfeishu.NewHandler(feishubot.WebhookSender(feishubot.NewClient(webhookURL, secret)), &slogfeishu.Options{
+//		Level: slog.LevelError,
+//		Title: "api-gateway",
+//	})
+//	defer handler.Close(context.Background())
+//	logger := slog.New(handler)
+//	logger.Error("payment failed", "order", orderID, "err", err)
+package slogfeishu
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+// Defaults of Options.
+const (
+	DefaultTitle      = "Logs"
+	DefaultInterval   = 10 * time.Second
+	DefaultMaxRecords = 10
+	DefaultTimeout    = 10 * time.Second
+)
+
+// maxValueBytes is the length records' messages and attribute values are
+// truncated to.
+const maxValueBytes = 1000
+
+// Options configures a Handler.
+type Options struct {
+	// Level is the minimum level of records posted. If nil, slog.LevelError
+	// is used.
+	Level slog.Leveler
+
+	// Title is the title of the cards. If empty, DefaultTitle is used.
+	Title string
+
+	// Interval is how long records are collected before they are posted,
+	// which is also the minimum interval between cards. If zero,
+	// DefaultInterval is used.
+	Interval time.Duration
+
+	// Sample reports whether a record of the level is posted, e.g. to post
+	// a share of noisy warnings. Records not sampled are neither posted nor
+	// counted. If nil, all records of the level are posted.
+	Sample func(r slog.Record) bool
+
+	// MaxRecords is the maximum number of distinct messages per card;
+	// further records are only counted. If zero, DefaultMaxRecords is used.
+	MaxRecords int
+
+	// Timeout bounds sending a card after an interval. If zero,
+	// DefaultTimeout is used.
+	Timeout time.Duration
+
+	// OnError is called with errors of sending cards after an interval. If
+	// nil, such errors are ignored.
+	OnError func(error)
+}
+
+// Handler is a slog.Handler posting records to Feishu in batches. Handlers
+// returned by WithAttrs and WithGroup share the batches of the handler they
+// are derived from.
+type Handler struct {
+	batch  *batch
+	level  slog.Leveler
+	attrs  []feishubot.KV
+	prefix string
+}
+
+// NewHandler creates a handler posting records with send. opts may be nil
+// to use the defaults. Close the handler before the program exits to post
+// the records of the current interval.
+func NewHandler(send feishubot.SendFunc, opts *Options) *Handler {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.Level == nil {
+		o.Level = slog.LevelError
+	}
+	if o.Title == "" {
+		o.Title = DefaultTitle
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultInterval
+	}
+	if o.MaxRecords <= 0 {
+		o.MaxRecords = DefaultMaxRecords
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	return &Handler{
+		batch: &batch{send: send, opts: o, index: make(map[string]*entry)},
+		level: o.Level,
+	}
+}
+
+// Enabled reports whether records of the level are posted.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle adds the record to the current batch, unless it is not sampled.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	if sample := h.batch.opts.Sample; sample != nil && !sample(r) {
+		return nil
+	}
+	fields := append([]feishubot.KV(nil), h.attrs...)
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, h.prefix, attr)
+		return true
+	})
+	h.batch.add(r.Level, r.Time, r.Message, fields)
+	return nil
+}
+
+// WithAttrs returns a handler adding attrs to each record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := *h
+	derived.attrs = append([]feishubot.KV(nil), h.attrs...)
+	for _, attr := range attrs {
+		derived.attrs = appendAttr(derived.attrs, h.prefix, attr)
+	}
+	return &derived
+}
+
+// WithGroup returns a handler qualifying the keys of attributes with name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	derived := *h
+	derived.prefix = h.prefix + name + "."
+	return &derived
+}
+
+// Flush posts the records of the current batch, if any.
+func (h *Handler) Flush(ctx context.Context) error {
+	return h.batch.flush(ctx)
+}
+
+// Close posts the records of the current batch and stops posting further
+// records.
+func (h *Handler) Close(ctx context.Context) error {
+	h.batch.mu.Lock()
+	h.batch.closed = true
+	if h.batch.timer != nil {
+		h.batch.timer.Stop()
+	}
+	h.batch.mu.Unlock()
+	return h.batch.flush(ctx)
+}
+
+// appendAttr appends attr as fields, flattening groups into keys qualified
+// with their name.
+func appendAttr(fields []feishubot.KV, prefix string, attr slog.Attr) []feishubot.KV {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			fields = appendAttr(fields, groupPrefix, member)
+		}
+		return fields
+	}
+	return append(fields, feishubot.KV{
+		Label: prefix + attr.Key,
+		Value: feishubot.TruncateText(attr.Value.String(), maxValueBytes),
+	})
+}
+
+// entry is a distinct message of a batch.
+type entry struct {
+	level   slog.Level
+	time    time.Time
+	message string
+	fields  []feishubot.KV
+	count   int
+}
+
+// batch collects the records of an interval.
+type batch struct {
+	send feishubot.SendFunc
+	opts Options
+
+	mu      sync.Mutex
+	entries []*entry
+	index   map[string]*entry
+	dropped int
+	timer   *time.Timer
+	closed  bool
+}
+
+// add adds a record, starting the interval if it is the first of the batch.
+// Records with the level and message of a previous record are counted.
+func (b *batch) add(level slog.Level, t time.Time, message string, fields []feishubot.KV) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	key := level.String() + "\x00" + message
+	if e, ok := b.index[key]; ok {
+		e.count++
+	} else if len(b.entries) >= b.opts.MaxRecords {
+		b.dropped++
+	} else {
+		e := &entry{level: level, time: t, message: message, fields: fields, count: 1}
+		b.entries = append(b.entries, e)
+		b.index[key] = e
+	}
+
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.opts.Interval, b.flushAfterInterval)
+	}
+}
+
+// flushAfterInterval posts the batch at the end of an interval.
+func (b *batch) flushAfterInterval() {
+	ctx, cancel := context.WithTimeout(context.Background(), b.opts.Timeout)
+	defer cancel()
+
+	if err := b.flush(ctx); err != nil && b.opts.OnError != nil {
+		b.opts.OnError(err)
+	}
+}
+
+// flush posts the collected records and starts a new batch.
+func (b *batch) flush(ctx context.Context) error {
+	b.mu.Lock()
+	entries, dropped := b.entries, b.dropped
+	b.entries = nil
+	b.index = make(map[string]*entry)
+	b.dropped = 0
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := b.send(ctx, feishubot.NewInteractiveMessage(b.card(entries, dropped))); err != nil {
+		return fmt.Errorf("failed to post log records: %w", err)
+	}
+	return nil
+}
+
+// card renders the records of a batch.
+func (b *batch) card(entries []*entry, dropped int) *feishubot.Card {
+	maxLevel := entries[0].level
+	total := dropped
+	for _, e := range entries {
+		if e.level > maxLevel {
+			maxLevel = e.level
+		}
+		total += e.count
+	}
+
+	template, tagColor := levelColors(maxLevel)
+	builder := feishubot.NewCardBuilder().
+		Header(b.opts.Title, template).
+		HeaderTags(feishubot.NewTextTag(maxLevel.String(), tagColor))
+	if total > 1 {
+		builder.Subtitle(fmt.Sprintf("%d records", total))
+	}
+
+	for i, e := range entries {
+		if i > 0 {
+			builder.Divider()
+		}
+		var line strings.Builder
+		fmt.Fprintf(&line, "**%s** %s %s", e.level, e.time.Format("15:04:05"),
+			feishubot.EscapeMarkdown(feishubot.TruncateText(e.message, maxValueBytes)))
+		if e.count > 1 {
+			fmt.Fprintf(&line, " (×%d)", e.count)
+		}
+		builder.Markdown(line.String())
+		if len(e.fields) > 0 {
+			builder.Fields(e.fields)
+		}
+	}
+	if dropped > 0 {
+		builder.Divider().Markdown(fmt.Sprintf("*%d more records dropped*", dropped))
+	}
+	return builder.Build()
+}
+
+// levelColors returns the header template and tag color of a level.
+func levelColors(level slog.Level) (template, tagColor string) {
+	switch {
+	case level >= slog.LevelError:
+		return feishubot.TemplateRed, feishubot.TextTagRed
+	case level >= slog.LevelWarn:
+		return feishubot.TemplateOrange, feishubot.TextTagOrange
+	case level >= slog.LevelInfo:
+		return feishubot.TemplateBlue, feishubot.TextTagBlue
+	}
+	return feishubot.TemplateGrey, feishubot.TextTagNeutral
+}
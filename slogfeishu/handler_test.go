@@ -0,0 +1,195 @@
+//go:build go1.21
+
+package slogfeishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the cards sent by a handler.
+type recorder struct {
+	mu    sync.Mutex
+	cards []string
+	err   error
+}
+
+func (r *recorder) send(_ context.Context, msg *feishubot.Message) error {
+	data, err := json.Marshal(msg.TypedCard)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cards = append(r.cards, string(data))
+	return r.err
+}
+
+func (r *recorder) sent() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.cards...)
+}
+
+func TestHandler(t *testing.T) {
+	rec := &recorder{}
+	handler := NewHandler(rec.send, &Options{Level: slog.LevelWarn, Title: "api", Interval: time.Hour})
+	logger := slog.New(handler)
+
+	at := time.Date(2024, 5, 1, 12, 30, 45, 0, time.UTC)
+	record := func(level slog.Level, msg string, args ...any) {
+		r := slog.NewRecord(at, level, msg, 0)
+		r.Add(args...)
+		require.NoError(t, logger.Handler().Handle(context.Background(), r))
+	}
+
+	logger.Info("ignored")
+	require.False(t, handler.Enabled(context.Background(), slog.LevelInfo))
+
+	record(slog.LevelWarn, "slow query", "ms", 1200)
+	reqLogger := logger.With("request", "r-1").WithGroup("db")
+	r := slog.NewRecord(at, slog.LevelError, "connection lost", 0)
+	r.Add("host", "db-1", slog.Group("pool", "size", 10))
+	require.NoError(t, reqLogger.Handler().Handle(context.Background(), r))
+	record(slog.LevelWarn, "slow query", "ms", 900)
+
+	require.Empty(t, rec.sent())
+	require.NoError(t, handler.Close(context.Background()))
+
+	cards := rec.sent()
+	require.Len(t, cards, 1)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {
+			"title": {"tag": "plain_text", "content": "api"},
+			"subtitle": {"tag": "plain_text", "content": "3 records"},
+			"template": "red",
+			"text_tag_list": [{"tag": "text_tag", "text": {"tag": "plain_text", "content": "ERROR"}, "color": "red"}]
+		},
+		"body": {"elements": [
+			{"tag": "markdown", "content": "**WARN** 12:30:45 slow query (×2)"},
+			{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**ms:** 1200"}}
+			]},
+			{"tag": "hr"},
+			{"tag": "markdown", "content": "**ERROR** 12:30:45 connection lost"},
+			{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**request:** r-1"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**db.host:** db-1"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**db.pool.size:** 10"}}
+			]}
+		]}
+	}`, cards[0])
+
+	logger.Error("after close")
+	require.NoError(t, handler.Flush(context.Background()))
+	require.Len(t, rec.sent(), 1)
+}
+
+func TestHandlerEscapesMarkdown(t *testing.T) {
+	rec := &recorder{}
+	handler := NewHandler(rec.send, &Options{Interval: time.Hour})
+
+	r := slog.NewRecord(time.Date(2024, 5, 1, 12, 30, 45, 0, time.UTC), slog.LevelError,
+		"user [x](http://evil) **bold** <at id=all></at>", 0)
+	r.Add("name", "<at id=all></at> **bold**")
+	require.NoError(t, handler.Handle(context.Background(), r))
+	require.NoError(t, handler.Flush(context.Background()))
+
+	cards := rec.sent()
+	require.Len(t, cards, 1)
+	var card struct {
+		Body struct {
+			Elements []json.RawMessage
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(cards[0]), &card))
+	require.Len(t, card.Body.Elements, 2)
+	require.JSONEq(t, `{"tag": "markdown", "content": "**ERROR** 12:30:45 user &#91;x&#93;(http://evil) &#42;&#42;bold&#42;&#42; &lt;at id=all&gt;&lt;/at&gt;"}`, string(card.Body.Elements[0]))
+	require.JSONEq(t, `{"tag": "div", "fields": [
+		{"is_short": true, "text": {"tag": "lark_md", "content": "**name:** &lt;at id=all&gt;&lt;/at&gt; &#42;&#42;bold&#42;&#42;"}}
+	]}`, string(card.Body.Elements[1]))
+}
+
+func TestHandlerDropsRecords(t *testing.T) {
+	rec := &recorder{}
+	handler := NewHandler(rec.send, &Options{Interval: time.Hour, MaxRecords: 2})
+	logger := slog.New(handler)
+
+	for _, msg := range []string{"a", "b", "c", "d", "a"} {
+		logger.Error(msg)
+	}
+	require.NoError(t, handler.Flush(context.Background()))
+
+	var card struct {
+		Header struct {
+			Subtitle struct{ Content string }
+		}
+		Body struct {
+			Elements []map[string]interface{}
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(rec.sent()[0]), &card))
+	require.Equal(t, "5 records", card.Header.Subtitle.Content)
+	require.Len(t, card.Body.Elements, 5)
+	require.Contains(t, card.Body.Elements[0]["content"], "a (×2)")
+	require.Equal(t, "*2 more records dropped*", card.Body.Elements[4]["content"])
+
+	require.NoError(t, handler.Flush(context.Background()))
+	require.Len(t, rec.sent(), 1)
+}
+
+func TestHandlerSample(t *testing.T) {
+	rec := &recorder{}
+	handler := NewHandler(rec.send, &Options{
+		Level:    slog.LevelWarn,
+		Interval: time.Hour,
+		Sample: func(r slog.Record) bool {
+			return r.Level >= slog.LevelError || r.Message != "slow query"
+		},
+	})
+	logger := slog.New(handler)
+
+	logger.Warn("slow query")
+	require.NoError(t, handler.Flush(context.Background()))
+	require.Empty(t, rec.sent())
+
+	logger.Warn("slow query")
+	logger.Warn("cache miss")
+	logger.Error("slow query")
+	require.NoError(t, handler.Flush(context.Background()))
+	cards := rec.sent()
+	require.Len(t, cards, 1)
+	require.Contains(t, cards[0], `"2 records"`)
+	require.Contains(t, cards[0], "**WARN** ")
+	require.Contains(t, cards[0], "cache miss")
+	require.Contains(t, cards[0], "**ERROR** ")
+}
+
+func TestHandlerInterval(t *testing.T) {
+	rec := &recorder{err: errors.New("webhook down")}
+	errs := make(chan error, 1)
+	handler := NewHandler(rec.send, &Options{
+		Interval: 10 * time.Millisecond,
+		OnError:  func(err error) { errs <- err },
+	})
+	defer handler.Close(context.Background())
+
+	slog.New(handler).Error("boom")
+
+	select {
+	case err := <-errs:
+		require.EqualError(t, err, "failed to post log records: webhook down")
+	case <-time.After(5 * time.Second):
+		t.Fatal("records were not posted after the interval")
+	}
+	require.Len(t, rec.sent(), 1)
+}
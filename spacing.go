@@ -0,0 +1,161 @@
+package feishubot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Direction is the direction in which the elements of a card body or
+// container are laid out.
+type Direction string
+
+const (
+	// DirectionVertical stacks elements from top to bottom.
+	DirectionVertical Direction = "vertical"
+
+	// DirectionHorizontal places elements side by side.
+	DirectionHorizontal Direction = "horizontal"
+)
+
+// Spacing is the spacing between elements, either a preset or a number of
+// pixels created with SpacingPx.
+type Spacing string
+
+const (
+	// SpacingSmall is a 4px spacing.
+	SpacingSmall Spacing = "small"
+
+	// SpacingMedium is an 8px spacing.
+	SpacingMedium Spacing = "medium"
+
+	// SpacingLarge is a 12px spacing.
+	SpacingLarge Spacing = "large"
+
+	// SpacingExtraLarge is a 16px spacing.
+	SpacingExtraLarge Spacing = "extra_large"
+)
+
+// Limits of spacing values accepted by schema 2.0 cards, in pixels.
+const (
+	maxSpacingPx = 99
+	minMarginPx  = -99
+)
+
+// SpacingPx returns a spacing of px pixels (0-99).
+func SpacingPx(px int) Spacing {
+	return Spacing(pixels(px))
+}
+
+// Padding is the padding of a card body or container: one value for all
+// sides, two values for vertical and horizontal padding, or four values for
+// the top, right, bottom and left padding, each 0-99px.
+type Padding string
+
+// PaddingAll returns a padding of px pixels on all sides.
+func PaddingAll(px int) Padding {
+	return Padding(pixels(px))
+}
+
+// PaddingXY returns a padding of vertical pixels at the top and bottom and
+// horizontal pixels at the left and right.
+func PaddingXY(vertical, horizontal int) Padding {
+	return Padding(pixels(vertical, horizontal))
+}
+
+// NewPadding returns a padding with the given number of pixels per side.
+func NewPadding(top, right, bottom, left int) Padding {
+	return Padding(pixels(top, right, bottom, left))
+}
+
+// Margin is the margin around an element, in the same format as Padding
+// but with values of -99 to 99px.
+type Margin string
+
+// MarginAll returns a margin of px pixels on all sides.
+func MarginAll(px int) Margin {
+	return Margin(pixels(px))
+}
+
+// MarginXY returns a margin of vertical pixels at the top and bottom and
+// horizontal pixels at the left and right.
+func MarginXY(vertical, horizontal int) Margin {
+	return Margin(pixels(vertical, horizontal))
+}
+
+// NewMargin returns a margin with the given number of pixels per side.
+func NewMargin(top, right, bottom, left int) Margin {
+	return Margin(pixels(top, right, bottom, left))
+}
+
+// WithMargin sets the margin around an element.
+func WithMargin(margin Margin) ElementOption {
+	return func(element MapElement) {
+		element["margin"] = margin
+	}
+}
+
+// WithPadding sets the padding inside a container element such as a
+// collapsible panel or form.
+func WithPadding(padding Padding) ElementOption {
+	return func(element MapElement) {
+		element["padding"] = padding
+	}
+}
+
+// pixels formats values as space-separated pixel values, e.g. "4px 12px".
+func pixels(values ...int) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		parts = append(parts, strconv.Itoa(v)+"px")
+	}
+	return strings.Join(parts, " ")
+}
+
+// checkDirection returns an error if s is not a valid direction.
+func checkDirection(s string) error {
+	if Direction(s) == DirectionVertical || Direction(s) == DirectionHorizontal {
+		return nil
+	}
+	return fmt.Errorf("invalid direction %q, must be %q or %q", s, DirectionVertical, DirectionHorizontal)
+}
+
+// checkSpacing returns an error if s is not a spacing preset or a pixel
+// value of 0-99px.
+func checkSpacing(s string) error {
+	switch Spacing(s) {
+	case SpacingSmall, SpacingMedium, SpacingLarge, SpacingExtraLarge:
+		return nil
+	}
+	if _, ok := parsePixels(s, 0, maxSpacingPx); !ok {
+		return fmt.Errorf("invalid spacing %q, must be small, medium, large, extra_large or 0-%dpx", s, maxSpacingPx)
+	}
+	return nil
+}
+
+// checkBox returns an error if s is not a padding or margin of one, two or
+// four pixel values between min and max.
+func checkBox(s string, min, max int) error {
+	parts := strings.Fields(s)
+	if len(parts) == 1 || len(parts) == 2 || len(parts) == 4 {
+		valid := true
+		for _, part := range parts {
+			if _, ok := parsePixels(part, min, max); !ok {
+				valid = false
+			}
+		}
+		if valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value %q, must be one, two or four values of %d-%dpx", s, min, max)
+}
+
+// parsePixels parses a value such as "12px" and checks its range.
+func parsePixels(s string, min, max int) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSuffix(s, "px"))
+	if err != nil || !strings.HasSuffix(s, "px") || n < min || n > max {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,55 @@
+package feishubot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpacingConstructors(t *testing.T) {
+	require.Equal(t, Spacing("8px"), SpacingPx(8))
+	require.Equal(t, Padding("12px"), PaddingAll(12))
+	require.Equal(t, Padding("4px 12px"), PaddingXY(4, 12))
+	require.Equal(t, Padding("1px 2px 3px 4px"), NewPadding(1, 2, 3, 4))
+	require.Equal(t, Margin("0px"), MarginAll(0))
+	require.Equal(t, Margin("-4px 0px"), MarginXY(-4, 0))
+	require.Equal(t, Margin("1px 2px 3px 4px"), NewMargin(1, 2, 3, 4))
+
+	element := NewCollapsiblePanelElement("title", false).(MapElement)
+	WithMargin(MarginXY(8, 0))(element)
+	WithPadding(PaddingAll(4))(element)
+	require.Equal(t, Margin("8px 0px"), element["margin"])
+	require.Equal(t, Padding("4px"), element["padding"])
+}
+
+func TestCheckSpacing(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(string) error
+		value string
+		valid bool
+	}{
+		{name: "vertical", check: checkDirection, value: "vertical", valid: true},
+		{name: "bad direction", check: checkDirection, value: "row"},
+		{name: "spacing preset", check: checkSpacing, value: "extra_large", valid: true},
+		{name: "spacing pixels", check: checkSpacing, value: "99px", valid: true},
+		{name: "spacing too large", check: checkSpacing, value: "100px"},
+		{name: "spacing without unit", check: checkSpacing, value: "8"},
+		{name: "padding four values", check: func(s string) error { return checkBox(s, 0, 99) }, value: "12px 12px 12px 12px", valid: true},
+		{name: "padding three values", check: func(s string) error { return checkBox(s, 0, 99) }, value: "1px 2px 3px"},
+		{name: "negative padding", check: func(s string) error { return checkBox(s, 0, 99) }, value: "-1px"},
+		{name: "negative margin", check: func(s string) error { return checkBox(s, -99, 99) }, value: "-8px 0px", valid: true},
+		{name: "margin with percent", check: func(s string) error { return checkBox(s, -99, 99) }, value: "10%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check(tt.value)
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package feishubot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SplitText splits s into chunks of at most maxBytes bytes each.
+//
+// Splits are made on paragraph boundaries (blank lines) where possible, then
+// on line boundaries, and only as a last resort inside a line, always at a
+// rune boundary and never inside an <at> mention tag or markdown link.
+// When more than one chunk is produced, each chunk ends with a continuation
+// marker such as "(2/5)" on its own line; the marker counts towards maxBytes.
+//
+// If maxBytes is too small to hold a marker, chunks are returned without it.
+func SplitText(s string, maxBytes int) []string {
+	if len(s) <= maxBytes || maxBytes <= 0 {
+		return []string{s}
+	}
+
+	// The marker size depends on the number of chunks, which in turn depends
+	// on the space left after reserving room for the marker.
+	for digits := 1; ; digits++ {
+		reserve := len("\n(/)") + 2*digits
+		if maxBytes-reserve < 1 {
+			return splitChunks(s, maxBytes)
+		}
+
+		chunks := splitChunks(s, maxBytes-reserve)
+		if len(strconv.Itoa(len(chunks))) > digits {
+			continue
+		}
+
+		for i := range chunks {
+			chunks[i] += fmt.Sprintf("\n(%d/%d)", i+1, len(chunks))
+		}
+		return chunks
+	}
+}
+
+// SplitTextMessages splits s with SplitText and returns one text message per
+// chunk, ready to be sent in order.
+func SplitTextMessages(s string, maxBytes int) []*Message {
+	chunks := SplitText(s, maxBytes)
+	messages := make([]*Message, 0, len(chunks))
+	for _, chunk := range chunks {
+		messages = append(messages, NewTextMessage(chunk))
+	}
+	return messages
+}
+
+// splitChunks greedily splits s into chunks of at most limit bytes.
+func splitChunks(s string, limit int) []string {
+	var chunks []string
+
+	for len(s) > limit {
+		chunk, rest := splitOnce(s, limit)
+		if chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		s = rest
+	}
+	if s != "" || len(chunks) == 0 {
+		chunks = append(chunks, s)
+	}
+
+	return chunks
+}
+
+// splitOnce returns the first chunk of at most limit bytes and the remainder.
+// limit must be less than len(s).
+func splitOnce(s string, limit int) (string, string) {
+	// Prefer a paragraph boundary, then a line boundary
+	if idx := strings.LastIndex(s[:min(len(s), limit+2)], "\n\n"); idx > 0 {
+		return s[:idx], s[idx+2:]
+	}
+	if idx := strings.LastIndex(s[:limit+1], "\n"); idx > 0 {
+		return s[:idx], s[idx+1:]
+	}
+
+	cut := truncationPoint(s, limit)
+	if cut == 0 {
+		// A single mention or link longer than the limit: cut through it
+		cut = limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			_, size := utf8.DecodeRuneInString(s)
+			cut = size
+		}
+	}
+	return s[:cut], s[cut:]
+}
+
+// min returns the smaller of a and b.
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,148 @@
+package feishubot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		maxBytes int
+		want     []string
+	}{
+		{
+			name:     "fits in one chunk",
+			s:        "short text",
+			maxBytes: 100,
+			want:     []string{"short text"},
+		},
+		{
+			name:     "split on paragraphs",
+			s:        "first paragraph\n\nsecond paragraph\n\nthird paragraph",
+			maxBytes: 40,
+			want: []string{
+				"first paragraph\n\nsecond paragraph\n(1/2)",
+				"third paragraph\n(2/2)",
+			},
+		},
+		{
+			name:     "split on lines",
+			s:        "line one\nline two\nline three",
+			maxBytes: 20,
+			want: []string{
+				"line one\n(1/3)",
+				"line two\n(2/3)",
+				"line three\n(3/3)",
+			},
+		},
+		{
+			name:     "split inside long line",
+			s:        strings.Repeat("a", 30),
+			maxBytes: 16,
+			want: []string{
+				"aaaaaaaaaa\n(1/3)",
+				"aaaaaaaaaa\n(2/3)",
+				"aaaaaaaaaa\n(3/3)",
+			},
+		},
+		{
+			name:     "too small for marker",
+			s:        "abcdef",
+			maxBytes: 2,
+			want:     []string{"ab", "cd", "ef"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitText(tt.s, tt.maxBytes)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSplitTextLimits(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 200; i++ {
+		b.WriteString(`日志行 <at user_id="ou_xxx">Tom</at> see [log](https://example.com/log)`)
+		if i%7 == 0 {
+			b.WriteString("\n\n")
+		}
+	}
+	s := b.String()
+
+	chunks := SplitText(s, 500)
+	require.Greater(t, len(chunks), 10)
+	for _, chunk := range chunks {
+		require.LessOrEqual(t, len(chunk), 500)
+		require.True(t, utf8.ValidString(chunk))
+		require.Equal(t, strings.Count(chunk, "<at "), strings.Count(chunk, "</at>"))
+	}
+}
+
+func TestSplitTextMessages(t *testing.T) {
+	messages := SplitTextMessages("line one\nline two", 14)
+	require.Len(t, messages, 2)
+	require.Equal(t, NewTextMessage("line one\n(1/2)"), messages[0])
+	require.Equal(t, NewTextMessage("line two\n(2/2)"), messages[1])
+}
+
+func TestSendAutoSplit(t *testing.T) {
+	var received []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		received = append(received, msg.Content["text"].(string))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(SuccessResponse)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/webhook", "secret")
+	client.SetAutoSplit(20)
+
+	message := NewTextMessage("line one\nline two\nline three")
+	resp, err := client.Send(context.Background(), message)
+	require.NoError(t, err)
+	require.Equal(t, 0, resp.Code)
+	require.Equal(t, []string{"line one\n(1/3)", "line two\n(2/3)", "line three\n(3/3)"}, received)
+
+	// Other message types and short texts are sent unchanged
+	received = nil
+	_, err = client.Send(context.Background(), NewTextMessage("short"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"short"}, received)
+}
+
+func TestSendAutoSplitStopsOnError(t *testing.T) {
+	calls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{Code: 9499, Msg: "Bad Request"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/webhook", "")
+	client.SetAutoSplit(20)
+
+	resp, err := client.Send(context.Background(), NewTextMessage("line one\nline two\nline three"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "chunk 1/3")
+	require.Equal(t, 9499, resp.Code)
+	require.Equal(t, 1, calls)
+}
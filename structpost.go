@@ -0,0 +1,148 @@
+package feishubot
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PostFromStruct renders the exported fields of a struct as post content,
+// one "Label: value" paragraph per field.
+//
+// Fields are configured with the `feishu` struct tag:
+//
+//	type Deployment struct {
+//		Service string `feishu:"Service"`
+//		Commit  string `feishu:"Commit,url"`       // rendered as a link
+//		Owner   string `feishu:"Owner,open_id"`    // rendered as an @ mention
+//		Note    string `feishu:"Note,omitempty"`   // skipped when empty
+//		Secret  string `feishu:"-"`                // never rendered
+//	}
+//
+// Fields without a tag use the field name as label. Fields of embedded
+// structs are rendered as if they were fields of the outer struct, and nil
+// pointers are skipped. v must be a struct or a pointer to a struct.
+//
+// Example:
+//
+//	content, err := feishubot.PostFromStruct("Deployment", deployment)
+//	if err != nil {
+//	    // handle error
+//	}
+//	message := feishubot.NewPostMessage(feishubot.LanguageZhCN, content)
+func PostFromStruct(title string, v interface{}) (*PostContent, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("PostFromStruct: nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("PostFromStruct: expected struct, got %s", rv.Kind())
+	}
+
+	return NewPostContent(title, structParagraphs(rv)...), nil
+}
+
+// fieldTag is a parsed `feishu` struct tag.
+type fieldTag struct {
+	label     string
+	url       bool
+	openID    bool
+	omitEmpty bool
+}
+
+// parseFieldTag parses the `feishu` tag of a struct field. The second result
+// is false when the field must be skipped.
+func parseFieldTag(field reflect.StructField) (fieldTag, bool) {
+	tag, hasTag := field.Tag.Lookup("feishu")
+	if tag == "-" {
+		return fieldTag{}, false
+	}
+
+	parts := strings.Split(tag, ",")
+	ft := fieldTag{label: parts[0]}
+	if !hasTag || ft.label == "" {
+		ft.label = field.Name
+	}
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "url":
+			ft.url = true
+		case "open_id":
+			ft.openID = true
+		case "omitempty":
+			ft.omitEmpty = true
+		}
+	}
+
+	return ft, true
+}
+
+// structParagraphs renders the fields of a struct value as paragraphs.
+func structParagraphs(rv reflect.Value) []Paragraph {
+	var paragraphs []Paragraph
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+
+		if field.Anonymous && field.Tag.Get("feishu") == "" {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				paragraphs = append(paragraphs, structParagraphs(fv)...)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		ft, ok := parseFieldTag(field)
+		if !ok {
+			continue
+		}
+		if fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+			if fv.IsNil() {
+				continue
+			}
+		}
+		if ft.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		paragraphs = append(paragraphs, NewParagraph(
+			NewTextElement(ft.label+": "),
+			fieldElement(ft, formatFieldValue(fv)),
+		))
+	}
+
+	return paragraphs
+}
+
+// fieldElement returns the element rendering a formatted field value.
+func fieldElement(ft fieldTag, value string) Element {
+	switch {
+	case ft.url:
+		return NewLinkElement(value, value)
+	case ft.openID:
+		return NewAtElement(value, "")
+	default:
+		return NewTextElement(value)
+	}
+}
+
+// formatFieldValue formats a field value for display, dereferencing pointers.
+func formatFieldValue(fv reflect.Value) string {
+	for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+		if s, ok := fv.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+		fv = fv.Elem()
+	}
+	return fmt.Sprint(fv.Interface())
+}
@@ -0,0 +1,83 @@
+package feishubot
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+)
+
+type testEventMeta struct {
+	Env string `feishu:"Environment"`
+}
+
+type testDeployEvent struct {
+	testEventMeta
+	Service   string   `feishu:"Service"`
+	Replicas  int      `feishu:"Replicas"`
+	Commit    string   `feishu:"Commit,url"`
+	Owner     string   `feishu:"Owner,open_id"`
+	Note      string   `feishu:"Note,omitempty"`
+	Token     string   `feishu:"-"`
+	Dashboard *url.URL `feishu:"Dashboard,url"`
+	Previous  *string
+	Untagged  bool
+	internal  string
+}
+
+func TestPostFromStruct(t *testing.T) {
+	dashboard, err := url.Parse("https://grafana.example.com/d/api")
+	require.NoError(t, err)
+
+	event := testDeployEvent{
+		testEventMeta: testEventMeta{Env: "prod"},
+		Service:       "api",
+		Replicas:      3,
+		Commit:        "https://git.example.com/c/abc123",
+		Owner:         "ou_xxx",
+		Token:         "secret",
+		Dashboard:     dashboard,
+		Untagged:      true,
+		internal:      "hidden",
+	}
+
+	want := NewPostContent(
+		"Deployment",
+		NewParagraph(NewTextElement("Environment: "), NewTextElement("prod")),
+		NewParagraph(NewTextElement("Service: "), NewTextElement("api")),
+		NewParagraph(NewTextElement("Replicas: "), NewTextElement("3")),
+		NewParagraph(NewTextElement("Commit: "), NewLinkElement("https://git.example.com/c/abc123", "https://git.example.com/c/abc123")),
+		NewParagraph(NewTextElement("Owner: "), NewAtElement("ou_xxx", "")),
+		NewParagraph(NewTextElement("Dashboard: "), NewLinkElement("https://grafana.example.com/d/api", "https://grafana.example.com/d/api")),
+		NewParagraph(NewTextElement("Untagged: "), NewTextElement("true")),
+	)
+
+	for _, v := range []interface{}{event, &event} {
+		got, err := PostFromStruct("Deployment", v)
+		require.NoError(t, err)
+		if !cmp.Equal(got, want) {
+			t.Errorf("PostFromStruct() diff = %v", cmp.Diff(want, got))
+		}
+	}
+}
+
+func TestPostFromStructErrors(t *testing.T) {
+	var nilEvent *testDeployEvent
+
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{"nil pointer", nilEvent},
+		{"not a struct", "text"},
+		{"nil", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PostFromStruct("Title", tt.v)
+			require.Error(t, err)
+		})
+	}
+}
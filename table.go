@@ -0,0 +1,190 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// TableDataType is the data type of a table column, controlling how its
+// cells are rendered.
+type TableDataType string
+
+const (
+	// TableDataText renders cells as plain text.
+	TableDataText TableDataType = "text"
+
+	// TableDataLarkMd renders cells as lark_md, e.g. links created with TableLink.
+	TableDataLarkMd TableDataType = "lark_md"
+
+	// TableDataMarkdown renders cells as markdown.
+	TableDataMarkdown TableDataType = "markdown"
+
+	// TableDataNumber renders cells as numbers, optionally formatted with
+	// TableNumberFormat.
+	TableDataNumber TableDataType = "number"
+
+	// TableDataDate renders cells holding millisecond Unix timestamps as dates.
+	TableDataDate TableDataType = "date"
+
+	// TableDataPersons renders cells holding one or more open IDs as people.
+	TableDataPersons TableDataType = "persons"
+
+	// TableDataOptions renders cells as colored option tags.
+	TableDataOptions TableDataType = "options"
+)
+
+// TableNumberFormat formats the cells of a number column.
+type TableNumberFormat struct {
+	// Symbol is a currency symbol shown before the number, e.g. "¥".
+	Symbol string `json:"symbol,omitempty"`
+	// Precision is the number of decimal places.
+	Precision int `json:"precision"`
+	// Separator enables thousands separators.
+	Separator bool `json:"separator,omitempty"`
+}
+
+// TableColumn defines a column of a table element.
+type TableColumn struct {
+	// Name is the key of the column's value in each row.
+	Name string `json:"name"`
+	// DisplayName is the column header; Name is used if empty.
+	DisplayName string        `json:"display_name,omitempty"`
+	DataType    TableDataType `json:"data_type"`
+	// Width is "auto", a fixed width such as "120px", or a percentage.
+	Width string `json:"width,omitempty"`
+	// HorizontalAlign is "left", "center" or "right".
+	HorizontalAlign string             `json:"horizontal_align,omitempty"`
+	Format          *TableNumberFormat `json:"format,omitempty"`
+	// DateFormat formats date columns, e.g. "YYYY/MM/DD HH:mm".
+	DateFormat string `json:"date_format,omitempty"`
+}
+
+// NewTableColumn creates a table column.
+func NewTableColumn(name, displayName string, dataType TableDataType) TableColumn {
+	return TableColumn{
+		Name:        name,
+		DisplayName: displayName,
+		DataType:    dataType,
+	}
+}
+
+// TableLink formats a link for a TableDataLarkMd cell.
+func TableLink(text, url string) string {
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+// Table is a typed builder for the card table element.
+//
+// Example:
+//
+//	table := feishubot.NewTable(
+//		feishubot.NewTableColumn("service", "Service", feishubot.TableDataText),
+//		feishubot.NewTableColumn("latency", "p99 (ms)", feishubot.TableDataNumber),
+//		feishubot.NewTableColumn("owner", "Owner", feishubot.TableDataPersons),
+//	).AddRow(map[string]interface{}{
+//		"service": "api",
+//		"latency": 120,
+//		"owner":   "ou_xxx",
+//	}).SetPageSize(10)
+//	body := &feishubot.CardBody{Elements: []feishubot.CardElement{table.ToElement()}}
+type Table struct {
+	Columns []TableColumn
+	Rows    []map[string]interface{}
+	// PageSize is the number of rows per page (1-10); zero uses the default.
+	PageSize int
+	// RowHeight is "low", "middle", "high" or a fixed height such as "40px".
+	RowHeight string
+	// FreezeFirstColumn keeps the first column visible when scrolling.
+	FreezeFirstColumn bool
+}
+
+// NewTable creates a table with the given columns.
+func NewTable(columns ...TableColumn) *Table {
+	return &Table{
+		Columns: columns,
+	}
+}
+
+// AddRow appends a row, mapping column names to cell values.
+func (t *Table) AddRow(row map[string]interface{}) *Table {
+	t.Rows = append(t.Rows, row)
+	return t
+}
+
+// SetRows replaces all rows.
+func (t *Table) SetRows(rows []map[string]interface{}) *Table {
+	t.Rows = rows
+	return t
+}
+
+// SetPageSize sets the number of rows per page.
+func (t *Table) SetPageSize(pageSize int) *Table {
+	t.PageSize = pageSize
+	return t
+}
+
+// SetRowHeight sets the row height.
+func (t *Table) SetRowHeight(height string) *Table {
+	t.RowHeight = height
+	return t
+}
+
+// SetFreezeFirstColumn sets whether the first column stays visible when
+// scrolling horizontally.
+func (t *Table) SetFreezeFirstColumn(freeze bool) *Table {
+	t.FreezeFirstColumn = freeze
+	return t
+}
+
+// ToElement converts the table to a card element.
+func (t *Table) ToElement() CardElement {
+	columns := t.Columns
+	if columns == nil {
+		columns = []TableColumn{}
+	}
+	rows := t.Rows
+	if rows == nil {
+		rows = []map[string]interface{}{}
+	}
+
+	result := MapElement{
+		"tag":     "table",
+		"columns": columns,
+		"rows":    rows,
+	}
+
+	if t.PageSize > 0 {
+		result["page_size"] = t.PageSize
+	}
+	if t.RowHeight != "" {
+		result["row_height"] = t.RowHeight
+	}
+	if t.FreezeFirstColumn {
+		result["freeze_first_column"] = true
+	}
+
+	return result
+}
+
+// TableRowsFromStructs converts a slice of structs (or pointers to structs)
+// to table rows, using the same keys as encoding/json, i.e. honoring `json`
+// struct tags. Column names must match those keys.
+func TableRowsFromStructs(rows interface{}) ([]map[string]interface{}, error) {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("TableRowsFromStructs: expected slice, got %s", rv.Kind())
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rows: %w", err)
+	}
+
+	var result []map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("TableRowsFromStructs: rows must be structs: %w", err)
+	}
+
+	return result, nil
+}
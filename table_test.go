@@ -0,0 +1,108 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable(t *testing.T) {
+	latency := NewTableColumn("latency", "p99 (ms)", TableDataNumber)
+	latency.Format = &TableNumberFormat{Precision: 1, Separator: true}
+	latency.HorizontalAlign = "right"
+
+	tests := []struct {
+		name  string
+		table *Table
+		want  string
+	}{
+		{
+			name: "typed columns and rows",
+			table: NewTable(
+				NewTableColumn("service", "Service", TableDataText),
+				latency,
+				NewTableColumn("logs", "Logs", TableDataLarkMd),
+			).AddRow(map[string]interface{}{
+				"service": "api",
+				"latency": 120.5,
+				"logs":    TableLink("view", "https://example.com/logs"),
+			}).SetPageSize(5).SetRowHeight("low").SetFreezeFirstColumn(true),
+			want: `{
+				"tag": "table",
+				"page_size": 5,
+				"row_height": "low",
+				"freeze_first_column": true,
+				"columns": [
+					{"name": "service", "display_name": "Service", "data_type": "text"},
+					{"name": "latency", "display_name": "p99 (ms)", "data_type": "number", "horizontal_align": "right", "format": {"precision": 1, "separator": true}},
+					{"name": "logs", "display_name": "Logs", "data_type": "lark_md"}
+				],
+				"rows": [
+					{"service": "api", "latency": 120.5, "logs": "[view](https://example.com/logs)"}
+				]
+			}`,
+		},
+		{
+			name:  "empty table",
+			table: NewTable(),
+			want:  `{"tag": "table", "columns": [], "rows": []}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.table.ToElement())
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestTableRowsFromStructs(t *testing.T) {
+	type row struct {
+		Service string `json:"service"`
+		Latency int    `json:"latency"`
+		Ignored string `json:"-"`
+	}
+
+	tests := []struct {
+		name    string
+		rows    interface{}
+		want    []map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "slice of structs",
+			rows: []row{{Service: "api", Latency: 120, Ignored: "x"}},
+			want: []map[string]interface{}{{"service": "api", "latency": float64(120)}},
+		},
+		{
+			name: "slice of pointers",
+			rows: []*row{{Service: "web"}},
+			want: []map[string]interface{}{{"service": "web", "latency": float64(0)}},
+		},
+		{
+			name:    "not a slice",
+			rows:    row{},
+			wantErr: true,
+		},
+		{
+			name:    "slice of non-structs",
+			rows:    []int{1, 2},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TableRowsFromStructs(tt.rows)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package testreport
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	feishubot "github.com/cium-cc/feishurobot"
+)
+
+const (
+	// maxFailures is the number of failed tests and packages shown with
+	// their output.
+	maxFailures = 10
+	// slowestTests is the number of slowest tests listed.
+	slowestTests = 5
+	// maxOutputBytes is the length of the output shown per failure.
+	maxOutputBytes = 1200
+)
+
+// Card renders the report as a card: green if all tests passed, red
+// otherwise, with the counts, the slowest tests, and the failed tests and
+// packages with their output in collapsed panels.
+func (r *Report) Card(title string) *feishubot.Card {
+	template, tag := feishubot.TemplateGreen, feishubot.NewTextTag("passed", feishubot.TextTagGreen)
+	if !r.OK() {
+		template, tag = feishubot.TemplateRed, feishubot.NewTextTag("failed", feishubot.TextTagRed)
+	}
+	builder := feishubot.NewCardBuilder().Header(title, template).HeaderTags(tag)
+
+	builder.Fields([]feishubot.KV{
+		{Label: "Passed", Value: strconv.Itoa(r.Passed)},
+		{Label: "Failed", Value: strconv.Itoa(r.Failed)},
+		{Label: "Skipped", Value: strconv.Itoa(r.Skipped)},
+		{Label: "Packages", Value: strconv.Itoa(len(r.Packages))},
+		{Label: "Duration", Value: r.Elapsed.Round(time.Second).String()},
+	})
+
+	var failures []feishubot.CardElement
+	for _, p := range r.FailedPackages() {
+		failures = append(failures, failurePanel("Package "+p.Name, p.Output))
+	}
+	for _, t := range r.FailedTests() {
+		failures = append(failures, failurePanel(testName(t)+" ("+formatElapsed(t.Elapsed)+")", t.Output))
+	}
+	if len(failures) > 0 {
+		builder.Markdown("**Failures**")
+		if len(failures) > maxFailures {
+			builder.Element(failures[:maxFailures]...)
+			builder.Markdown(fmt.Sprintf("*and %d more*", len(failures)-maxFailures))
+		} else {
+			builder.Element(failures...)
+		}
+	}
+
+	if slowest := r.Slowest(slowestTests); len(slowest) > 0 {
+		lines := make([]string, 0, len(slowest))
+		for _, t := range slowest {
+			lines = append(lines, fmt.Sprintf("- `%s` %s", testName(t), formatElapsed(t.Elapsed)))
+		}
+		builder.Markdown("**Slowest tests**\n" + strings.Join(lines, "\n"))
+	}
+	return builder.Build()
+}
+
+// failurePanel returns a collapsed panel showing the end of the output of
+// a failure, where the failure is usually reported.
+func failurePanel(title, output string) feishubot.CardElement {
+	output = strings.TrimRight(output, "\n")
+	if len(output) > maxOutputBytes {
+		output = "..." + tail(output, maxOutputBytes)
+	}
+	if output == "" {
+		output = "(no output)"
+	}
+	output = strings.ReplaceAll(output, "```", "'''")
+	return feishubot.NewCollapsiblePanelElement("❌ "+title, false,
+		feishubot.NewMarkdownElement("```\n"+output+"\n```"),
+	)
+}
+
+// tail returns the last at most n bytes of s, starting at a line if
+// possible.
+func tail(s string, n int) string {
+	s = s[len(s)-n:]
+	if i := strings.IndexByte(s, '\n'); i >= 0 && i < len(s)-1 {
+		return s[i+1:]
+	}
+	return strings.ToValidUTF8(s, "")
+}
+
+// testName returns the name of a test qualified with the last element of
+// its package, e.g. "feishubot.TestSend".
+func testName(t TestResult) string {
+	return path.Base(t.Package) + "." + t.Name
+}
+
+// formatElapsed formats the elapsed time of a test.
+func formatElapsed(d time.Duration) string {
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
@@ -0,0 +1,68 @@
+package testreport
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportCard(t *testing.T) {
+	data, err := json.Marshal(parseTestdata(t).Card("Nightly tests"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "2.0",
+		"header": {
+			"title": {"tag": "plain_text", "content": "Nightly tests"},
+			"template": "red",
+			"text_tag_list": [{"tag": "text_tag", "text": {"tag": "plain_text", "content": "failed"}, "color": "red"}]
+		},
+		"body": {"elements": [
+			{"tag": "div", "fields": [
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Passed:** 2"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Failed:** 3"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Skipped:** 1"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Packages:** 3"}},
+				{"is_short": true, "text": {"tag": "lark_md", "content": "**Duration:** 4s"}}
+			]},
+			{"tag": "markdown", "content": "**Failures**"},
+			{"tag": "collapsible_panel", "expanded": false,
+				"header": {"title": {"tag": "markdown", "content": "❌ Package example.com/app/store"}},
+				"elements": [{"tag": "markdown", "content": "`+"```"+`\n# example.com/app/store\nstore/store.go:12:2: undefined: sql\nFAIL\texample.com/app/store [build failed]\n`+"```"+`"}]
+			},
+			{"tag": "collapsible_panel", "expanded": false,
+				"header": {"title": {"tag": "markdown", "content": "❌ api.TestDelete/missing (0.10s)"}},
+				"elements": [{"tag": "markdown", "content": "`+"```"+`\n    api_test.go:42: got 500, want 404\n`+"```"+`"}]
+			},
+			{"tag": "collapsible_panel", "expanded": false,
+				"header": {"title": {"tag": "markdown", "content": "❌ worker.TestDrain (0.00s)"}},
+				"elements": [{"tag": "markdown", "content": "`+"```"+`\npanic: test timed out after 3s\n`+"```"+`"}]
+			},
+			{"tag": "markdown", "content": "**Slowest tests**\n- `+"`api.TestCreate`"+` 1.20s\n- `+"`api.TestDelete`"+` 0.10s\n- `+"`worker.TestDrain`"+` 0.00s"}
+		]}
+	}`, string(data))
+}
+
+func TestReportCardLimits(t *testing.T) {
+	report := &Report{}
+	for i := 0; i < 12; i++ {
+		report.Tests = append(report.Tests, TestResult{
+			Package: "p",
+			Name:    "TestFlaky" + string(rune('A'+i)),
+			Status:  StatusFail,
+			Elapsed: time.Second,
+			Output:  strings.Repeat("line of output\n", 500),
+		})
+		report.Failed++
+	}
+
+	card := report.Card("Tests")
+	require.Equal(t, "red", card.Header.Template)
+	data, err := json.Marshal(card)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "*and 2 more*")
+	require.Equal(t, 10, strings.Count(string(data), "collapsible_panel"))
+	require.Less(t, len(data), 20000)
+}
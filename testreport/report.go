@@ -0,0 +1,273 @@
+// Package testreport parses the output of "go test -json" into a report
+// and renders it as a Feishu card, with pass and fail counts, the slowest
+// tests, and the output of failed tests in collapsible panels, e.g. to post
+// nightly test results to a chat without a CI integration.
+//
+// See cmd/feishu-testreport for a command posting reports from a pipe:
+//
+//	go test -json ./... | feishu-testreport -title "Nightly tests"
+package testreport
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Status is the outcome of a test or package.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// event is a line of "go test -json" output.
+//
+// See: https://pkg.go.dev/cmd/test2json
+type event struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+	Output  string    `json:"Output"`
+	// ImportPath is the package of build events.
+	ImportPath string `json:"ImportPath"`
+}
+
+// TestResult is the result of a test or subtest.
+type TestResult struct {
+	Package string
+	Name    string
+	Status  Status
+	Elapsed time.Duration
+	// Output is the output of the test, including that of its subtests.
+	Output string
+}
+
+// PackageResult is the result of a package.
+type PackageResult struct {
+	Name    string
+	Status  Status
+	Elapsed time.Duration
+	// Output is the output of the package outside of its tests, such as
+	// build errors and panics.
+	Output string
+}
+
+// Report is the result of a test run.
+type Report struct {
+	// Packages are in the order they finished.
+	Packages []PackageResult
+	// Tests are in the order they finished.
+	Tests                   []TestResult
+	Passed, Failed, Skipped int
+	// Elapsed is the wall time of the run.
+	Elapsed time.Duration
+}
+
+// Parse reads "go test -json" output. Lines that are not JSON, such as
+// build errors printed to stderr and merged into the output, are ignored.
+// Tests that did not finish before their package failed, e.g. because of a
+// panic or timeout, are reported as failed.
+func Parse(r io.Reader) (*Report, error) {
+	var (
+		report       Report
+		first, last  time.Time
+		tests        = make(map[string]*TestResult)
+		running      = make(map[string][]*TestResult)
+		packages     = make(map[string]*PackageResult)
+		buildOutputs = make(map[string]*strings.Builder)
+	)
+	pkg := func(name string) *PackageResult {
+		p := packages[name]
+		if p == nil {
+			p = &PackageResult{Name: name}
+			packages[name] = p
+		}
+		return p
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var e event
+		if err := json.Unmarshal(line, &e); err != nil {
+			continue
+		}
+		if !e.Time.IsZero() {
+			if first.IsZero() || e.Time.Before(first) {
+				first = e.Time
+			}
+			if e.Time.After(last) {
+				last = e.Time
+			}
+		}
+
+		switch {
+		case e.Action == "build-output":
+			b := buildOutputs[e.ImportPath]
+			if b == nil {
+				b = &strings.Builder{}
+				buildOutputs[e.ImportPath] = b
+			}
+			b.WriteString(e.Output)
+
+		case e.Action == "build-fail":
+			// The package fails with a separate event
+
+		case e.Test == "":
+			p := pkg(e.Package)
+			switch e.Action {
+			case "output":
+				p.Output += e.Output
+			case "pass", "fail", "skip":
+				p.Status = Status(e.Action)
+				p.Elapsed = seconds(e.Elapsed)
+				if b := buildOutputs[e.Package]; b != nil {
+					p.Output = b.String() + p.Output
+				}
+				for _, t := range running[e.Package] {
+					if t.Status == "" {
+						t.Status = StatusFail
+						report.add(t)
+					}
+				}
+				delete(running, e.Package)
+				report.Packages = append(report.Packages, *p)
+			}
+
+		default:
+			key := e.Package + "\x00" + e.Test
+			t := tests[key]
+			if t == nil {
+				t = &TestResult{Package: e.Package, Name: e.Test}
+				tests[key] = t
+				running[e.Package] = append(running[e.Package], t)
+			}
+			switch e.Action {
+			case "output":
+				t.Output += e.Output
+				// Subtest output is part of the output of their parents
+				for parent := e.Test; strings.Contains(parent, "/"); {
+					parent = parent[:strings.LastIndex(parent, "/")]
+					if p := tests[e.Package+"\x00"+parent]; p != nil {
+						p.Output += e.Output
+					}
+				}
+			case "pass", "fail", "skip":
+				t.Status = Status(e.Action)
+				t.Elapsed = seconds(e.Elapsed)
+				report.add(t)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read test output: %w", err)
+	}
+
+	report.Elapsed = last.Sub(first)
+	return &report, nil
+}
+
+// add adds the result of a finished test.
+func (r *Report) add(t *TestResult) {
+	r.Tests = append(r.Tests, *t)
+	switch t.Status {
+	case StatusPass:
+		r.Passed++
+	case StatusFail:
+		r.Failed++
+	case StatusSkip:
+		r.Skipped++
+	}
+}
+
+// OK reports whether no test or package failed.
+func (r *Report) OK() bool {
+	if r.Failed > 0 {
+		return false
+	}
+	for _, p := range r.Packages {
+		if p.Status == StatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+// FailedTests returns the failed tests without failed subtests, the ones
+// whose output explains the failure; parents of failed subtests are left
+// out.
+func (r *Report) FailedTests() []TestResult {
+	var failed []TestResult
+	for i, t := range r.Tests {
+		if t.Status != StatusFail || r.hasFailedSubtest(i) {
+			continue
+		}
+		failed = append(failed, t)
+	}
+	return failed
+}
+
+// hasFailedSubtest reports whether the test at index i has a failed
+// subtest.
+func (r *Report) hasFailedSubtest(i int) bool {
+	parent := r.Tests[i]
+	for _, t := range r.Tests {
+		if t.Status == StatusFail && t.Package == parent.Package && strings.HasPrefix(t.Name, parent.Name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// FailedPackages returns the failed packages without failed tests, e.g.
+// because they failed to build.
+func (r *Report) FailedPackages() []PackageResult {
+	failedTests := make(map[string]bool)
+	for _, t := range r.Tests {
+		if t.Status == StatusFail {
+			failedTests[t.Package] = true
+		}
+	}
+	var failed []PackageResult
+	for _, p := range r.Packages {
+		if p.Status == StatusFail && !failedTests[p.Name] {
+			failed = append(failed, p)
+		}
+	}
+	return failed
+}
+
+// Slowest returns the n slowest top-level tests, slowest first.
+func (r *Report) Slowest(n int) []TestResult {
+	var tests []TestResult
+	for _, t := range r.Tests {
+		if !strings.Contains(t.Name, "/") && t.Status != StatusSkip {
+			tests = append(tests, t)
+		}
+	}
+	sort.SliceStable(tests, func(i, j int) bool {
+		return tests[i].Elapsed > tests[j].Elapsed
+	})
+	if len(tests) > n {
+		tests = tests[:n]
+	}
+	return tests
+}
+
+// seconds converts elapsed seconds to a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+}
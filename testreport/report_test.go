@@ -0,0 +1,79 @@
+package testreport
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestdata(t *testing.T) *Report {
+	t.Helper()
+	f, err := os.Open("testdata/run.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	report, err := Parse(f)
+	require.NoError(t, err)
+	return report
+}
+
+func TestParse(t *testing.T) {
+	report := parseTestdata(t)
+
+	require.Equal(t, 2, report.Passed)
+	require.Equal(t, 3, report.Failed)
+	require.Equal(t, 1, report.Skipped)
+	require.Equal(t, 3500*time.Millisecond, report.Elapsed)
+	require.False(t, report.OK())
+
+	require.Len(t, report.Packages, 3)
+	require.Equal(t, PackageResult{
+		Name:    "example.com/app/api",
+		Status:  StatusFail,
+		Elapsed: 1500 * time.Millisecond,
+		Output:  "FAIL\n",
+	}, report.Packages[0])
+	require.Equal(t, "# example.com/app/store\nstore/store.go:12:2: undefined: sql\nFAIL\texample.com/app/store [build failed]\n", report.Packages[2].Output)
+
+	failed := report.FailedTests()
+	require.Len(t, failed, 2)
+	require.Equal(t, TestResult{
+		Package: "example.com/app/api",
+		Name:    "TestDelete/missing",
+		Status:  StatusFail,
+		Elapsed: 100 * time.Millisecond,
+		Output:  "    api_test.go:42: got 500, want 404\n",
+	}, failed[0])
+	// Unfinished when the package failed
+	require.Equal(t, "TestDrain", failed[1].Name)
+	require.Equal(t, "panic: test timed out after 3s\n", failed[1].Output)
+
+	// The output of subtests is part of their parent's
+	for _, test := range report.Tests {
+		if test.Name == "TestDelete" {
+			require.Equal(t, "    api_test.go:42: got 500, want 404\n", test.Output)
+		}
+	}
+
+	packages := report.FailedPackages()
+	require.Len(t, packages, 1)
+	require.Equal(t, "example.com/app/store", packages[0].Name)
+
+	slowest := report.Slowest(2)
+	require.Equal(t, []string{"TestCreate", "TestDelete"}, []string{slowest[0].Name, slowest[1].Name})
+}
+
+func TestParsePassing(t *testing.T) {
+	report, err := Parse(strings.NewReader(`{"Action":"run","Package":"p","Test":"TestA"}
+{"Action":"pass","Package":"p","Test":"TestA","Elapsed":0.01}
+{"Action":"pass","Package":"p","Elapsed":0.02}
+`))
+	require.NoError(t, err)
+	require.True(t, report.OK())
+	require.Equal(t, 1, report.Passed)
+	require.Empty(t, report.FailedTests())
+	require.Empty(t, report.FailedPackages())
+}
@@ -0,0 +1,114 @@
+package feishubot
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// NewTableParagraphs renders a small table as a fixed-width code block inside
+// a post, for quick tabular reports without switching to interactive cards.
+//
+// Columns are aligned by display width, counting East Asian wide characters
+// as two columns. Rows may have fewer cells than headers; missing cells are
+// left blank.
+//
+// Example:
+//
+//	content := feishubot.NewPostContent(
+//		"Service status",
+//		feishubot.NewTableParagraphs(
+//			[]string{"Service", "Status", "Replicas"},
+//			[][]string{
+//				{"api", "ok", "3"},
+//				{"worker", "degraded", "1"},
+//			},
+//		)...,
+//	)
+func NewTableParagraphs(headers []string, rows [][]string) []Paragraph {
+	return []Paragraph{
+		NewParagraph(NewCodeBlockElement("PLAIN_TEXT", formatTextTable(headers, rows))),
+	}
+}
+
+// formatTextTable renders headers and rows as aligned plain text lines with
+// a dashed separator below the headers.
+func formatTextTable(headers []string, rows [][]string) string {
+	columns := len(headers)
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	widths := make([]int, columns)
+	for _, row := range append([][]string{headers}, rows...) {
+		for i, cell := range row {
+			if w := displayWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(row []string) {
+		var line strings.Builder
+		for i := 0; i < columns; i++ {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(cell)
+			line.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteString("\n")
+	}
+
+	if len(headers) > 0 {
+		writeRow(headers)
+		separator := make([]string, columns)
+		for i, w := range widths {
+			separator[i] = strings.Repeat("-", w)
+		}
+		writeRow(separator)
+	}
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// displayWidth returns the number of columns s occupies in a monospace font,
+// counting East Asian wide and fullwidth characters as two columns.
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if isWideRune(r) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// isWideRune reports whether r is an East Asian wide or fullwidth character.
+func isWideRune(r rune) bool {
+	if r < 0x1100 || r == utf8.RuneError {
+		return false
+	}
+	return r <= 0x115F || // Hangul Jamo
+		(r >= 0x2E80 && r <= 0xA4CF && r != 0x303F) || // CJK radicals .. Yi
+		(r >= 0xAC00 && r <= 0xD7A3) || // Hangul syllables
+		(r >= 0xF900 && r <= 0xFAFF) || // CJK compatibility ideographs
+		(r >= 0xFE30 && r <= 0xFE4F) || // CJK compatibility forms
+		(r >= 0xFF00 && r <= 0xFF60) || // Fullwidth forms
+		(r >= 0xFFE0 && r <= 0xFFE6) ||
+		(r >= 0x1F300 && r <= 0x1F64F) || // Emoji
+		(r >= 0x1F900 && r <= 0x1F9FF) ||
+		(r >= 0x20000 && r <= 0x3FFFD) // CJK extensions
+}
@@ -0,0 +1,65 @@
+package feishubot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTableParagraphs(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		rows    [][]string
+		want    string
+	}{
+		{
+			name:    "simple table",
+			headers: []string{"Service", "Status", "Replicas"},
+			rows: [][]string{
+				{"api", "ok", "3"},
+				{"worker", "degraded", "1"},
+			},
+			want: "Service  Status    Replicas\n" +
+				"-------  --------  --------\n" +
+				"api      ok        3\n" +
+				"worker   degraded  1",
+		},
+		{
+			name:    "wide characters",
+			headers: []string{"服务", "状态"},
+			rows: [][]string{
+				{"api", "正常"},
+			},
+			want: "服务  状态\n" +
+				"----  ----\n" +
+				"api   正常",
+		},
+		{
+			name:    "ragged rows",
+			headers: []string{"A"},
+			rows: [][]string{
+				{"1", "extra"},
+				{},
+			},
+			want: "A\n" +
+				"-  -----\n" +
+				"1  extra\n",
+		},
+		{
+			name: "no headers",
+			rows: [][]string{{"a", "b"}},
+			want: "a  b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewTableParagraphs(tt.headers, tt.rows)
+			require.Len(t, got, 1)
+			require.Len(t, got[0], 1)
+			require.Equal(t, "code_block", got[0][0]["tag"])
+			require.Equal(t, tt.want, got[0][0]["text"])
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package feishubot
+
+import (
+	"regexp"
+	"unicode/utf8"
+)
+
+// DefaultEllipsis is the marker appended by TruncateText when text is cut.
+const DefaultEllipsis = "..."
+
+// atomicSpanPattern matches spans that must never be cut in half:
+// <at> mention tags and markdown links (including images).
+var atomicSpanPattern = regexp.MustCompile(`(?s)<at\b[^>]*>.*?</at>|!?\[[^\]]*\]\([^)]*\)`)
+
+// TruncateText shortens s to at most max bytes, appending DefaultEllipsis
+// when the text is cut.
+//
+// The cut is always made at a rune boundary and never splits an <at> mention
+// tag or a markdown link; such spans are dropped entirely instead.
+//
+// Example:
+//
+//	text := feishubot.TruncateText(logExcerpt, 4096)
+//	message := feishubot.NewTextMessage(text)
+func TruncateText(s string, max int) string {
+	return TruncateTextWithEllipsis(s, max, DefaultEllipsis)
+}
+
+// TruncateTextWithEllipsis is like TruncateText but appends the given
+// ellipsis marker instead of DefaultEllipsis. The marker counts towards max.
+// If the marker itself does not fit, the text is cut without it.
+func TruncateTextWithEllipsis(s string, max int, ellipsis string) string {
+	if max < 0 {
+		max = 0
+	}
+	if len(s) <= max {
+		return s
+	}
+	if len(ellipsis) > max {
+		return s[:truncationPoint(s, max)]
+	}
+	return s[:truncationPoint(s, max-len(ellipsis))] + ellipsis
+}
+
+// truncationPoint returns the largest safe cut position in s that is not
+// greater than limit. limit must be less than len(s).
+func truncationPoint(s string, limit int) int {
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	for _, loc := range atomicSpanPattern.FindAllStringIndex(s, -1) {
+		if loc[0] >= cut {
+			break
+		}
+		if cut < loc[1] {
+			cut = loc[0]
+			break
+		}
+	}
+	return cut
+}
+
+// TruncatePostContent returns a copy of content whose text, link and markdown
+// elements together hold at most max bytes of text. The element where the
+// limit is reached is cut with DefaultEllipsis and everything after it is
+// dropped. The original content is not modified.
+func TruncatePostContent(content *PostContent, max int) *PostContent {
+	result := &PostContent{
+		Title:   content.Title,
+		Content: make([]Paragraph, 0, len(content.Content)),
+	}
+
+	remaining := max
+	for _, p := range content.Content {
+		paragraph := make(Paragraph, 0, len(p))
+		for _, e := range p {
+			text, ok := e["text"].(string)
+			if !ok {
+				paragraph = append(paragraph, e)
+				continue
+			}
+			if len(text) <= remaining {
+				paragraph = append(paragraph, e)
+				remaining -= len(text)
+				continue
+			}
+
+			truncated := copyElement(e)
+			truncated["text"] = TruncateText(text, remaining)
+			paragraph = append(paragraph, truncated)
+			result.Content = append(result.Content, paragraph)
+			return result
+		}
+		result.Content = append(result.Content, paragraph)
+	}
+
+	return result
+}
+
+// TruncateCardBody returns a copy of body in which the content of every
+// markdown element and div text is cut to at most max bytes using
+// TruncateText, including the elements nested in containers such as column
+// sets, collapsible panels and forms. The original body is not modified.
+func TruncateCardBody(body *CardBody, max int) *CardBody {
+	result := *body
+	result.Elements = truncateElements(body.Elements, max)
+	return &result
+}
+
+// truncateElements returns elements with their markdown cut to at most max
+// bytes, copying the elements that are changed and their containers.
+func truncateElements(elements []CardElement, max int) []CardElement {
+	if elements == nil {
+		return nil
+	}
+	result := make([]CardElement, 0, len(elements))
+	for _, element := range elements {
+		result = append(result, truncateElement(element, max))
+	}
+	return result
+}
+
+// truncateElement returns element with its markdown, or the markdown of the
+// elements it holds, cut to at most max bytes.
+func truncateElement(element CardElement, max int) CardElement {
+	switch e := element.(type) {
+	case *MarkdownElement:
+		if len(e.Content) > max {
+			truncated := *e
+			truncated.Content = TruncateText(e.Content, max)
+			return &truncated
+		}
+	case *Div:
+		if e.Text != nil && len(e.Text.Content) > max {
+			text := *e.Text
+			text.Content = TruncateText(e.Text.Content, max)
+			truncated := *e
+			truncated.Text = &text
+			return &truncated
+		}
+	case *ColumnSet:
+		truncated := *e
+		truncated.Columns = make([]*Column, len(e.Columns))
+		for i, column := range e.Columns {
+			truncated.Columns[i] = truncateElement(column, max).(*Column)
+		}
+		return &truncated
+	case *Column:
+		truncated := *e
+		truncated.Elements = truncateElements(e.Elements, max)
+		return &truncated
+	case *FormElement:
+		truncated := *e
+		truncated.Elements = truncateElements(e.Elements, max)
+		return &truncated
+	case MapElement:
+		switch tag := e.Tag(); {
+		case tag == "markdown":
+			if content, ok := e["content"].(string); ok && len(content) > max {
+				truncated := MapElement(copyElement(Element(e)))
+				truncated["content"] = TruncateText(content, max)
+				return truncated
+			}
+		case tag == "column_set":
+			columns, ok := e["columns"].([]interface{})
+			if !ok {
+				break
+			}
+			truncated := MapElement(copyElement(Element(e)))
+			result := make([]interface{}, len(columns))
+			for i, column := range columns {
+				result[i] = column
+				if column, ok := column.(map[string]interface{}); ok {
+					result[i] = map[string]interface{}(truncateChildren(column, max))
+				}
+			}
+			truncated["columns"] = result
+			return truncated
+		case containerTags[tag]:
+			return MapElement(truncateChildren(e, max))
+		}
+	}
+	return element
+}
+
+// truncateChildren returns a copy of an element decoded as a map with the
+// elements it holds truncated.
+func truncateChildren(element map[string]interface{}, max int) Element {
+	children := mapElements(element["elements"])
+	if children == nil {
+		return element
+	}
+	truncated := copyElement(element)
+	result := make([]interface{}, 0, len(children))
+	for _, child := range truncateElements(children, max) {
+		result = append(result, child)
+	}
+	truncated["elements"] = result
+	return truncated
+}
+
+// copyElement returns a shallow copy of e.
+func copyElement(e Element) Element {
+	result := make(Element, len(e))
+	for k, v := range e {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,197 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		max  int
+		want string
+	}{
+		{
+			name: "shorter than max",
+			s:    "hello",
+			max:  10,
+			want: "hello",
+		},
+		{
+			name: "exactly max",
+			s:    "hello",
+			max:  5,
+			want: "hello",
+		},
+		{
+			name: "plain ascii",
+			s:    "hello world",
+			max:  8,
+			want: "hello...",
+		},
+		{
+			name: "cut at rune boundary",
+			s:    "你好世界",
+			max:  8,
+			want: "你...",
+		},
+		{
+			name: "does not split at tag",
+			s:    `ping <at user_id="ou_xxx">Tom</at> now`,
+			max:  20,
+			want: "ping ...",
+		},
+		{
+			name: "keeps complete at tag",
+			s:    `<at user_id="all">所有人</at> deploy finished`,
+			max:  40,
+			want: `<at user_id="all">所有人</at> depl...`,
+		},
+		{
+			name: "does not split markdown link",
+			s:    "see [build log](https://example.com/build/1) for details",
+			max:  20,
+			want: "see ...",
+		},
+		{
+			name: "ellipsis longer than max",
+			s:    "hello world",
+			max:  2,
+			want: "he",
+		},
+		{
+			name: "negative max",
+			s:    "hello",
+			max:  -1,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateText(tt.s, tt.max)
+			if got != tt.want {
+				t.Errorf("TruncateText() = %q, want %q", got, tt.want)
+			}
+			if len(got) > tt.max && tt.max >= 0 {
+				t.Errorf("TruncateText() length = %d, exceeds max %d", len(got), tt.max)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("TruncateText() = %q, not valid UTF-8", got)
+			}
+		})
+	}
+}
+
+func TestTruncateTextWithEllipsis(t *testing.T) {
+	got := TruncateTextWithEllipsis("hello world", 8, "…")
+	require.Equal(t, "hello…", got)
+
+	got = TruncateTextWithEllipsis("hello world", 5, "")
+	require.Equal(t, "hello", got)
+}
+
+func TestTruncatePostContent(t *testing.T) {
+	content := NewPostContent(
+		"Title",
+		NewParagraph(
+			NewTextElement("first "),
+			NewAtElement("ou_xxx", "Tom"),
+			NewLinkElement("link", "https://example.com"),
+		),
+		NewParagraph(NewTextElement(strings.Repeat("x", 50))),
+		NewParagraph(NewTextElement("dropped")),
+	)
+
+	got := TruncatePostContent(content, 20)
+
+	require.Equal(t, "Title", got.Title)
+	require.Len(t, got.Content, 2)
+	require.Len(t, got.Content[0], 3)
+	require.Equal(t, "xxxxxxx...", got.Content[1][0]["text"])
+
+	// The original content must not be modified
+	require.Len(t, content.Content, 3)
+	require.Equal(t, strings.Repeat("x", 50), content.Content[1][0]["text"])
+}
+
+func TestTruncateCardBody(t *testing.T) {
+	body := &CardBody{
+		Padding: "12px",
+		Elements: []CardElement{
+			NewMarkdownElement("a very long markdown content"),
+			NewDivElement(NewCardTitle("a very long div text")),
+			NewButtonElement("a very long button", "primary", "https://example.com"),
+		},
+	}
+
+	got := TruncateCardBody(body, 10)
+
+	require.Equal(t, Padding("12px"), got.Padding)
+	require.Equal(t, "a very ...", got.Elements[0].(*MarkdownElement).Content)
+	require.Equal(t, "a very ...", got.Elements[1].(*Div).Text.Content)
+	require.Equal(t, body.Elements[2], got.Elements[2])
+
+	// The original body must not be modified
+	require.Equal(t, "a very long markdown content", body.Elements[0].(*MarkdownElement).Content)
+	require.Equal(t, "a very long div text", body.Elements[1].(*Div).Text.Content)
+}
+
+func TestTruncateCardBodyKeepsUnknownFields(t *testing.T) {
+	card, err := ParseCard([]byte(`{"schema": "2.0", "body": {"elements": [
+		{"tag": "div", "text": {"tag": "plain_text", "content": "a very long div text", "i18n_content": {"en_us": "a very long div text"}}}
+	]}}`))
+	require.NoError(t, err)
+
+	got, err := json.Marshal(TruncateCardBody(card.Body, 10))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"elements": [
+		{"tag": "div", "text": {"tag": "plain_text", "content": "a very ...", "i18n_content": {"en_us": "a very long div text"}}}
+	]}`, string(got))
+}
+
+func TestTruncateCardBodyNested(t *testing.T) {
+	form, err := DecodeCardElement([]byte(`{"tag": "form", "name": "f", "elements": [
+		{"tag": "markdown", "content": "a very long form text"},
+		{"tag": "column_set", "columns": [
+			{"tag": "column", "elements": [{"tag": "markdown", "content": "a very long nested text"}]}
+		]}
+	]}`))
+	require.NoError(t, err)
+
+	body := &CardBody{Elements: []CardElement{
+		NewColumnSet(NewColumn(1, NewMarkdownElement("a very long column text"))),
+		NewCollapsiblePanelElement("Details", false, NewMarkdownElement("a very long panel text")),
+		form,
+	}}
+	original, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	got, err := json.Marshal(TruncateCardBody(body, 10))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"elements": [
+		{"tag": "column_set", "columns": [
+			{"tag": "column", "weight": 1, "width": "weighted", "elements": [{"tag": "markdown", "content": "a very ..."}]}
+		]},
+		{"tag": "collapsible_panel", "expanded": false,
+			"header": {"title": {"tag": "markdown", "content": "Details"}},
+			"elements": [{"tag": "markdown", "content": "a very ..."}]
+		},
+		{"tag": "form", "name": "f", "elements": [
+			{"tag": "markdown", "content": "a very ..."},
+			{"tag": "column_set", "columns": [
+				{"tag": "column", "elements": [{"tag": "markdown", "content": "a very ..."}]}
+			]}
+		]}
+	]}`, string(got))
+
+	// The original body must not be modified
+	after, err := json.Marshal(body)
+	require.NoError(t, err)
+	require.JSONEq(t, string(original), string(after))
+}
@@ -0,0 +1,131 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// UnknownFields holds the members of a parsed JSON object that the typed
+// structure it was parsed into does not model, keyed by their JSON names.
+// They are written back as-is when the structure is marshaled again, so
+// that parsing a card, modifying it and sending it does not strip features
+// this package does not support yet.
+type UnknownFields map[string]json.RawMessage
+
+// jsonFieldNames returns the JSON names of the fields of the struct type of
+// v, plus any extra names handled separately.
+func jsonFieldNames(v interface{}, extra ...string) map[string]bool {
+	t := reflect.TypeOf(v)
+	names := make(map[string]bool, t.NumField()+len(extra))
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	for _, name := range extra {
+		names[name] = true
+	}
+	return names
+}
+
+var (
+	cardFields       = jsonFieldNames(Card{}, "elements")
+	cardBodyFields   = jsonFieldNames(CardBody{})
+	cardHeaderFields = jsonFieldNames(CardHeader{})
+	cardTitleFields  = jsonFieldNames(CardTitle{})
+)
+
+// unknownFields returns the members of the JSON object data whose names are
+// not in known, or nil if there are none.
+func unknownFields(data []byte, known map[string]bool) (UnknownFields, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	var unknown UnknownFields
+	for name, value := range members {
+		if known[name] {
+			continue
+		}
+		if unknown == nil {
+			unknown = make(UnknownFields)
+		}
+		unknown[name] = value
+	}
+	return unknown, nil
+}
+
+// marshalWithUnknown marshals v, a JSON object, adding the unknown fields
+// that it does not already contain.
+func marshalWithUnknown(v interface{}, unknown UnknownFields) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(unknown) == 0 {
+		return data, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for name, value := range unknown {
+		if _, ok := members[name]; !ok {
+			members[name] = value
+		}
+	}
+	return json.Marshal(members)
+}
+
+// addUnknown adds the unknown fields to m where it does not have them yet.
+func addUnknown(m map[string]interface{}, unknown UnknownFields) {
+	for name, value := range unknown {
+		if _, ok := m[name]; !ok {
+			m[name] = value
+		}
+	}
+}
+
+// UnmarshalJSON decodes the card header, keeping unknown fields.
+func (h *CardHeader) UnmarshalJSON(data []byte) error {
+	type alias CardHeader
+	if err := json.Unmarshal(data, (*alias)(h)); err != nil {
+		return err
+	}
+
+	unknown, err := unknownFields(data, cardHeaderFields)
+	h.UnknownFields = unknown
+	return err
+}
+
+// MarshalJSON encodes the card header, including unknown fields.
+func (h CardHeader) MarshalJSON() ([]byte, error) {
+	type alias CardHeader
+	return marshalWithUnknown(alias(h), h.UnknownFields)
+}
+
+// MarshalJSON encodes the card body, including unknown fields.
+func (b CardBody) MarshalJSON() ([]byte, error) {
+	type alias CardBody
+	return marshalWithUnknown(alias(b), b.UnknownFields)
+}
+
+// UnmarshalJSON decodes the title, keeping unknown fields such as
+// i18n_content.
+func (t *CardTitle) UnmarshalJSON(data []byte) error {
+	type alias CardTitle
+	if err := json.Unmarshal(data, (*alias)(t)); err != nil {
+		return err
+	}
+
+	unknown, err := unknownFields(data, cardTitleFields)
+	t.UnknownFields = unknown
+	return err
+}
+
+// MarshalJSON encodes the title, including unknown fields.
+func (t CardTitle) MarshalJSON() ([]byte, error) {
+	type alias CardTitle
+	return marshalWithUnknown(alias(t), t.UnknownFields)
+}
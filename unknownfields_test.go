@@ -0,0 +1,59 @@
+package feishubot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownFieldsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		v    any
+	}{
+		{
+			name: "title",
+			data: `{"tag":"plain_text","content":"x","text_size":"heading","text_color":"red"}`,
+			v:    &CardTitle{},
+		},
+		{
+			name: "header",
+			data: `{"title":{"tag":"plain_text","content":"x"},"template":"blue","padding":"8px"}`,
+			v:    &CardHeader{},
+		},
+		{
+			name: "body",
+			data: `{"elements":[],"horizontal_align":"center"}`,
+			v:    &CardBody{},
+		},
+		{
+			name: "no unknown fields",
+			data: `{"tag":"plain_text","content":"x"}`,
+			v:    &CardTitle{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, json.Unmarshal([]byte(tt.data), tt.v))
+			got, err := json.Marshal(tt.v)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.data, string(got))
+		})
+	}
+}
+
+func TestUnknownFields(t *testing.T) {
+	unknown, err := unknownFields([]byte(`{"a":1,"b":2}`), map[string]bool{"a": true})
+	require.NoError(t, err)
+	require.Equal(t, UnknownFields{"b": json.RawMessage(`2`)}, unknown)
+
+	unknown, err = unknownFields([]byte(`{"a":1}`), map[string]bool{"a": true})
+	require.NoError(t, err)
+	require.Nil(t, unknown)
+
+	_, err = unknownFields([]byte(`[]`), nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package feishubot
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	// ErrInvalidImageKey is returned when an image key is malformed.
+	ErrInvalidImageKey = errors.New("invalid image key")
+
+	// ErrInvalidShareChatID is returned when a share chat ID is malformed.
+	ErrInvalidShareChatID = errors.New("invalid share chat ID")
+)
+
+const (
+	// maxImageKeyLength is a generous upper bound for image keys;
+	// current keys such as "img_v3_..." are well below it.
+	maxImageKeyLength = 128
+
+	// shareChatIDLength is the length of a chat ID: "oc_" and 32 characters.
+	shareChatIDLength = 35
+)
+
+var (
+	imageKeyPattern    = regexp.MustCompile(`^img_[A-Za-z0-9_-]+$`)
+	shareChatIDPattern = regexp.MustCompile(`^oc_[A-Za-z0-9]+$`)
+)
+
+// ValidateImageKey checks that imageKey looks like a key returned by the
+// Feishu image upload API ("img_" followed by letters, digits, '_' or '-').
+// The returned error wraps ErrInvalidImageKey.
+func ValidateImageKey(imageKey string) error {
+	if len(imageKey) > maxImageKeyLength {
+		return fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidImageKey, imageKey, maxImageKeyLength)
+	}
+	if !imageKeyPattern.MatchString(imageKey) {
+		return fmt.Errorf("%w: %q must start with \"img_\" followed by letters, digits, '_' or '-'", ErrInvalidImageKey, imageKey)
+	}
+	return nil
+}
+
+// ValidateShareChatID checks that shareChatID looks like a group chat ID
+// ("oc_" followed by 32 letters or digits).
+// The returned error wraps ErrInvalidShareChatID.
+func ValidateShareChatID(shareChatID string) error {
+	if !shareChatIDPattern.MatchString(shareChatID) {
+		return fmt.Errorf("%w: %q must start with \"oc_\" followed by letters or digits", ErrInvalidShareChatID, shareChatID)
+	}
+	if len(shareChatID) != shareChatIDLength {
+		return fmt.Errorf("%w: %q must be %d characters long, got %d", ErrInvalidShareChatID, shareChatID, shareChatIDLength, len(shareChatID))
+	}
+	return nil
+}
+
+// NewImageMessageE is like NewImageMessage but validates the image key first
+// with ValidateImageKey, so malformed keys are reported with a descriptive
+// error instead of a generic invalid-parameter code from Feishu.
+func NewImageMessageE(imageKey string) (*Message, error) {
+	if err := ValidateImageKey(imageKey); err != nil {
+		return nil, err
+	}
+	return NewImageMessage(imageKey), nil
+}
+
+// NewShareChatMessageE is like NewShareChatMessage but validates the chat ID
+// first with ValidateShareChatID.
+func NewShareChatMessageE(shareChatID string) (*Message, error) {
+	if err := ValidateShareChatID(shareChatID); err != nil {
+		return nil, err
+	}
+	return NewShareChatMessage(shareChatID), nil
+}
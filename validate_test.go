@@ -0,0 +1,64 @@
+package feishubot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewImageMessageE(t *testing.T) {
+	tests := []struct {
+		name     string
+		imageKey string
+		wantErr  bool
+	}{
+		{"valid key", "img_ecffc3b9-8f14-400f-a014-05eca1a4310g", false},
+		{"valid v3 key", "img_v3_02ab_0b2f1c6e-4bd4-45c8-9d8f-9e04c4a5cc9g", false},
+		{"empty", "", true},
+		{"missing prefix", "ecffc3b9-8f14-400f-a014-05eca1a4310g", true},
+		{"prefix only", "img_", true},
+		{"invalid characters", "img_abc def", true},
+		{"too long", "img_" + strings.Repeat("a", 200), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewImageMessageE(tt.imageKey)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidImageKey)
+				require.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, NewImageMessage(tt.imageKey), got)
+		})
+	}
+}
+
+func TestNewShareChatMessageE(t *testing.T) {
+	tests := []struct {
+		name        string
+		shareChatID string
+		wantErr     bool
+	}{
+		{"valid ID", "oc_a0553eda9014c201e6969b478895c230", false},
+		{"empty", "", true},
+		{"wrong prefix", "ou_a0553eda9014c201e6969b478895c230", true},
+		{"too short", "oc_12345", true},
+		{"masked ID", "oc_f5b1a7eb27ae2****339ff", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewShareChatMessageE(tt.shareChatID)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidShareChatID)
+				require.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, NewShareChatMessage(tt.shareChatID), got)
+		})
+	}
+}